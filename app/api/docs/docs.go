@@ -24,9 +24,189 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/v1/payments": {
+        "/admin/audit-log": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists recorded changes, newest first, with the target as it stood before and after each. Sealed entries carry their position and hash in the tamper-evident chain",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "audit"
+                ],
+                "summary": "List audit log entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only changes made by this actor",
+                        "name": "actor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only this action, e.g. payment.created",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes to this kind of target, e.g. payment",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes to this target",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes made by this request",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes recorded at or after this RFC 3339 time",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only changes recorded before this RFC 3339 time",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Audit log entries",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AuditList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/disputes": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists disputes newest first, optionally for one payment or in one status. A merchant API key sees only disputes on its own payments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "disputes"
+                ],
+                "summary": "List disputes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only disputes against this payment",
+                        "name": "payment_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "needs_response",
+                            "under_review",
+                            "won",
+                            "lost"
+                        ],
+                        "type": "string",
+                        "description": "Only disputes in this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of disputes to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Disputes",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DisputeList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
             "post": {
-                "description": "Creates a new payment with the provided details",
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Records a chargeback raised against a successful payment. The dispute needs a response before evidence_due_by; the amount defaults to the full payment amount. Publishes a dispute.created event",
                 "consumes": [
                     "application/json"
                 ],
@@ -34,62 +214,82 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payments"
+                    "disputes"
                 ],
-                "summary": "Create a new payment",
+                "summary": "Create a dispute",
                 "parameters": [
                     {
-                        "description": "Payment details",
-                        "name": "payment",
+                        "description": "Dispute details",
+                        "name": "dispute",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.PaymentRequest"
+                            "$ref": "#/definitions/domain.DisputeRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Payment created successfully",
+                        "description": "Dispute created",
                         "schema": {
-                            "$ref": "#/definitions/domain.Payment"
+                            "$ref": "#/definitions/domain.Dispute"
                         }
                     },
                     "400": {
                         "description": "Invalid request body or validation failed",
                         "schema": {
-                            "$ref": "#/definitions/http.ErrorResponse"
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
                         }
                     },
                     "409": {
-                        "description": "Payment with this reference already exists",
+                        "description": "Payment not successful, disputes would exceed the payment amount, or provider reference already used",
                         "schema": {
-                            "$ref": "#/definitions/http.ErrorResponse"
+                            "$ref": "#/definitions/domain.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/http.ErrorResponse"
+                            "$ref": "#/definitions/domain.ProblemDetails"
                         }
                     }
                 }
             }
         },
-        "/v1/payments/{id}": {
+        "/admin/disputes/{id}": {
             "get": {
-                "description": "Retrieves payment details by payment ID",
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a dispute with its status and evidence deadline",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "payments"
+                    "disputes"
                 ],
-                "summary": "Get payment by ID",
+                "summary": "Get dispute by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Payment ID",
+                        "description": "Dispute ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -97,123 +297,6603 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Payment found",
+                        "description": "Dispute found",
                         "schema": {
-                            "$ref": "#/definitions/domain.Payment"
+                            "$ref": "#/definitions/domain.Dispute"
                         }
                     },
                     "400": {
-                        "description": "Invalid payment ID format",
+                        "description": "Invalid dispute ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
                         "schema": {
-                            "$ref": "#/definitions/http.ErrorResponse"
+                            "$ref": "#/definitions/domain.ProblemDetails"
                         }
                     },
                     "404": {
-                        "description": "Payment not found",
+                        "description": "Dispute not found",
                         "schema": {
-                            "$ref": "#/definitions/http.ErrorResponse"
+                            "$ref": "#/definitions/domain.ProblemDetails"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/http.ErrorResponse"
+                            "$ref": "#/definitions/domain.ProblemDetails"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "domain.Payment": {
-            "type": "object",
-            "required": [
-                "amount",
-                "currency",
-                "reference"
-            ],
-            "properties": {
-                "amount": {
-                    "type": "number"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "currency": {
-                    "type": "string",
-                    "enum": [
-                        "ETB",
-                        "USD"
-                    ]
-                },
-                "id": {
-                    "type": "string"
-                },
-                "reference": {
-                    "type": "string"
-                },
-                "status": {
-                    "$ref": "#/definitions/domain.PaymentStatus"
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
         },
-        "domain.PaymentRequest": {
-            "type": "object",
-            "required": [
-                "amount",
-                "currency",
-                "reference"
-            ],
-            "properties": {
-                "amount": {
-                    "type": "number"
-                },
-                "currency": {
-                    "type": "string",
-                    "enum": [
-                        "ETB",
-                        "USD"
-                    ]
-                },
-                "reference": {
-                    "type": "string"
+        "/admin/disputes/{id}/close": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Records whether the dispute was won or lost. Publishes a dispute.won or dispute.lost event",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "disputes"
+                ],
+                "summary": "Close a dispute",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dispute ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Outcome",
+                        "name": "outcome",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CloseDisputeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dispute closed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Dispute"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dispute ID, request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Dispute not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Dispute already closed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
                 }
             }
         },
-        "domain.PaymentStatus": {
-            "type": "string",
-            "enum": [
-                "PENDING",
-                "SUCCESS",
-                "FAILED"
-            ],
-            "x-enum-varnames": [
-                "StatusPending",
-                "StatusSuccess",
-                "StatusFailed"
-            ]
-        },
-        "http.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "body": {
-                    "description": "in: body",
-                    "type": "object",
-                    "properties": {
-                        "code": {
-                            "type": "integer"
-                        },
-                        "description": {
-                            "type": "string"
-                        },
-                        "message": {
+        "/admin/disputes/{id}/evidence": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the evidence files submitted for a dispute",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "disputes"
+                ],
+                "summary": "List dispute evidence",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dispute ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Evidence files",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DisputeEvidenceList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dispute ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Dispute not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/disputes/{id}/evidence/{evidence_id}": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Downloads an evidence file as it was uploaded",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "disputes"
+                ],
+                "summary": "Download dispute evidence",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dispute ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Evidence ID",
+                        "name": "evidence_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Evidence file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dispute or evidence ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Evidence not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/fee-schedules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists fee schedules by currency, the most recently effective first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fees"
+                ],
+                "summary": "List fee schedules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of fee schedules to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fee schedules",
+                        "schema": {
+                            "$ref": "#/definitions/domain.FeeScheduleList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a fee schedule for a currency, optionally limited to one payment method and one merchant. The fee is percentage percent of the amount plus fixed_amount, held between min_fee and max_fee. Schedules cannot be edited; create a new one with a later effective_from to change fees",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fees"
+                ],
+                "summary": "Create a fee schedule",
+                "parameters": [
+                    {
+                        "description": "Fee schedule details",
+                        "name": "schedule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.FeeScheduleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Fee schedule created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.FeeSchedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/fee-schedules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a fee schedule by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fees"
+                ],
+                "summary": "Get fee schedule by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Fee schedule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fee schedule found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.FeeSchedule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid fee schedule ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Fee schedule not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/ledger/balances": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the balance of every ledger account per currency, in the account's normal direction",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Get ledger balances",
+                "responses": {
+                    "200": {
+                        "description": "Account balances",
+                        "schema": {
+                            "$ref": "#/definitions/domain.LedgerBalances"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/ledger/integrity": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reports journal entries whose postings do not sum to zero and successful payments missing from the ledger. ok is true when there are none.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "Check ledger integrity",
+                "responses": {
+                    "200": {
+                        "description": "Integrity report",
+                        "schema": {
+                            "$ref": "#/definitions/domain.IntegrityReport"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/ledger/payments/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the journal entries recorded for a payment, with their postings, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ledger"
+                ],
+                "summary": "List payment journal entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Journal entries",
+                        "schema": {
+                            "$ref": "#/definitions/domain.LedgerEntryList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payments/redrive": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Requeues the pending payments matching the filter, oldest first, one at a time under each payment's lock. Payments processed since they matched are skipped. A dry run only lists the matches",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Re-drive pending payments",
+                "parameters": [
+                    {
+                        "description": "Filter, limit and dry run flag",
+                        "name": "redrive",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.RedriveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matched and requeued payments",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RedriveResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Stopped partway; the body lists the payments requeued before it stopped",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RedriveResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payments/{id}/attempts": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists every provider call the worker recorded for the payment, oldest first, with provider references and errors",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List a payment's provider attempts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attempts",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentAttemptList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payments/{id}/fail": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fails a payment stuck in PENDING without charging it. The reason is required and kept in the audit log. Waits for a worker charging the payment",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Force-fail a payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason for failing the payment",
+                        "name": "failure",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ForceFailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID, request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment not pending",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payments/{id}/requeue": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Publishes a pending payment to the processing queue again, e.g. after its message was lost. Waits for a worker charging the payment. Processing is idempotent, so a payment queued twice is charged once",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Requeue a payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment queued",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment not pending",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "503": {
+                        "description": "Processing queue unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/payments/{id}/risk": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the risk score, outcome and matched rules of a payment, and the client IP it was created from. These are not part of the payment merchants see",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Get a payment's risk assessment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment risk",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentRisk"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reconciliation-exceptions": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the lines of every reconciliation that did not match and are not yet resolved, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliations"
+                ],
+                "summary": "List reconciliation exceptions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of exceptions to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Unresolved exceptions",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReconciliationItemList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reconciliation-exceptions/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Closes an exception with a note on how it was settled, taking it off the unresolved list",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliations"
+                ],
+                "summary": "Resolve a reconciliation exception",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reconciliation exception ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Resolution note",
+                        "name": "resolution",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ResolveExceptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Exception resolved",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReconciliationItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid exception ID, request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Exception not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Exception already resolved",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reconciliations": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists imported statements with their reconciliation totals, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliations"
+                ],
+                "summary": "List reconciliations",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of reconciliations to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reconciliations",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReconciliationRunList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reconciles a provider or bank statement, sent as the raw request body, against the payments. Each line is matched to a payment by its reference or the provider's reference and compared by amount and currency; successful payments within the statement period that are missing from it are reported too. The format defaults from the Content-Type: text/csv for csv, application/xml or text/xml for camt053",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliations"
+                ],
+                "summary": "Import a statement",
+                "parameters": [
+                    {
+                        "enum": [
+                            "csv",
+                            "camt053"
+                        ],
+                        "type": "string",
+                        "description": "Statement format",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only expect payments routed to this provider on the statement",
+                        "name": "provider",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Statement file",
+                        "name": "statement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
                             "type": "string"
-                        },
-                        "params": {}
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Statement reconciled",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReconciliationRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Unsupported format or unreadable statement",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "413": {
+                        "description": "Statement too large",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "415": {
+                        "description": "No format given and none implied by the Content-Type",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reconciliations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves the report of an imported statement: how many lines matched, were missing internally, missing at the provider or mismatched in amount",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliations"
+                ],
+                "summary": "Get reconciliation by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reconciliation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reconciliation found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReconciliationRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid reconciliation ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Reconciliation not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reconciliations/{id}/items": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the statement lines and missing payments of a reconciliation, optionally with one result",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reconciliations"
+                ],
+                "summary": "List reconciliation lines",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Reconciliation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "matched",
+                            "missing_internally",
+                            "missing_at_provider",
+                            "amount_mismatch"
+                        ],
+                        "type": "string",
+                        "description": "Only lines with this result",
+                        "name": "result",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of lines to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reconciliation lines",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReconciliationItemList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid reconciliation ID, result filter or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Reconciliation not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists reviews of payments held by fraud screening. Escalated reviews come first, then the oldest",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "List payment reviews",
+                "parameters": [
+                    {
+                        "enum": [
+                            "open",
+                            "claimed",
+                            "approved",
+                            "declined"
+                        ],
+                        "type": "string",
+                        "description": "Only reviews in this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only escalated (true) or unescalated (false) reviews",
+                        "name": "escalated",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of reviews to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Reviews",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReviewList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a review with its reviewer, decision and notes",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Get payment review by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid review ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Approves a review claimed by the operator whose token is used. The payment goes back to PENDING and is queued for processing",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Approve a payment review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Optional notes",
+                        "name": "decision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReviewDecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review approved",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid review ID, request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Review not claimed by this operator or already decided",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/{id}/claim": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Assigns an open review to the operator whose token is used, stopping its SLA timers. Claiming a review the operator already holds succeeds again",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Claim a payment review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review claimed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid review ID",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Review claimed by another operator or already decided",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/{id}/decline": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Declines a review claimed by the operator whose token is used. Notes are required. The payment is failed and never processed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "reviews"
+                ],
+                "summary": "Decline a payment review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notes",
+                        "name": "decision",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ReviewDecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review declined",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentReview"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid review ID, request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Review not claimed by this operator or already decided",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/risk-blocklist": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists blocklist entries, newest first, optionally of one kind",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "List blocklist entries",
+                "parameters": [
+                    {
+                        "enum": [
+                            "ip",
+                            "email",
+                            "customer"
+                        ],
+                        "type": "string",
+                        "description": "Only entries of this kind",
+                        "name": "kind",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Entries",
+                        "schema": {
+                            "$ref": "#/definitions/domain.BlocklistEntryList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid kind filter or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds an IP address, email address or customer ID to the blocklist. Rules test entries with blocked(kind, value)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Add a blocklist entry",
+                "parameters": [
+                    {
+                        "description": "Blocklist entry",
+                        "name": "entry",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.BlocklistEntryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Entry created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.BlocklistEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Value already on the blocklist",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/risk-blocklist/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Removes an entry from the blocklist",
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Delete blocklist entry",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Blocklist entry ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Entry deleted"
+                    },
+                    "400": {
+                        "description": "Invalid blocklist entry ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Blocklist entry not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/risk-rules": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists fraud rules in evaluation order, oldest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "List fraud rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of rules to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rules",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RiskRuleList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a rule evaluated against every payment created through the API. The expression must evaluate to a boolean; see the README for the variables and functions available. A match adds score to the payment's risk score and applies action (review or block)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Create a fraud rule",
+                "parameters": [
+                    {
+                        "description": "Rule details",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.RiskRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Rule created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RiskRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body, validation failed or the expression does not compile",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "A rule with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/risk-rules/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a fraud rule by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Get fraud rule by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rule found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RiskRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces a rule's name, expression, action and score. Leaving enabled out keeps the rule's current state",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Update fraud rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule details",
+                        "name": "rule",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.RiskRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rule updated",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RiskRule"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid rule ID, request body, validation failed or the expression does not compile",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "A rule with this name already exists",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a rule. Payments already screened keep the rule's name in their matched rules",
+                "tags": [
+                    "risk"
+                ],
+                "summary": "Delete fraud rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Rule deleted"
+                    },
+                    "400": {
+                        "description": "Invalid rule ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Rule not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/settlements": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists settlement batches, latest cutoff first, optionally for one merchant or in one status. A merchant API key sees only its own batches",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "List settlement batches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only batches of this merchant; empty for payments without merchant_id metadata. Ignored for a merchant API key",
+                        "name": "merchant_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "closed",
+                            "paid"
+                        ],
+                        "type": "string",
+                        "description": "Only batches in this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of batches to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settlement batches",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SettlementBatchList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/settlements/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a settlement batch with its totals",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Get settlement batch by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settlement batch found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SettlementBatch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid settlement batch ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Settlement batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/settlements/{id}/adjustments": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the refunds and chargebacks on payments settled in earlier batches that this batch takes off its payout",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "List settlement batch adjustments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of adjustments to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settlement adjustments",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SettlementAdjustmentList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid settlement batch ID or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Settlement batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/settlements/{id}/pay": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Records that a closed batch's net amount has been paid out to the merchant and posts the payout to the ledger",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Mark a settlement batch paid",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settlement batch paid",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SettlementBatch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid settlement batch ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Settlement batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Settlement batch is still open or already paid",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/settlements/{id}/payments": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the payments settled in a batch with the fee and refunds taken off each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "List settlement batch payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of payments to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settled payments",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SettlementItemList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid settlement batch ID or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Settlement batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/checkout/sessions": {
+            "post": {
+                "description": "Creates a pending payment and a hosted checkout page on which the payer chooses a payment method. Send the payer to the returned url; the payment is only processed once they confirm.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Create a checkout session",
+                "parameters": [
+                    {
+                        "description": "Payment and redirect details",
+                        "name": "session",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CheckoutSessionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Checkout session created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.CheckoutSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Metadata merchant_id is not the merchant of the API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment with this reference already exists",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/checkout/sessions/{id}": {
+            "get": {
+                "description": "Retrieves a checkout session together with its payment. Open sessions past their expiry are reported as expired and their payment failed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "checkout"
+                ],
+                "summary": "Get checkout session by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Checkout session ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Checkout session found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.CheckoutSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid checkout session ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Checkout session not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/customers": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    }
+                ],
+                "description": "Lists customers, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "List customers",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of customers to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Customers",
+                        "schema": {
+                            "$ref": "#/definitions/domain.CustomerList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid merchant API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    }
+                ],
+                "description": "Creates a new customer with the provided details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Create a new customer",
+                "parameters": [
+                    {
+                        "description": "Customer details",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CustomerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Customer created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid merchant API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Customer with this external ID already exists",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/customers/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    }
+                ],
+                "description": "Retrieves customer details by customer ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Get customer by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Customer found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid customer ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid merchant API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    }
+                ],
+                "description": "Replaces the details of an existing customer",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Update customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Customer details",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.CustomerRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Customer updated",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid merchant API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Customer with this external ID already exists",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    }
+                ],
+                "description": "Deletes a customer. Customers with payments cannot be deleted.",
+                "tags": [
+                    "customers"
+                ],
+                "summary": "Delete customer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Customer deleted"
+                    },
+                    "400": {
+                        "description": "Invalid customer ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid merchant API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Customer has payments",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/customers/{id}/payments": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    }
+                ],
+                "description": "Lists the payments made by a customer, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "customers"
+                ],
+                "summary": "List customer payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of payments to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payments",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid customer ID or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid merchant API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/disputes": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists disputes newest first, optionally for one payment or in one status. A merchant API key sees only disputes on its own payments",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "disputes"
+                ],
+                "summary": "List disputes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only disputes against this payment",
+                        "name": "payment_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "needs_response",
+                            "under_review",
+                            "won",
+                            "lost"
+                        ],
+                        "type": "string",
+                        "description": "Only disputes in this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of disputes to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Disputes",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DisputeList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/disputes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a dispute with its status and evidence deadline",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "disputes"
+                ],
+                "summary": "Get dispute by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dispute ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dispute found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Dispute"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dispute ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Dispute not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/disputes/{id}/evidence": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the evidence files submitted for a dispute",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "disputes"
+                ],
+                "summary": "List dispute evidence",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dispute ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Evidence files",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DisputeEvidenceList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dispute ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Dispute not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    }
+                ],
+                "description": "Submits a written description and up to 10 files (PDF, PNG, JPEG or plain text, 5 MiB each, 25 MiB in total) before the evidence deadline, moving the dispute under review. Evidence can be submitted once. Publishes a dispute.under_review event",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "disputes"
+                ],
+                "summary": "Submit dispute evidence",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dispute ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Written response to the dispute",
+                        "name": "description",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Evidence files; repeat the field for several",
+                        "name": "files",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Evidence submitted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Dispute"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dispute ID, form or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid merchant API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Dispute not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Dispute not awaiting a response or deadline passed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "413": {
+                        "description": "Evidence too large",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/disputes/{id}/evidence/{evidence_id}": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Downloads an evidence file as it was uploaded",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "disputes"
+                ],
+                "summary": "Download dispute evidence",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dispute ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Evidence ID",
+                        "name": "evidence_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Evidence file",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid dispute or evidence ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Evidence not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/payment-links": {
+            "get": {
+                "description": "Lists payment links, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-links"
+                ],
+                "summary": "List payment links",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of payment links to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment links",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentLinkList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a shareable link payers can pay through without an API integration. Leave amount out to let the payer enter it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-links"
+                ],
+                "summary": "Create a payment link",
+                "parameters": [
+                    {
+                        "description": "Payment link details",
+                        "name": "link",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentLinkRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Payment link created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Metadata merchant_id is not the merchant of the API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/payment-links/{id}": {
+            "get": {
+                "description": "Retrieves a payment link with its use count and the total of the successful payments collected through it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-links"
+                ],
+                "summary": "Get payment link by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment link found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment link ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment link not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/payment-links/{id}/activate": {
+            "post": {
+                "description": "Makes a deactivated payment link usable again. Expired and used-up links stay unusable.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-links"
+                ],
+                "summary": "Activate payment link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment link activated",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment link ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment link not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/payment-links/{id}/deactivate": {
+            "post": {
+                "description": "Stops a payment link from accepting new payments. Payments already started through it are unaffected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-links"
+                ],
+                "summary": "Deactivate payment link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment link deactivated",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentLink"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment link ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment link not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/payment-links/{id}/payments": {
+            "get": {
+                "description": "Lists the payments made through a payment link, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payment-links"
+                ],
+                "summary": "List payment link payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment link ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of payments to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payments",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment link ID or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment link not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/payments": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    }
+                ],
+                "description": "Lists the merchant's payments, newest first. Filter by metadata with metadata[key]=value; all given pairs must match.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "List payments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of payments to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only payments whose metadata has this key/value",
+                        "name": "metadata[key]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payments",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination or filter parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid merchant API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a new payment with the provided details. The payment is screened against the fraud rules first; a blocked payment is stored as FAILED and never processed",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Create a new payment",
+                "parameters": [
+                    {
+                        "description": "Payment details",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.PaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Payment created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Metadata merchant_id is not the merchant of the API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment with this reference already exists",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/payments/{id}": {
+            "get": {
+                "description": "Retrieves payment details by payment ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Get payment by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/payments/{id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    }
+                ],
+                "description": "Moves a PENDING payment to CANCELED so the worker never charges it. A cancel that arrives while the worker is charging the payment waits for the charge and then fails with 409. Only the merchant that owns the payment may cancel it.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "payments"
+                ],
+                "summary": "Cancel a pending payment",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Payment ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Payment canceled",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Payment"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid payment ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid merchant API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Payment not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Payment is not pending",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans": {
+            "get": {
+                "description": "Lists plans, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List plans",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of plans to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plans",
+                        "schema": {
+                            "$ref": "#/definitions/domain.PlanList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Creates a plan that subscriptions bill: an amount every interval_count intervals, optionally after a free trial",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Create a plan",
+                "parameters": [
+                    {
+                        "description": "Plan details",
+                        "name": "plan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.PlanRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Plan created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Plan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/plans/{id}": {
+            "get": {
+                "description": "Retrieves a plan by its ID",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get plan by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Plan ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Plan found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Plan"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid plan ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/settlements": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists settlement batches, latest cutoff first, optionally for one merchant or in one status. A merchant API key sees only its own batches",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "List settlement batches",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only batches of this merchant; empty for payments without merchant_id metadata. Ignored for a merchant API key",
+                        "name": "merchant_id",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "closed",
+                            "paid"
+                        ],
+                        "type": "string",
+                        "description": "Only batches in this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of batches to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settlement batches",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SettlementBatchList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid filter or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/settlements/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Retrieves a settlement batch with its totals",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "Get settlement batch by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settlement batch found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SettlementBatch"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid settlement batch ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Settlement batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/settlements/{id}/adjustments": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the refunds and chargebacks on payments settled in earlier batches that this batch takes off its payout",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "List settlement batch adjustments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of adjustments to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settlement adjustments",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SettlementAdjustmentList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid settlement batch ID or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Settlement batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/settlements/{id}/payments": {
+            "get": {
+                "security": [
+                    {
+                        "MerchantKey": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lists the payments settled in a batch with the fee and refunds taken off each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "settlements"
+                ],
+                "summary": "List settlement batch payments",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Settlement batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of payments to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Settled payments",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SettlementItemList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid settlement batch ID or pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "401": {
+                        "description": "Missing merchant API key or admin token",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Settlement batch not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/subscriptions": {
+            "get": {
+                "description": "Lists subscriptions, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "List subscriptions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20, capped at 100)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of subscriptions to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscriptions",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SubscriptionList"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Subscribes a customer to a plan. The worker charges the payment method once per cycle, starting at the billing anchor, which defaults to the end of the plan's trial.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Create a subscription",
+                "parameters": [
+                    {
+                        "description": "Subscription details",
+                        "name": "subscription",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.SubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Subscription created",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or validation failed",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "403": {
+                        "description": "Metadata merchant_id is not the merchant of the API key",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Plan or customer not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/subscriptions/{id}": {
+            "get": {
+                "description": "Retrieves a subscription with its current period and billing state",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Get subscription by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription ID format",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/subscriptions/{id}/cancel": {
+            "post": {
+                "description": "Cancels a subscription right away or, with at_period_end, once the current period ends. A cycle payment already being processed is not recalled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "subscriptions"
+                ],
+                "summary": "Cancel subscription",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Subscription ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Cancellation options",
+                        "name": "cancel",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/domain.CancelSubscriptionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Subscription canceled or set to cancel",
+                        "schema": {
+                            "$ref": "#/definitions/domain.Subscription"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid subscription ID format or request body",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "404": {
+                        "description": "Subscription not found",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Subscription already canceled",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "domain.AccountBalance": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "balance": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.AccountType"
+                }
+            }
+        },
+        "domain.AccountType": {
+            "type": "string",
+            "enum": [
+                "asset",
+                "liability",
+                "revenue",
+                "expense"
+            ],
+            "x-enum-varnames": [
+                "AccountAsset",
+                "AccountLiability",
+                "AccountRevenue",
+                "AccountExpense"
+            ]
+        },
+        "domain.AttemptStatus": {
+            "type": "string",
+            "enum": [
+                "succeeded",
+                "declined",
+                "failed",
+                "unavailable"
+            ],
+            "x-enum-varnames": [
+                "AttemptSucceeded",
+                "AttemptDeclined",
+                "AttemptFailed",
+                "AttemptUnavailable"
+            ]
+        },
+        "domain.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "sealed_at": {
+                    "type": "string"
+                },
+                "seq": {
+                    "type": "integer"
+                },
+                "source_ip": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.AuditList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AuditEntry"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.BankTransferDetails": {
+            "type": "object",
+            "properties": {
+                "account_name": {
+                    "type": "string"
+                },
+                "account_number": {
+                    "type": "string"
+                },
+                "bank_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BillingInterval": {
+            "type": "string",
+            "enum": [
+                "day",
+                "week",
+                "month",
+                "year"
+            ],
+            "x-enum-varnames": [
+                "IntervalDay",
+                "IntervalWeek",
+                "IntervalMonth",
+                "IntervalYear"
+            ]
+        },
+        "domain.BlocklistEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/domain.BlocklistKind"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BlocklistEntryList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.BlocklistEntry"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.BlocklistEntryRequest": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "$ref": "#/definitions/domain.BlocklistKind"
+                },
+                "reason": {
+                    "description": "Reason records why the value was blocked.",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.BlocklistKind": {
+            "type": "string",
+            "enum": [
+                "ip",
+                "email",
+                "customer"
+            ],
+            "x-enum-varnames": [
+                "BlockIP",
+                "BlockEmail",
+                "BlockCustomer"
+            ]
+        },
+        "domain.CancelSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "at_period_end": {
+                    "description": "AtPeriodEnd keeps the subscription running until the paid period\nends instead of canceling it right away.",
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.CardDetails": {
+            "type": "object",
+            "properties": {
+                "brand": {
+                    "type": "string"
+                },
+                "exp_month": {
+                    "type": "integer"
+                },
+                "exp_year": {
+                    "type": "integer"
+                },
+                "last4": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CheckoutSession": {
+            "type": "object",
+            "properties": {
+                "cancel_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payment": {
+                    "$ref": "#/definitions/domain.Payment"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.CheckoutStatus"
+                },
+                "success_url": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL is the hosted checkout page to send the payer to.",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CheckoutSessionRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency",
+                "reference"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "cancel_url": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "enum": [
+                        "ETB",
+                        "USD"
+                    ]
+                },
+                "customer_id": {
+                    "description": "CustomerID links the payment to an existing customer.",
+                    "type": "string"
+                },
+                "expires_in": {
+                    "description": "ExpiresIn is the session lifetime in seconds, 30 minutes by default.",
+                    "type": "integer"
+                },
+                "metadata": {
+                    "description": "Metadata is stored with the payment and can be used to filter listings.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Metadata"
+                        }
+                    ]
+                },
+                "payer": {
+                    "description": "Payer creates (or, by external_id, reuses) a customer inline. Mutually exclusive with CustomerID.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CustomerRequest"
+                        }
+                    ]
+                },
+                "payment_method": {
+                    "description": "PaymentMethod says how the payer pays and carries the method's details.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.PaymentMethod"
+                        }
+                    ]
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "success_url": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CheckoutStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "confirmed",
+                "canceled",
+                "expired"
+            ],
+            "x-enum-varnames": [
+                "CheckoutOpen",
+                "CheckoutConfirmed",
+                "CheckoutCanceled",
+                "CheckoutExpired"
+            ]
+        },
+        "domain.CloseDisputeRequest": {
+            "type": "object",
+            "properties": {
+                "outcome": {
+                    "description": "Outcome is the provider's decision: won or lost.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.DisputeStatus"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.Customer": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "description": "ExternalID is unique per merchant.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "merchant_id": {
+                    "description": "MerchantID is the merchant whose API key created the customer; empty\nfor the payer of a payment made without one.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.CustomerList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Customer"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.CustomerRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Dispute": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "evidence_description": {
+                    "type": "string"
+                },
+                "evidence_due_by": {
+                    "description": "EvidenceDueBy is the deadline for submitting evidence.",
+                    "type": "string"
+                },
+                "evidence_submitted_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "provider_reference": {
+                    "description": "ProviderReference is the provider's identifier for the chargeback.",
+                    "type": "string"
+                },
+                "reason_code": {
+                    "description": "ReasonCode is the card network or provider reason, e.g. \"4853\".",
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.DisputeStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DisputeEvidence": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dispute_id": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.DisputeEvidenceList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DisputeEvidence"
+                    }
+                }
+            }
+        },
+        "domain.DisputeList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Dispute"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.DisputeRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount defaults to the full payment amount.",
+                    "type": "number"
+                },
+                "evidence_due_by": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "provider_reference": {
+                    "type": "string"
+                },
+                "reason_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.DisputeStatus": {
+            "type": "string",
+            "enum": [
+                "needs_response",
+                "under_review",
+                "won",
+                "lost"
+            ],
+            "x-enum-varnames": [
+                "DisputeNeedsResponse",
+                "DisputeUnderReview",
+                "DisputeWon",
+                "DisputeLost"
+            ]
+        },
+        "domain.EntryKind": {
+            "type": "string",
+            "enum": [
+                "payment_captured",
+                "refund",
+                "fee",
+                "payout"
+            ],
+            "x-enum-varnames": [
+                "EntryPaymentCaptured",
+                "EntryRefund",
+                "EntryFee",
+                "EntryPayout"
+            ]
+        },
+        "domain.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "internal.error",
+                "request.invalid",
+                "request.invalid_body",
+                "request.unauthorized",
+                "request.forbidden",
+                "request.route_not_found",
+                "request.method_not_allowed",
+                "request.too_large",
+                "request.unsupported_media_type",
+                "request.rate_limited",
+                "service.unavailable",
+                "validation.failed",
+                "payment.invalid_id",
+                "payment.not_found",
+                "payment.duplicate_reference",
+                "payment.already_processed",
+                "payment.not_pending",
+                "payment.not_confirmed",
+                "provider.unavailable",
+                "provider.circuit_open",
+                "customer.invalid_id",
+                "customer.not_found",
+                "customer.duplicate_external_id",
+                "customer.has_payments",
+                "checkout.invalid_id",
+                "checkout.not_found",
+                "checkout.expired",
+                "checkout.closed",
+                "payment_link.invalid_id",
+                "payment_link.not_found",
+                "payment_link.unavailable",
+                "plan.invalid_id",
+                "plan.not_found",
+                "subscription.invalid_id",
+                "subscription.not_found",
+                "subscription.canceled",
+                "fee_schedule.invalid_id",
+                "fee_schedule.not_found",
+                "settlement.invalid_id",
+                "settlement.not_found",
+                "settlement.not_payable",
+                "reconciliation.invalid_statement",
+                "reconciliation.invalid_id",
+                "reconciliation.not_found",
+                "reconciliation.invalid_exception_id",
+                "reconciliation.exception_not_found",
+                "reconciliation.exception_resolved",
+                "dispute.invalid_id",
+                "dispute.not_found",
+                "dispute.duplicate_provider_reference",
+                "dispute.payment_not_disputable",
+                "dispute.amount_exceeded",
+                "dispute.not_awaiting_response",
+                "dispute.deadline_passed",
+                "dispute.closed",
+                "dispute.invalid_evidence_id",
+                "dispute.evidence_not_found",
+                "risk.invalid_rule_id",
+                "risk.rule_not_found",
+                "risk.duplicate_rule_name",
+                "risk.invalid_blocklist_id",
+                "risk.blocklist_entry_not_found",
+                "risk.duplicate_blocklist_entry",
+                "review.invalid_id",
+                "review.not_found",
+                "review.already_claimed",
+                "review.not_claimed",
+                "review.decided",
+                "review.payment_not_held"
+            ],
+            "x-enum-varnames": [
+                "ErrInternal",
+                "ErrInvalidRequest",
+                "ErrInvalidRequestBody",
+                "ErrUnauthorized",
+                "ErrForbidden",
+                "ErrRouteNotFound",
+                "ErrMethodNotAllowed",
+                "ErrRequestTooLarge",
+                "ErrUnsupportedMediaType",
+                "ErrRateLimited",
+                "ErrServiceUnavailable",
+                "ErrValidationFailed",
+                "ErrInvalidPaymentID",
+                "ErrPaymentNotFound",
+                "ErrDuplicateReference",
+                "ErrPaymentAlreadyProcessed",
+                "ErrPaymentNotPending",
+                "ErrPaymentNotConfirmed",
+                "ErrNoProviderAvailable",
+                "ErrProviderCircuitOpen",
+                "ErrInvalidCustomerID",
+                "ErrCustomerNotFound",
+                "ErrDuplicateExternalID",
+                "ErrCustomerHasPayments",
+                "ErrInvalidCheckoutID",
+                "ErrCheckoutNotFound",
+                "ErrCheckoutExpired",
+                "ErrCheckoutClosed",
+                "ErrInvalidPaymentLinkID",
+                "ErrPaymentLinkNotFound",
+                "ErrPaymentLinkUnavailable",
+                "ErrInvalidPlanID",
+                "ErrPlanNotFound",
+                "ErrInvalidSubscriptionID",
+                "ErrSubscriptionNotFound",
+                "ErrSubscriptionCanceled",
+                "ErrInvalidFeeScheduleID",
+                "ErrFeeScheduleNotFound",
+                "ErrInvalidSettlementID",
+                "ErrSettlementNotFound",
+                "ErrSettlementNotPayable",
+                "ErrInvalidStatement",
+                "ErrInvalidReconciliationID",
+                "ErrReconciliationNotFound",
+                "ErrInvalidExceptionID",
+                "ErrExceptionNotFound",
+                "ErrExceptionResolved",
+                "ErrInvalidDisputeID",
+                "ErrDisputeNotFound",
+                "ErrDuplicateDispute",
+                "ErrPaymentNotDisputable",
+                "ErrDisputeAmountExceeded",
+                "ErrDisputeNotAwaiting",
+                "ErrDisputeDeadlinePassed",
+                "ErrDisputeClosed",
+                "ErrInvalidEvidenceID",
+                "ErrEvidenceNotFound",
+                "ErrInvalidRiskRuleID",
+                "ErrRiskRuleNotFound",
+                "ErrDuplicateRiskRule",
+                "ErrInvalidBlocklistID",
+                "ErrBlocklistEntryNotFound",
+                "ErrDuplicateBlocklistEntry",
+                "ErrInvalidReviewID",
+                "ErrReviewNotFound",
+                "ErrReviewAlreadyClaimed",
+                "ErrReviewNotClaimed",
+                "ErrReviewDecided",
+                "ErrPaymentNotInReview"
+            ]
+        },
+        "domain.FeeSchedule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "description": "EffectiveFrom and EffectiveTo bound when the schedule is in force.\nEffectiveTo is exclusive and nil while the schedule has no end.",
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "fixed_amount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_fee": {
+                    "type": "number"
+                },
+                "merchant_id": {
+                    "description": "MerchantID is matched against the payment's merchant_id metadata and is\nempty for a schedule covering every merchant.",
+                    "type": "string"
+                },
+                "min_fee": {
+                    "type": "number"
+                },
+                "payment_method": {
+                    "description": "PaymentMethod is empty for a schedule covering every method.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.PaymentMethodType"
+                        }
+                    ]
+                },
+                "percentage": {
+                    "description": "Percentage is the percent of the amount charged, e.g. 2.9 for 2.9%.",
+                    "type": "number"
+                }
+            }
+        },
+        "domain.FeeScheduleList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FeeSchedule"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.FeeScheduleRequest": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "description": "EffectiveFrom defaults to now.",
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "fixed_amount": {
+                    "type": "number"
+                },
+                "max_fee": {
+                    "type": "number"
+                },
+                "merchant_id": {
+                    "type": "string"
+                },
+                "min_fee": {
+                    "type": "number"
+                },
+                "payment_method": {
+                    "$ref": "#/definitions/domain.PaymentMethodType"
+                },
+                "percentage": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ForceFailRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "Reason says why the payment was failed by hand. It is kept in the\naudit log.",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.IntegrityReport": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "string"
+                },
+                "ok": {
+                    "type": "boolean"
+                },
+                "unbalanced": {
+                    "description": "Unbalanced lists entries whose postings do not sum to zero.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.UnbalancedEntry"
+                    }
+                },
+                "unrecorded_payments": {
+                    "description": "UnrecordedPayments lists successful payments without a capture entry.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "domain.JournalEntry": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "$ref": "#/definitions/domain.EntryKind"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "postings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Posting"
+                    }
+                }
+            }
+        },
+        "domain.LedgerBalances": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.AccountBalance"
+                    }
+                }
+            }
+        },
+        "domain.LedgerEntryList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.JournalEntry"
+                    }
+                }
+            }
+        },
+        "domain.Metadata": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "domain.MobileMoneyDetails": {
+            "type": "object",
+            "properties": {
+                "msisdn": {
+                    "description": "MSISDN is the payer's mobile number in E.164 format",
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.Payment": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency",
+                "reference"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "attempts": {
+                    "description": "Attempts lists every provider call, in order. Only filled in when a\nsingle payment is fetched.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PaymentAttempt"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string",
+                    "enum": [
+                        "ETB",
+                        "USD"
+                    ]
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "fee_amount": {
+                    "description": "FeeAmount is what the merchant is charged for the payment and NetAmount\nwhat they receive. Both are set once the payment succeeds.",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/domain.Metadata"
+                },
+                "net_amount": {
+                    "type": "number"
+                },
+                "payment_link_id": {
+                    "description": "PaymentLinkID is set on payments made through a payment link.",
+                    "type": "string"
+                },
+                "payment_method": {
+                    "description": "PaymentMethod is nil for payments created before methods were recorded.\nIt is written out masked; see MaskedPaymentMethod.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.PaymentMethod"
+                        }
+                    ]
+                },
+                "provider": {
+                    "description": "Provider is the provider that produced the final status.",
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.PaymentStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PaymentAttempt": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "provider_reference": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.AttemptStatus"
+                }
+            }
+        },
+        "domain.PaymentAttemptList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PaymentAttempt"
+                    }
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.PaymentStatus"
+                }
+            }
+        },
+        "domain.PaymentLink": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "amount": {
+                    "description": "Amount is nil when the payer enters the amount.",
+                    "type": "number"
+                },
+                "cancel_url": {
+                    "type": "string"
+                },
+                "collected": {
+                    "description": "Collected totals the successful payments made through the link. Only\nfilled in when a single link is fetched.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.PaymentLinkTotals"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "description": "Metadata is copied to every payment made through the link.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Metadata"
+                        }
+                    ]
+                },
+                "slug": {
+                    "type": "string"
+                },
+                "state": {
+                    "description": "State says whether the link can be used right now.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.PaymentLinkState"
+                        }
+                    ]
+                },
+                "success_url": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "URL is the page to share with payers.",
+                    "type": "string"
+                },
+                "uses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.PaymentLinkList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PaymentLink"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.PaymentLinkRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount fixes the amount to pay. Leave it out to let the payer enter it.",
+                    "type": "number"
+                },
+                "cancel_url": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "max_uses": {
+                    "type": "integer"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/domain.Metadata"
+                },
+                "success_url": {
+                    "description": "SuccessURL and CancelURL are where payers are sent after checkout. They\ndefault to the link page itself.",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PaymentLinkState": {
+            "type": "string",
+            "enum": [
+                "active",
+                "inactive",
+                "expired",
+                "exhausted"
+            ],
+            "x-enum-varnames": [
+                "LinkActive",
+                "LinkInactive",
+                "LinkExpired",
+                "LinkExhausted"
+            ]
+        },
+        "domain.PaymentLinkTotals": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.PaymentList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Payment"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.PaymentMethod": {
+            "type": "object",
+            "properties": {
+                "bank_transfer": {
+                    "$ref": "#/definitions/domain.BankTransferDetails"
+                },
+                "card": {
+                    "$ref": "#/definitions/domain.CardDetails"
+                },
+                "mobile_money": {
+                    "$ref": "#/definitions/domain.MobileMoneyDetails"
+                },
+                "type": {
+                    "$ref": "#/definitions/domain.PaymentMethodType"
+                },
+                "wallet": {
+                    "$ref": "#/definitions/domain.WalletDetails"
+                }
+            }
+        },
+        "domain.PaymentMethodType": {
+            "type": "string",
+            "enum": [
+                "card",
+                "bank_transfer",
+                "mobile_money",
+                "wallet"
+            ],
+            "x-enum-varnames": [
+                "MethodCard",
+                "MethodBankTransfer",
+                "MethodMobileMoney",
+                "MethodWallet"
+            ]
+        },
+        "domain.PaymentRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency",
+                "reference"
+            ],
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string",
+                    "enum": [
+                        "ETB",
+                        "USD"
+                    ]
+                },
+                "customer_id": {
+                    "description": "CustomerID links the payment to an existing customer.",
+                    "type": "string"
+                },
+                "metadata": {
+                    "description": "Metadata is stored with the payment and can be used to filter listings.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Metadata"
+                        }
+                    ]
+                },
+                "payer": {
+                    "description": "Payer creates (or, by external_id, reuses) a customer inline. Mutually exclusive with CustomerID.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.CustomerRequest"
+                        }
+                    ]
+                },
+                "payment_method": {
+                    "description": "PaymentMethod says how the payer pays and carries the method's details.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.PaymentMethod"
+                        }
+                    ]
+                },
+                "reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PaymentReview": {
+            "type": "object",
+            "properties": {
+                "claimed_at": {
+                    "type": "string"
+                },
+                "claimed_by": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "string"
+                },
+                "escalated_at": {
+                    "description": "EscalatedAt is set when the review was left open past the escalation\ndeadline.",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "risk": {
+                    "description": "Risk is how the payment was screened. Only filled in when a single\nreview is fetched.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.PaymentRisk"
+                        }
+                    ]
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.ReviewStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PaymentRisk": {
+            "type": "object",
+            "properties": {
+                "client_ip": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "risk": {
+                    "description": "Risk is nil for payments that were not screened.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.RiskAssessment"
+                        }
+                    ]
+                }
+            }
+        },
+        "domain.PaymentStatus": {
+            "type": "string",
+            "enum": [
+                "PENDING",
+                "SUCCESS",
+                "FAILED",
+                "IN_REVIEW",
+                "CANCELED"
+            ],
+            "x-enum-varnames": [
+                "StatusPending",
+                "StatusSuccess",
+                "StatusFailed",
+                "StatusInReview",
+                "StatusCanceled"
+            ]
+        },
+        "domain.Plan": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interval": {
+                    "$ref": "#/definitions/domain.BillingInterval"
+                },
+                "interval_count": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "trial_days": {
+                    "description": "TrialDays is how long new subscriptions run before their first charge.",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.PlanList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Plan"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.PlanRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "interval": {
+                    "$ref": "#/definitions/domain.BillingInterval"
+                },
+                "interval_count": {
+                    "description": "IntervalCount defaults to 1, e.g. 3 with a month interval bills quarterly.",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "trial_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.Posting": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "type": "string"
+                },
+                "amount": {
+                    "type": "number"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ProblemDetails": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/domain.ErrorCode"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ReconciliationItem": {
+            "type": "object",
+            "properties": {
+                "booked_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payment_amount": {
+                    "type": "number"
+                },
+                "payment_currency": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "description": "PaymentID is nil for lines without a payment.",
+                    "type": "string"
+                },
+                "reference": {
+                    "type": "string"
+                },
+                "resolution_note": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "result": {
+                    "$ref": "#/definitions/domain.ReconciliationResult"
+                },
+                "run_id": {
+                    "type": "string"
+                },
+                "statement_amount": {
+                    "type": "number"
+                },
+                "statement_currency": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ReconciliationItemList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ReconciliationItem"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ReconciliationResult": {
+            "type": "string",
+            "enum": [
+                "matched",
+                "missing_internally",
+                "missing_at_provider",
+                "amount_mismatch"
+            ],
+            "x-enum-varnames": [
+                "ResultMatched",
+                "ResultMissingInternally",
+                "ResultMissingAtProvider",
+                "ResultAmountMismatch"
+            ]
+        },
+        "domain.ReconciliationRun": {
+            "type": "object",
+            "properties": {
+                "amount_mismatch_count": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "$ref": "#/definitions/domain.StatementFormat"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "line_count": {
+                    "type": "integer"
+                },
+                "matched_count": {
+                    "type": "integer"
+                },
+                "missing_at_provider_count": {
+                    "type": "integer"
+                },
+                "missing_internally_count": {
+                    "type": "integer"
+                },
+                "period_end": {
+                    "type": "string"
+                },
+                "period_start": {
+                    "type": "string"
+                },
+                "provider": {
+                    "description": "Provider limits the payments expected on the statement; empty means\nevery provider.",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ReconciliationRunList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ReconciliationRun"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RedriveRequest": {
+            "type": "object",
+            "properties": {
+                "created_from": {
+                    "description": "CreatedFrom and CreatedTo bound when the payments were created; the\nrange includes CreatedFrom and excludes CreatedTo.",
+                    "type": "string"
+                },
+                "created_to": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "description": "DryRun lists the matching payments without queuing them.",
+                    "type": "boolean"
+                },
+                "limit": {
+                    "description": "Limit defaults to DefaultRedriveLimit and is at most MaxRedriveLimit.",
+                    "type": "integer"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/domain.Metadata"
+                }
+            }
+        },
+        "domain.RedriveResult": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "payment_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "requeued": {
+                    "type": "integer"
+                },
+                "requeued_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stopped_at": {
+                    "description": "StoppedAt and Error are set when the re-drive stopped at a payment it\ncould not queue. The payments in RequeuedIDs were queued before it.",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ResolveExceptionRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ReviewDecisionRequest": {
+            "type": "object",
+            "properties": {
+                "notes": {
+                    "description": "Notes explain the decision. They are required to decline.",
+                    "type": "string"
+                }
+            }
+        },
+        "domain.ReviewList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.PaymentReview"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.ReviewStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "claimed",
+                "approved",
+                "declined"
+            ],
+            "x-enum-varnames": [
+                "ReviewOpen",
+                "ReviewClaimed",
+                "ReviewApproved",
+                "ReviewDeclined"
+            ]
+        },
+        "domain.RiskAssessment": {
+            "type": "object",
+            "properties": {
+                "outcome": {
+                    "$ref": "#/definitions/domain.RiskOutcome"
+                },
+                "rules": {
+                    "description": "Rules names the rules that matched, in evaluation order.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "score": {
+                    "description": "Score is the sum of the scores of the matched rules, at most 100.",
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RiskOutcome": {
+            "type": "string",
+            "enum": [
+                "allow",
+                "review",
+                "block"
+            ],
+            "x-enum-varnames": [
+                "RiskAllow",
+                "RiskReview",
+                "RiskBlock"
+            ]
+        },
+        "domain.RiskRule": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "Action is the outcome of a match: review or block.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.RiskOutcome"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "expression": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "score": {
+                    "description": "Score is added to the payment's risk score on a match.",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.RiskRuleList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RiskRule"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.RiskRuleRequest": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/domain.RiskOutcome"
+                },
+                "enabled": {
+                    "description": "Enabled defaults to true.",
+                    "type": "boolean"
+                },
+                "expression": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "score": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SettlementAdjustment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "batch_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payment_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SettlementAdjustmentList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SettlementAdjustment"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SettlementBatch": {
+            "type": "object",
+            "properties": {
+                "adjustment_amount": {
+                    "description": "AdjustmentAmount totals the batch's adjustments and is never positive.",
+                    "type": "number"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "cutoff_at": {
+                    "type": "string"
+                },
+                "fee_amount": {
+                    "type": "number"
+                },
+                "gross_amount": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "merchant_id": {
+                    "description": "MerchantID is the payments' merchant_id metadata, empty for payments\nwithout one.",
+                    "type": "string"
+                },
+                "net_amount": {
+                    "description": "NetAmount is what is paid out: gross less fees and refunds, plus\nadjustments.",
+                    "type": "number"
+                },
+                "paid_at": {
+                    "type": "string"
+                },
+                "payment_count": {
+                    "type": "integer"
+                },
+                "refunded_amount": {
+                    "type": "number"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.SettlementStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SettlementBatchList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SettlementBatch"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SettlementItem": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "batch_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "fee_amount": {
+                    "type": "number"
+                },
+                "net_amount": {
+                    "type": "number"
+                },
+                "payment_id": {
+                    "type": "string"
+                },
+                "refunded_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.SettlementItemList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.SettlementItem"
+                    }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SettlementStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "closed",
+                "paid"
+            ],
+            "x-enum-varnames": [
+                "SettlementOpen",
+                "SettlementClosed",
+                "SettlementPaid"
+            ]
+        },
+        "domain.StatementFormat": {
+            "type": "string",
+            "enum": [
+                "csv",
+                "camt053"
+            ],
+            "x-enum-varnames": [
+                "FormatCSV",
+                "FormatCamt053"
+            ]
+        },
+        "domain.Subscription": {
+            "type": "object",
+            "properties": {
+                "billing_anchor": {
+                    "description": "BillingAnchor is the date cycles are counted from.",
+                    "type": "string"
+                },
+                "cancel_at_period_end": {
+                    "type": "boolean"
+                },
+                "canceled_at": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current_period_end": {
+                    "type": "string"
+                },
+                "current_period_start": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "failed_attempts": {
+                    "description": "FailedAttempts counts the failed charges for the current cycle.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "$ref": "#/definitions/domain.Metadata"
+                },
+                "next_billing_at": {
+                    "description": "NextBillingAt is when the next charge or dunning retry is due. It is\nnil once the subscription is canceled.",
+                    "type": "string"
+                },
+                "payment_method": {
+                    "$ref": "#/definitions/domain.PaymentMethod"
+                },
+                "pending_payment_id": {
+                    "description": "PendingPaymentID is the cycle payment the worker has not settled yet.",
+                    "type": "string"
+                },
+                "plan_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/domain.SubscriptionStatus"
+                },
+                "trial_end": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SubscriptionList": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.Subscription"
                     }
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "domain.SubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "billing_anchor": {
+                    "description": "BillingAnchor sets the date of the first charge, which must not be\nbefore the trial ends. It defaults to the end of the trial, or now.",
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "metadata": {
+                    "description": "Metadata is copied to every cycle payment.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.Metadata"
+                        }
+                    ]
+                },
+                "payment_method": {
+                    "description": "PaymentMethod is charged for every cycle.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/domain.PaymentMethod"
+                        }
+                    ]
+                },
+                "plan_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "domain.SubscriptionStatus": {
+            "type": "string",
+            "enum": [
+                "trialing",
+                "active",
+                "past_due",
+                "canceled"
+            ],
+            "x-enum-varnames": [
+                "SubscriptionTrialing",
+                "SubscriptionActive",
+                "SubscriptionPastDue",
+                "SubscriptionCanceled"
+            ]
+        },
+        "domain.UnbalancedEntry": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string"
+                },
+                "journal_entry_id": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "domain.WalletDetails": {
+            "type": "object",
+            "properties": {
+                "provider": {
+                    "type": "string"
+                },
+                "wallet_id": {
+                    "type": "string"
                 }
             }
         }
@@ -223,6 +6903,11 @@ const docTemplate = `{
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
+        },
+        "MerchantKey": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
         }
     }
 }`
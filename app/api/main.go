@@ -2,26 +2,40 @@ package main
 
 import (
 	"context"
-	"database/sql"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"pgm/internal/blob"
 	"pgm/internal/domain"
+	adm "pgm/internal/handler/admin"
+	adt "pgm/internal/handler/audit"
+	chk "pgm/internal/handler/checkout"
+	cst "pgm/internal/handler/customer"
+	dsp "pgm/internal/handler/dispute"
+	fee "pgm/internal/handler/fee"
+	ldg "pgm/internal/handler/ledger"
+	mw "pgm/internal/handler/middleware"
 	pmt "pgm/internal/handler/payment"
+	pl "pgm/internal/handler/paymentlink"
+	rcn "pgm/internal/handler/reconciliation"
+	rvw "pgm/internal/handler/review"
+	rsk "pgm/internal/handler/risk"
+	stl "pgm/internal/handler/settlement"
+	sub "pgm/internal/handler/subscription"
+	"pgm/internal/health"
+	"pgm/internal/logger"
 	q "pgm/internal/queue"
+	"pgm/internal/ratelimit"
 	"pgm/internal/repo"
 	"pgm/internal/repo/db"
 	"pgm/internal/service"
+	"pgm/internal/statement"
 	"time"
 
-	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-
 	_ "pgm/app/api/docs" // docs is generated by Swag CLI, you have to import it.
 
-	_ "github.com/golang-migrate/migrate/v4/source/file"
-	_ "github.com/jackc/pgx/v5/stdlib"
 	echoSwagger "github.com/swaggo/echo-swagger"
 
 	"github.com/labstack/echo/v4"
@@ -46,24 +60,44 @@ import (
 // @securityDefinitions.apikey ApiKeyAuth
 // @in header
 // @name Authorization
+
+// @securityDefinitions.apikey MerchantKey
+// @in header
+// @name X-API-Key
 func main() {
+	skipMigrations := flag.Bool("skip-migrations", os.Getenv("SKIP_MIGRATIONS") == "true", "do not apply database migrations at startup")
+	flag.Parse()
+
+	// Logger
+	level, err := logger.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		fatal("invalid log level", err)
+	}
+	slog.SetDefault(logger.New(os.Stdout, level))
+
 	// Database
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"))
+	dsn := repo.DSNFromEnv()
 	pool, err := repo.NewPool(context.Background(), dsn)
 	if err != nil {
-		log.Fatalf("failed to connect to database: %v", err)
+		fatal("failed to connect to database", err)
 	}
 	defer pool.Close()
 
-	// Run migrations
-	if err := runMigrations("file:///app/internal/repo/schema", os.Getenv("DB_NAME"), dsn); err != nil {
-		log.Fatalf("failed to run migrations: %v", err)
+	// Run migrations unless a separate migrate job owns them
+	if *skipMigrations {
+		slog.Info("skipping migrations")
+	} else if err := repo.Migrate(dsn); err != nil {
+		fatal("failed to run migrations", err)
+	}
+	schemaVersion, err := repo.SchemaVersion()
+	if err != nil {
+		fatal("failed to read schema version", err)
 	}
 
 	// RabbitMQ Publisher
 	publisher, err := q.NewRabbitMQPublisher()
 	if err != nil {
-		log.Fatalf("failed to connect to rabbitmq: %v", err)
+		fatal("failed to connect to rabbitmq", err)
 	}
 	// Note: In a real app, we'd handle closing the publisher gracefully
 
@@ -71,12 +105,49 @@ func main() {
 	queries := db.New(pool)
 
 	// service
-	uc := service.NewPaymentService(queries, pool, publisher)
+	uow := repo.NewUnitOfWork(pool)
+	// The API never processes payments, so it needs no provider router
+	uc := service.NewPaymentService(uow, publisher, nil)
+	cs := service.NewCustomerService(uow)
+	// Checkout and payment link URLs handed to payers are built from the public base URL
+	baseURL := os.Getenv("PUBLIC_BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
+	chs := service.NewCheckoutService(uow, publisher, baseURL)
+	pls := service.NewPaymentLinkService(uow, baseURL)
+	ps := service.NewPlanService(uow)
+	ss := service.NewSubscriptionService(uow)
+	ls := service.NewLedgerService(uow)
+	fs := service.NewFeeService(uow)
+	sts := service.NewSettlementService(uow)
+	rs := service.NewReconciliationService(uow, statement.Parsers())
+	// Dispute evidence files are kept on disk under BLOB_DIR
+	blobDir := os.Getenv("BLOB_DIR")
+	if blobDir == "" {
+		blobDir = "data/blobs"
+	}
+	blobs, err := blob.NewLocal(blobDir)
+	if err != nil {
+		fatal("failed to open blob store", err)
+	}
+	ds := service.NewDisputeService(uow, blobs, publisher)
+	rks := service.NewRiskService(uow)
+	rvs := service.NewReviewService(uow, publisher)
+	as := service.NewAuditService(uow)
+	ads := service.NewAdminService(uow, publisher)
 
 	// Echo
 	e := echo.New()
 
+	e.HideBanner = true
+	// Trust X-Forwarded-For only from proxies on loopback and private
+	// networks, so a client cannot pick its own IP
+	e.IPExtractor = echo.ExtractIPFromXFFHeader()
+
 	// Middleware
+	e.Use(mw.RequestID())
+	e.Use(mw.Actor())
 	e.Use(middleware.RequestLogger())
 	e.Use(middleware.Recover())
 	e.Use(middleware.TimeoutWithConfig(middleware.TimeoutConfig{
@@ -89,6 +160,40 @@ func main() {
 	// API v1 group
 	g := e.Group("/v1")
 
+	// Merchant API keys
+	merchants, err := mw.ParseMerchantKeys(os.Getenv("MERCHANT_API_KEYS"))
+	if err != nil {
+		fatal("invalid MERCHANT_API_KEYS", err)
+	}
+	g.Use(mw.MerchantAuth(merchants))
+
+	// Rate limiting
+	limiter, defaultLimit, routeLimits, err := newRateLimiter(queries)
+	if err != nil {
+		fatal("invalid rate limit configuration", err)
+	}
+	g.Use(mw.RateLimit(limiter, defaultLimit, routeLimits))
+
+	// Operator endpoints sit outside /v1, behind per-operator tokens. Without
+	// any token every request to them is rejected.
+	operators, err := mw.ParseOperatorTokens(os.Getenv("ADMIN_API_TOKENS"))
+	if err != nil {
+		fatal("invalid ADMIN_API_TOKENS", err)
+	}
+	if len(operators) == 0 {
+		slog.Warn("ADMIN_API_TOKENS is not set, admin API disabled")
+	}
+	admin := e.Group("/admin", mw.AdminAuth(operators))
+
+	// Health probes
+	hc := health.NewHandler(2 * time.Second)
+	hc.Add("postgres", health.CheckerFunc(pool.Ping))
+	hc.Add("rabbitmq", publisher)
+	hc.Add("migrations", health.CheckerFunc(func(ctx context.Context) error {
+		return repo.CheckSchemaVersion(ctx, pool, schemaVersion)
+	}))
+	health.Register(e, hc)
+
 	// Swagger documentation
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 	srv := &http.Server{
@@ -100,46 +205,56 @@ func main() {
 
 	// Handlers
 	pmt.NewPaymentHandler(g, uc)
+	cst.NewCustomerHandler(g, cs)
+	pages := e.Group("")
+	chk.NewCheckoutHandler(g, pages, chs)
+	pl.NewPaymentLinkHandler(g, pages, pls)
+	sub.NewPlanHandler(g, ps)
+	sub.NewSubscriptionHandler(g, ss)
+	stl.NewSettlementHandler(g, admin, sts)
+	dsp.NewDisputeHandler(g, admin, ds)
 
-	// Start server
-	e.StartServer(srv)
-}
-
-// RunMigrations automatically applies migrations on startup.
-func runMigrations(filePath, dbname string, dsn string) error {
-	log.Println("Running migrations...")
-	db, err := sql.Open("pgx", dsn)
-	if err != nil {
-		log.Fatalf("Failed to open temp DB for migrations: %v", err)
-	}
-	defer db.Close()
+	// Operator endpoints
+	adm.NewAdminHandler(admin, ads)
+	ldg.NewLedgerHandler(admin, ls)
+	fee.NewFeeHandler(admin, fs)
+	rcn.NewReconciliationHandler(admin, rs)
+	rsk.NewRiskHandler(admin, rks)
+	rvw.NewReviewHandler(admin, rvs)
+	adt.NewAuditHandler(admin, as)
 
-	if err := db.Ping(); err != nil {
-		log.Fatalf("Failed to ping temp DB for migrations: %v", err)
+	// Start server
+	if err := e.StartServer(srv); err != nil && err != http.ErrServerClosed {
+		fatal("server stopped", err)
 	}
+}
 
-	// 2. Create a new "postgres" driver instance for migrate
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+// newRateLimiter builds the limiter selected by RATE_LIMIT_BACKEND together
+// with the default and per-route limits.
+func newRateLimiter(queries db.Querier) (ratelimit.Limiter, ratelimit.Limit, map[string]ratelimit.Limit, error) {
+	def, err := ratelimit.ParseLimit(os.Getenv("RATE_LIMIT_DEFAULT"))
 	if err != nil {
-		log.Fatalf("Failed to create migrate driver instance: %v", err)
+		return nil, ratelimit.Limit{}, nil, err
 	}
-
-	// 3. Create the migrate instance
-	// Point to your migrations directory
-	m, err := migrate.NewWithDatabaseInstance(
-		filePath, // Source URL
-		dbname,   // Database name
-		driver,   // The driver instance
-	)
+	routes, err := ratelimit.ParseRules(os.Getenv("RATE_LIMIT_ROUTES"))
 	if err != nil {
-		log.Fatalf("Failed to create migrate instance: %v", err)
+		return nil, ratelimit.Limit{}, nil, err
 	}
 
-	// 4. Run the migrations
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		log.Fatalf("An error occurred while running migrations: %v", err)
+	switch backend := os.Getenv("RATE_LIMIT_BACKEND"); backend {
+	case "", "memory":
+		return ratelimit.NewMemoryLimiter(), def, routes, nil
+	case "postgres":
+		pl := ratelimit.NewPostgresLimiter(queries)
+		go pl.RunCleanup(context.Background(), 10*time.Minute, time.Hour)
+		return pl, def, routes, nil
+	default:
+		return nil, ratelimit.Limit{}, nil, fmt.Errorf("invalid RATE_LIMIT_BACKEND: %s. Must be 'memory' or 'postgres'", backend)
 	}
+}
 
-	log.Println("Migrations applied successfully!")
-	return nil
+// fatal logs err and exits the process.
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
 }
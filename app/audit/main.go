@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"pgm/internal/logger"
+	"pgm/internal/repo"
+	"pgm/internal/service"
+)
+
+const usage = `Usage: audit <command>
+
+Commands:
+  verify      check the audit log hash chain; exits 1 if it is broken
+  seal        chain every committed entry now instead of waiting for the worker
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	level, err := logger.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		fatal("invalid log level", err)
+	}
+	slog.SetDefault(logger.New(os.Stdout, level))
+
+	ctx := context.Background()
+	pool, err := repo.NewPool(ctx, repo.DSNFromEnv())
+	if err != nil {
+		fatal("failed to connect to database", err)
+	}
+	defer pool.Close()
+	uow := repo.NewUnitOfWork(pool)
+
+	switch flag.Arg(0) {
+	case "verify":
+		v, err := service.NewAuditService(uow).Verify(ctx)
+		if err != nil {
+			fatal("audit verify failed", err)
+		}
+		if !v.OK {
+			// Verify has logged where the chain breaks
+			pool.Close()
+			os.Exit(1)
+		}
+		slog.Info("audit log intact",
+			slog.Int64("checked", v.Checked),
+			slog.Int64("unsealed", v.Unsealed),
+			slog.Int64("head_seq", v.HeadSeq),
+			slog.String("head_hash", v.HeadHash),
+		)
+	case "seal":
+		if err := service.NewAuditSealJob(uow, service.DefaultAuditConfig).RunOnce(ctx); err != nil {
+			fatal("audit seal failed", err)
+		}
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// fatal logs err and exits the process.
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"pgm/internal/logger"
+	"pgm/internal/repo"
+	"strconv"
+)
+
+const usage = `Usage: migrate <command> [arg]
+
+Commands:
+  up          apply all pending migrations
+  down N      roll back the last N migrations
+  goto V      migrate up or down to version V
+  version     print the current version
+  force V     set the version to V without running migrations (clears dirty state)
+`
+
+func main() {
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	level, err := logger.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		fatal("invalid log level", err)
+	}
+	slog.SetDefault(logger.New(os.Stdout, level))
+
+	m, err := repo.NewMigrator(repo.DSNFromEnv())
+	if err != nil {
+		fatal("failed to initialise migrator", err)
+	}
+	defer m.Close()
+
+	cmd := flag.Arg(0)
+	switch cmd {
+	case "up":
+		err = m.Up()
+	case "down":
+		var n int
+		if n, err = intArg(); err == nil {
+			err = m.Down(n)
+		}
+	case "goto":
+		var v int
+		if v, err = intArg(); err == nil {
+			err = m.Goto(uint(v))
+		}
+	case "force":
+		var v int
+		if v, err = intArg(); err == nil {
+			err = m.Force(v)
+		}
+	case "version":
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fatal("migrate "+cmd+" failed", err)
+	}
+
+	version, dirty, err := m.Version()
+	if err != nil {
+		fatal("failed to read version", err)
+	}
+	slog.Info("migration state", slog.String("command", cmd), slog.Uint64("version", uint64(version)), slog.Bool("dirty", dirty))
+}
+
+// intArg parses the command's numeric argument.
+func intArg() (int, error) {
+	if flag.NArg() < 2 {
+		return 0, fmt.Errorf("%s requires a numeric argument", flag.Arg(0))
+	}
+	n, err := strconv.Atoi(flag.Arg(1))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid argument %q", flag.Arg(1))
+	}
+	return n, nil
+}
+
+// fatal logs err and exits the process.
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
+}
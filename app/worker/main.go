@@ -3,42 +3,103 @@ package main
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
+	"pgm/internal/domain"
+	"pgm/internal/health"
+	"pgm/internal/logger"
+	"pgm/internal/provider"
 	rabbitmq "pgm/internal/queue"
 	"pgm/internal/repo"
-	"pgm/internal/repo/db"
 	service "pgm/internal/service"
+	"strconv"
+	"strings"
 	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/labstack/echo/v4"
 )
 
 func main() {
+	// Logger
+	level, err := logger.ParseLevel(os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		fatal("invalid log level", err)
+	}
+	slog.SetDefault(logger.New(os.Stdout, level))
+
 	// Database
-	dsn := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable", os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"), os.Getenv("DB_HOST"), os.Getenv("DB_PORT"), os.Getenv("DB_NAME"))
-	pool, err := repo.NewPool(context.Background(), dsn)
+	pool, err := repo.NewPool(context.Background(), repo.DSNFromEnv())
 	if err != nil {
-		log.Fatalf("failed to connect to database: %+v", err)
+		fatal("failed to connect to database", err)
 	}
 	defer pool.Close()
 
-	// Repository
-	queries := db.New(pool)
+	// Refuse to consume against a schema this build does not expect
+	schemaVersion, err := repo.SchemaVersion()
+	if err != nil {
+		fatal("failed to read schema version", err)
+	}
+	if err := waitForSchema(context.Background(), pool, schemaVersion); err != nil {
+		fatal("database schema is not at the expected version", err)
+	}
+
+	// Provider routing
+	router, providers, err := newRouter()
+	if err != nil {
+		fatal("invalid provider configuration", err)
+	}
+
+	// Subscription billing queues the cycle payments it creates, and the
+	// review SLA job publishes escalations
+	schedulerCfg, err := service.SchedulerConfigFromEnv()
+	if err != nil {
+		fatal("invalid subscription scheduler configuration", err)
+	}
+	settlementCfg, err := service.SettlementConfigFromEnv()
+	if err != nil {
+		fatal("invalid settlement configuration", err)
+	}
+	reviewCfg, err := service.ReviewConfigFromEnv()
+	if err != nil {
+		fatal("invalid review SLA configuration", err)
+	}
+	auditCfg, err := service.AuditConfigFromEnv()
+	if err != nil {
+		fatal("invalid audit configuration", err)
+	}
+	checkoutCfg, err := service.CheckoutExpiryConfigFromEnv()
+	if err != nil {
+		fatal("invalid checkout expiry configuration", err)
+	}
+	publisher, err := rabbitmq.NewRabbitMQPublisher()
+	if err != nil {
+		fatal("failed to connect to rabbitmq", err)
+	}
+	defer publisher.Close()
 
 	// UseCase
-	// Worker doesn't need to publish messages, so we can pass nil for publisher
-	// or a mock if needed. In our case, Process doesn't use publisher.
-	uc := service.NewPaymentService(queries, pool, nil)
+	// Process doesn't use the publisher, so the payment service gets nil.
+	uow := repo.NewUnitOfWork(pool)
+	uc := service.NewPaymentService(uow, nil, router)
+	scheduler := service.NewSubscriptionScheduler(uow, publisher, schedulerCfg)
+	settlements := service.NewSettlementJob(uow, settlementCfg)
+	reviews := service.NewReviewSLAJob(uow, publisher, reviewCfg)
+	audit := service.NewAuditSealJob(uow, auditCfg)
+	checkouts := service.NewCheckoutExpiryJob(uow, checkoutCfg)
 
 	// RabbitMQ Consumer
 	consumer, err := rabbitmq.NewRabbitMQConsumer(uc)
 	if err != nil {
-		log.Fatalf("failed to connect to rabbitmq: %+v", err)
+		fatal("failed to connect to rabbitmq", err)
 	}
 	defer consumer.Close()
 
-	// Context for graceful shutdown
-	ctx, cancel := context.WithCancel(context.Background())
+	// Context for graceful shutdown. Changes made from it are audited as the worker's
+	ctx, cancel := context.WithCancel(domain.WithActor(context.Background(), domain.Actor{Name: domain.ActorWorker}))
 	defer cancel()
 
 	// Handle termination signals
@@ -47,12 +108,152 @@ func main() {
 
 	go func() {
 		<-sigChan
-		log.Println("Shutting down worker...")
+		slog.Info("shutting down worker")
 		cancel()
 	}()
 
+	// Admin server exposing health probes
+	hc := health.NewHandler(2 * time.Second)
+	hc.Add("postgres", health.CheckerFunc(pool.Ping))
+	hc.Add("rabbitmq", consumer)
+	hc.Add("migrations", health.CheckerFunc(func(ctx context.Context) error {
+		return repo.CheckSchemaVersion(ctx, pool, schemaVersion)
+	}))
+	// An open breaker degrades the worker but it can still serve other providers
+	for _, p := range providers {
+		hc.AddOptional("provider:"+p.Name(), p.Breaker())
+	}
+	admin := newAdminServer(hc, providers)
+	go func() {
+		if err := admin.Start(adminAddr()); err != nil && err != http.ErrServerClosed {
+			slog.Error("admin server stopped", slog.Any("error", err))
+		}
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = admin.Shutdown(shutdownCtx)
+	}()
+
+	go scheduler.Run(ctx)
+	go settlements.Run(ctx)
+	go reviews.Run(ctx)
+	go audit.Run(ctx)
+	go checkouts.Run(ctx)
+
 	// Start consumer
 	if err := consumer.Start(ctx); err != nil {
-		log.Fatalf("failed to start consumer: %+v", err)
+		fatal("failed to start consumer", err)
+	}
+}
+
+// waitForSchema polls until the database is migrated to the expected version,
+// giving a separate migrate job time to finish. SCHEMA_WAIT_TIMEOUT bounds the wait.
+func waitForSchema(ctx context.Context, pool *pgxpool.Pool, expected uint) error {
+	timeout := time.Minute
+	if v := os.Getenv("SCHEMA_WAIT_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid SCHEMA_WAIT_TIMEOUT value: %v", err)
+		}
+		timeout = d
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+	for {
+		err := repo.CheckSchemaVersion(ctx, pool, expected)
+		if err == nil {
+			return nil
+		}
+		slog.Info("waiting for database schema", slog.Uint64("expected_version", uint64(expected)), slog.Any("error", err))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-ticker.C:
+		}
+	}
+}
+
+// newAdminServer builds the worker's small HTTP surface for operational endpoints.
+func newAdminServer(hc *health.Handler, providers []*provider.Guarded) *echo.Echo {
+	e := echo.New()
+	e.HideBanner = true
+	e.HidePort = true
+	health.Register(e, hc)
+	e.GET("/metrics", func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "text/plain; version=0.0.4")
+		return provider.WriteMetrics(c.Response(), providers)
+	})
+	return e
+}
+
+// adminAddr returns the listen address of the admin server.
+func adminAddr() string {
+	port := os.Getenv("WORKER_ADMIN_PORT")
+	if port == "" {
+		port = "8081"
+	}
+	return ":" + port
+}
+
+// newRouter registers a simulated provider for each name in PROVIDERS and
+// routes between them with PROVIDER_ROUTES. PROVIDER_UNAVAILABLE_RATE sets the
+// share of calls the simulated providers fail as unavailable. Every provider
+// is guarded by its own circuit breaker and a PROVIDER_TIMEOUT per call.
+func newRouter() (*provider.RuleRouter, []*provider.Guarded, error) {
+	names := os.Getenv("PROVIDERS")
+	if names == "" {
+		names = "primary,secondary"
+	}
+	unavailableRate := 0.1
+	if v := os.Getenv("PROVIDER_UNAVAILABLE_RATE"); v != "" {
+		rate, err := strconv.ParseFloat(v, 32)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, nil, fmt.Errorf("invalid PROVIDER_UNAVAILABLE_RATE: %s", v)
+		}
+		unavailableRate = rate
+	}
+	timeout := 10 * time.Second
+	if v := os.Getenv("PROVIDER_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return nil, nil, fmt.Errorf("invalid PROVIDER_TIMEOUT: %s", v)
+		}
+		timeout = d
+	}
+	breakerCfg, err := provider.BreakerConfigFromEnv()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		providers []domain.Provider
+		guarded   []*provider.Guarded
+	)
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			g := provider.NewGuarded(provider.NewSimulated(name, float32(unavailableRate)), breakerCfg, timeout)
+			providers = append(providers, g)
+			guarded = append(guarded, g)
+		}
 	}
+
+	rules, err := provider.ParseRules(os.Getenv("PROVIDER_ROUTES"))
+	if err != nil {
+		return nil, nil, err
+	}
+	router, err := provider.NewRuleRouter(providers, rules)
+	if err != nil {
+		return nil, nil, err
+	}
+	return router, guarded, nil
+}
+
+// fatal logs err and exits the process.
+func fatal(msg string, err error) {
+	slog.Error(msg, slog.Any("error", err))
+	os.Exit(1)
 }
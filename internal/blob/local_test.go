@@ -0,0 +1,48 @@
+package blob_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"pgm/internal/blob"
+	"pgm/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	store, err := blob.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.NoError(t, store.Put(ctx, "disputes/d1/e1", strings.NewReader("receipt")))
+	r, err := store.Open(ctx, "disputes/d1/e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := io.ReadAll(r)
+	r.Close()
+	assert.NoError(t, err)
+	assert.Equal(t, "receipt", string(content))
+
+	assert.NoError(t, store.Delete(ctx, "disputes/d1/e1"))
+	_, err = store.Open(ctx, "disputes/d1/e1")
+	assert.True(t, errors.Is(err, domain.ErrNotFound))
+	// Deleting a missing blob is not an error
+	assert.NoError(t, store.Delete(ctx, "disputes/d1/e1"))
+}
+
+func TestLocalRejectsEscapingKeys(t *testing.T) {
+	store, err := blob.NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"", "../outside", "/etc/passwd", "a/../../outside"} {
+		assert.Error(t, store.Put(context.Background(), key, strings.NewReader("x")), key)
+	}
+}
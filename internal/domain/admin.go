@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// DefaultRedriveLimit is how many payments a re-drive queues when the
+	// request sets no limit.
+	DefaultRedriveLimit = 100
+	// MaxRedriveLimit caps how many payments one re-drive queues.
+	MaxRedriveLimit = 1000
+)
+
+type ForceFailRequest struct {
+	// Reason says why the payment was failed by hand. It is kept in the
+	// audit log.
+	Reason string `json:"reason"`
+}
+
+func (fr ForceFailRequest) Validate() error {
+	return validation.ValidateStruct(&fr,
+		validation.Field(&fr.Reason, validation.Required.Error("reason is required"), validation.Length(1, 2000)))
+}
+
+// PendingPaymentFilter narrows the pending payments a re-drive queues. Empty
+// fields match every pending payment.
+type PendingPaymentFilter struct {
+	Currency    string
+	Metadata    Metadata
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+}
+
+// RedriveRequest selects pending payments to queue for processing again,
+// oldest first.
+type RedriveRequest struct {
+	Currency string   `json:"currency,omitempty"`
+	Metadata Metadata `json:"metadata,omitempty"`
+	// CreatedFrom and CreatedTo bound when the payments were created; the
+	// range includes CreatedFrom and excludes CreatedTo.
+	CreatedFrom *time.Time `json:"created_from,omitempty"`
+	CreatedTo   *time.Time `json:"created_to,omitempty"`
+	// Limit defaults to DefaultRedriveLimit and is at most MaxRedriveLimit.
+	Limit int `json:"limit,omitempty"`
+	// DryRun lists the matching payments without queuing them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+func (rr RedriveRequest) Validate() error {
+	return validation.ValidateStruct(&rr,
+		validation.Field(&rr.Currency, validation.In("ETB", "USD")),
+		validation.Field(&rr.Metadata),
+		validation.Field(&rr.CreatedTo, validation.By(func(interface{}) error {
+			if rr.CreatedFrom != nil && rr.CreatedTo != nil && !rr.CreatedTo.After(*rr.CreatedFrom) {
+				return errors.New("created_to must be after created_from")
+			}
+			return nil
+		})),
+		validation.Field(&rr.Limit, validation.Min(0), validation.Max(MaxRedriveLimit)))
+}
+
+// Filter returns the payments the request selects.
+func (rr RedriveRequest) Filter() PendingPaymentFilter {
+	return PendingPaymentFilter{
+		Currency:    rr.Currency,
+		Metadata:    rr.Metadata,
+		CreatedFrom: rr.CreatedFrom,
+		CreatedTo:   rr.CreatedTo,
+	}
+}
+
+// PageSize returns the requested limit or DefaultRedriveLimit.
+func (rr RedriveRequest) PageSize() int {
+	if rr.Limit == 0 {
+		return DefaultRedriveLimit
+	}
+	return rr.Limit
+}
+
+// RedriveResult lists the payments a re-drive matched. Requeued counts those
+// queued, which leaves out payments processed since they were matched and
+// every payment of a dry run. Checkout payments the payer has not confirmed
+// are never matched.
+type RedriveResult struct {
+	Matched     int         `json:"matched"`
+	Requeued    int         `json:"requeued"`
+	DryRun      bool        `json:"dry_run"`
+	PaymentIDs  []uuid.UUID `json:"payment_ids"`
+	RequeuedIDs []uuid.UUID `json:"requeued_ids"`
+	// StoppedAt and Error are set when the re-drive stopped at a payment it
+	// could not queue. The payments in RequeuedIDs were queued before it.
+	StoppedAt *uuid.UUID `json:"stopped_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// PaymentAttemptList is a payment's provider calls as the worker recorded
+// them, oldest first.
+type PaymentAttemptList struct {
+	PaymentID uuid.UUID        `json:"payment_id"`
+	Status    PaymentStatus    `json:"status"`
+	Data      []PaymentAttempt `json:"data"`
+}
+
+// AdminService backs the operator endpoints. Every change locks the payment
+// the way ProcessPayment does, so it waits for a worker charging the payment
+// and the worker never charges a payment an operator has just failed.
+type AdminService interface {
+	// RequeuePayment queues a pending payment for processing again.
+	RequeuePayment(ctx context.Context, id string) (*Payment, error)
+	// ForceFailPayment fails a pending payment without charging it.
+	ForceFailPayment(ctx context.Context, id string, fr *ForceFailRequest) (*Payment, error)
+	ListPaymentAttempts(ctx context.Context, id string) (*PaymentAttemptList, error)
+	// RedrivePayments queues every pending payment the request selects. When
+	// it stops partway it returns the partial result with the error.
+	RedrivePayments(ctx context.Context, rr *RedriveRequest) (*RedriveResult, error)
+}
+
+type AdminHandler interface {
+	RequeuePayment(c echo.Context) error
+	ForceFailPayment(c echo.Context) error
+	ListPaymentAttempts(c echo.Context) error
+	RedrivePayments(c echo.Context) error
+}
@@ -0,0 +1,234 @@
+package domain
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"pgm/internal/logger"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Actors recorded for changes not made by a named operator.
+const (
+	// ActorAPI is an API request outside the operator routes.
+	ActorAPI = "api"
+	// ActorWorker is the worker: payment processing and its periodic jobs.
+	ActorWorker = "worker"
+	// ActorSystem is anything else, such as a command-line tool.
+	ActorSystem = "system"
+)
+
+// Actor is who makes a change, as recorded in the audit log.
+type Actor struct {
+	Name     string
+	SourceIP string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the actor.
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+// ActorFromContext returns the actor stored in ctx, or ActorSystem.
+func ActorFromContext(ctx context.Context) Actor {
+	if a, ok := ctx.Value(actorKey{}).(Actor); ok {
+		return a
+	}
+	return Actor{Name: ActorSystem}
+}
+
+// Audit target types.
+const (
+	AuditPayment                 = "payment"
+	AuditCustomer                = "customer"
+	AuditCheckoutSession         = "checkout_session"
+	AuditPaymentLink             = "payment_link"
+	AuditPlan                    = "plan"
+	AuditSubscription            = "subscription"
+	AuditFeeSchedule             = "fee_schedule"
+	AuditSettlementBatch         = "settlement_batch"
+	AuditReconciliation          = "reconciliation"
+	AuditReconciliationException = "reconciliation_exception"
+	AuditDispute                 = "dispute"
+	AuditRiskRule                = "risk_rule"
+	AuditRiskBlocklist           = "risk_blocklist"
+	AuditReview                  = "payment_review"
+)
+
+// AuditGenesisHash is the previous hash of the first entry in the chain.
+var AuditGenesisHash = strings.Repeat("0", 64)
+
+// AuditEntry records one change: who made it, to what, and the target as it
+// stood before and after. Entries are append-only. Each is sealed into a
+// hash chain after it commits, which sets Seq, PrevHash and Hash.
+type AuditEntry struct {
+	ID         int64           `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id"`
+	RequestID  string          `json:"request_id,omitempty"`
+	SourceIP   string          `json:"source_ip,omitempty"`
+	Before     json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After      json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"created_at"`
+	Seq        *int64          `json:"seq,omitempty"`
+	PrevHash   string          `json:"prev_hash,omitempty"`
+	Hash       string          `json:"hash,omitempty"`
+	SealedAt   *time.Time      `json:"sealed_at,omitempty"`
+}
+
+// NewAuditEntry builds an entry for a change made by the actor and request in
+// ctx. before is nil for a creation and after is nil for a deletion.
+func NewAuditEntry(ctx context.Context, action, targetType, targetID string, before, after interface{}) (*AuditEntry, error) {
+	actor := ActorFromContext(ctx)
+	entry := &AuditEntry{
+		Actor:      actor.Name,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		RequestID:  logger.RequestID(ctx),
+		SourceIP:   actor.SourceIP,
+	}
+	var err error
+	if entry.Before, err = auditSnapshot(before); err != nil {
+		return nil, fmt.Errorf("failed to encode %s snapshot before %s: %w", targetType, action, err)
+	}
+	if entry.After, err = auditSnapshot(after); err != nil {
+		return nil, fmt.Errorf("failed to encode %s snapshot after %s: %w", targetType, action, err)
+	}
+	return entry, nil
+}
+
+func auditSnapshot(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil || string(b) == "null" {
+		return nil, err
+	}
+	return b, nil
+}
+
+// ComputeHash returns the hex SHA-256 of the entry at position seq in the
+// chain, following the entry whose hash is prevHash. Every field is length
+// prefixed so no two entries encode alike.
+func (e *AuditEntry) ComputeHash(seq int64, prevHash string) string {
+	h := sha256.New()
+	for _, field := range []string{
+		strconv.FormatInt(seq, 10),
+		prevHash,
+		strconv.FormatInt(e.ID, 10),
+		e.Actor,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		e.RequestID,
+		e.SourceIP,
+		string(e.Before),
+		string(e.After),
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	} {
+		fmt.Fprintf(h, "%d:%s;", len(field), field)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// AuditFilter narrows an audit log listing. Empty fields match everything.
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+}
+
+// ParseAuditFilter reads the actor, action, target_type, target_id,
+// request_id, from and to query values. from and to are RFC 3339 times.
+func ParseAuditFilter(query url.Values) (AuditFilter, error) {
+	f := AuditFilter{
+		Actor:      query.Get("actor"),
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		TargetID:   query.Get("target_id"),
+		RequestID:  query.Get("request_id"),
+	}
+	for _, param := range []string{"from", "to"} {
+		if !query.Has(param) {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, query.Get(param))
+		if err != nil {
+			return f, NewError(ErrInvalidRequest, "invalid "+param+" filter", param+" must be an RFC 3339 time", err, map[string]interface{}{param: query.Get(param)})
+		}
+		if param == "from" {
+			f.From = &t
+		} else {
+			f.To = &t
+		}
+	}
+	return f, nil
+}
+
+type AuditList struct {
+	Data   []AuditEntry `json:"data"`
+	Limit  int          `json:"limit"`
+	Offset int          `json:"offset"`
+}
+
+// AuditVerification is the result of walking the hash chain. OK is false
+// when an entry's hash, its link to the previous entry or its position in the
+// sequence is wrong; BrokenAt is then the first such entry's seq.
+type AuditVerification struct {
+	OK        bool      `json:"ok"`
+	Checked   int64     `json:"checked"`
+	Unsealed  int64     `json:"unsealed"`
+	HeadSeq   int64     `json:"head_seq"`
+	HeadHash  string    `json:"head_hash"`
+	BrokenAt  *int64    `json:"broken_at,omitempty"`
+	Problem   string    `json:"problem,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+type AuditRepo interface {
+	// AppendEntry inserts entry unsealed and fills in the generated fields.
+	AppendEntry(ctx context.Context, entry *AuditEntry) error
+	ListEntries(ctx context.Context, filter AuditFilter, page Page) ([]AuditEntry, error)
+	// LockChain takes the sealing lock for the current transaction. It
+	// returns false when another transaction holds it.
+	LockChain(ctx context.Context) (bool, error)
+	// GetChainHead returns the sealed entry with the highest seq, or
+	// ErrNotFound before anything is sealed.
+	GetChainHead(ctx context.Context) (*AuditEntry, error)
+	// ListUnsealedEntries returns up to limit unsealed entries, oldest first.
+	ListUnsealedEntries(ctx context.Context, limit int) ([]AuditEntry, error)
+	SealEntry(ctx context.Context, id, seq int64, prevHash, hash string) (*AuditEntry, error)
+	// ListSealedEntries returns up to limit sealed entries after afterSeq in
+	// chain order.
+	ListSealedEntries(ctx context.Context, afterSeq int64, limit int) ([]AuditEntry, error)
+	CountUnsealedEntries(ctx context.Context) (int64, error)
+}
+
+type AuditService interface {
+	ListEntries(ctx context.Context, filter AuditFilter, page Page) (*AuditList, error)
+	// Verify recomputes every sealed entry's hash and checks the links
+	// between them.
+	Verify(ctx context.Context) (*AuditVerification, error)
+}
+
+type AuditHandler interface {
+	ListEntries(c echo.Context) error
+}
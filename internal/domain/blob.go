@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStore keeps files such as dispute evidence outside the database. Keys
+// are slash-separated paths chosen by the caller.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) error
+	// Open returns ErrNotFound if there is no blob under key.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
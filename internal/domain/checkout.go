@@ -0,0 +1,109 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type CheckoutStatus string
+
+const (
+	CheckoutOpen      CheckoutStatus = "open"
+	CheckoutConfirmed CheckoutStatus = "confirmed"
+	CheckoutCanceled  CheckoutStatus = "canceled"
+	CheckoutExpired   CheckoutStatus = "expired"
+)
+
+const (
+	DefaultCheckoutExpiry = 30 * time.Minute
+	MaxCheckoutExpiry     = 24 * time.Hour
+)
+
+// CheckoutSession is a hosted page on which the payer picks a payment method
+// for a pending payment. The payment is only queued for processing once the
+// session is confirmed; canceling or letting it expire fails the payment.
+type CheckoutSession struct {
+	ID     uuid.UUID      `json:"id"`
+	Status CheckoutStatus `json:"status"`
+	// URL is the hosted checkout page to send the payer to.
+	URL        string    `json:"url"`
+	SuccessURL string    `json:"success_url"`
+	CancelURL  string    `json:"cancel_url"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Payment    *Payment  `json:"payment,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	PaymentID  uuid.UUID `json:"-"`
+	// Token identifies the session on the hosted page. It is only exposed
+	// as part of URL.
+	Token string `json:"-"`
+}
+
+type CheckoutSessionRequest struct {
+	PaymentRequest
+	SuccessURL string `json:"success_url"`
+	CancelURL  string `json:"cancel_url"`
+	// ExpiresIn is the session lifetime in seconds, 30 minutes by default.
+	ExpiresIn int `json:"expires_in,omitempty"`
+}
+
+func (cr CheckoutSessionRequest) Validate() error {
+	if err := cr.PaymentRequest.Validate(); err != nil {
+		return err
+	}
+	return validation.ValidateStruct(&cr,
+		validation.Field(&cr.SuccessURL, validation.Required.Error("success url is required"), is.URL),
+		validation.Field(&cr.CancelURL, validation.Required.Error("cancel url is required"), is.URL),
+		validation.Field(&cr.ExpiresIn, validation.Min(60), validation.Max(int(MaxCheckoutExpiry/time.Second))),
+		validation.Field(&cr.PaymentMethod, validation.By(func(interface{}) error {
+			if cr.PaymentMethod != nil {
+				return errors.New("the payment method is chosen by the payer on the checkout page")
+			}
+			return nil
+		})))
+}
+
+// Expiry returns the requested session lifetime.
+func (cr CheckoutSessionRequest) Expiry() time.Duration {
+	if cr.ExpiresIn == 0 {
+		return DefaultCheckoutExpiry
+	}
+	return time.Duration(cr.ExpiresIn) * time.Second
+}
+
+type CheckoutSessionRepo interface {
+	// CreateCheckoutSession inserts session and fills in the generated fields.
+	CreateCheckoutSession(ctx context.Context, session *CheckoutSession) error
+	GetCheckoutSessionByID(ctx context.Context, id uuid.UUID) (*CheckoutSession, error)
+	GetCheckoutSessionByToken(ctx context.Context, token string) (*CheckoutSession, error)
+	GetCheckoutSessionByPaymentID(ctx context.Context, paymentID uuid.UUID) (*CheckoutSession, error)
+	// CloseCheckoutSession moves an open session to status. It returns
+	// ErrNotFound if the session is no longer open.
+	CloseCheckoutSession(ctx context.Context, id uuid.UUID, status CheckoutStatus) (*CheckoutSession, error)
+	// ClaimExpiredCheckoutSession locks the open session that expired
+	// earliest, no later than now, skipping rows other workers hold. It
+	// returns ErrNotFound when none is left.
+	ClaimExpiredCheckoutSession(ctx context.Context, now time.Time) (*CheckoutSession, error)
+}
+
+type CheckoutService interface {
+	CreateSession(ctx context.Context, cr *CheckoutSessionRequest) (*CheckoutSession, error)
+	GetSession(ctx context.Context, id string) (*CheckoutSession, error)
+	GetSessionByToken(ctx context.Context, token string) (*CheckoutSession, error)
+	ConfirmSession(ctx context.Context, token string, method *PaymentMethod) (*CheckoutSession, error)
+	CancelSession(ctx context.Context, token string) (*CheckoutSession, error)
+}
+
+type CheckoutHandler interface {
+	CreateSession(c echo.Context) error
+	GetSession(c echo.Context) error
+	ShowPage(c echo.Context) error
+	Confirm(c echo.Context) error
+	Cancel(c echo.Context) error
+}
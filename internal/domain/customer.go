@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// phonePattern accepts E.164 numbers, e.g. +251911234567.
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+type Customer struct {
+	ID    uuid.UUID `json:"id"`
+	Name  string    `json:"name"`
+	Email string    `json:"email,omitempty"`
+	Phone string    `json:"phone,omitempty"`
+	// ExternalID is unique per merchant.
+	ExternalID string `json:"external_id,omitempty"`
+	// MerchantID is the merchant whose API key created the customer; empty
+	// for the payer of a payment made without one.
+	MerchantID string    `json:"merchant_id,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// CustomerRequest is used both to manage customers and as the inline payer on a PaymentRequest.
+type CustomerRequest struct {
+	Name       string `json:"name"`
+	Email      string `json:"email,omitempty"`
+	Phone      string `json:"phone,omitempty"`
+	ExternalID string `json:"external_id,omitempty"`
+}
+
+func (cr CustomerRequest) Validate() error {
+	return validation.ValidateStruct(&cr,
+		validation.Field(&cr.Name, validation.Required.Error("customer name is required"), validation.Length(1, 255)),
+		validation.Field(&cr.Email, is.Email.Error("must be a valid email address"), validation.Length(0, 255)),
+		validation.Field(&cr.Phone, validation.Match(phonePattern).Error("must be a valid phone number in E.164 format")),
+		validation.Field(&cr.ExternalID, validation.Length(0, 255)))
+}
+
+type CustomerList struct {
+	Data   []Customer `json:"data"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+// CustomerRepo stores customers. Every lookup is made within one merchant's
+// customers; another merchant's customer is ErrNotFound.
+type CustomerRepo interface {
+	CreateCustomer(ctx context.Context, customer *Customer) error
+	GetCustomerByID(ctx context.Context, merchantID string, id uuid.UUID) (*Customer, error)
+	GetCustomerByExternalID(ctx context.Context, merchantID, externalID string) (*Customer, error)
+	ListCustomers(ctx context.Context, merchantID string, page Page) ([]Customer, error)
+	// UpdateCustomer updates the customer with customer.ID of
+	// customer.MerchantID.
+	UpdateCustomer(ctx context.Context, customer *Customer) error
+	DeleteCustomer(ctx context.Context, merchantID string, id uuid.UUID) error
+}
+
+type CustomerService interface {
+	CreateCustomer(ctx context.Context, cr *CustomerRequest) (*Customer, error)
+	GetCustomerByID(ctx context.Context, id string) (*Customer, error)
+	ListCustomers(ctx context.Context, page Page) (*CustomerList, error)
+	UpdateCustomer(ctx context.Context, id string, cr *CustomerRequest) (*Customer, error)
+	DeleteCustomer(ctx context.Context, id string) error
+	ListCustomerPayments(ctx context.Context, id string, page Page) (*PaymentList, error)
+}
+
+type CustomerHandler interface {
+	CreateCustomer(c echo.Context) error
+	GetCustomerByID(c echo.Context) error
+	ListCustomers(c echo.Context) error
+	UpdateCustomer(c echo.Context) error
+	DeleteCustomer(c echo.Context) error
+	ListCustomerPayments(c echo.Context) error
+}
@@ -0,0 +1,233 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type DisputeStatus string
+
+const (
+	// DisputeNeedsResponse disputes wait for evidence until EvidenceDueBy.
+	DisputeNeedsResponse DisputeStatus = "needs_response"
+	// DisputeUnderReview disputes have evidence submitted and await the
+	// provider's decision.
+	DisputeUnderReview DisputeStatus = "under_review"
+	DisputeWon         DisputeStatus = "won"
+	DisputeLost        DisputeStatus = "lost"
+)
+
+// Closed reports whether the provider has decided the dispute.
+func (s DisputeStatus) Closed() bool {
+	return s == DisputeWon || s == DisputeLost
+}
+
+// Event types published as a dispute moves through its states.
+const (
+	EventDisputeCreated     = "dispute.created"
+	EventDisputeUnderReview = "dispute.under_review"
+	EventDisputeWon         = "dispute.won"
+	EventDisputeLost        = "dispute.lost"
+)
+
+const (
+	MaxEvidenceFiles    = 10
+	MaxEvidenceFileSize = 5 << 20
+	// MaxEvidenceSize caps a whole evidence submission in bytes.
+	MaxEvidenceSize = 25 << 20
+)
+
+// EvidenceContentTypes are the file types accepted as dispute evidence.
+var EvidenceContentTypes = []interface{}{"application/pdf", "image/png", "image/jpeg", "text/plain"}
+
+// Dispute is a chargeback the payer raised with their bank or provider
+// against a successful payment.
+type Dispute struct {
+	ID        uuid.UUID `json:"id"`
+	PaymentID uuid.UUID `json:"payment_id"`
+	// ProviderReference is the provider's identifier for the chargeback.
+	ProviderReference string `json:"provider_reference,omitempty"`
+	// ReasonCode is the card network or provider reason, e.g. "4853".
+	ReasonCode string        `json:"reason_code"`
+	Amount     float64       `json:"amount"`
+	Currency   string        `json:"currency"`
+	Status     DisputeStatus `json:"status"`
+	// EvidenceDueBy is the deadline for submitting evidence.
+	EvidenceDueBy       time.Time  `json:"evidence_due_by"`
+	EvidenceDescription string     `json:"evidence_description,omitempty"`
+	EvidenceSubmittedAt *time.Time `json:"evidence_submitted_at,omitempty"`
+	ClosedAt            *time.Time `json:"closed_at,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type DisputeRequest struct {
+	PaymentID         uuid.UUID `json:"payment_id"`
+	ProviderReference string    `json:"provider_reference,omitempty"`
+	ReasonCode        string    `json:"reason_code"`
+	// Amount defaults to the full payment amount.
+	Amount        *float64  `json:"amount,omitempty"`
+	EvidenceDueBy time.Time `json:"evidence_due_by"`
+}
+
+func (dr DisputeRequest) Validate() error {
+	return validation.ValidateStruct(&dr,
+		validation.Field(&dr.PaymentID, validation.By(func(interface{}) error {
+			if dr.PaymentID == uuid.Nil {
+				return errors.New("payment_id is required")
+			}
+			return nil
+		})),
+		validation.Field(&dr.ProviderReference, validation.Length(1, 255)),
+		validation.Field(&dr.ReasonCode, validation.Required.Error("reason_code is required"), validation.Length(1, 64)),
+		validation.Field(&dr.Amount, validation.Min(0.01)),
+		validation.Field(&dr.EvidenceDueBy, validation.Required.Error("evidence_due_by is required")))
+}
+
+// DisputeEvidence is one file submitted in response to a dispute.
+type DisputeEvidence struct {
+	ID          uuid.UUID `json:"id"`
+	DisputeID   uuid.UUID `json:"dispute_id"`
+	FileName    string    `json:"file_name"`
+	ContentType string    `json:"content_type"`
+	Size        int64     `json:"size"`
+	// StorageKey locates the file in the BlobStore.
+	StorageKey string    `json:"-"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// EvidenceFile is an uploaded file not yet stored.
+type EvidenceFile struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Content     io.Reader
+}
+
+// EvidenceSubmission is the response to a dispute: a written description,
+// files, or both.
+type EvidenceSubmission struct {
+	Description string
+	Files       []EvidenceFile
+}
+
+func (es EvidenceSubmission) Validate() error {
+	if es.Description == "" && len(es.Files) == 0 {
+		return errors.New("description or at least one file is required")
+	}
+	if len(es.Description) > 5000 {
+		return errors.New("description must be at most 5000 characters")
+	}
+	if len(es.Files) > MaxEvidenceFiles {
+		return fmt.Errorf("at most %d files can be submitted", MaxEvidenceFiles)
+	}
+	for _, f := range es.Files {
+		err := validation.ValidateStruct(&f,
+			validation.Field(&f.FileName, validation.Required.Error("file name is required"), validation.Length(1, 255)),
+			validation.Field(&f.ContentType, validation.In(EvidenceContentTypes...).Error("must be a PDF, PNG, JPEG or plain text file")),
+			validation.Field(&f.Size, validation.Max(int64(MaxEvidenceFileSize)).Error("must be at most 5 MiB")))
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.FileName, err)
+		}
+	}
+	return nil
+}
+
+type CloseDisputeRequest struct {
+	// Outcome is the provider's decision: won or lost.
+	Outcome DisputeStatus `json:"outcome"`
+}
+
+func (cr CloseDisputeRequest) Validate() error {
+	return validation.ValidateStruct(&cr,
+		validation.Field(&cr.Outcome, validation.Required.Error("outcome is required"), validation.In(DisputeWon, DisputeLost)))
+}
+
+// DisputeFilter narrows a dispute listing. Nil fields match every dispute.
+type DisputeFilter struct {
+	PaymentID *uuid.UUID
+	Status    *DisputeStatus
+	// MerchantID is set from the caller's API key, not the query string.
+	MerchantID *string
+}
+
+// ParseDisputeFilter reads the payment_id and status query values.
+func ParseDisputeFilter(query url.Values) (DisputeFilter, error) {
+	var f DisputeFilter
+	if query.Has("payment_id") {
+		paymentID, err := uuid.Parse(query.Get("payment_id"))
+		if err != nil {
+			return f, NewError(ErrInvalidRequest, "invalid payment_id filter", "payment_id must be a UUID", err, map[string]interface{}{"payment_id": query.Get("payment_id")})
+		}
+		f.PaymentID = &paymentID
+	}
+	if query.Has("status") {
+		status := DisputeStatus(query.Get("status"))
+		switch status {
+		case DisputeNeedsResponse, DisputeUnderReview, DisputeWon, DisputeLost:
+		default:
+			return f, NewError(ErrInvalidRequest, "invalid status filter", "status must be one of needs_response, under_review, won or lost", nil, map[string]interface{}{"status": status})
+		}
+		f.Status = &status
+	}
+	return f, nil
+}
+
+type DisputeList struct {
+	Data   []Dispute `json:"data"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
+}
+
+type DisputeEvidenceList struct {
+	Data []DisputeEvidence `json:"data"`
+}
+
+type DisputeRepo interface {
+	// CreateDispute inserts dispute and fills in the generated fields.
+	CreateDispute(ctx context.Context, dispute *Dispute) error
+	GetDisputeByID(ctx context.Context, id uuid.UUID) (*Dispute, error)
+	GetDisputeByIDForUpdate(ctx context.Context, id uuid.UUID) (*Dispute, error)
+	// DisputedAmount sums the payment's disputes that are still open or were
+	// lost. Won disputes gave the money back, so they do not count.
+	DisputedAmount(ctx context.Context, paymentID uuid.UUID) (float64, error)
+	ListDisputes(ctx context.Context, filter DisputeFilter, page Page) ([]Dispute, error)
+	// SubmitEvidence moves the dispute under review with description.
+	SubmitEvidence(ctx context.Context, id uuid.UUID, description string) (*Dispute, error)
+	CloseDispute(ctx context.Context, id uuid.UUID, outcome DisputeStatus) (*Dispute, error)
+	CreateEvidence(ctx context.Context, evidence *DisputeEvidence) error
+	ListEvidence(ctx context.Context, disputeID uuid.UUID) ([]DisputeEvidence, error)
+	GetEvidenceByID(ctx context.Context, disputeID, id uuid.UUID) (*DisputeEvidence, error)
+}
+
+type DisputeService interface {
+	CreateDispute(ctx context.Context, dr *DisputeRequest) (*Dispute, error)
+	GetDisputeByID(ctx context.Context, id string) (*Dispute, error)
+	ListDisputes(ctx context.Context, filter DisputeFilter, page Page) (*DisputeList, error)
+	// SubmitEvidence stores the files and moves the dispute under review.
+	SubmitEvidence(ctx context.Context, id string, es *EvidenceSubmission) (*Dispute, error)
+	// CloseDispute records the provider's decision.
+	CloseDispute(ctx context.Context, id string, cr *CloseDisputeRequest) (*Dispute, error)
+	ListEvidence(ctx context.Context, id string) (*DisputeEvidenceList, error)
+	// OpenEvidence returns an evidence file's details and content. The caller
+	// closes the content.
+	OpenEvidence(ctx context.Context, id, evidenceID string) (*DisputeEvidence, io.ReadCloser, error)
+}
+
+type DisputeHandler interface {
+	CreateDispute(c echo.Context) error
+	GetDisputeByID(c echo.Context) error
+	ListDisputes(c echo.Context) error
+	SubmitEvidence(c echo.Context) error
+	CloseDispute(c echo.Context) error
+	ListEvidence(c echo.Context) error
+	GetEvidenceFile(c echo.Context) error
+}
@@ -1,31 +1,47 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"runtime"
 	"runtime/debug"
+	"sort"
 
+	"pgm/internal/logger"
+
+	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/labstack/echo/v4"
 )
 
+// MIMEApplicationProblemJSON is the RFC 7807 media type for error responses.
+const MIMEApplicationProblemJSON = "application/problem+json"
+
+// problemTypePrefix namespaces ErrorCode values into problem type URIs.
+const problemTypePrefix = "urn:pgm:error:"
+
 type Error struct {
 	Code        int                    `json:"code"`
+	Type        ErrorCode              `json:"type"`
 	Message     string                 `json:"message"`
 	Description string                 `json:"description"`
-	Args        map[string]interface{} `json:"params"`
-	Err         error                  `json:"err"`
+	Args        map[string]interface{} `json:"-"`
+	Err         error                  `json:"-"`
 	File        string                 `json:"-"`
 	Line        int                    `json:"-"`
 	Func        string                 `json:"-"`
 	Stack       string                 `json:"-"`
 }
 
-func NewError(code int, message string, description string, err error, args map[string]interface{}) Error {
+// NewError creates an Error whose HTTP status comes from the catalog entry of
+// code. Args are logged but never sent to clients.
+func NewError(code ErrorCode, message string, description string, err error, args map[string]interface{}) Error {
 	pc, file, line, _ := runtime.Caller(1)
 	fn := runtime.FuncForPC(pc)
 	return Error{
-		Code:        code,
+		Code:        code.Status(),
+		Type:        code,
 		Message:     message,
 		Description: description,
 		Err:         err,
@@ -42,7 +58,7 @@ func (e Error) Error() string {
 	if e.Err != nil {
 		cause = e.Err.Error()
 	}
-	return fmt.Sprintf("Code:%d: Message:%s Description:%s Cause:%s", e.Code, e.Message, e.Description, cause)
+	return fmt.Sprintf("Code:%d: Type:%s Message:%s Description:%s Cause:%s", e.Code, e.Type, e.Message, e.Description, cause)
 }
 
 func (e Error) ErrorCode() int {
@@ -57,76 +73,82 @@ func (e Error) Unwrap() error {
 	return e.Err
 }
 
-type ErrorResponse struct {
-	Code        int            `json:"code"`
-	Message     string         `json:"message"`
-	Description string         `json:"description,omitempty"`
-	Params      map[string]any `json:"params,omitempty"`
+// FieldError describes why a single request field was rejected.
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ProblemDetails is an RFC 7807 error response body.
+type ProblemDetails struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      ErrorCode    `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
 }
 
 func ErrorHandler(err error, c echo.Context) {
 	var (
-		code        = http.StatusInternalServerError
-		message     = "internal server error"
-		description = ""
+		errCode     = ErrInternal
+		status      = http.StatusInternalServerError
+		detail      = ""
 		params      map[string]interface{}
-		internalErr error
-		Line        = ""
-		File        = ""
-		Func        = ""
-		Stack       = ""
+		internalErr = err
+		attrs       []slog.Attr
 	)
 
 	switch e := err.(type) {
 
 	// Your custom error
 	case Error:
-		code = e.Code
-		message = e.Message
-		description = e.Description
+		errCode = e.Type
+		status = e.Code
+		detail = e.Description
+		if detail == "" {
+			detail = e.Message
+		}
 		params = e.Args
-		internalErr = e.Err
-		Line = fmt.Sprintf("%d", e.Line)
-		File = e.File
-		Func = e.Func
-		Stack = e.Stack
+		if e.Err != nil {
+			internalErr = e.Err
+		}
+		attrs = append(attrs,
+			slog.String("file", e.File),
+			slog.Int("line", e.Line),
+			slog.String("func", e.Func),
+		)
 
 	// Echo HTTP error
 	case *echo.HTTPError:
-		code = e.Code
-		message = fmt.Sprint(e.Message)
-		internalErr = e.Internal
-
-	default:
-		internalErr = err
+		status = e.Code
+		errCode = ErrorCodeForStatus(e.Code)
+		detail = fmt.Sprint(e.Message)
+		if e.Internal != nil {
+			internalErr = e.Internal
+		}
 	}
 
-	//LOG INTERNAL ERROR
-	if internalErr != nil {
-		c.Logger().Errorf(
-			"error=%+v code=%d message=%s params=%v file=%s line=%s func=%s stack=%s",
-			internalErr,
-			code,
-			message,
-			params,
-			File,
-			Line,
-			Func,
-			Stack,
-		)
-	} else {
-		c.Logger().Errorf(
-			"error=%+v code=%d message=%s params=%v file=%s line=%s func=%s stack=%s",
-			err,
-			code,
-			message,
-			params,
-			File,
-			Line,
-			Func,
-			Stack,
-		)
+	ctx := c.Request().Context()
+	log := logger.FromContext(ctx)
+	attrs = append(attrs,
+		slog.Any("error", internalErr),
+		slog.Int("status", status),
+		slog.String("code", string(errCode)),
+		slog.String("detail", detail),
+		slog.Any("params", params),
+	)
+	// Stack traces are noisy; only emit them when debugging.
+	if e, ok := err.(Error); ok && log.Enabled(ctx, slog.LevelDebug) {
+		attrs = append(attrs, slog.String("stack", e.Stack))
+	}
+	level := slog.LevelError
+	if status < http.StatusInternalServerError {
+		level = slog.LevelWarn
 	}
+	log.LogAttrs(ctx, level, "request failed", attrs...)
 
 	// Response already sent?
 	if c.Response().Committed {
@@ -134,10 +156,45 @@ func ErrorHandler(err error, c echo.Context) {
 	}
 
 	// Write safe response
-	_ = c.JSON(code, ErrorResponse{
-		Code:        code,
-		Message:     message,
-		Description: description,
-		Params:      params,
+	c.Response().Header().Set(echo.HeaderContentType, MIMEApplicationProblemJSON)
+	_ = c.JSON(status, ProblemDetails{
+		Type:      problemTypePrefix + string(errCode),
+		Title:     errCode.Definition().Title,
+		Status:    status,
+		Detail:    detail,
+		Instance:  c.Request().URL.Path,
+		Code:      errCode,
+		RequestID: c.Response().Header().Get(echo.HeaderXRequestID),
+		Errors:    fieldErrors(err),
 	})
 }
+
+// fieldErrors extracts per-field messages from ozzo validation errors.
+// Nested structs are flattened into dotted paths, e.g. "payer.email".
+func fieldErrors(err error) []FieldError {
+	var verrs validation.Errors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+	out := flattenFieldErrors("", verrs)
+	sort.Slice(out, func(i, j int) bool { return out[i].Field < out[j].Field })
+	return out
+}
+
+func flattenFieldErrors(prefix string, verrs validation.Errors) []FieldError {
+	var out []FieldError
+	for field, ferr := range verrs {
+		if ferr == nil {
+			continue
+		}
+		if prefix != "" {
+			field = prefix + "." + field
+		}
+		if nested, ok := ferr.(validation.Errors); ok {
+			out = append(out, flattenFieldErrors(field, nested)...)
+			continue
+		}
+		out = append(out, FieldError{Field: field, Message: ferr.Error()})
+	}
+	return out
+}
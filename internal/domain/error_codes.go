@@ -0,0 +1,198 @@
+package domain
+
+import "net/http"
+
+// ErrorCode is a stable, machine-readable identifier for an error. Clients may
+// branch on it; the human-readable message may change at any time.
+type ErrorCode string
+
+const (
+	ErrInternal                ErrorCode = "internal.error"
+	ErrInvalidRequest          ErrorCode = "request.invalid"
+	ErrInvalidRequestBody      ErrorCode = "request.invalid_body"
+	ErrUnauthorized            ErrorCode = "request.unauthorized"
+	ErrForbidden               ErrorCode = "request.forbidden"
+	ErrRouteNotFound           ErrorCode = "request.route_not_found"
+	ErrMethodNotAllowed        ErrorCode = "request.method_not_allowed"
+	ErrRequestTooLarge         ErrorCode = "request.too_large"
+	ErrUnsupportedMediaType    ErrorCode = "request.unsupported_media_type"
+	ErrRateLimited             ErrorCode = "request.rate_limited"
+	ErrServiceUnavailable      ErrorCode = "service.unavailable"
+	ErrValidationFailed        ErrorCode = "validation.failed"
+	ErrInvalidPaymentID        ErrorCode = "payment.invalid_id"
+	ErrPaymentNotFound         ErrorCode = "payment.not_found"
+	ErrDuplicateReference      ErrorCode = "payment.duplicate_reference"
+	ErrPaymentAlreadyProcessed ErrorCode = "payment.already_processed"
+	ErrPaymentNotPending       ErrorCode = "payment.not_pending"
+	ErrPaymentNotConfirmed     ErrorCode = "payment.not_confirmed"
+	ErrNoProviderAvailable     ErrorCode = "provider.unavailable"
+	ErrProviderCircuitOpen     ErrorCode = "provider.circuit_open"
+	ErrInvalidCustomerID       ErrorCode = "customer.invalid_id"
+	ErrCustomerNotFound        ErrorCode = "customer.not_found"
+	ErrDuplicateExternalID     ErrorCode = "customer.duplicate_external_id"
+	ErrCustomerHasPayments     ErrorCode = "customer.has_payments"
+	ErrInvalidCheckoutID       ErrorCode = "checkout.invalid_id"
+	ErrCheckoutNotFound        ErrorCode = "checkout.not_found"
+	ErrCheckoutExpired         ErrorCode = "checkout.expired"
+	ErrCheckoutClosed          ErrorCode = "checkout.closed"
+	ErrInvalidPaymentLinkID    ErrorCode = "payment_link.invalid_id"
+	ErrPaymentLinkNotFound     ErrorCode = "payment_link.not_found"
+	ErrPaymentLinkUnavailable  ErrorCode = "payment_link.unavailable"
+	ErrInvalidPlanID           ErrorCode = "plan.invalid_id"
+	ErrPlanNotFound            ErrorCode = "plan.not_found"
+	ErrInvalidSubscriptionID   ErrorCode = "subscription.invalid_id"
+	ErrSubscriptionNotFound    ErrorCode = "subscription.not_found"
+	ErrSubscriptionCanceled    ErrorCode = "subscription.canceled"
+	ErrInvalidFeeScheduleID    ErrorCode = "fee_schedule.invalid_id"
+	ErrFeeScheduleNotFound     ErrorCode = "fee_schedule.not_found"
+	ErrInvalidSettlementID     ErrorCode = "settlement.invalid_id"
+	ErrSettlementNotFound      ErrorCode = "settlement.not_found"
+	ErrSettlementNotPayable    ErrorCode = "settlement.not_payable"
+	ErrInvalidStatement        ErrorCode = "reconciliation.invalid_statement"
+	ErrInvalidReconciliationID ErrorCode = "reconciliation.invalid_id"
+	ErrReconciliationNotFound  ErrorCode = "reconciliation.not_found"
+	ErrInvalidExceptionID      ErrorCode = "reconciliation.invalid_exception_id"
+	ErrExceptionNotFound       ErrorCode = "reconciliation.exception_not_found"
+	ErrExceptionResolved       ErrorCode = "reconciliation.exception_resolved"
+	ErrInvalidDisputeID        ErrorCode = "dispute.invalid_id"
+	ErrDisputeNotFound         ErrorCode = "dispute.not_found"
+	ErrDuplicateDispute        ErrorCode = "dispute.duplicate_provider_reference"
+	ErrPaymentNotDisputable    ErrorCode = "dispute.payment_not_disputable"
+	ErrDisputeAmountExceeded   ErrorCode = "dispute.amount_exceeded"
+	ErrDisputeNotAwaiting      ErrorCode = "dispute.not_awaiting_response"
+	ErrDisputeDeadlinePassed   ErrorCode = "dispute.deadline_passed"
+	ErrDisputeClosed           ErrorCode = "dispute.closed"
+	ErrInvalidEvidenceID       ErrorCode = "dispute.invalid_evidence_id"
+	ErrEvidenceNotFound        ErrorCode = "dispute.evidence_not_found"
+	ErrInvalidRiskRuleID       ErrorCode = "risk.invalid_rule_id"
+	ErrRiskRuleNotFound        ErrorCode = "risk.rule_not_found"
+	ErrDuplicateRiskRule       ErrorCode = "risk.duplicate_rule_name"
+	ErrInvalidBlocklistID      ErrorCode = "risk.invalid_blocklist_id"
+	ErrBlocklistEntryNotFound  ErrorCode = "risk.blocklist_entry_not_found"
+	ErrDuplicateBlocklistEntry ErrorCode = "risk.duplicate_blocklist_entry"
+	ErrInvalidReviewID         ErrorCode = "review.invalid_id"
+	ErrReviewNotFound          ErrorCode = "review.not_found"
+	ErrReviewAlreadyClaimed    ErrorCode = "review.already_claimed"
+	ErrReviewNotClaimed        ErrorCode = "review.not_claimed"
+	ErrReviewDecided           ErrorCode = "review.decided"
+	ErrPaymentNotInReview      ErrorCode = "review.payment_not_held"
+)
+
+// ErrorDefinition is the catalog entry for an ErrorCode.
+type ErrorDefinition struct {
+	Status int
+	Title  string
+}
+
+var errorCatalog = map[ErrorCode]ErrorDefinition{
+	ErrInternal:                {http.StatusInternalServerError, "Internal server error"},
+	ErrInvalidRequest:          {http.StatusBadRequest, "Invalid request"},
+	ErrInvalidRequestBody:      {http.StatusBadRequest, "Invalid request body"},
+	ErrUnauthorized:            {http.StatusUnauthorized, "Unauthorized"},
+	ErrForbidden:               {http.StatusForbidden, "Forbidden"},
+	ErrRouteNotFound:           {http.StatusNotFound, "Route not found"},
+	ErrMethodNotAllowed:        {http.StatusMethodNotAllowed, "Method not allowed"},
+	ErrRequestTooLarge:         {http.StatusRequestEntityTooLarge, "Request too large"},
+	ErrUnsupportedMediaType:    {http.StatusUnsupportedMediaType, "Unsupported media type"},
+	ErrRateLimited:             {http.StatusTooManyRequests, "Rate limit exceeded"},
+	ErrServiceUnavailable:      {http.StatusServiceUnavailable, "Service unavailable"},
+	ErrValidationFailed:        {http.StatusBadRequest, "Validation failed"},
+	ErrInvalidPaymentID:        {http.StatusBadRequest, "Invalid payment ID"},
+	ErrPaymentNotFound:         {http.StatusNotFound, "Payment not found"},
+	ErrDuplicateReference:      {http.StatusConflict, "Duplicate payment reference"},
+	ErrPaymentAlreadyProcessed: {http.StatusConflict, "Payment already processed"},
+	ErrPaymentNotPending:       {http.StatusConflict, "Payment not pending"},
+	ErrPaymentNotConfirmed:     {http.StatusConflict, "Payment not confirmed by the payer"},
+	ErrNoProviderAvailable:     {http.StatusServiceUnavailable, "No payment provider available"},
+	ErrProviderCircuitOpen:     {http.StatusServiceUnavailable, "Payment providers temporarily disabled"},
+	ErrInvalidCustomerID:       {http.StatusBadRequest, "Invalid customer ID"},
+	ErrCustomerNotFound:        {http.StatusNotFound, "Customer not found"},
+	ErrDuplicateExternalID:     {http.StatusConflict, "Duplicate customer external ID"},
+	ErrCustomerHasPayments:     {http.StatusConflict, "Customer has payments"},
+	ErrInvalidCheckoutID:       {http.StatusBadRequest, "Invalid checkout session ID"},
+	ErrCheckoutNotFound:        {http.StatusNotFound, "Checkout session not found"},
+	ErrCheckoutExpired:         {http.StatusGone, "Checkout session expired"},
+	ErrCheckoutClosed:          {http.StatusConflict, "Checkout session closed"},
+	ErrInvalidPaymentLinkID:    {http.StatusBadRequest, "Invalid payment link ID"},
+	ErrPaymentLinkNotFound:     {http.StatusNotFound, "Payment link not found"},
+	ErrPaymentLinkUnavailable:  {http.StatusConflict, "Payment link unavailable"},
+	ErrInvalidPlanID:           {http.StatusBadRequest, "Invalid plan ID"},
+	ErrPlanNotFound:            {http.StatusNotFound, "Plan not found"},
+	ErrInvalidSubscriptionID:   {http.StatusBadRequest, "Invalid subscription ID"},
+	ErrSubscriptionNotFound:    {http.StatusNotFound, "Subscription not found"},
+	ErrSubscriptionCanceled:    {http.StatusConflict, "Subscription canceled"},
+	ErrInvalidFeeScheduleID:    {http.StatusBadRequest, "Invalid fee schedule ID"},
+	ErrFeeScheduleNotFound:     {http.StatusNotFound, "Fee schedule not found"},
+	ErrInvalidSettlementID:     {http.StatusBadRequest, "Invalid settlement batch ID"},
+	ErrSettlementNotFound:      {http.StatusNotFound, "Settlement batch not found"},
+	ErrSettlementNotPayable:    {http.StatusConflict, "Settlement batch not payable"},
+	ErrInvalidStatement:        {http.StatusBadRequest, "Invalid statement"},
+	ErrInvalidReconciliationID: {http.StatusBadRequest, "Invalid reconciliation ID"},
+	ErrReconciliationNotFound:  {http.StatusNotFound, "Reconciliation not found"},
+	ErrInvalidExceptionID:      {http.StatusBadRequest, "Invalid reconciliation exception ID"},
+	ErrExceptionNotFound:       {http.StatusNotFound, "Reconciliation exception not found"},
+	ErrExceptionResolved:       {http.StatusConflict, "Reconciliation exception already resolved"},
+	ErrInvalidDisputeID:        {http.StatusBadRequest, "Invalid dispute ID"},
+	ErrDisputeNotFound:         {http.StatusNotFound, "Dispute not found"},
+	ErrDuplicateDispute:        {http.StatusConflict, "Duplicate dispute provider reference"},
+	ErrPaymentNotDisputable:    {http.StatusConflict, "Payment cannot be disputed"},
+	ErrDisputeAmountExceeded:   {http.StatusConflict, "Dispute amount exceeds the undisputed payment amount"},
+	ErrDisputeNotAwaiting:      {http.StatusConflict, "Dispute not awaiting a response"},
+	ErrDisputeDeadlinePassed:   {http.StatusConflict, "Dispute evidence deadline passed"},
+	ErrDisputeClosed:           {http.StatusConflict, "Dispute closed"},
+	ErrInvalidEvidenceID:       {http.StatusBadRequest, "Invalid dispute evidence ID"},
+	ErrEvidenceNotFound:        {http.StatusNotFound, "Dispute evidence not found"},
+	ErrInvalidRiskRuleID:       {http.StatusBadRequest, "Invalid risk rule ID"},
+	ErrRiskRuleNotFound:        {http.StatusNotFound, "Risk rule not found"},
+	ErrDuplicateRiskRule:       {http.StatusConflict, "Duplicate risk rule name"},
+	ErrInvalidBlocklistID:      {http.StatusBadRequest, "Invalid blocklist entry ID"},
+	ErrBlocklistEntryNotFound:  {http.StatusNotFound, "Blocklist entry not found"},
+	ErrDuplicateBlocklistEntry: {http.StatusConflict, "Duplicate blocklist entry"},
+	ErrInvalidReviewID:         {http.StatusBadRequest, "Invalid review ID"},
+	ErrReviewNotFound:          {http.StatusNotFound, "Review not found"},
+	ErrReviewAlreadyClaimed:    {http.StatusConflict, "Review claimed by another reviewer"},
+	ErrReviewNotClaimed:        {http.StatusConflict, "Review not claimed by this reviewer"},
+	ErrReviewDecided:           {http.StatusConflict, "Review already decided"},
+	ErrPaymentNotInReview:      {http.StatusConflict, "Payment not held for review"},
+}
+
+// Definition returns the catalog entry for c. Unknown codes are treated as internal errors.
+func (c ErrorCode) Definition() ErrorDefinition {
+	if d, ok := errorCatalog[c]; ok {
+		return d
+	}
+	return errorCatalog[ErrInternal]
+}
+
+// Status returns the HTTP status for c.
+func (c ErrorCode) Status() int {
+	return c.Definition().Status
+}
+
+// ErrorCodeForStatus maps a bare HTTP status (e.g. from Echo's router) to a code.
+func ErrorCodeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrInvalidRequest
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrForbidden
+	case http.StatusNotFound:
+		return ErrRouteNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrMethodNotAllowed
+	case http.StatusRequestEntityTooLarge:
+		return ErrRequestTooLarge
+	case http.StatusUnsupportedMediaType:
+		return ErrUnsupportedMediaType
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	case http.StatusServiceUnavailable:
+		return ErrServiceUnavailable
+	}
+	if status >= 400 && status < 500 {
+		return ErrInvalidRequest
+	}
+	return ErrInternal
+}
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event tells subscribers that something happened to a resource, e.g. a
+// dispute changing state. Data is the resource as it stood afterwards.
+type Event struct {
+	ID        uuid.UUID   `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+func NewEvent(eventType string, data interface{}) *Event {
+	return &Event{ID: uuid.New(), Type: eventType, CreatedAt: time.Now().UTC(), Data: data}
+}
+
+// EventPublisher hands events to whoever relays them to merchants, such as a
+// webhook sender subscribed to the events queue.
+type EventPublisher interface {
+	PublishEvent(ctx context.Context, event *Event) error
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// FeeSchedule prices the fee charged to the merchant for a successful
+// payment. A schedule applies to one currency and, optionally, one payment
+// method and one merchant; when several apply, the one naming the merchant
+// wins, then the one naming the method, then the most recent.
+type FeeSchedule struct {
+	ID       uuid.UUID `json:"id"`
+	Currency string    `json:"currency"`
+	// PaymentMethod is empty for a schedule covering every method.
+	PaymentMethod PaymentMethodType `json:"payment_method,omitempty"`
+	// MerchantID is matched against the payment's merchant_id metadata and is
+	// empty for a schedule covering every merchant.
+	MerchantID string `json:"merchant_id,omitempty"`
+	// Percentage is the percent of the amount charged, e.g. 2.9 for 2.9%.
+	Percentage  float64  `json:"percentage"`
+	FixedAmount float64  `json:"fixed_amount"`
+	MinFee      *float64 `json:"min_fee,omitempty"`
+	MaxFee      *float64 `json:"max_fee,omitempty"`
+	// EffectiveFrom and EffectiveTo bound when the schedule is in force.
+	// EffectiveTo is exclusive and nil while the schedule has no end.
+	EffectiveFrom time.Time  `json:"effective_from"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Compute returns the fee on amount: the percentage plus the fixed amount,
+// held within the caps and never more than amount itself, rounded to cents.
+func (s *FeeSchedule) Compute(amount float64) float64 {
+	fee := amount*s.Percentage/100 + s.FixedAmount
+	if s.MinFee != nil && fee < *s.MinFee {
+		fee = *s.MinFee
+	}
+	if s.MaxFee != nil && fee > *s.MaxFee {
+		fee = *s.MaxFee
+	}
+	if fee > amount {
+		fee = amount
+	}
+	return math.Round(fee*100) / 100
+}
+
+type FeeScheduleRequest struct {
+	Currency      string            `json:"currency"`
+	PaymentMethod PaymentMethodType `json:"payment_method,omitempty"`
+	MerchantID    string            `json:"merchant_id,omitempty"`
+	Percentage    float64           `json:"percentage"`
+	FixedAmount   float64           `json:"fixed_amount"`
+	MinFee        *float64          `json:"min_fee,omitempty"`
+	MaxFee        *float64          `json:"max_fee,omitempty"`
+	// EffectiveFrom defaults to now.
+	EffectiveFrom *time.Time `json:"effective_from,omitempty"`
+	EffectiveTo   *time.Time `json:"effective_to,omitempty"`
+}
+
+func (fr FeeScheduleRequest) Validate() error {
+	return validation.ValidateStruct(&fr,
+		validation.Field(&fr.Currency, validation.Required.Error("currency is required"), validation.In("ETB", "USD")),
+		validation.Field(&fr.PaymentMethod, validation.In(MethodCard, MethodBankTransfer, MethodMobileMoney, MethodWallet)),
+		validation.Field(&fr.MerchantID, validation.Length(1, MaxMetadataValueLength)),
+		validation.Field(&fr.Percentage, validation.Min(0.0), validation.Max(100.0)),
+		validation.Field(&fr.FixedAmount, validation.Min(0.0)),
+		validation.Field(&fr.MinFee, validation.Min(0.0)),
+		validation.Field(&fr.MaxFee, validation.Min(0.0), validation.By(func(interface{}) error {
+			if fr.MinFee != nil && fr.MaxFee != nil && *fr.MaxFee < *fr.MinFee {
+				return errors.New("max_fee must not be less than min_fee")
+			}
+			return nil
+		})),
+		validation.Field(&fr.EffectiveTo, validation.By(func(interface{}) error {
+			if fr.EffectiveTo == nil {
+				return nil
+			}
+			from := time.Now()
+			if fr.EffectiveFrom != nil {
+				from = *fr.EffectiveFrom
+			}
+			if !fr.EffectiveTo.After(from) {
+				return errors.New("effective_to must be after effective_from")
+			}
+			return nil
+		})))
+}
+
+type FeeScheduleList struct {
+	Data   []FeeSchedule `json:"data"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+type FeeRepo interface {
+	// CreateFeeSchedule inserts schedule and fills in the generated fields.
+	CreateFeeSchedule(ctx context.Context, schedule *FeeSchedule) error
+	GetFeeScheduleByID(ctx context.Context, id uuid.UUID) (*FeeSchedule, error)
+	ListFeeSchedules(ctx context.Context, page Page) ([]FeeSchedule, error)
+	// FindFeeSchedule returns the schedule in force at for payment, or
+	// ErrNotFound if none applies.
+	FindFeeSchedule(ctx context.Context, payment *Payment, at time.Time) (*FeeSchedule, error)
+}
+
+type FeeService interface {
+	CreateFeeSchedule(ctx context.Context, fr *FeeScheduleRequest) (*FeeSchedule, error)
+	GetFeeScheduleByID(ctx context.Context, id string) (*FeeSchedule, error)
+	ListFeeSchedules(ctx context.Context, page Page) (*FeeScheduleList, error)
+}
+
+type FeeHandler interface {
+	CreateFeeSchedule(c echo.Context) error
+	GetFeeScheduleByID(c echo.Context) error
+	ListFeeSchedules(c echo.Context) error
+}
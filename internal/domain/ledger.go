@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AccountType string
+
+const (
+	AccountAsset     AccountType = "asset"
+	AccountLiability AccountType = "liability"
+	AccountRevenue   AccountType = "revenue"
+	AccountExpense   AccountType = "expense"
+)
+
+// Ledger accounts. Each exists once per currency and is created on first use.
+const (
+	// AccountProviderReceivable holds captured funds the providers have yet
+	// to settle to us.
+	AccountProviderReceivable = "provider_receivable"
+	// AccountCash holds funds providers have settled to our bank account.
+	AccountCash = "cash"
+	// AccountMerchantPayable holds what we owe the merchant.
+	AccountMerchantPayable = "merchant_payable"
+	// AccountFeeRevenue holds the fees charged to the merchant.
+	AccountFeeRevenue = "fee_revenue"
+)
+
+// LedgerAccountTypes gives the type of every ledger account.
+var LedgerAccountTypes = map[string]AccountType{
+	AccountProviderReceivable: AccountAsset,
+	AccountCash:               AccountAsset,
+	AccountMerchantPayable:    AccountLiability,
+	AccountFeeRevenue:         AccountRevenue,
+}
+
+type EntryKind string
+
+const (
+	EntryPaymentCaptured EntryKind = "payment_captured"
+	EntryRefund          EntryKind = "refund"
+	EntryFee             EntryKind = "fee"
+	EntryPayout          EntryKind = "payout"
+)
+
+// JournalEntry records one money movement. Entries are append-only; a
+// mistake is corrected by posting another entry.
+type JournalEntry struct {
+	ID          uuid.UUID  `json:"id"`
+	Kind        EntryKind  `json:"kind"`
+	PaymentID   *uuid.UUID `json:"payment_id,omitempty"`
+	Description string     `json:"description"`
+	Postings    []Posting  `json:"postings"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Posting moves Amount into (debit, positive) or out of (credit, negative)
+// an account.
+type Posting struct {
+	ID       uuid.UUID `json:"id"`
+	Account  string    `json:"account"`
+	Currency string    `json:"currency"`
+	Amount   float64   `json:"amount"`
+}
+
+// Validate checks that the entry is balanced: its postings sum to zero in
+// every currency.
+func (e JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return errors.New("a journal entry needs at least two postings")
+	}
+	totals := make(map[string]float64)
+	for _, p := range e.Postings {
+		if _, ok := LedgerAccountTypes[p.Account]; !ok {
+			return fmt.Errorf("unknown ledger account %q", p.Account)
+		}
+		if cents(p.Amount) == 0 {
+			return fmt.Errorf("posting to %s has no amount", p.Account)
+		}
+		totals[p.Currency] += p.Amount
+	}
+	for currency, total := range totals {
+		if cents(total) != 0 {
+			return fmt.Errorf("postings in %s sum to %.2f, not zero", currency, total)
+		}
+	}
+	return nil
+}
+
+func cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// transfer builds an entry that moves amount from one account to another.
+func transfer(kind EntryKind, paymentID *uuid.UUID, description, currency string, amount float64, debit, credit string) *JournalEntry {
+	return &JournalEntry{
+		Kind:        kind,
+		PaymentID:   paymentID,
+		Description: description,
+		Postings: []Posting{
+			{Account: debit, Currency: currency, Amount: amount},
+			{Account: credit, Currency: currency, Amount: -amount},
+		},
+	}
+}
+
+// NewCaptureEntry records a successful payment: the provider owes us the
+// amount and we owe it to the merchant.
+func NewCaptureEntry(p *Payment) *JournalEntry {
+	return transfer(EntryPaymentCaptured, &p.ID, "Payment "+p.Reference+" captured",
+		p.Currency, p.Amount, AccountProviderReceivable, AccountMerchantPayable)
+}
+
+// NewRefundEntry records amount of a payment returned to the payer.
+func NewRefundEntry(p *Payment, amount float64) *JournalEntry {
+	return transfer(EntryRefund, &p.ID, "Payment "+p.Reference+" refunded",
+		p.Currency, amount, AccountMerchantPayable, AccountProviderReceivable)
+}
+
+// NewChargebackEntry records a dispute lost to the payer. The disputed
+// amount is returned like a refund, so an unsettled payment is paid out
+// without it.
+func NewChargebackEntry(p *Payment, d *Dispute) *JournalEntry {
+	entry := NewRefundEntry(p, d.Amount)
+	entry.Description = "Payment " + p.Reference + " charged back"
+	return entry
+}
+
+// NewFeeEntry records a fee charged to the merchant for a payment.
+func NewFeeEntry(p *Payment, fee float64) *JournalEntry {
+	return transfer(EntryFee, &p.ID, "Fee on payment "+p.Reference,
+		p.Currency, fee, AccountMerchantPayable, AccountFeeRevenue)
+}
+
+// NewPayoutEntry records funds paid out to the merchant.
+func NewPayoutEntry(currency string, amount float64, description string) *JournalEntry {
+	return transfer(EntryPayout, nil, description, currency, amount, AccountMerchantPayable, AccountCash)
+}
+
+// AccountBalance is an account's balance in its normal direction: debits
+// less credits for assets and expenses, credits less debits otherwise.
+type AccountBalance struct {
+	Account  string      `json:"account"`
+	Type     AccountType `json:"type"`
+	Currency string      `json:"currency"`
+	Balance  float64     `json:"balance"`
+}
+
+type LedgerBalances struct {
+	Data []AccountBalance `json:"data"`
+}
+
+// UnbalancedEntry is a journal entry whose postings in Currency do not sum
+// to zero. An entry without postings is reported with an empty Currency.
+type UnbalancedEntry struct {
+	JournalEntryID uuid.UUID `json:"journal_entry_id"`
+	Currency       string    `json:"currency,omitempty"`
+	Total          float64   `json:"total"`
+}
+
+// IntegrityReport is the result of checking the ledger.
+type IntegrityReport struct {
+	OK bool `json:"ok"`
+	// Unbalanced lists entries whose postings do not sum to zero.
+	Unbalanced []UnbalancedEntry `json:"unbalanced"`
+	// UnrecordedPayments lists successful payments without a capture entry.
+	UnrecordedPayments []uuid.UUID `json:"unrecorded_payments"`
+	CheckedAt          time.Time   `json:"checked_at"`
+}
+
+type LedgerEntryList struct {
+	Data []JournalEntry `json:"data"`
+}
+
+type LedgerRepo interface {
+	// PostJournalEntry stores entry and its postings, creating the accounts
+	// it posts to as needed. The caller runs it inside a transaction.
+	PostJournalEntry(ctx context.Context, entry *JournalEntry) error
+	ListJournalEntriesByPayment(ctx context.Context, paymentID uuid.UUID) ([]JournalEntry, error)
+	ListBalances(ctx context.Context) ([]AccountBalance, error)
+	// ListUnbalancedEntries returns up to limit entries that fail to balance.
+	ListUnbalancedEntries(ctx context.Context, limit int) ([]UnbalancedEntry, error)
+	// ListUnrecordedPayments returns up to limit successful payments without
+	// a capture entry.
+	ListUnrecordedPayments(ctx context.Context, limit int) ([]uuid.UUID, error)
+}
+
+type LedgerService interface {
+	GetBalances(ctx context.Context) (*LedgerBalances, error)
+	ListPaymentEntries(ctx context.Context, paymentID string) (*LedgerEntryList, error)
+	CheckIntegrity(ctx context.Context) (*IntegrityReport, error)
+}
+
+type LedgerHandler interface {
+	GetBalances(c echo.Context) error
+	ListPaymentEntries(c echo.Context) error
+	CheckIntegrity(c echo.Context) error
+}
@@ -0,0 +1,18 @@
+package domain
+
+import "context"
+
+type merchantKey struct{}
+
+// WithMerchant returns a copy of ctx carrying the merchant whose API key
+// authenticated the request.
+func WithMerchant(ctx context.Context, merchantID string) context.Context {
+	return context.WithValue(ctx, merchantKey{}, merchantID)
+}
+
+// MerchantFromContext returns the authenticated merchant stored in ctx, if
+// any.
+func MerchantFromContext(ctx context.Context) (string, bool) {
+	merchantID, ok := ctx.Value(merchantKey{}).(string)
+	return merchantID, ok
+}
@@ -0,0 +1,65 @@
+package domain
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MerchantMetadataKey is the payment metadata key naming the merchant a
+// payment is for. Provider routing rules and fee schedules match against it.
+const MerchantMetadataKey = "merchant_id"
+
+const (
+	MaxMetadataKeys        = 50
+	MaxMetadataKeyLength   = 40
+	MaxMetadataValueLength = 500
+)
+
+// Metadata is free-form merchant data attached to a payment, e.g. an order ID
+// or sales channel. Nested structures such as a cart should be JSON-encoded
+// into a single value.
+type Metadata map[string]string
+
+func (m Metadata) Validate() error {
+	if len(m) > MaxMetadataKeys {
+		return fmt.Errorf("metadata cannot have more than %d keys", MaxMetadataKeys)
+	}
+	for k, v := range m {
+		if k == "" || len(k) > MaxMetadataKeyLength {
+			return fmt.Errorf("metadata keys must be 1 to %d characters", MaxMetadataKeyLength)
+		}
+		if len(v) > MaxMetadataValueLength {
+			return fmt.Errorf("metadata value for %q cannot be longer than %d characters", k, MaxMetadataValueLength)
+		}
+	}
+	return nil
+}
+
+// PaymentFilter narrows a payment listing. A payment matches when it belongs
+// to MerchantID and its metadata contains every key/value pair in Metadata.
+type PaymentFilter struct {
+	// MerchantID is set from the caller's API key, not the query string.
+	MerchantID string
+	Metadata   Metadata
+}
+
+// ParsePaymentFilter reads metadata[key]=value query values.
+func ParsePaymentFilter(query url.Values) (PaymentFilter, error) {
+	f := PaymentFilter{Metadata: Metadata{}}
+	for param, values := range query {
+		key, ok := strings.CutPrefix(param, "metadata[")
+		if !ok {
+			continue
+		}
+		key, ok = strings.CutSuffix(key, "]")
+		if !ok || len(values) != 1 {
+			return f, NewError(ErrInvalidRequest, "invalid metadata filter", "metadata filters must be given once as metadata[key]=value", nil, map[string]interface{}{"param": param})
+		}
+		f.Metadata[key] = values[0]
+	}
+	if err := f.Metadata.Validate(); err != nil {
+		return f, NewError(ErrInvalidRequest, "invalid metadata filter", err.Error(), err, nil)
+	}
+	return f, nil
+}
@@ -0,0 +1,34 @@
+package domain
+
+import "strconv"
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// Page selects a window of a listing.
+type Page struct {
+	Limit  int
+	Offset int
+}
+
+// ParsePage reads limit/offset query values, applying defaults and bounds.
+func ParsePage(limit, offset string) (Page, error) {
+	p := Page{Limit: DefaultPageLimit}
+	if limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 {
+			return p, NewError(ErrInvalidRequest, "invalid limit", "limit must be a positive integer", err, map[string]interface{}{"limit": limit})
+		}
+		p.Limit = min(n, MaxPageLimit)
+	}
+	if offset != "" {
+		n, err := strconv.Atoi(offset)
+		if err != nil || n < 0 {
+			return p, NewError(ErrInvalidRequest, "invalid offset", "offset must be a non-negative integer", err, map[string]interface{}{"offset": offset})
+		}
+		p.Offset = n
+	}
+	return p, nil
+}
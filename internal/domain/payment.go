@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
@@ -15,47 +16,119 @@ const (
 	StatusPending PaymentStatus = "PENDING"
 	StatusSuccess PaymentStatus = "SUCCESS"
 	StatusFailed  PaymentStatus = "FAILED"
+	// StatusInReview payments are held until a reviewer approves them; the
+	// worker skips them.
+	StatusInReview PaymentStatus = "IN_REVIEW"
+	// StatusCanceled payments were stopped before the worker charged them;
+	// the worker skips them.
+	StatusCanceled PaymentStatus = "CANCELED"
 )
 
 type Payment struct {
-	ID        uuid.UUID     `json:"id"`
-	Amount    float64       `json:"amount" validate:"required,gt=0"`
-	Currency  string        `json:"currency" validate:"required,oneof=ETB USD"`
-	Reference string        `json:"reference" validate:"required"`
-	Status    PaymentStatus `json:"status"`
-	CreatedAt time.Time     `json:"created_at"`
-	UpdatedAt time.Time     `json:"updated_at"`
+	ID         uuid.UUID     `json:"id"`
+	Amount     float64       `json:"amount" validate:"required,gt=0"`
+	Currency   string        `json:"currency" validate:"required,oneof=ETB USD"`
+	Reference  string        `json:"reference" validate:"required"`
+	Status     PaymentStatus `json:"status"`
+	CustomerID *uuid.UUID    `json:"customer_id,omitempty"`
+	Metadata   Metadata      `json:"metadata"`
+	// PaymentMethod is nil for payments created before methods were recorded.
+	// It is written out masked; see MaskedPaymentMethod.
+	PaymentMethod *PaymentMethod `json:"payment_method,omitempty"`
+	// PaymentLinkID is set on payments made through a payment link.
+	PaymentLinkID *uuid.UUID `json:"payment_link_id,omitempty"`
+	// Provider is the provider that produced the final status.
+	Provider string `json:"provider,omitempty"`
+	// FeeAmount is what the merchant is charged for the payment and NetAmount
+	// what they receive. Both are set once the payment succeeds.
+	FeeAmount *float64 `json:"fee_amount,omitempty"`
+	NetAmount *float64 `json:"net_amount,omitempty"`
+	// ClientIP is the address the payment was created from, if known. It and
+	// Risk are never shown to merchants; operators read them as a
+	// PaymentRisk.
+	ClientIP string `json:"-"`
+	// Risk is nil for payments that were not screened.
+	Risk *RiskAssessment `json:"-"`
+	// Attempts lists every provider call, in order. Only filled in when a
+	// single payment is fetched.
+	Attempts  []PaymentAttempt `json:"attempts,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
 }
 type PaymentRequest struct {
 	Amount    float64 `json:"amount" validate:"required,gt=0"`
 	Currency  string  `json:"currency" validate:"required,oneof=ETB USD"`
 	Reference string  `json:"reference" validate:"required"`
+	// CustomerID links the payment to an existing customer.
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"`
+	// Payer creates (or, by external_id, reuses) a customer inline. Mutually exclusive with CustomerID.
+	Payer *CustomerRequest `json:"payer,omitempty"`
+	// Metadata is stored with the payment and can be used to filter listings.
+	Metadata Metadata `json:"metadata,omitempty"`
+	// PaymentMethod says how the payer pays and carries the method's details.
+	PaymentMethod *PaymentMethod `json:"payment_method,omitempty"`
+	// ClientIP is set by the handler from the request for screening.
+	ClientIP string `json:"-"`
 }
 
 func (pr PaymentRequest) Validate() error {
 	return validation.ValidateStruct(&pr,
 		validation.Field(&pr.Amount, validation.Required.Error("payment amount is required"), validation.Min(0.0).Error("payment amount must be greater than 0.0")),
 		validation.Field(&pr.Currency, validation.Required.Error("currency is required"), validation.In("ETB", "USD")),
-		validation.Field(&pr.Reference, validation.Required.Error("payment reference is required")))
+		validation.Field(&pr.Reference, validation.Required.Error("payment reference is required")),
+		validation.Field(&pr.Payer, validation.By(func(interface{}) error {
+			if pr.Payer != nil && pr.CustomerID != nil {
+				return errors.New("payer cannot be combined with customer_id")
+			}
+			return nil
+		})),
+		validation.Field(&pr.Metadata),
+		validation.Field(&pr.PaymentMethod))
+}
+
+type PaymentList struct {
+	Data   []Payment `json:"data"`
+	Limit  int       `json:"limit"`
+	Offset int       `json:"offset"`
 }
 
 type PaymentRepo interface {
+	// CreatePayment inserts payment and fills in the generated fields.
 	CreatePayment(ctx context.Context, payment *Payment) error
-	GetPaymentByID(ctx context.Context, id string) (*Payment, error)
+	GetPaymentByID(ctx context.Context, id uuid.UUID) (*Payment, error)
 	GetPaymentByReference(ctx context.Context, reference string) (*Payment, error)
-	UpdatePaymentStatus(ctx context.Context, id string, status PaymentStatus) error
+	UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status PaymentStatus) (*Payment, error)
+	// UpdatePaymentResult sets the final status along with the provider that produced it.
+	UpdatePaymentResult(ctx context.Context, id uuid.UUID, status PaymentStatus, provider string) (*Payment, error)
 	// For row-level locking and idempotency
-	GetPaymentByIDWithLock(ctx context.Context, id string) (*Payment, error)
+	GetPaymentByIDWithLock(ctx context.Context, id uuid.UUID) (*Payment, error)
+	ListPaymentsByCustomer(ctx context.Context, customerID uuid.UUID, page Page) ([]Payment, error)
+	ListPayments(ctx context.Context, filter PaymentFilter, page Page) ([]Payment, error)
+	ListPaymentsByLink(ctx context.Context, linkID uuid.UUID, page Page) ([]Payment, error)
+	// ListPendingPayments returns up to limit pending payments, oldest first.
+	ListPendingPayments(ctx context.Context, filter PendingPaymentFilter, limit int) ([]Payment, error)
+	// SetPaymentMethod records the method of a payment that is still pending
+	// or held for review. It returns ErrNotFound for any other payment.
+	SetPaymentMethod(ctx context.Context, id uuid.UUID, method *PaymentMethod) (*Payment, error)
+	// SetPaymentFee records the fee of a payment along with its net amount.
+	SetPaymentFee(ctx context.Context, id uuid.UUID, fee float64) (*Payment, error)
+	CreatePaymentAttempt(ctx context.Context, attempt *PaymentAttempt) error
+	ListPaymentAttempts(ctx context.Context, paymentID uuid.UUID) ([]PaymentAttempt, error)
 }
 
 type PaymentService interface {
 	CreatePayment(ctx context.Context, pr *PaymentRequest) (*Payment, error)
 	GetPaymentByID(ctx context.Context, id string) (*Payment, error)
+	ListPayments(ctx context.Context, filter PaymentFilter, page Page) (*PaymentList, error)
 	ProcessPayment(ctx context.Context, id string) error
+	// CancelPayment moves a pending payment to CANCELED so the worker skips it.
+	CancelPayment(ctx context.Context, id string) (*Payment, error)
 }
 type PaymentHandler interface {
 	CreatePayment(c echo.Context) error
 	GetPaymentByID(c echo.Context) error
+	ListPayments(c echo.Context) error
+	CancelPayment(c echo.Context) error
 }
 type MessagePublisher interface {
 	PublishPaymentCreated(ctx context.Context, paymentID string) error
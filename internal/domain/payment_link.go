@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type PaymentLinkState string
+
+const (
+	LinkActive    PaymentLinkState = "active"
+	LinkInactive  PaymentLinkState = "inactive"
+	LinkExpired   PaymentLinkState = "expired"
+	LinkExhausted PaymentLinkState = "exhausted"
+)
+
+// PaymentLink is a shareable URL through which payers pay the merchant
+// without an API integration. Every use creates a payment and a checkout
+// session for it.
+type PaymentLink struct {
+	ID   uuid.UUID `json:"id"`
+	Slug string    `json:"slug"`
+	// URL is the page to share with payers.
+	URL string `json:"url"`
+	// Amount is nil when the payer enters the amount.
+	Amount      *float64   `json:"amount,omitempty"`
+	Currency    string     `json:"currency"`
+	Description string     `json:"description,omitempty"`
+	MaxUses     *int       `json:"max_uses,omitempty"`
+	Uses        int        `json:"uses"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Active      bool       `json:"active"`
+	// State says whether the link can be used right now.
+	State      PaymentLinkState `json:"state"`
+	SuccessURL string           `json:"success_url,omitempty"`
+	CancelURL  string           `json:"cancel_url,omitempty"`
+	// Metadata is copied to every payment made through the link.
+	Metadata Metadata `json:"metadata"`
+	// Collected totals the successful payments made through the link. Only
+	// filled in when a single link is fetched.
+	Collected *PaymentLinkTotals `json:"collected,omitempty"`
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// StateAt reports whether the link can be used at now and, if not, why.
+func (l PaymentLink) StateAt(now time.Time) PaymentLinkState {
+	switch {
+	case !l.Active:
+		return LinkInactive
+	case l.ExpiresAt != nil && !now.Before(*l.ExpiresAt):
+		return LinkExpired
+	case l.MaxUses != nil && l.Uses >= *l.MaxUses:
+		return LinkExhausted
+	}
+	return LinkActive
+}
+
+type PaymentLinkTotals struct {
+	Count  int     `json:"count"`
+	Amount float64 `json:"amount"`
+}
+
+type PaymentLinkRequest struct {
+	// Amount fixes the amount to pay. Leave it out to let the payer enter it.
+	Amount      *float64   `json:"amount,omitempty"`
+	Currency    string     `json:"currency"`
+	Description string     `json:"description,omitempty"`
+	MaxUses     *int       `json:"max_uses,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	// SuccessURL and CancelURL are where payers are sent after checkout. They
+	// default to the link page itself.
+	SuccessURL string   `json:"success_url,omitempty"`
+	CancelURL  string   `json:"cancel_url,omitempty"`
+	Metadata   Metadata `json:"metadata,omitempty"`
+}
+
+func (lr PaymentLinkRequest) Validate() error {
+	return validation.ValidateStruct(&lr,
+		validation.Field(&lr.Amount, validation.By(func(interface{}) error {
+			if lr.Amount != nil && *lr.Amount <= 0 {
+				return errors.New("payment amount must be greater than 0.0")
+			}
+			return nil
+		})),
+		validation.Field(&lr.Currency, validation.Required.Error("currency is required"), validation.In("ETB", "USD")),
+		validation.Field(&lr.Description, validation.Length(0, 500)),
+		validation.Field(&lr.MaxUses, validation.By(func(interface{}) error {
+			if lr.MaxUses != nil && *lr.MaxUses < 1 {
+				return errors.New("must be at least 1")
+			}
+			return nil
+		})),
+		validation.Field(&lr.ExpiresAt, validation.By(func(interface{}) error {
+			if lr.ExpiresAt != nil && !lr.ExpiresAt.After(time.Now()) {
+				return errors.New("must be in the future")
+			}
+			return nil
+		})),
+		validation.Field(&lr.SuccessURL, is.URL),
+		validation.Field(&lr.CancelURL, is.URL),
+		validation.Field(&lr.Metadata))
+}
+
+type PaymentLinkList struct {
+	Data   []PaymentLink `json:"data"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+}
+
+type PaymentLinkRepo interface {
+	// CreatePaymentLink inserts link and fills in the generated fields.
+	CreatePaymentLink(ctx context.Context, link *PaymentLink) error
+	GetPaymentLinkByID(ctx context.Context, id uuid.UUID) (*PaymentLink, error)
+	GetPaymentLinkBySlug(ctx context.Context, slug string) (*PaymentLink, error)
+	ListPaymentLinks(ctx context.Context, page Page) ([]PaymentLink, error)
+	SetPaymentLinkActive(ctx context.Context, id uuid.UUID, active bool) (*PaymentLink, error)
+	// UsePaymentLink counts a use of a link that is active, unexpired and
+	// below its max uses. It returns ErrNotFound for any other link.
+	UsePaymentLink(ctx context.Context, id uuid.UUID) (*PaymentLink, error)
+	// ReleasePaymentLink gives back a use whose payment was never made. It
+	// returns ErrNotFound for a link with no uses counted.
+	ReleasePaymentLink(ctx context.Context, id uuid.UUID) (*PaymentLink, error)
+	GetPaymentLinkTotals(ctx context.Context, id uuid.UUID) (*PaymentLinkTotals, error)
+}
+
+type PaymentLinkService interface {
+	CreatePaymentLink(ctx context.Context, lr *PaymentLinkRequest) (*PaymentLink, error)
+	GetPaymentLinkByID(ctx context.Context, id string) (*PaymentLink, error)
+	GetPaymentLinkBySlug(ctx context.Context, slug string) (*PaymentLink, error)
+	ListPaymentLinks(ctx context.Context, page Page) (*PaymentLinkList, error)
+	SetPaymentLinkActive(ctx context.Context, id string, active bool) (*PaymentLink, error)
+	ListPaymentLinkPayments(ctx context.Context, id string, page Page) (*PaymentList, error)
+	// PayPaymentLink uses the link to create a payment and returns the
+	// checkout session the payer completes it on. amount is only read for
+	// links without a fixed amount. clientIP is the payer's address, used in
+	// fraud screening.
+	PayPaymentLink(ctx context.Context, slug string, amount float64, clientIP string) (*CheckoutSession, error)
+}
+
+type PaymentLinkHandler interface {
+	CreatePaymentLink(c echo.Context) error
+	GetPaymentLinkByID(c echo.Context) error
+	ListPaymentLinks(c echo.Context) error
+	ActivatePaymentLink(c echo.Context) error
+	DeactivatePaymentLink(c echo.Context) error
+	ListPaymentLinkPayments(c echo.Context) error
+	ShowPage(c echo.Context) error
+	Pay(c echo.Context) error
+}
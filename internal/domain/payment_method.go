@@ -0,0 +1,171 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+)
+
+type PaymentMethodType string
+
+const (
+	MethodCard         PaymentMethodType = "card"
+	MethodBankTransfer PaymentMethodType = "bank_transfer"
+	MethodMobileMoney  PaymentMethodType = "mobile_money"
+	MethodWallet       PaymentMethodType = "wallet"
+)
+
+var (
+	last4Pattern         = regexp.MustCompile(`^[0-9]{4}$`)
+	accountNumberPattern = regexp.MustCompile(`^[0-9]{6,20}$`)
+)
+
+// PaymentMethod describes how the payer pays. Exactly one of the detail
+// objects is set, the one named by Type.
+type PaymentMethod struct {
+	Type         PaymentMethodType    `json:"type"`
+	Card         *CardDetails         `json:"card,omitempty"`
+	BankTransfer *BankTransferDetails `json:"bank_transfer,omitempty"`
+	MobileMoney  *MobileMoneyDetails  `json:"mobile_money,omitempty"`
+	Wallet       *WalletDetails       `json:"wallet,omitempty"`
+}
+
+// CardDetails references a card tokenized by the card vault. Raw card numbers
+// never reach this service.
+type CardDetails struct {
+	Token    string `json:"token"`
+	Brand    string `json:"brand,omitempty"`
+	Last4    string `json:"last4"`
+	ExpMonth int    `json:"exp_month"`
+	ExpYear  int    `json:"exp_year"`
+}
+
+type BankTransferDetails struct {
+	BankCode      string `json:"bank_code"`
+	AccountNumber string `json:"account_number"`
+	AccountName   string `json:"account_name,omitempty"`
+}
+
+// MaskedPaymentMethod is how a payment method is shown in responses, events
+// and audit snapshots. The card token and the full account number are only
+// kept in storage, where the worker reads them to charge the payer.
+type MaskedPaymentMethod struct {
+	Type         PaymentMethodType   `json:"type"`
+	Card         *MaskedCard         `json:"card,omitempty"`
+	BankTransfer *MaskedBankTransfer `json:"bank_transfer,omitempty"`
+	MobileMoney  *MobileMoneyDetails `json:"mobile_money,omitempty"`
+	Wallet       *WalletDetails      `json:"wallet,omitempty"`
+}
+
+type MaskedCard struct {
+	Brand string `json:"brand,omitempty"`
+	Last4 string `json:"last4"`
+}
+
+type MaskedBankTransfer struct {
+	BankCode string `json:"bank_code"`
+	// AccountNumber shows only the last 4 digits, e.g. ******6789
+	AccountNumber string `json:"account_number"`
+}
+
+// Masked returns the method without the card token or the full account
+// number.
+func (pm PaymentMethod) Masked() MaskedPaymentMethod {
+	m := MaskedPaymentMethod{Type: pm.Type, MobileMoney: pm.MobileMoney, Wallet: pm.Wallet}
+	if pm.Card != nil {
+		m.Card = &MaskedCard{Brand: pm.Card.Brand, Last4: pm.Card.Last4}
+	}
+	if pm.BankTransfer != nil {
+		m.BankTransfer = &MaskedBankTransfer{BankCode: pm.BankTransfer.BankCode, AccountNumber: maskAccountNumber(pm.BankTransfer.AccountNumber)}
+	}
+	return m
+}
+
+// MarshalJSON writes the masked method, so the card token and account
+// number never leave the service in a response, event or audit snapshot.
+func (pm PaymentMethod) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pm.Masked())
+}
+
+func maskAccountNumber(number string) string {
+	if len(number) <= 4 {
+		return strings.Repeat("*", len(number))
+	}
+	return strings.Repeat("*", len(number)-4) + number[len(number)-4:]
+}
+
+type MobileMoneyDetails struct {
+	Provider string `json:"provider"`
+	// MSISDN is the payer's mobile number in E.164 format
+	MSISDN string `json:"msisdn"`
+}
+
+type WalletDetails struct {
+	Provider string `json:"provider"`
+	WalletID string `json:"wallet_id"`
+}
+
+func (pm PaymentMethod) Validate() error {
+	details := map[PaymentMethodType]bool{
+		MethodCard:         pm.Card != nil,
+		MethodBankTransfer: pm.BankTransfer != nil,
+		MethodMobileMoney:  pm.MobileMoney != nil,
+		MethodWallet:       pm.Wallet != nil,
+	}
+	return validation.ValidateStruct(&pm,
+		validation.Field(&pm.Type,
+			validation.Required.Error("payment method type is required"),
+			validation.In(MethodCard, MethodBankTransfer, MethodMobileMoney, MethodWallet).Error("must be one of card, bank_transfer, mobile_money, wallet"),
+			validation.By(func(interface{}) error {
+				for t, set := range details {
+					if set && t != pm.Type {
+						return errors.New("only the " + string(pm.Type) + " details may be given")
+					}
+				}
+				if _, known := details[pm.Type]; known && !details[pm.Type] {
+					return errors.New(string(pm.Type) + " details are required")
+				}
+				return nil
+			})),
+		validation.Field(&pm.Card),
+		validation.Field(&pm.BankTransfer),
+		validation.Field(&pm.MobileMoney),
+		validation.Field(&pm.Wallet))
+}
+
+func (c CardDetails) Validate() error {
+	now := time.Now()
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Token, validation.Required.Error("card token is required")),
+		validation.Field(&c.Last4, validation.Required, validation.Match(last4Pattern).Error("must be the last 4 digits of the card")),
+		validation.Field(&c.ExpMonth, validation.Required, validation.Min(1), validation.Max(12)),
+		validation.Field(&c.ExpYear, validation.Required, validation.By(func(interface{}) error {
+			if c.ExpYear < now.Year() || (c.ExpYear == now.Year() && c.ExpMonth < int(now.Month())) {
+				return errors.New("card has expired")
+			}
+			return nil
+		})))
+}
+
+func (b BankTransferDetails) Validate() error {
+	return validation.ValidateStruct(&b,
+		validation.Field(&b.BankCode, validation.Required, validation.Length(1, 32)),
+		validation.Field(&b.AccountNumber, validation.Required, validation.Match(accountNumberPattern).Error("must be 6 to 20 digits")),
+		validation.Field(&b.AccountName, validation.Length(0, 255)))
+}
+
+func (m MobileMoneyDetails) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Provider, validation.Required, validation.Length(1, 64)),
+		validation.Field(&m.MSISDN, validation.Required, validation.Match(phonePattern).Error("must be a valid MSISDN in E.164 format")))
+}
+
+func (w WalletDetails) Validate() error {
+	return validation.ValidateStruct(&w,
+		validation.Field(&w.Provider, validation.Required, validation.Length(1, 64)),
+		validation.Field(&w.WalletID, validation.Required, validation.Length(1, 255)))
+}
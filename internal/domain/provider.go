@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Errors returned by providers and routers.
+var (
+	// ErrProviderUnavailable marks a provider failure worth retrying elsewhere,
+	// e.g. a timeout or an outage. Routing fails over to the next candidate.
+	ErrProviderUnavailable = errors.New("provider unavailable")
+	// ErrCircuitOpen means a provider's circuit breaker rejected the call
+	// without trying it. It is always wrapped together with ErrProviderUnavailable.
+	ErrCircuitOpen = errors.New("circuit breaker open")
+	// ErrNoRoute means no routing rule matches the payment.
+	ErrNoRoute = errors.New("no provider route matches the payment")
+)
+
+// ChargeResult is a provider's definitive answer for a payment. A decline is a
+// result with StatusFailed, not an error.
+type ChargeResult struct {
+	Status    PaymentStatus
+	Reference string
+}
+
+// Provider is an acquirer or payment gateway that can charge a payment.
+type Provider interface {
+	Name() string
+	Charge(ctx context.Context, p *Payment) (*ChargeResult, error)
+}
+
+// Router selects the providers to try for a payment, in order of preference.
+type Router interface {
+	Route(p *Payment) ([]Provider, error)
+}
+
+type AttemptStatus string
+
+const (
+	AttemptSucceeded   AttemptStatus = "succeeded"
+	AttemptDeclined    AttemptStatus = "declined"
+	AttemptFailed      AttemptStatus = "failed"
+	AttemptUnavailable AttemptStatus = "unavailable"
+)
+
+// PaymentAttempt records one call to a provider while processing a payment.
+type PaymentAttempt struct {
+	ID                uuid.UUID     `json:"id"`
+	PaymentID         uuid.UUID     `json:"-"`
+	Provider          string        `json:"provider"`
+	Status            AttemptStatus `json:"status"`
+	ProviderReference string        `json:"provider_reference,omitempty"`
+	Error             string        `json:"error,omitempty"`
+	CreatedAt         time.Time     `json:"created_at"`
+}
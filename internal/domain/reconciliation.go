@@ -0,0 +1,202 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// MaxStatementSize caps the size of an uploaded statement in bytes.
+const MaxStatementSize = 10 << 20
+
+// StatementFormat names a statement file format a StatementParser reads.
+type StatementFormat string
+
+const (
+	// FormatCSV is a CSV file with a header row naming at least the
+	// reference, amount and currency columns and optionally a date column.
+	FormatCSV StatementFormat = "csv"
+	// FormatCamt053 is an ISO 20022 camt.053 bank-to-customer statement.
+	FormatCamt053 StatementFormat = "camt053"
+)
+
+// Statement is what the provider or bank reports as settled.
+type Statement struct {
+	// PeriodStart and PeriodEnd bound the statement; PeriodEnd is exclusive.
+	// Both are nil when the file does not say and no line carries a date.
+	PeriodStart *time.Time
+	PeriodEnd   *time.Time
+	Lines       []StatementLine
+}
+
+// StatementLine is one settled credit on a statement.
+type StatementLine struct {
+	// Reference is the payment reference or the provider's reference for it.
+	Reference string
+	Amount    float64
+	Currency  string
+	BookedAt  *time.Time
+}
+
+// StatementParser reads one statement format. Parsers are looked up by
+// format, so supporting a new provider file means adding one.
+type StatementParser interface {
+	Parse(r io.Reader) (*Statement, error)
+}
+
+type ReconciliationResult string
+
+const (
+	// ResultMatched lines agree with a successful payment.
+	ResultMatched ReconciliationResult = "matched"
+	// ResultMissingInternally lines have no successful payment behind them.
+	ResultMissingInternally ReconciliationResult = "missing_internally"
+	// ResultMissingAtProvider payments succeeded within the statement period
+	// but are not on the statement.
+	ResultMissingAtProvider ReconciliationResult = "missing_at_provider"
+	// ResultAmountMismatch lines settled a different amount or currency than
+	// the payment they match.
+	ResultAmountMismatch ReconciliationResult = "amount_mismatch"
+)
+
+// ReconciliationRun is the report of one imported statement.
+type ReconciliationRun struct {
+	ID     uuid.UUID       `json:"id"`
+	Format StatementFormat `json:"format"`
+	// Provider limits the payments expected on the statement; empty means
+	// every provider.
+	Provider               string     `json:"provider,omitempty"`
+	PeriodStart            *time.Time `json:"period_start,omitempty"`
+	PeriodEnd              *time.Time `json:"period_end,omitempty"`
+	LineCount              int        `json:"line_count"`
+	MatchedCount           int        `json:"matched_count"`
+	MissingInternallyCount int        `json:"missing_internally_count"`
+	MissingAtProviderCount int        `json:"missing_at_provider_count"`
+	AmountMismatchCount    int        `json:"amount_mismatch_count"`
+	CreatedAt              time.Time  `json:"created_at"`
+}
+
+// Count adds one item with result to the run's totals.
+func (r *ReconciliationRun) Count(result ReconciliationResult) {
+	switch result {
+	case ResultMatched:
+		r.MatchedCount++
+	case ResultMissingInternally:
+		r.MissingInternallyCount++
+	case ResultMissingAtProvider:
+		r.MissingAtProviderCount++
+	case ResultAmountMismatch:
+		r.AmountMismatchCount++
+	}
+}
+
+// ReconciliationItem is one statement line or one payment missing from the
+// statement. Items other than matched ones are exceptions until resolved.
+type ReconciliationItem struct {
+	ID        uuid.UUID            `json:"id"`
+	RunID     uuid.UUID            `json:"run_id"`
+	Result    ReconciliationResult `json:"result"`
+	Reference string               `json:"reference"`
+	// PaymentID is nil for lines without a payment.
+	PaymentID         *uuid.UUID `json:"payment_id,omitempty"`
+	StatementAmount   *float64   `json:"statement_amount,omitempty"`
+	StatementCurrency string     `json:"statement_currency,omitempty"`
+	BookedAt          *time.Time `json:"booked_at,omitempty"`
+	PaymentAmount     *float64   `json:"payment_amount,omitempty"`
+	PaymentCurrency   string     `json:"payment_currency,omitempty"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+	ResolutionNote    string     `json:"resolution_note,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// ParseReconciliationResult reads the result query value; nil matches every
+// item.
+func ParseReconciliationResult(query url.Values) (*ReconciliationResult, error) {
+	if !query.Has("result") {
+		return nil, nil
+	}
+	result := ReconciliationResult(query.Get("result"))
+	switch result {
+	case ResultMatched, ResultMissingInternally, ResultMissingAtProvider, ResultAmountMismatch:
+	default:
+		return nil, NewError(ErrInvalidRequest, "invalid result filter", "result must be one of matched, missing_internally, missing_at_provider or amount_mismatch", nil, map[string]interface{}{"result": result})
+	}
+	return &result, nil
+}
+
+type ResolveExceptionRequest struct {
+	Note string `json:"note"`
+}
+
+func (rr ResolveExceptionRequest) Validate() error {
+	return validation.ValidateStruct(&rr,
+		validation.Field(&rr.Note, validation.Required.Error("note is required"), validation.Length(1, 1000)))
+}
+
+type ReconciliationRunList struct {
+	Data   []ReconciliationRun `json:"data"`
+	Limit  int                 `json:"limit"`
+	Offset int                 `json:"offset"`
+}
+
+type ReconciliationItemList struct {
+	Data   []ReconciliationItem `json:"data"`
+	Limit  int                  `json:"limit"`
+	Offset int                  `json:"offset"`
+}
+
+// ReconciledPayment is the part of a payment reconciliation compares.
+type ReconciledPayment struct {
+	ID        uuid.UUID
+	Reference string
+	Amount    float64
+	Currency  string
+	Status    PaymentStatus
+}
+
+type ReconciliationRepo interface {
+	// FindPayment returns the payment whose reference, or whose provider
+	// reference on any attempt, is reference. It returns ErrNotFound if none.
+	FindPayment(ctx context.Context, reference string) (*ReconciledPayment, error)
+	// ListSuccessfulPayments lists payments that succeeded in [from, to),
+	// only those routed to provider unless it is empty.
+	ListSuccessfulPayments(ctx context.Context, provider string, from, to time.Time) ([]ReconciledPayment, error)
+	// CreateRun and CreateItem insert and fill in the generated fields.
+	CreateRun(ctx context.Context, run *ReconciliationRun) error
+	CreateItem(ctx context.Context, item *ReconciliationItem) error
+	GetRunByID(ctx context.Context, id uuid.UUID) (*ReconciliationRun, error)
+	ListRuns(ctx context.Context, page Page) ([]ReconciliationRun, error)
+	ListItems(ctx context.Context, runID uuid.UUID, result *ReconciliationResult, page Page) ([]ReconciliationItem, error)
+	GetItemByID(ctx context.Context, id uuid.UUID) (*ReconciliationItem, error)
+	// ListUnresolvedItems lists exceptions of every run not yet resolved,
+	// oldest first.
+	ListUnresolvedItems(ctx context.Context, page Page) ([]ReconciliationItem, error)
+	// ResolveItem returns ErrNotFound unless the item is an unresolved
+	// exception.
+	ResolveItem(ctx context.Context, id uuid.UUID, note string) (*ReconciliationItem, error)
+}
+
+type ReconciliationService interface {
+	// ImportStatement parses body as format and reconciles it against the
+	// payments, only those routed to provider unless it is empty.
+	ImportStatement(ctx context.Context, format StatementFormat, provider string, body io.Reader) (*ReconciliationRun, error)
+	ListRuns(ctx context.Context, page Page) (*ReconciliationRunList, error)
+	GetRunByID(ctx context.Context, id string) (*ReconciliationRun, error)
+	ListRunItems(ctx context.Context, id string, result *ReconciliationResult, page Page) (*ReconciliationItemList, error)
+	ListExceptions(ctx context.Context, page Page) (*ReconciliationItemList, error)
+	ResolveException(ctx context.Context, id string, rr *ResolveExceptionRequest) (*ReconciliationItem, error)
+}
+
+type ReconciliationHandler interface {
+	ImportStatement(c echo.Context) error
+	ListRuns(c echo.Context) error
+	GetRunByID(c echo.Context) error
+	ListRunItems(c echo.Context) error
+	ListExceptions(c echo.Context) error
+	ResolveException(c echo.Context) error
+}
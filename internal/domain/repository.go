@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// Errors returned by repositories. Implementations wrap the underlying driver
+// error so callers can match with errors.Is and still log the cause.
+var (
+	ErrNotFound             = errors.New("record not found")
+	ErrConflict             = errors.New("record conflicts with an existing one")
+	ErrInvalidReference     = errors.New("referenced record does not exist or is still referenced")
+	ErrSerializationFailure = errors.New("transaction could not be serialized")
+	ErrUnavailable          = errors.New("database unavailable")
+)
+
+// UnitOfWork gives access to repositories that share a single transaction
+// when obtained inside WithinTx.
+type UnitOfWork interface {
+	Payments() PaymentRepo
+	Customers() CustomerRepo
+	CheckoutSessions() CheckoutSessionRepo
+	PaymentLinks() PaymentLinkRepo
+	Plans() PlanRepo
+	Subscriptions() SubscriptionRepo
+	Ledger() LedgerRepo
+	Fees() FeeRepo
+	Settlements() SettlementRepo
+	Reconciliations() ReconciliationRepo
+	Disputes() DisputeRepo
+	Risk() RiskRepo
+	Reviews() ReviewRepo
+	Audit() AuditRepo
+	// WithinTx runs fn in a serializable transaction, committing if fn returns
+	// nil and rolling back otherwise. Repositories from the UnitOfWork passed
+	// to fn are bound to the transaction.
+	WithinTx(ctx context.Context, fn func(tx UnitOfWork) error) error
+}
@@ -0,0 +1,158 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ReviewStatus string
+
+const (
+	// ReviewOpen reviews wait in the queue for a reviewer to claim them.
+	ReviewOpen ReviewStatus = "open"
+	// ReviewClaimed reviews are being worked by ClaimedBy.
+	ReviewClaimed  ReviewStatus = "claimed"
+	ReviewApproved ReviewStatus = "approved"
+	ReviewDeclined ReviewStatus = "declined"
+)
+
+// Decided reports whether the review has been approved or declined.
+func (s ReviewStatus) Decided() bool {
+	return s == ReviewApproved || s == ReviewDeclined
+}
+
+// ReviewerSLA is recorded as the reviewer of a review declined for being
+// left untouched past its deadline.
+const ReviewerSLA = "sla"
+
+// EventReviewEscalated is published when an open review passes its
+// escalation deadline.
+const EventReviewEscalated = "payment_review.escalated"
+
+// PaymentReview is a payment held in the review queue because a fraud rule
+// asked for a person to look at it. Approving it queues the payment for
+// processing; declining it fails the payment.
+type PaymentReview struct {
+	ID        uuid.UUID    `json:"id"`
+	PaymentID uuid.UUID    `json:"payment_id"`
+	Status    ReviewStatus `json:"status"`
+	ClaimedBy string       `json:"claimed_by,omitempty"`
+	ClaimedAt *time.Time   `json:"claimed_at,omitempty"`
+	DecidedBy string       `json:"decided_by,omitempty"`
+	DecidedAt *time.Time   `json:"decided_at,omitempty"`
+	Notes     string       `json:"notes,omitempty"`
+	// EscalatedAt is set when the review was left open past the escalation
+	// deadline.
+	EscalatedAt *time.Time `json:"escalated_at,omitempty"`
+	// Risk is how the payment was screened. Only filled in when a single
+	// review is fetched.
+	Risk      *PaymentRisk `json:"risk,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+type ClaimReviewRequest struct {
+	// Reviewer is the authenticated operator, never read from the body.
+	Reviewer string `json:"-"`
+}
+
+func (cr ClaimReviewRequest) Validate() error {
+	return validation.ValidateStruct(&cr,
+		validation.Field(&cr.Reviewer, validation.Required.Error("reviewer is required"), validation.Length(1, 100)))
+}
+
+type ReviewDecisionRequest struct {
+	// Reviewer is the authenticated operator, never read from the body. It
+	// must be the reviewer who claimed the review.
+	Reviewer string `json:"-"`
+	// Notes explain the decision. They are required to decline.
+	Notes string `json:"notes,omitempty"`
+}
+
+func (dr ReviewDecisionRequest) Validate(decision ReviewStatus) error {
+	notes := []validation.Rule{validation.Length(0, 2000)}
+	if decision == ReviewDeclined {
+		notes = append(notes, validation.Required.Error("notes are required to decline"))
+	}
+	return validation.ValidateStruct(&dr,
+		validation.Field(&dr.Reviewer, validation.Required.Error("reviewer is required"), validation.Length(1, 100)),
+		validation.Field(&dr.Notes, notes...))
+}
+
+// ReviewFilter narrows a review listing. Nil fields match every review.
+type ReviewFilter struct {
+	Status    *ReviewStatus
+	Escalated *bool
+}
+
+// ParseReviewFilter reads the status and escalated query values.
+func ParseReviewFilter(query url.Values) (ReviewFilter, error) {
+	var f ReviewFilter
+	if query.Has("status") {
+		status := ReviewStatus(query.Get("status"))
+		switch status {
+		case ReviewOpen, ReviewClaimed, ReviewApproved, ReviewDeclined:
+		default:
+			return f, NewError(ErrInvalidRequest, "invalid status filter", "status must be one of open, claimed, approved or declined", nil, map[string]interface{}{"status": status})
+		}
+		f.Status = &status
+	}
+	if query.Has("escalated") {
+		escalated, err := strconv.ParseBool(query.Get("escalated"))
+		if err != nil {
+			return f, NewError(ErrInvalidRequest, "invalid escalated filter", "escalated must be true or false", err, map[string]interface{}{"escalated": query.Get("escalated")})
+		}
+		f.Escalated = &escalated
+	}
+	return f, nil
+}
+
+type ReviewList struct {
+	Data   []PaymentReview `json:"data"`
+	Limit  int             `json:"limit"`
+	Offset int             `json:"offset"`
+}
+
+type ReviewRepo interface {
+	// CreateReview inserts review and fills in the generated fields.
+	CreateReview(ctx context.Context, review *PaymentReview) error
+	GetReviewByID(ctx context.Context, id uuid.UUID) (*PaymentReview, error)
+	GetReviewByIDForUpdate(ctx context.Context, id uuid.UUID) (*PaymentReview, error)
+	// ListReviews returns escalated reviews first, then the oldest.
+	ListReviews(ctx context.Context, filter ReviewFilter, page Page) ([]PaymentReview, error)
+	ClaimReview(ctx context.Context, id uuid.UUID, reviewer string) (*PaymentReview, error)
+	DecideReview(ctx context.Context, id uuid.UUID, decision ReviewStatus, reviewer, notes string) (*PaymentReview, error)
+	// EscalateOverdueReviews marks the open reviews created at or before
+	// before as escalated and returns them. Reviews already escalated are
+	// left alone.
+	EscalateOverdueReviews(ctx context.Context, before time.Time) ([]PaymentReview, error)
+	// ClaimOverdueReview locks the oldest open review created at or before
+	// before, skipping rows other workers hold. It returns ErrNotFound when
+	// there is none.
+	ClaimOverdueReview(ctx context.Context, before time.Time) (*PaymentReview, error)
+}
+
+type ReviewService interface {
+	GetReviewByID(ctx context.Context, id string) (*PaymentReview, error)
+	ListReviews(ctx context.Context, filter ReviewFilter, page Page) (*ReviewList, error)
+	// ClaimReview assigns an open review to a reviewer.
+	ClaimReview(ctx context.Context, id string, cr *ClaimReviewRequest) (*PaymentReview, error)
+	// ApproveReview releases the held payment and queues it for processing.
+	ApproveReview(ctx context.Context, id string, dr *ReviewDecisionRequest) (*PaymentReview, error)
+	// DeclineReview fails the held payment.
+	DeclineReview(ctx context.Context, id string, dr *ReviewDecisionRequest) (*PaymentReview, error)
+}
+
+type ReviewHandler interface {
+	GetReviewByID(c echo.Context) error
+	ListReviews(c echo.Context) error
+	ClaimReview(c echo.Context) error
+	ApproveReview(c echo.Context) error
+	DeclineReview(c echo.Context) error
+}
@@ -0,0 +1,192 @@
+package domain
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// RiskOutcome is what screening decided for a payment.
+type RiskOutcome string
+
+const (
+	RiskAllow RiskOutcome = "allow"
+	// RiskReview payments are flagged for a person to look at.
+	RiskReview RiskOutcome = "review"
+	// RiskBlock payments are stored as FAILED and never processed.
+	RiskBlock RiskOutcome = "block"
+)
+
+// RiskAssessment is the result of screening a payment when it was created.
+type RiskAssessment struct {
+	// Score is the sum of the scores of the matched rules, at most 100.
+	Score   int         `json:"score"`
+	Outcome RiskOutcome `json:"outcome"`
+	// Rules names the rules that matched, in evaluation order.
+	Rules []string `json:"rules"`
+}
+
+// PaymentRisk is how a payment was screened, for operators only.
+type PaymentRisk struct {
+	PaymentID uuid.UUID `json:"payment_id"`
+	ClientIP  string    `json:"client_ip,omitempty"`
+	// Risk is nil for payments that were not screened.
+	Risk *RiskAssessment `json:"risk,omitempty"`
+}
+
+func NewPaymentRisk(p *Payment) *PaymentRisk {
+	return &PaymentRisk{PaymentID: p.ID, ClientIP: p.ClientIP, Risk: p.Risk}
+}
+
+// RiskRule is a fraud rule evaluated against every payment created through
+// the API. Expression is written in the rule language of package risk.
+type RiskRule struct {
+	ID         uuid.UUID `json:"id"`
+	Name       string    `json:"name"`
+	Expression string    `json:"expression"`
+	// Action is the outcome of a match: review or block.
+	Action RiskOutcome `json:"action"`
+	// Score is added to the payment's risk score on a match.
+	Score     int       `json:"score"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RiskRuleRequest struct {
+	Name       string      `json:"name"`
+	Expression string      `json:"expression"`
+	Action     RiskOutcome `json:"action"`
+	Score      int         `json:"score"`
+	// Enabled defaults to true.
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+func (rr RiskRuleRequest) Validate() error {
+	return validation.ValidateStruct(&rr,
+		validation.Field(&rr.Name, validation.Required.Error("rule name is required"), validation.Length(1, 100)),
+		validation.Field(&rr.Expression, validation.Required.Error("expression is required"), validation.Length(1, 2000)),
+		validation.Field(&rr.Action, validation.Required.Error("action is required"), validation.In(RiskReview, RiskBlock)),
+		validation.Field(&rr.Score, validation.Min(0), validation.Max(100)))
+}
+
+type RiskRuleList struct {
+	Data   []RiskRule `json:"data"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+// BlocklistKind is what a blocklist entry's value is matched against.
+type BlocklistKind string
+
+const (
+	BlockIP       BlocklistKind = "ip"
+	BlockEmail    BlocklistKind = "email"
+	BlockCustomer BlocklistKind = "customer"
+)
+
+// BlocklistEntry is a value rules can test with blocked(kind, value).
+type BlocklistEntry struct {
+	ID        uuid.UUID     `json:"id"`
+	Kind      BlocklistKind `json:"kind"`
+	Value     string        `json:"value"`
+	Reason    string        `json:"reason,omitempty"`
+	CreatedAt time.Time     `json:"created_at"`
+}
+
+type BlocklistEntryRequest struct {
+	Kind  BlocklistKind `json:"kind"`
+	Value string        `json:"value"`
+	// Reason records why the value was blocked.
+	Reason string `json:"reason,omitempty"`
+}
+
+func (br BlocklistEntryRequest) Validate() error {
+	return validation.ValidateStruct(&br,
+		validation.Field(&br.Kind, validation.Required.Error("kind is required"), validation.In(BlockIP, BlockEmail, BlockCustomer)),
+		validation.Field(&br.Value,
+			validation.Required.Error("value is required"),
+			validation.Length(1, 255),
+			validation.By(func(interface{}) error {
+				switch br.Kind {
+				case BlockIP:
+					return is.IP.Error("must be a valid IP address").Validate(br.Value)
+				case BlockEmail:
+					return is.Email.Error("must be a valid email address").Validate(br.Value)
+				case BlockCustomer:
+					return is.UUID.Error("must be a customer ID").Validate(br.Value)
+				}
+				return nil
+			})),
+		validation.Field(&br.Reason, validation.Length(0, 1000)))
+}
+
+// ParseBlocklistKind reads the optional kind query value.
+func ParseBlocklistKind(query url.Values) (*BlocklistKind, error) {
+	if !query.Has("kind") {
+		return nil, nil
+	}
+	kind := BlocklistKind(query.Get("kind"))
+	switch kind {
+	case BlockIP, BlockEmail, BlockCustomer:
+		return &kind, nil
+	}
+	return nil, NewError(ErrInvalidRequest, "invalid kind filter", "kind must be one of ip, email or customer", nil, map[string]interface{}{"kind": kind})
+}
+
+type BlocklistEntryList struct {
+	Data   []BlocklistEntry `json:"data"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
+
+type RiskRepo interface {
+	// CreateRule inserts rule and fills in the generated fields.
+	CreateRule(ctx context.Context, rule *RiskRule) error
+	GetRuleByID(ctx context.Context, id uuid.UUID) (*RiskRule, error)
+	ListRules(ctx context.Context, page Page) ([]RiskRule, error)
+	// ListEnabledRules returns every enabled rule, oldest first.
+	ListEnabledRules(ctx context.Context) ([]RiskRule, error)
+	UpdateRule(ctx context.Context, rule *RiskRule) error
+	DeleteRule(ctx context.Context, id uuid.UUID) error
+	// CreateBlocklistEntry inserts entry and fills in the generated fields.
+	CreateBlocklistEntry(ctx context.Context, entry *BlocklistEntry) error
+	ListBlocklistEntries(ctx context.Context, kind *BlocklistKind, page Page) ([]BlocklistEntry, error)
+	DeleteBlocklistEntry(ctx context.Context, id uuid.UUID) error
+	IsBlocklisted(ctx context.Context, kind BlocklistKind, value string) (bool, error)
+	// The counts below are of payments created since the given time and
+	// back velocity rules.
+	CountPaymentsByClientIP(ctx context.Context, ip string, since time.Time) (int, error)
+	CountPaymentsByCustomer(ctx context.Context, customerID uuid.UUID, since time.Time) (int, error)
+	CountPaymentsByReferencePrefix(ctx context.Context, prefix string, since time.Time) (int, error)
+}
+
+type RiskService interface {
+	// CreateRule adds a rule after checking its expression compiles.
+	CreateRule(ctx context.Context, rr *RiskRuleRequest) (*RiskRule, error)
+	GetRuleByID(ctx context.Context, id string) (*RiskRule, error)
+	ListRules(ctx context.Context, page Page) (*RiskRuleList, error)
+	UpdateRule(ctx context.Context, id string, rr *RiskRuleRequest) (*RiskRule, error)
+	DeleteRule(ctx context.Context, id string) error
+	CreateBlocklistEntry(ctx context.Context, br *BlocklistEntryRequest) (*BlocklistEntry, error)
+	ListBlocklistEntries(ctx context.Context, kind *BlocklistKind, page Page) (*BlocklistEntryList, error)
+	DeleteBlocklistEntry(ctx context.Context, id string) error
+	GetPaymentRisk(ctx context.Context, paymentID string) (*PaymentRisk, error)
+}
+
+type RiskHandler interface {
+	CreateRule(c echo.Context) error
+	GetRuleByID(c echo.Context) error
+	ListRules(c echo.Context) error
+	UpdateRule(c echo.Context) error
+	DeleteRule(c echo.Context) error
+	CreateBlocklistEntry(c echo.Context) error
+	ListBlocklistEntries(c echo.Context) error
+	DeleteBlocklistEntry(c echo.Context) error
+	GetPaymentRisk(c echo.Context) error
+}
@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type SettlementStatus string
+
+const (
+	// SettlementOpen batches take payments until their cutoff.
+	SettlementOpen SettlementStatus = "open"
+	// SettlementClosed batches are final and waiting to be paid out.
+	SettlementClosed SettlementStatus = "closed"
+	SettlementPaid   SettlementStatus = "paid"
+)
+
+// SettlementBatch totals what is owed to one merchant in one currency for
+// the payments that succeeded before CutoffAt.
+type SettlementBatch struct {
+	ID uuid.UUID `json:"id"`
+	// MerchantID is the payments' merchant_id metadata, empty for payments
+	// without one.
+	MerchantID     string           `json:"merchant_id"`
+	Currency       string           `json:"currency"`
+	Status         SettlementStatus `json:"status"`
+	CutoffAt       time.Time        `json:"cutoff_at"`
+	PaymentCount   int              `json:"payment_count"`
+	GrossAmount    float64          `json:"gross_amount"`
+	FeeAmount      float64          `json:"fee_amount"`
+	RefundedAmount float64          `json:"refunded_amount"`
+	// AdjustmentAmount totals the batch's adjustments and is never positive.
+	AdjustmentAmount float64 `json:"adjustment_amount"`
+	// NetAmount is what is paid out: gross less fees and refunds, plus
+	// adjustments.
+	NetAmount float64    `json:"net_amount"`
+	ClosedAt  *time.Time `json:"closed_at,omitempty"`
+	PaidAt    *time.Time `json:"paid_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// SettlementItem is one payment's share of a batch. A payment is settled in
+// at most one batch.
+type SettlementItem struct {
+	PaymentID      uuid.UUID `json:"payment_id"`
+	BatchID        uuid.UUID `json:"batch_id"`
+	Amount         float64   `json:"amount"`
+	FeeAmount      float64   `json:"fee_amount"`
+	RefundedAmount float64   `json:"refunded_amount"`
+	NetAmount      float64   `json:"net_amount"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// NewSettlementItem works out what a successful payment contributes to a
+// batch once its fee and the refunded amount are taken off.
+func NewSettlementItem(p *Payment, refunded float64) *SettlementItem {
+	item := &SettlementItem{
+		PaymentID:      p.ID,
+		Amount:         p.Amount,
+		RefundedAmount: refunded,
+	}
+	// Payments that succeeded before fees were recorded carry none
+	if p.FeeAmount != nil {
+		item.FeeAmount = *p.FeeAmount
+	}
+	item.NetAmount = math.Round((p.Amount-item.FeeAmount-refunded)*100) / 100
+	return item
+}
+
+// SettlementAdjustment takes money returned on a payment after it was
+// batched, by a refund or a lost dispute, out of the merchant's next open
+// batch. Amount is negative.
+type SettlementAdjustment struct {
+	ID        uuid.UUID `json:"id"`
+	PaymentID uuid.UUID `json:"payment_id"`
+	BatchID   uuid.UUID `json:"batch_id"`
+	Amount    float64   `json:"amount"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SettlementFilter narrows a batch listing. Nil fields match every batch.
+type SettlementFilter struct {
+	MerchantID *string
+	Status     *SettlementStatus
+}
+
+// ParseSettlementFilter reads the merchant_id and status query values.
+func ParseSettlementFilter(query url.Values) (SettlementFilter, error) {
+	var f SettlementFilter
+	if query.Has("merchant_id") {
+		merchantID := query.Get("merchant_id")
+		f.MerchantID = &merchantID
+	}
+	if query.Has("status") {
+		status := SettlementStatus(query.Get("status"))
+		switch status {
+		case SettlementOpen, SettlementClosed, SettlementPaid:
+		default:
+			return f, NewError(ErrInvalidRequest, "invalid status filter", "status must be one of open, closed or paid", nil, map[string]interface{}{"status": status})
+		}
+		f.Status = &status
+	}
+	return f, nil
+}
+
+type SettlementBatchList struct {
+	Data   []SettlementBatch `json:"data"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+type SettlementItemList struct {
+	Data   []SettlementItem `json:"data"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
+
+type SettlementAdjustmentList struct {
+	Data   []SettlementAdjustment `json:"data"`
+	Limit  int                    `json:"limit"`
+	Offset int                    `json:"offset"`
+}
+
+type SettlementRepo interface {
+	// ClaimUnsettledPayment locks the earliest successful payment not yet in
+	// a batch, skipping rows other workers hold, and returns it with the
+	// amount refunded on it so far. It returns ErrNotFound when none is left.
+	ClaimUnsettledPayment(ctx context.Context) (*Payment, float64, error)
+	// ClaimUnsettledRefund locks the earliest batched payment with money
+	// returned on it that neither its settlement item nor its adjustments
+	// have taken off yet, and returns it with that amount. It returns
+	// ErrNotFound when none is left.
+	ClaimUnsettledRefund(ctx context.Context) (*Payment, float64, error)
+	// OpenBatch returns the open batch of the merchant in currency, creating
+	// one that closes at cutoff if there is none.
+	OpenBatch(ctx context.Context, merchantID, currency string, cutoff time.Time) (*SettlementBatch, error)
+	// AddItem stores item and adds it to its batch's totals.
+	AddItem(ctx context.Context, item *SettlementItem) (*SettlementBatch, error)
+	// AddAdjustment stores adjustment and adds it to its batch's totals.
+	AddAdjustment(ctx context.Context, adjustment *SettlementAdjustment) (*SettlementBatch, error)
+	CloseBatch(ctx context.Context, id uuid.UUID) (*SettlementBatch, error)
+	// CloseDueBatches closes every open batch whose cutoff is not after now.
+	CloseDueBatches(ctx context.Context, now time.Time) ([]SettlementBatch, error)
+	// MarkBatchPaid returns ErrNotFound unless the batch is closed.
+	MarkBatchPaid(ctx context.Context, id uuid.UUID) (*SettlementBatch, error)
+	GetBatchByID(ctx context.Context, id uuid.UUID) (*SettlementBatch, error)
+	GetBatchByIDForUpdate(ctx context.Context, id uuid.UUID) (*SettlementBatch, error)
+	ListBatches(ctx context.Context, filter SettlementFilter, page Page) ([]SettlementBatch, error)
+	ListItems(ctx context.Context, batchID uuid.UUID, page Page) ([]SettlementItem, error)
+	ListAdjustments(ctx context.Context, batchID uuid.UUID, page Page) ([]SettlementAdjustment, error)
+}
+
+type SettlementService interface {
+	ListBatches(ctx context.Context, filter SettlementFilter, page Page) (*SettlementBatchList, error)
+	GetBatchByID(ctx context.Context, id string) (*SettlementBatch, error)
+	ListBatchPayments(ctx context.Context, id string, page Page) (*SettlementItemList, error)
+	ListBatchAdjustments(ctx context.Context, id string, page Page) (*SettlementAdjustmentList, error)
+	// PayBatch records that a closed batch has been paid out to the merchant.
+	PayBatch(ctx context.Context, id string) (*SettlementBatch, error)
+}
+
+type SettlementHandler interface {
+	ListBatches(c echo.Context) error
+	GetBatchByID(c echo.Context) error
+	ListBatchPayments(c echo.Context) error
+	ListBatchAdjustments(c echo.Context) error
+	PayBatch(c echo.Context) error
+}
@@ -0,0 +1,239 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type BillingInterval string
+
+const (
+	IntervalDay   BillingInterval = "day"
+	IntervalWeek  BillingInterval = "week"
+	IntervalMonth BillingInterval = "month"
+	IntervalYear  BillingInterval = "year"
+)
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionTrialing SubscriptionStatus = "trialing"
+	SubscriptionActive   SubscriptionStatus = "active"
+	// SubscriptionPastDue means the last cycle payment failed and is being
+	// retried on the dunning schedule.
+	SubscriptionPastDue  SubscriptionStatus = "past_due"
+	SubscriptionCanceled SubscriptionStatus = "canceled"
+)
+
+// Plan is what a subscription bills: Amount every IntervalCount Intervals.
+type Plan struct {
+	ID            uuid.UUID       `json:"id"`
+	Name          string          `json:"name"`
+	Amount        float64         `json:"amount"`
+	Currency      string          `json:"currency"`
+	Interval      BillingInterval `json:"interval"`
+	IntervalCount int             `json:"interval_count"`
+	// TrialDays is how long new subscriptions run before their first charge.
+	TrialDays int       `json:"trial_days"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CycleEnd returns the end of the n-th billing cycle counted from anchor.
+// Cycles are always measured from the anchor rather than from the previous
+// cycle, so a subscription anchored on the 31st bills on the last day of
+// shorter months and returns to the 31st afterwards.
+func (p Plan) CycleEnd(anchor time.Time, n int) time.Time {
+	steps := n * p.IntervalCount
+	switch p.Interval {
+	case IntervalDay:
+		return anchor.AddDate(0, 0, steps)
+	case IntervalWeek:
+		return anchor.AddDate(0, 0, 7*steps)
+	case IntervalYear:
+		return addMonthsClamped(anchor, 12*steps)
+	default:
+		return addMonthsClamped(anchor, steps)
+	}
+}
+
+// NextBillingDate returns the first cycle boundary after t.
+func (p Plan) NextBillingDate(anchor, t time.Time) time.Time {
+	for n := 1; ; n++ {
+		if end := p.CycleEnd(anchor, n); end.After(t) {
+			return end
+		}
+	}
+}
+
+// addMonthsClamped adds months to t, moving to the last day of the target
+// month when t's day does not exist there.
+func addMonthsClamped(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+	day := t.Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return first.AddDate(0, 0, day-1)
+}
+
+type PlanRequest struct {
+	Name     string          `json:"name"`
+	Amount   float64         `json:"amount"`
+	Currency string          `json:"currency"`
+	Interval BillingInterval `json:"interval"`
+	// IntervalCount defaults to 1, e.g. 3 with a month interval bills quarterly.
+	IntervalCount int `json:"interval_count,omitempty"`
+	TrialDays     int `json:"trial_days,omitempty"`
+}
+
+func (pr PlanRequest) Validate() error {
+	return validation.ValidateStruct(&pr,
+		validation.Field(&pr.Name, validation.Required.Error("plan name is required"), validation.Length(1, 255)),
+		validation.Field(&pr.Amount, validation.By(func(interface{}) error {
+			if pr.Amount <= 0 {
+				return errors.New("plan amount must be greater than 0.0")
+			}
+			return nil
+		})),
+		validation.Field(&pr.Currency, validation.Required.Error("currency is required"), validation.In("ETB", "USD")),
+		validation.Field(&pr.Interval, validation.Required.Error("interval is required"),
+			validation.In(IntervalDay, IntervalWeek, IntervalMonth, IntervalYear)),
+		validation.Field(&pr.IntervalCount, validation.Min(0), validation.Max(365)),
+		validation.Field(&pr.TrialDays, validation.Min(0), validation.Max(730)))
+}
+
+type PlanList struct {
+	Data   []Plan `json:"data"`
+	Limit  int    `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+// Subscription bills its customer for a plan once per cycle. The worker's
+// scheduler charges each cycle at CurrentPeriodEnd, for the period that
+// starts there, and only moves the period forward once that charge succeeds.
+type Subscription struct {
+	ID            uuid.UUID          `json:"id"`
+	PlanID        uuid.UUID          `json:"plan_id"`
+	CustomerID    uuid.UUID          `json:"customer_id"`
+	Status        SubscriptionStatus `json:"status"`
+	PaymentMethod *PaymentMethod     `json:"payment_method"`
+	// BillingAnchor is the date cycles are counted from.
+	BillingAnchor      time.Time  `json:"billing_anchor"`
+	TrialEnd           *time.Time `json:"trial_end,omitempty"`
+	CurrentPeriodStart time.Time  `json:"current_period_start"`
+	CurrentPeriodEnd   time.Time  `json:"current_period_end"`
+	// NextBillingAt is when the next charge or dunning retry is due. It is
+	// nil once the subscription is canceled.
+	NextBillingAt     *time.Time `json:"next_billing_at,omitempty"`
+	CancelAtPeriodEnd bool       `json:"cancel_at_period_end"`
+	CanceledAt        *time.Time `json:"canceled_at,omitempty"`
+	// PendingPaymentID is the cycle payment the worker has not settled yet.
+	PendingPaymentID *uuid.UUID `json:"pending_payment_id,omitempty"`
+	// FailedAttempts counts the failed charges for the current cycle.
+	FailedAttempts int       `json:"failed_attempts"`
+	Metadata       Metadata  `json:"metadata"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type SubscriptionRequest struct {
+	PlanID     uuid.UUID `json:"plan_id"`
+	CustomerID uuid.UUID `json:"customer_id"`
+	// PaymentMethod is charged for every cycle.
+	PaymentMethod *PaymentMethod `json:"payment_method"`
+	// BillingAnchor sets the date of the first charge, which must not be
+	// before the trial ends. It defaults to the end of the trial, or now.
+	BillingAnchor *time.Time `json:"billing_anchor,omitempty"`
+	// Metadata is copied to every cycle payment.
+	Metadata Metadata `json:"metadata,omitempty"`
+}
+
+func (sr SubscriptionRequest) Validate() error {
+	return validation.ValidateStruct(&sr,
+		validation.Field(&sr.PlanID, validation.By(func(interface{}) error {
+			if sr.PlanID == uuid.Nil {
+				return errors.New("plan_id is required")
+			}
+			return nil
+		})),
+		validation.Field(&sr.CustomerID, validation.By(func(interface{}) error {
+			if sr.CustomerID == uuid.Nil {
+				return errors.New("customer_id is required")
+			}
+			return nil
+		})),
+		validation.Field(&sr.PaymentMethod, validation.Required.Error("payment method is required")),
+		validation.Field(&sr.Metadata))
+}
+
+type CancelSubscriptionRequest struct {
+	// AtPeriodEnd keeps the subscription running until the paid period
+	// ends instead of canceling it right away.
+	AtPeriodEnd bool `json:"at_period_end"`
+}
+
+type SubscriptionList struct {
+	Data   []Subscription `json:"data"`
+	Limit  int            `json:"limit"`
+	Offset int            `json:"offset"`
+}
+
+type PlanRepo interface {
+	// CreatePlan inserts plan and fills in the generated fields.
+	CreatePlan(ctx context.Context, plan *Plan) error
+	GetPlanByID(ctx context.Context, id uuid.UUID) (*Plan, error)
+	ListPlans(ctx context.Context, page Page) ([]Plan, error)
+}
+
+type SubscriptionRepo interface {
+	// CreateSubscription inserts sub and fills in the generated fields.
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	GetSubscriptionByID(ctx context.Context, id uuid.UUID) (*Subscription, error)
+	// GetSubscriptionByIDForUpdate locks the subscription until the
+	// transaction ends.
+	GetSubscriptionByIDForUpdate(ctx context.Context, id uuid.UUID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, page Page) ([]Subscription, error)
+	// UpdateSubscription saves the billing state of sub.
+	UpdateSubscription(ctx context.Context, sub *Subscription) error
+	// ClaimDueSubscription locks a subscription without a pending payment
+	// whose next billing date is not after now. Subscriptions locked by other
+	// transactions are skipped. It returns ErrNotFound when none is due.
+	ClaimDueSubscription(ctx context.Context, now time.Time) (*Subscription, error)
+	// ClaimSettledSubscription locks a subscription whose pending payment has
+	// succeeded or failed and returns it with that payment's status. It
+	// returns ErrNotFound when there is none.
+	ClaimSettledSubscription(ctx context.Context) (*Subscription, PaymentStatus, error)
+}
+
+type PlanService interface {
+	CreatePlan(ctx context.Context, pr *PlanRequest) (*Plan, error)
+	GetPlanByID(ctx context.Context, id string) (*Plan, error)
+	ListPlans(ctx context.Context, page Page) (*PlanList, error)
+}
+
+type SubscriptionService interface {
+	CreateSubscription(ctx context.Context, sr *SubscriptionRequest) (*Subscription, error)
+	GetSubscriptionByID(ctx context.Context, id string) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, page Page) (*SubscriptionList, error)
+	CancelSubscription(ctx context.Context, id string, cr *CancelSubscriptionRequest) (*Subscription, error)
+}
+
+type PlanHandler interface {
+	CreatePlan(c echo.Context) error
+	GetPlanByID(c echo.Context) error
+	ListPlans(c echo.Context) error
+}
+
+type SubscriptionHandler interface {
+	CreateSubscription(c echo.Context) error
+	GetSubscriptionByID(c echo.Context) error
+	ListSubscriptions(c echo.Context) error
+	CancelSubscription(c echo.Context) error
+}
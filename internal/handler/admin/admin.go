@@ -0,0 +1,144 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// adminHandler handles HTTP requests for operator interventions on payments
+type adminHandler struct {
+	svc domain.AdminService
+}
+
+// NewAdminHandler initializes the operator routes. g is expected to require
+// the admin token.
+func NewAdminHandler(g *echo.Group, svc domain.AdminService) domain.AdminHandler {
+	handler := &adminHandler{
+		svc: svc,
+	}
+	g.POST("/payments/redrive", handler.RedrivePayments)
+	g.POST("/payments/:id/requeue", handler.RequeuePayment)
+	g.POST("/payments/:id/fail", handler.ForceFailPayment)
+	g.GET("/payments/:id/attempts", handler.ListPaymentAttempts)
+	return handler
+}
+
+// RequeuePayment queues a pending payment for the worker again
+// @Summary Requeue a payment
+// @Description Publishes a pending payment to the processing queue again, e.g. after its message was lost. Waits for a worker charging the payment. Processing is idempotent, so a payment queued twice is charged once
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} domain.Payment "Payment queued"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Payment not found"
+// @Failure 409 {object} domain.ProblemDetails "Payment not pending"
+// @Failure 503 {object} domain.ProblemDetails "Processing queue unavailable"
+// @Router /admin/payments/{id}/requeue [post]
+func (h *adminHandler) RequeuePayment(c echo.Context) error {
+	res, err := h.svc.RequeuePayment(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ForceFailPayment fails a stuck payment
+// @Summary Force-fail a payment
+// @Description Fails a payment stuck in PENDING without charging it. The reason is required and kept in the audit log. Waits for a worker charging the payment
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Payment ID"
+// @Param failure body domain.ForceFailRequest true "Reason for failing the payment"
+// @Success 200 {object} domain.Payment "Payment failed"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment ID, request body or validation failed"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Payment not found"
+// @Failure 409 {object} domain.ProblemDetails "Payment not pending"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/payments/{id}/fail [post]
+func (h *adminHandler) ForceFailPayment(c echo.Context) error {
+	var fr domain.ForceFailRequest
+	if err := c.Bind(&fr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.ForceFailPayment(c.Request().Context(), c.Param("id"), &fr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListPaymentAttempts shows the worker's provider calls for a payment
+// @Summary List a payment's provider attempts
+// @Description Lists every provider call the worker recorded for the payment, oldest first, with provider references and errors
+// @Tags admin
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Payment ID"
+// @Success 200 {object} domain.PaymentAttemptList "Attempts"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Payment not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/payments/{id}/attempts [get]
+func (h *adminHandler) ListPaymentAttempts(c echo.Context) error {
+	res, err := h.svc.ListPaymentAttempts(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// RedrivePayments queues every pending payment matching a filter
+// @Summary Re-drive pending payments
+// @Description Requeues the pending payments matching the filter, oldest first, one at a time under each payment's lock. Payments processed since they matched are skipped. A dry run only lists the matches
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param redrive body domain.RedriveRequest true "Filter, limit and dry run flag"
+// @Success 200 {object} domain.RedriveResult "Matched and requeued payments"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Failure 503 {object} domain.RedriveResult "Stopped partway; the body lists the payments requeued before it stopped"
+// @Router /admin/payments/redrive [post]
+func (h *adminHandler) RedrivePayments(c echo.Context) error {
+	var rr domain.RedriveRequest
+	if err := c.Bind(&rr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.RedrivePayments(c.Request().Context(), &rr)
+	var derr domain.Error
+	if res != nil && errors.As(err, &derr) {
+		// Some payments may already be queued; the operator needs to know which
+		return c.JSON(derr.Code, res)
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,96 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	admin "pgm/internal/handler/admin"
+)
+
+type mockService struct {
+	domain.AdminService
+	failed     *domain.ForceFailRequest
+	redriven   *domain.RedriveRequest
+	redriveErr error
+}
+
+func (m *mockService) ForceFailPayment(ctx context.Context, id string, fr *domain.ForceFailRequest) (*domain.Payment, error) {
+	m.failed = fr
+	if err := fr.Validate(); err != nil {
+		return nil, domain.NewError(domain.ErrValidationFailed, "validation failed", "force fail request validation failed", err, nil)
+	}
+	return &domain.Payment{ID: uuid.MustParse(id), Status: domain.StatusFailed}, nil
+}
+
+func (m *mockService) RedrivePayments(ctx context.Context, rr *domain.RedriveRequest) (*domain.RedriveResult, error) {
+	m.redriven = rr
+	res := &domain.RedriveResult{DryRun: rr.DryRun, PaymentIDs: []uuid.UUID{}}
+	if m.redriveErr != nil {
+		res.Error = m.redriveErr.Error()
+	}
+	return res, m.redriveErr
+}
+
+func serve(svc domain.AdminService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	admin.NewAdminHandler(e.Group("/admin"), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestForceFailPayment(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"with reason", `{"reason":"provider confirmed no charge"}`, http.StatusOK},
+		{"without reason", `{}`, http.StatusBadRequest},
+		{"malformed body", `{"reason":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin/payments/"+uuid.NewString()+"/fail", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := serve(&mockService{}, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestRedrivePayments(t *testing.T) {
+	svc := &mockService{}
+	req := httptest.NewRequest(http.MethodPost, "/admin/payments/redrive", strings.NewReader(`{"currency":"USD","metadata":{"order":"42"},"created_to":"2026-02-01T00:00:00Z","limit":50,"dry_run":true}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := serve(svc, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.NotNil(t, svc.redriven) {
+		assert.Equal(t, "USD", svc.redriven.Currency)
+		assert.Equal(t, "42", svc.redriven.Metadata["order"])
+		assert.NotNil(t, svc.redriven.CreatedTo)
+		assert.Equal(t, 50, svc.redriven.Limit)
+		assert.True(t, svc.redriven.DryRun)
+	}
+	assert.Contains(t, rec.Body.String(), `"dry_run":true`)
+}
+
+func TestRedrivePaymentsStoppedPartway(t *testing.T) {
+	svc := &mockService{redriveErr: domain.NewError(domain.ErrServiceUnavailable, "Failed to queue payment", "broker down", nil, nil)}
+	req := httptest.NewRequest(http.MethodPost, "/admin/payments/redrive", strings.NewReader(`{}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := serve(svc, req)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"payment_ids":[]`)
+	assert.Contains(t, rec.Body.String(), `"error":`)
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"net/http"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// auditHandler handles HTTP requests for the audit log
+type auditHandler struct {
+	svc domain.AuditService
+}
+
+// NewAuditHandler initializes the audit log routes
+func NewAuditHandler(g *echo.Group, svc domain.AuditService) domain.AuditHandler {
+	handler := &auditHandler{
+		svc: svc,
+	}
+	g.GET("/audit-log", handler.ListEntries)
+	return handler
+}
+
+// ListEntries lists audit log entries
+// @Summary List audit log entries
+// @Description Lists recorded changes, newest first, with the target as it stood before and after each. Sealed entries carry their position and hash in the tamper-evident chain
+// @Tags audit
+// @Produce json
+// @Security ApiKeyAuth
+// @Param actor query string false "Only changes made by this actor"
+// @Param action query string false "Only this action, e.g. payment.created"
+// @Param target_type query string false "Only changes to this kind of target, e.g. payment"
+// @Param target_id query string false "Only changes to this target"
+// @Param request_id query string false "Only changes made by this request"
+// @Param from query string false "Only changes recorded at or after this RFC 3339 time"
+// @Param to query string false "Only changes recorded before this RFC 3339 time"
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {object} domain.AuditList "Audit log entries"
+// @Failure 400 {object} domain.ProblemDetails "Invalid filter or pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/audit-log [get]
+func (h *auditHandler) ListEntries(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+	filter, err := domain.ParseAuditFilter(c.QueryParams())
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListEntries(c.Request().Context(), filter, page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
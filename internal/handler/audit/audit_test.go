@@ -0,0 +1,65 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	audit "pgm/internal/handler/audit"
+)
+
+type mockService struct {
+	domain.AuditService
+	filter domain.AuditFilter
+}
+
+func (m *mockService) ListEntries(ctx context.Context, filter domain.AuditFilter, page domain.Page) (*domain.AuditList, error) {
+	m.filter = filter
+	return &domain.AuditList{Data: []domain.AuditEntry{}, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func serve(svc domain.AuditService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	audit.NewAuditHandler(e.Group("/admin"), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestListEntries(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{"unfiltered", "", http.StatusOK},
+		{"by target", "?target_type=payment&target_id=abc", http.StatusOK},
+		{"time range", "?from=2026-02-01T00:00:00Z&to=2026-03-01T00:00:00Z", http.StatusOK},
+		{"bad from", "?from=yesterday", http.StatusBadRequest},
+		{"bad limit", "?limit=-1", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(&mockService{}, httptest.NewRequest(http.MethodGet, "/admin/audit-log"+tt.query, nil))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+
+	svc := &mockService{}
+	serve(svc, httptest.NewRequest(http.MethodGet, "/admin/audit-log?actor=alice&action=payment.created&request_id=req-1&from=2026-02-01T00:00:00Z", nil))
+	assert.Equal(t, "alice", svc.filter.Actor)
+	assert.Equal(t, "payment.created", svc.filter.Action)
+	assert.Equal(t, "req-1", svc.filter.RequestID)
+	if assert.NotNil(t, svc.filter.From) {
+		assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), svc.filter.From.UTC())
+	}
+	assert.Nil(t, svc.filter.To)
+}
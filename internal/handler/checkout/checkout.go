@@ -0,0 +1,205 @@
+package http
+
+import (
+	"embed"
+	"errors"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed templates/checkout.html
+var templates embed.FS
+
+var page = template.Must(template.ParseFS(templates, "templates/checkout.html"))
+
+// checkoutHandler serves the checkout session API and the hosted pages payers use
+type checkoutHandler struct {
+	svc domain.CheckoutService
+}
+
+// pageData is rendered by the checkout page template
+type pageData struct {
+	Session *domain.CheckoutSession
+	Open    bool
+	Error   string
+}
+
+// NewCheckoutHandler initializes the checkout session routes on api and the hosted checkout pages on pages
+func NewCheckoutHandler(api *echo.Group, pages *echo.Group, svc domain.CheckoutService) domain.CheckoutHandler {
+	handler := &checkoutHandler{
+		svc: svc,
+	}
+	api.POST("/checkout/sessions", handler.CreateSession)
+	api.GET("/checkout/sessions/:id", handler.GetSession)
+	pages.GET("/checkout/:token", handler.ShowPage)
+	pages.POST("/checkout/:token/confirm", handler.Confirm)
+	pages.POST("/checkout/:token/cancel", handler.Cancel)
+	return handler
+}
+
+// CreateSession handles the creation of a new checkout session
+// @Summary Create a checkout session
+// @Description Creates a pending payment and a hosted checkout page on which the payer chooses a payment method. Send the payer to the returned url; the payment is only processed once they confirm.
+// @Tags checkout
+// @Accept json
+// @Produce json
+// @Param session body domain.CheckoutSessionRequest true "Payment and redirect details"
+// @Success 201 {object} domain.CheckoutSession "Checkout session created"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 403 {object} domain.ProblemDetails "Metadata merchant_id is not the merchant of the API key"
+// @Failure 404 {object} domain.ProblemDetails "Customer not found"
+// @Failure 409 {object} domain.ProblemDetails "Payment with this reference already exists"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/checkout/sessions [post]
+func (h *checkoutHandler) CreateSession(c echo.Context) error {
+	var cr domain.CheckoutSessionRequest
+	if err := c.Bind(&cr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+	// Velocity and blocklist rules match on the caller's address
+	cr.ClientIP = c.RealIP()
+
+	res, err := h.svc.CreateSession(c.Request().Context(), &cr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// GetSession retrieves a checkout session by its ID
+// @Summary Get checkout session by ID
+// @Description Retrieves a checkout session together with its payment. Open sessions past their expiry are reported as expired and their payment failed.
+// @Tags checkout
+// @Produce json
+// @Param id path string true "Checkout session ID"
+// @Success 200 {object} domain.CheckoutSession "Checkout session found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid checkout session ID format"
+// @Failure 404 {object} domain.ProblemDetails "Checkout session not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/checkout/sessions/{id} [get]
+func (h *checkoutHandler) GetSession(c echo.Context) error {
+	res, err := h.svc.GetSession(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ShowPage renders the hosted checkout page
+func (h *checkoutHandler) ShowPage(c echo.Context) error {
+	session, err := h.svc.GetSessionByToken(c.Request().Context(), c.Param("token"))
+	if err != nil {
+		return h.renderError(c, nil, err)
+	}
+	return h.render(c, http.StatusOK, pageData{Session: session, Open: session.Status == domain.CheckoutOpen})
+}
+
+// Confirm submits the payer's chosen method and redirects to the success url
+func (h *checkoutHandler) Confirm(c echo.Context) error {
+	ctx := c.Request().Context()
+	token := c.Param("token")
+	session, err := h.svc.ConfirmSession(ctx, token, methodFromForm(c))
+	if err == nil {
+		return c.Redirect(http.StatusSeeOther, withSessionID(session.SuccessURL, session))
+	}
+
+	// Let the payer correct invalid details on the same page
+	session, lookupErr := h.svc.GetSessionByToken(ctx, token)
+	if lookupErr != nil {
+		return h.renderError(c, nil, lookupErr)
+	}
+	return h.renderError(c, session, err)
+}
+
+// Cancel closes the session and redirects to the cancel url
+func (h *checkoutHandler) Cancel(c echo.Context) error {
+	session, err := h.svc.CancelSession(c.Request().Context(), c.Param("token"))
+	if err != nil {
+		return h.renderError(c, nil, err)
+	}
+	return c.Redirect(http.StatusSeeOther, withSessionID(session.CancelURL, session))
+}
+
+func (h *checkoutHandler) render(c echo.Context, status int, data pageData) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	c.Response().WriteHeader(status)
+	return page.Execute(c.Response(), data)
+}
+
+// renderError shows err on the page. Payers see the error's message rather
+// than the problem JSON the API returns; errors outside the catalog are left
+// to the error handler.
+func (h *checkoutHandler) renderError(c echo.Context, session *domain.CheckoutSession, err error) error {
+	var derr domain.Error
+	if !errors.As(err, &derr) {
+		return err
+	}
+	data := pageData{Session: session, Error: derr.Description}
+	if derr.Type == domain.ErrValidationFailed && derr.Err != nil {
+		data.Error = derr.Description + ": " + derr.Err.Error()
+	}
+	if session != nil {
+		data.Open = session.Status == domain.CheckoutOpen
+	}
+	return h.render(c, derr.Code, data)
+}
+
+// methodFromForm reads the payment method fields posted by the checkout page.
+// Only the details of the selected method are read.
+func methodFromForm(c echo.Context) *domain.PaymentMethod {
+	method := &domain.PaymentMethod{Type: domain.PaymentMethodType(c.FormValue("method"))}
+	switch method.Type {
+	case domain.MethodCard:
+		month, _ := strconv.Atoi(c.FormValue("card.exp_month"))
+		year, _ := strconv.Atoi(c.FormValue("card.exp_year"))
+		method.Card = &domain.CardDetails{
+			Token:    c.FormValue("card.token"),
+			Brand:    c.FormValue("card.brand"),
+			Last4:    c.FormValue("card.last4"),
+			ExpMonth: month,
+			ExpYear:  year,
+		}
+	case domain.MethodBankTransfer:
+		method.BankTransfer = &domain.BankTransferDetails{
+			BankCode:      c.FormValue("bank_transfer.bank_code"),
+			AccountNumber: c.FormValue("bank_transfer.account_number"),
+			AccountName:   c.FormValue("bank_transfer.account_name"),
+		}
+	case domain.MethodMobileMoney:
+		method.MobileMoney = &domain.MobileMoneyDetails{
+			Provider: c.FormValue("mobile_money.provider"),
+			MSISDN:   c.FormValue("mobile_money.msisdn"),
+		}
+	case domain.MethodWallet:
+		method.Wallet = &domain.WalletDetails{
+			Provider: c.FormValue("wallet.provider"),
+			WalletID: c.FormValue("wallet.wallet_id"),
+		}
+	}
+	return method
+}
+
+// withSessionID appends the session ID to a merchant redirect url so the
+// merchant can look the session up on return.
+func withSessionID(raw string, session *domain.CheckoutSession) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+	q := u.Query()
+	q.Set("session_id", session.ID.String())
+	u.RawQuery = q.Encode()
+	return u.String()
+}
@@ -0,0 +1,173 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	chk "pgm/internal/handler/checkout"
+)
+
+type mockService struct {
+	domain.CheckoutService
+	session *domain.CheckoutSession
+	method  *domain.PaymentMethod
+}
+
+func newMockService(status domain.CheckoutStatus) *mockService {
+	return &mockService{session: &domain.CheckoutSession{
+		ID:         uuid.New(),
+		Status:     status,
+		Token:      "tok",
+		SuccessURL: "https://shop.example.com/done?order=7",
+		CancelURL:  "https://shop.example.com/cancel",
+		Payment:    &domain.Payment{Amount: 250, Currency: "ETB", Reference: "order-7", Status: domain.StatusPending},
+	}}
+}
+
+func (m *mockService) CreateSession(ctx context.Context, cr *domain.CheckoutSessionRequest) (*domain.CheckoutSession, error) {
+	if err := cr.Validate(); err != nil {
+		return nil, domain.NewError(domain.ErrValidationFailed, "validation failed", "checkout session request validation failed", err, nil)
+	}
+	return m.session, nil
+}
+
+func (m *mockService) GetSessionByToken(ctx context.Context, token string) (*domain.CheckoutSession, error) {
+	if token != m.session.Token {
+		return nil, domain.NewError(domain.ErrCheckoutNotFound, "Checkout session not found", "The specified checkout session could not be found", nil, nil)
+	}
+	return m.session, nil
+}
+
+func (m *mockService) ConfirmSession(ctx context.Context, token string, method *domain.PaymentMethod) (*domain.CheckoutSession, error) {
+	m.method = method
+	if err := method.Validate(); err != nil {
+		return nil, domain.NewError(domain.ErrValidationFailed, "validation failed", "payment method validation failed", err, nil)
+	}
+	m.session.Status = domain.CheckoutConfirmed
+	return m.session, nil
+}
+
+func (m *mockService) CancelSession(ctx context.Context, token string) (*domain.CheckoutSession, error) {
+	if m.session.Status != domain.CheckoutOpen {
+		return nil, domain.NewError(domain.ErrCheckoutClosed, "Checkout session closed", "This checkout session has already been "+string(m.session.Status), nil, nil)
+	}
+	m.session.Status = domain.CheckoutCanceled
+	return m.session, nil
+}
+
+func serve(svc domain.CheckoutService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	chk.NewCheckoutHandler(e.Group("/v1"), e.Group(""), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateSession(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		expectedStatus int
+	}{
+		{
+			name: "created",
+			body: map[string]interface{}{
+				"amount": 250, "currency": "ETB", "reference": "order-7",
+				"success_url": "https://shop.example.com/done", "cancel_url": "https://shop.example.com/cancel",
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing redirect urls",
+			body:           map[string]interface{}{"amount": 250, "currency": "ETB", "reference": "order-7"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/v1/checkout/sessions", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := serve(newMockService(domain.CheckoutOpen), req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestShowPage(t *testing.T) {
+	t.Run("open session offers every method", func(t *testing.T) {
+		rec := serve(newMockService(domain.CheckoutOpen), httptest.NewRequest(http.MethodGet, "/checkout/tok", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/html")
+		assert.Contains(t, rec.Body.String(), "250.00 ETB")
+		for _, m := range []string{"card", "bank_transfer", "mobile_money", "wallet"} {
+			assert.Contains(t, rec.Body.String(), `value="`+m+`"`)
+		}
+	})
+
+	t.Run("closed session has no form", func(t *testing.T) {
+		rec := serve(newMockService(domain.CheckoutExpired), httptest.NewRequest(http.MethodGet, "/checkout/tok", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.NotContains(t, rec.Body.String(), "<form")
+		assert.Contains(t, rec.Body.String(), "expired")
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		rec := serve(newMockService(domain.CheckoutOpen), httptest.NewRequest(http.MethodGet, "/checkout/nope", nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/html")
+	})
+}
+
+func TestConfirm(t *testing.T) {
+	post := func(form url.Values) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/checkout/tok/confirm", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		return req
+	}
+
+	t.Run("redirects to the success url", func(t *testing.T) {
+		svc := newMockService(domain.CheckoutOpen)
+		rec := serve(svc, post(url.Values{
+			"method":                {"mobile_money"},
+			"mobile_money.provider": {"telebirr"},
+			"mobile_money.msisdn":   {"+251911234567"},
+			"card.token":            {"ignored"},
+		}))
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "https://shop.example.com/done?order=7&session_id="+svc.session.ID.String(), rec.Header().Get(echo.HeaderLocation))
+		assert.Equal(t, "+251911234567", svc.method.MobileMoney.MSISDN)
+		assert.Nil(t, svc.method.Card)
+	})
+
+	t.Run("invalid details re-render the form", func(t *testing.T) {
+		rec := serve(newMockService(domain.CheckoutOpen), post(url.Values{"method": {"mobile_money"}}))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "<form")
+		assert.Contains(t, rec.Body.String(), "payment method validation failed")
+	})
+}
+
+func TestCancel(t *testing.T) {
+	svc := newMockService(domain.CheckoutOpen)
+	rec := serve(svc, httptest.NewRequest(http.MethodPost, "/checkout/tok/cancel", nil))
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "https://shop.example.com/cancel?session_id="+svc.session.ID.String(), rec.Header().Get(echo.HeaderLocation))
+
+	rec = serve(svc, httptest.NewRequest(http.MethodPost, "/checkout/tok/cancel", nil))
+	assert.Equal(t, http.StatusConflict, rec.Code)
+}
@@ -0,0 +1,194 @@
+package http
+
+import (
+	"net/http"
+
+	"pgm/internal/domain"
+	mw "pgm/internal/handler/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+// customerHandler handles HTTP requests for customers
+type customerHandler struct {
+	svc domain.CustomerService
+}
+
+// NewCustomerHandler initializes the customer routes. Each merchant manages
+// only its own customers, so every route needs a merchant API key.
+func NewCustomerHandler(g *echo.Group, svc domain.CustomerService) domain.CustomerHandler {
+	handler := &customerHandler{
+		svc: svc,
+	}
+	g.POST("/customers", handler.CreateCustomer, mw.RequireMerchant())
+	g.GET("/customers", handler.ListCustomers, mw.RequireMerchant())
+	g.GET("/customers/:id", handler.GetCustomerByID, mw.RequireMerchant())
+	g.PUT("/customers/:id", handler.UpdateCustomer, mw.RequireMerchant())
+	g.DELETE("/customers/:id", handler.DeleteCustomer, mw.RequireMerchant())
+	g.GET("/customers/:id/payments", handler.ListCustomerPayments, mw.RequireMerchant())
+	return handler
+}
+
+// CreateCustomer handles the creation of a new customer
+// @Summary Create a new customer
+// @Description Creates a new customer with the provided details
+// @Tags customers
+// @Security MerchantKey
+// @Accept json
+// @Produce json
+// @Param customer body domain.CustomerRequest true "Customer details"
+// @Success 201 {object} domain.Customer "Customer created successfully"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid merchant API key"
+// @Failure 409 {object} domain.ProblemDetails "Customer with this external ID already exists"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/customers [post]
+func (h *customerHandler) CreateCustomer(c echo.Context) error {
+	var cr domain.CustomerRequest
+	if err := c.Bind(&cr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.CreateCustomer(c.Request().Context(), &cr)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+// ListCustomers lists customers, newest first
+// @Summary List customers
+// @Description Lists customers, newest first
+// @Tags customers
+// @Security MerchantKey
+// @Produce json
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of customers to skip"
+// @Success 200 {object} domain.CustomerList "Customers"
+// @Failure 400 {object} domain.ProblemDetails "Invalid pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid merchant API key"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/customers [get]
+func (h *customerHandler) ListCustomers(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListCustomers(c.Request().Context(), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetCustomerByID retrieves a customer by its ID
+// @Summary Get customer by ID
+// @Description Retrieves customer details by customer ID
+// @Tags customers
+// @Security MerchantKey
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Success 200 {object} domain.Customer "Customer found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid customer ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid merchant API key"
+// @Failure 404 {object} domain.ProblemDetails "Customer not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/customers/{id} [get]
+func (h *customerHandler) GetCustomerByID(c echo.Context) error {
+	res, err := h.svc.GetCustomerByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// UpdateCustomer replaces a customer's details
+// @Summary Update customer
+// @Description Replaces the details of an existing customer
+// @Tags customers
+// @Security MerchantKey
+// @Accept json
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param customer body domain.CustomerRequest true "Customer details"
+// @Success 200 {object} domain.Customer "Customer updated"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid merchant API key"
+// @Failure 404 {object} domain.ProblemDetails "Customer not found"
+// @Failure 409 {object} domain.ProblemDetails "Customer with this external ID already exists"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/customers/{id} [put]
+func (h *customerHandler) UpdateCustomer(c echo.Context) error {
+	var cr domain.CustomerRequest
+	if err := c.Bind(&cr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.UpdateCustomer(c.Request().Context(), c.Param("id"), &cr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteCustomer deletes a customer without payments
+// @Summary Delete customer
+// @Description Deletes a customer. Customers with payments cannot be deleted.
+// @Tags customers
+// @Security MerchantKey
+// @Param id path string true "Customer ID"
+// @Success 204 "Customer deleted"
+// @Failure 400 {object} domain.ProblemDetails "Invalid customer ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid merchant API key"
+// @Failure 404 {object} domain.ProblemDetails "Customer not found"
+// @Failure 409 {object} domain.ProblemDetails "Customer has payments"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/customers/{id} [delete]
+func (h *customerHandler) DeleteCustomer(c echo.Context) error {
+	if err := h.svc.DeleteCustomer(c.Request().Context(), c.Param("id")); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListCustomerPayments lists a customer's payments, newest first
+// @Summary List customer payments
+// @Description Lists the payments made by a customer, newest first
+// @Tags customers
+// @Security MerchantKey
+// @Produce json
+// @Param id path string true "Customer ID"
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of payments to skip"
+// @Success 200 {object} domain.PaymentList "Payments"
+// @Failure 400 {object} domain.ProblemDetails "Invalid customer ID or pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid merchant API key"
+// @Failure 404 {object} domain.ProblemDetails "Customer not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/customers/{id}/payments [get]
+func (h *customerHandler) ListCustomerPayments(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListCustomerPayments(c.Request().Context(), c.Param("id"), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
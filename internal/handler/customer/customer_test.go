@@ -0,0 +1,152 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	cst "pgm/internal/handler/customer"
+	mw "pgm/internal/handler/middleware"
+)
+
+type mockService struct {
+	domain.CustomerService
+	page domain.Page
+}
+
+func (m *mockService) CreateCustomer(ctx context.Context, cr *domain.CustomerRequest) (*domain.Customer, error) {
+	if err := cr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"customer request validation failed",
+			err,
+			nil,
+		)
+	}
+	return &domain.Customer{ID: uuid.New(), Name: cr.Name, Email: cr.Email}, nil
+}
+
+func (m *mockService) ListCustomers(ctx context.Context, page domain.Page) (*domain.CustomerList, error) {
+	m.page = page
+	return &domain.CustomerList{Data: []domain.Customer{}, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (m *mockService) DeleteCustomer(ctx context.Context, id string) error {
+	return nil
+}
+
+func serve(svc domain.CustomerService, method, target string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, bytes.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(mw.HeaderAPIKey, "k3y")
+	return serveRequest(svc, req)
+}
+
+func serveRequest(svc domain.CustomerService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	cst.NewCustomerHandler(e.Group("/v1", mw.MerchantAuth(map[string]string{"acme": "k3y"})), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCustomerRoutesNeedMerchantKey(t *testing.T) {
+	svc := &mockService{}
+	rec := serveRequest(svc, httptest.NewRequest(http.MethodGet, "/v1/customers", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Zero(t, svc.page)
+
+	rec = serveRequest(svc, httptest.NewRequest(http.MethodDelete, "/v1/customers/"+uuid.NewString(), nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestCreateCustomer(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		expectedStatus int
+	}{
+		{
+			name:           "created",
+			body:           map[string]interface{}{"name": "Abebe", "email": "abebe@example.com"},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name:           "missing name",
+			body:           map[string]interface{}{"email": "abebe@example.com"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid email",
+			body:           map[string]interface{}{"name": "Abebe", "email": "abebe"},
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			rec := serve(&mockService{}, http.MethodPost, "/v1/customers", body)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestListCustomers(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedPage   domain.Page
+	}{
+		{
+			name:           "defaults",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedPage:   domain.Page{Limit: domain.DefaultPageLimit},
+		},
+		{
+			name:           "explicit page",
+			query:          "?limit=5&offset=10",
+			expectedStatus: http.StatusOK,
+			expectedPage:   domain.Page{Limit: 5, Offset: 10},
+		},
+		{
+			name:           "limit is capped",
+			query:          "?limit=1000",
+			expectedStatus: http.StatusOK,
+			expectedPage:   domain.Page{Limit: domain.MaxPageLimit},
+		},
+		{
+			name:           "invalid offset",
+			query:          "?offset=-1",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{}
+			rec := serve(svc, http.MethodGet, "/v1/customers"+tt.query, nil)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedPage, svc.page)
+		})
+	}
+}
+
+func TestDeleteCustomer(t *testing.T) {
+	rec := serve(&mockService{}, http.MethodDelete, "/v1/customers/"+uuid.NewString(), nil)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Body.String())
+}
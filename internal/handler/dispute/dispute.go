@@ -0,0 +1,295 @@
+package http
+
+import (
+	"errors"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"pgm/internal/domain"
+	mw "pgm/internal/handler/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+// disputeHandler handles HTTP requests for disputes and their evidence
+type disputeHandler struct {
+	svc domain.DisputeService
+}
+
+// NewDisputeHandler initializes the dispute routes. Merchants read and answer
+// disputes on their own payments on g; operators record and close disputes
+// and read every one on admin.
+func NewDisputeHandler(g, admin *echo.Group, svc domain.DisputeService) domain.DisputeHandler {
+	handler := &disputeHandler{
+		svc: svc,
+	}
+	g.GET("/disputes", handler.ListDisputes, mw.RequireMerchant())
+	g.GET("/disputes/:id", handler.GetDisputeByID, mw.RequireMerchant())
+	g.POST("/disputes/:id/evidence", handler.SubmitEvidence, mw.RequireMerchant())
+	g.GET("/disputes/:id/evidence", handler.ListEvidence, mw.RequireMerchant())
+	g.GET("/disputes/:id/evidence/:evidence_id", handler.GetEvidenceFile, mw.RequireMerchant())
+	admin.POST("/disputes", handler.CreateDispute)
+	admin.GET("/disputes", handler.ListDisputes)
+	admin.GET("/disputes/:id", handler.GetDisputeByID)
+	admin.GET("/disputes/:id/evidence", handler.ListEvidence)
+	admin.GET("/disputes/:id/evidence/:evidence_id", handler.GetEvidenceFile)
+	admin.POST("/disputes/:id/close", handler.CloseDispute)
+	return handler
+}
+
+// CreateDispute records a chargeback against a payment
+// @Summary Create a dispute
+// @Description Records a chargeback raised against a successful payment. The dispute needs a response before evidence_due_by; the amount defaults to the full payment amount. Publishes a dispute.created event
+// @Tags disputes
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param dispute body domain.DisputeRequest true "Dispute details"
+// @Success 201 {object} domain.Dispute "Dispute created"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Payment not found"
+// @Failure 409 {object} domain.ProblemDetails "Payment not successful, disputes would exceed the payment amount, or provider reference already used"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/disputes [post]
+func (h *disputeHandler) CreateDispute(c echo.Context) error {
+	var dr domain.DisputeRequest
+	if err := c.Bind(&dr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.CreateDispute(c.Request().Context(), &dr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// ListDisputes lists disputes, newest first
+// @Summary List disputes
+// @Description Lists disputes newest first, optionally for one payment or in one status. A merchant API key sees only disputes on its own payments
+// @Tags disputes
+// @Security MerchantKey
+// @Security ApiKeyAuth
+// @Produce json
+// @Param payment_id query string false "Only disputes against this payment"
+// @Param status query string false "Only disputes in this status" Enums(needs_response, under_review, won, lost)
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of disputes to skip"
+// @Success 200 {object} domain.DisputeList "Disputes"
+// @Failure 400 {object} domain.ProblemDetails "Invalid filter or pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing merchant API key or admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/disputes [get]
+// @Router /admin/disputes [get]
+func (h *disputeHandler) ListDisputes(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+	filter, err := domain.ParseDisputeFilter(c.QueryParams())
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListDisputes(c.Request().Context(), filter, page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetDisputeByID retrieves a dispute by its ID
+// @Summary Get dispute by ID
+// @Description Retrieves a dispute with its status and evidence deadline
+// @Tags disputes
+// @Security MerchantKey
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Success 200 {object} domain.Dispute "Dispute found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid dispute ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing merchant API key or admin token"
+// @Failure 404 {object} domain.ProblemDetails "Dispute not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/disputes/{id} [get]
+// @Router /admin/disputes/{id} [get]
+func (h *disputeHandler) GetDisputeByID(c echo.Context) error {
+	res, err := h.svc.GetDisputeByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// SubmitEvidence uploads the response to a dispute
+// @Summary Submit dispute evidence
+// @Description Submits a written description and up to 10 files (PDF, PNG, JPEG or plain text, 5 MiB each, 25 MiB in total) before the evidence deadline, moving the dispute under review. Evidence can be submitted once. Publishes a dispute.under_review event
+// @Tags disputes
+// @Security MerchantKey
+// @Accept mpfd
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Param description formData string false "Written response to the dispute"
+// @Param files formData file false "Evidence files; repeat the field for several"
+// @Success 200 {object} domain.Dispute "Evidence submitted"
+// @Failure 400 {object} domain.ProblemDetails "Invalid dispute ID, form or validation failed"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid merchant API key"
+// @Failure 404 {object} domain.ProblemDetails "Dispute not found"
+// @Failure 409 {object} domain.ProblemDetails "Dispute not awaiting a response or deadline passed"
+// @Failure 413 {object} domain.ProblemDetails "Evidence too large"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/disputes/{id}/evidence [post]
+func (h *disputeHandler) SubmitEvidence(c echo.Context) error {
+	req := c.Request()
+	req.Body = http.MaxBytesReader(c.Response(), req.Body, domain.MaxEvidenceSize)
+	if err := req.ParseMultipartForm(domain.MaxEvidenceFileSize); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return domain.NewError(
+				domain.ErrRequestTooLarge,
+				"evidence too large",
+				"evidence submissions are limited to 25 MiB",
+				nil,
+				nil,
+			)
+		}
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to parse multipart form",
+			err,
+			nil,
+		)
+	}
+	defer req.MultipartForm.RemoveAll()
+
+	es := domain.EvidenceSubmission{Description: req.FormValue("description")}
+	for _, fh := range req.MultipartForm.File["files"] {
+		f, err := fh.Open()
+		if err != nil {
+			return domain.NewError(
+				domain.ErrInvalidRequestBody,
+				"invalid request body",
+				"failed to read uploaded file",
+				err,
+				map[string]interface{}{"file_name": fh.Filename},
+			)
+		}
+		defer f.Close()
+		es.Files = append(es.Files, evidenceFile(fh, f))
+	}
+
+	res, err := h.svc.SubmitEvidence(req.Context(), c.Param("id"), &es)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// evidenceFile describes an uploaded part, dropping any media type
+// parameters so "text/plain; charset=utf-8" is accepted as plain text.
+func evidenceFile(fh *multipart.FileHeader, f multipart.File) domain.EvidenceFile {
+	contentType, _, _ := mime.ParseMediaType(fh.Header.Get(echo.HeaderContentType))
+	return domain.EvidenceFile{
+		FileName:    fh.Filename,
+		ContentType: contentType,
+		Size:        fh.Size,
+		Content:     f,
+	}
+}
+
+// ListEvidence lists the files submitted for a dispute
+// @Summary List dispute evidence
+// @Description Lists the evidence files submitted for a dispute
+// @Tags disputes
+// @Security MerchantKey
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Success 200 {object} domain.DisputeEvidenceList "Evidence files"
+// @Failure 400 {object} domain.ProblemDetails "Invalid dispute ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing merchant API key or admin token"
+// @Failure 404 {object} domain.ProblemDetails "Dispute not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/disputes/{id}/evidence [get]
+// @Router /admin/disputes/{id}/evidence [get]
+func (h *disputeHandler) ListEvidence(c echo.Context) error {
+	res, err := h.svc.ListEvidence(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetEvidenceFile downloads an evidence file
+// @Summary Download dispute evidence
+// @Description Downloads an evidence file as it was uploaded
+// @Tags disputes
+// @Security MerchantKey
+// @Security ApiKeyAuth
+// @Produce octet-stream
+// @Param id path string true "Dispute ID"
+// @Param evidence_id path string true "Evidence ID"
+// @Success 200 {file} file "Evidence file"
+// @Failure 400 {object} domain.ProblemDetails "Invalid dispute or evidence ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing merchant API key or admin token"
+// @Failure 404 {object} domain.ProblemDetails "Evidence not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/disputes/{id}/evidence/{evidence_id} [get]
+// @Router /admin/disputes/{id}/evidence/{evidence_id} [get]
+func (h *disputeHandler) GetEvidenceFile(c echo.Context) error {
+	evidence, content, err := h.svc.OpenEvidence(c.Request().Context(), c.Param("id"), c.Param("evidence_id"))
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": evidence.FileName}))
+	header.Set(echo.HeaderContentLength, strconv.FormatInt(evidence.Size, 10))
+	return c.Stream(http.StatusOK, evidence.ContentType, content)
+}
+
+// CloseDispute records the provider's decision on a dispute
+// @Summary Close a dispute
+// @Description Records whether the dispute was won or lost. Publishes a dispute.won or dispute.lost event
+// @Tags disputes
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Dispute ID"
+// @Param outcome body domain.CloseDisputeRequest true "Outcome"
+// @Success 200 {object} domain.Dispute "Dispute closed"
+// @Failure 400 {object} domain.ProblemDetails "Invalid dispute ID, request body or validation failed"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Dispute not found"
+// @Failure 409 {object} domain.ProblemDetails "Dispute already closed"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/disputes/{id}/close [post]
+func (h *disputeHandler) CloseDispute(c echo.Context) error {
+	var cr domain.CloseDisputeRequest
+	if err := c.Bind(&cr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.CloseDispute(c.Request().Context(), c.Param("id"), &cr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
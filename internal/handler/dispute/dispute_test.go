@@ -0,0 +1,165 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	dsp "pgm/internal/handler/dispute"
+	mw "pgm/internal/handler/middleware"
+)
+
+type mockService struct {
+	domain.DisputeService
+	submission *domain.EvidenceSubmission
+	contents   []string
+	filter     domain.DisputeFilter
+	closed     bool
+}
+
+func (m *mockService) CloseDispute(ctx context.Context, id string, cr *domain.CloseDisputeRequest) (*domain.Dispute, error) {
+	m.closed = true
+	return &domain.Dispute{Status: cr.Outcome}, nil
+}
+
+func (m *mockService) SubmitEvidence(ctx context.Context, id string, es *domain.EvidenceSubmission) (*domain.Dispute, error) {
+	m.submission = es
+	for _, f := range es.Files {
+		b, _ := io.ReadAll(f.Content)
+		m.contents = append(m.contents, string(b))
+	}
+	return &domain.Dispute{Status: domain.DisputeUnderReview}, nil
+}
+
+func (m *mockService) ListDisputes(ctx context.Context, filter domain.DisputeFilter, page domain.Page) (*domain.DisputeList, error) {
+	m.filter = filter
+	return &domain.DisputeList{Data: []domain.Dispute{}, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (m *mockService) OpenEvidence(ctx context.Context, id, evidenceID string) (*domain.DisputeEvidence, io.ReadCloser, error) {
+	evidence := &domain.DisputeEvidence{FileName: "receipt.pdf", ContentType: "application/pdf", Size: 8}
+	return evidence, io.NopCloser(strings.NewReader("%PDF-1.4")), nil
+}
+
+func serve(svc domain.DisputeService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	dsp.NewDisputeHandler(e.Group("/v1", mw.MerchantAuth(map[string]string{"acme": "k3y"})), e.Group("/admin"), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSubmitEvidence(t *testing.T) {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	assert.NoError(t, w.WriteField("description", "Goods were delivered"))
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {`form-data; name="files"; filename="note.txt"`},
+		"Content-Type":        {"text/plain; charset=utf-8"},
+	})
+	assert.NoError(t, err)
+	_, _ = part.Write([]byte("tracking 123"))
+	assert.NoError(t, w.Close())
+
+	svc := &mockService{}
+	req := httptest.NewRequest(http.MethodPost, "/v1/disputes/"+uuid.NewString()+"/evidence", &body)
+	req.Header.Set(mw.HeaderAPIKey, "k3y")
+	req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+	rec := serve(svc, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if svc.submission == nil || len(svc.submission.Files) != 1 {
+		t.Fatal("expected a submission with one file")
+	}
+	assert.Equal(t, "Goods were delivered", svc.submission.Description)
+	assert.Equal(t, "note.txt", svc.submission.Files[0].FileName)
+	assert.Equal(t, "text/plain", svc.submission.Files[0].ContentType)
+	assert.Equal(t, int64(12), svc.submission.Files[0].Size)
+	assert.Equal(t, []string{"tracking 123"}, svc.contents)
+}
+
+func TestSubmitEvidenceErrors(t *testing.T) {
+	t.Run("not multipart", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/v1/disputes/abc/evidence", strings.NewReader(`{}`))
+		req.Header.Set(mw.HeaderAPIKey, "k3y")
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := serve(&mockService{}, req)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		part, _ := w.CreateFormFile("files", "big.pdf")
+		_, _ = part.Write(bytes.Repeat([]byte("x"), domain.MaxEvidenceSize+1))
+		_ = w.Close()
+
+		svc := &mockService{}
+		req := httptest.NewRequest(http.MethodPost, "/v1/disputes/abc/evidence", &body)
+		req.Header.Set(mw.HeaderAPIKey, "k3y")
+		req.Header.Set(echo.HeaderContentType, w.FormDataContentType())
+		rec := serve(svc, req)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+		assert.Nil(t, svc.submission)
+	})
+}
+
+func TestListDisputes(t *testing.T) {
+	svc := &mockService{}
+	req := httptest.NewRequest(http.MethodGet, "/v1/disputes?status=under_review", nil)
+	req.Header.Set(mw.HeaderAPIKey, "k3y")
+	rec := serve(svc, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.NotNil(t, svc.filter.Status) {
+		assert.Equal(t, domain.DisputeUnderReview, *svc.filter.Status)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/disputes?status=open", nil)
+	req.Header.Set(mw.HeaderAPIKey, "k3y")
+	rec = serve(svc, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = serve(&mockService{}, httptest.NewRequest(http.MethodGet, "/v1/disputes", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = serve(&mockService{}, httptest.NewRequest(http.MethodGet, "/admin/disputes", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestCloseDispute(t *testing.T) {
+	svc := &mockService{}
+	req := httptest.NewRequest(http.MethodPost, "/v1/disputes/abc/close", strings.NewReader(`{"outcome":"won"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(mw.HeaderAPIKey, "k3y")
+	rec := serve(svc, req)
+	assert.NotEqual(t, http.StatusOK, rec.Code)
+	assert.False(t, svc.closed)
+
+	req = httptest.NewRequest(http.MethodPost, "/admin/disputes/abc/close", strings.NewReader(`{"outcome":"won"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec = serve(svc, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, svc.closed)
+}
+
+func TestGetEvidenceFile(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/v1/disputes/abc/evidence/def", nil)
+	req.Header.Set(mw.HeaderAPIKey, "k3y")
+	rec := serve(&mockService{}, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pdf", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, `attachment; filename=receipt.pdf`, rec.Header().Get(echo.HeaderContentDisposition))
+	assert.Equal(t, "%PDF-1.4", rec.Body.String())
+}
@@ -0,0 +1,104 @@
+package http
+
+import (
+	"net/http"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// feeHandler handles HTTP requests for fee schedules
+type feeHandler struct {
+	svc domain.FeeService
+}
+
+// NewFeeHandler initializes the fee schedule routes
+func NewFeeHandler(g *echo.Group, svc domain.FeeService) domain.FeeHandler {
+	handler := &feeHandler{
+		svc: svc,
+	}
+	g.POST("/fee-schedules", handler.CreateFeeSchedule)
+	g.GET("/fee-schedules", handler.ListFeeSchedules)
+	g.GET("/fee-schedules/:id", handler.GetFeeScheduleByID)
+	return handler
+}
+
+// CreateFeeSchedule handles the creation of a new fee schedule
+// @Summary Create a fee schedule
+// @Description Creates a fee schedule for a currency, optionally limited to one payment method and one merchant. The fee is percentage percent of the amount plus fixed_amount, held between min_fee and max_fee. Schedules cannot be edited; create a new one with a later effective_from to change fees
+// @Tags fees
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param schedule body domain.FeeScheduleRequest true "Fee schedule details"
+// @Success 201 {object} domain.FeeSchedule "Fee schedule created"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/fee-schedules [post]
+func (h *feeHandler) CreateFeeSchedule(c echo.Context) error {
+	var fr domain.FeeScheduleRequest
+	if err := c.Bind(&fr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.CreateFeeSchedule(c.Request().Context(), &fr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// ListFeeSchedules lists fee schedules by currency, newest first
+// @Summary List fee schedules
+// @Description Lists fee schedules by currency, the most recently effective first
+// @Tags fees
+// @Security ApiKeyAuth
+// @Produce json
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of fee schedules to skip"
+// @Success 200 {object} domain.FeeScheduleList "Fee schedules"
+// @Failure 400 {object} domain.ProblemDetails "Invalid pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/fee-schedules [get]
+func (h *feeHandler) ListFeeSchedules(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListFeeSchedules(c.Request().Context(), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetFeeScheduleByID retrieves a fee schedule by its ID
+// @Summary Get fee schedule by ID
+// @Description Retrieves a fee schedule by its ID
+// @Tags fees
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Fee schedule ID"
+// @Success 200 {object} domain.FeeSchedule "Fee schedule found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid fee schedule ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Fee schedule not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/fee-schedules/{id} [get]
+func (h *feeHandler) GetFeeScheduleByID(c echo.Context) error {
+	res, err := h.svc.GetFeeScheduleByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
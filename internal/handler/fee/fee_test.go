@@ -0,0 +1,65 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	fee "pgm/internal/handler/fee"
+)
+
+type mockService struct {
+	domain.FeeService
+	created *domain.FeeScheduleRequest
+}
+
+func (m *mockService) CreateFeeSchedule(ctx context.Context, fr *domain.FeeScheduleRequest) (*domain.FeeSchedule, error) {
+	m.created = fr
+	return &domain.FeeSchedule{ID: uuid.New(), Currency: fr.Currency, Percentage: fr.Percentage, FixedAmount: fr.FixedAmount}, nil
+}
+
+func serve(svc domain.FeeService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	fee.NewFeeHandler(e.Group("/admin"), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateFeeSchedule(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"created", `{"currency":"USD","payment_method":"card","percentage":2.9,"fixed_amount":0.3,"max_fee":25}`, http.StatusCreated},
+		{"malformed body", `{"currency":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{}
+			req := httptest.NewRequest(http.MethodPost, "/admin/fee-schedules", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := serve(svc, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			if svc.created == nil || svc.created.MaxFee == nil {
+				t.Fatal("request not bound")
+			}
+			assert.Equal(t, domain.MethodCard, svc.created.PaymentMethod)
+			assert.Equal(t, 25.0, *svc.created.MaxFee)
+			assert.Contains(t, rec.Body.String(), `"percentage":2.9`)
+		})
+	}
+}
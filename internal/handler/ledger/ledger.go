@@ -0,0 +1,81 @@
+package http
+
+import (
+	"net/http"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ledgerHandler serves read-only views of the double-entry ledger
+type ledgerHandler struct {
+	svc domain.LedgerService
+}
+
+// NewLedgerHandler initializes the ledger routes
+func NewLedgerHandler(g *echo.Group, svc domain.LedgerService) domain.LedgerHandler {
+	handler := &ledgerHandler{
+		svc: svc,
+	}
+	g.GET("/ledger/balances", handler.GetBalances)
+	g.GET("/ledger/integrity", handler.CheckIntegrity)
+	g.GET("/ledger/payments/:id", handler.ListPaymentEntries)
+	return handler
+}
+
+// GetBalances returns the balance of every ledger account
+// @Summary Get ledger balances
+// @Description Returns the balance of every ledger account per currency, in the account's normal direction
+// @Tags ledger
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} domain.LedgerBalances "Account balances"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/ledger/balances [get]
+func (h *ledgerHandler) GetBalances(c echo.Context) error {
+	res, err := h.svc.GetBalances(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// CheckIntegrity verifies the ledger
+// @Summary Check ledger integrity
+// @Description Reports journal entries whose postings do not sum to zero and successful payments missing from the ledger. ok is true when there are none.
+// @Tags ledger
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} domain.IntegrityReport "Integrity report"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/ledger/integrity [get]
+func (h *ledgerHandler) CheckIntegrity(c echo.Context) error {
+	res, err := h.svc.CheckIntegrity(c.Request().Context())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListPaymentEntries lists the journal entries recorded for a payment
+// @Summary List payment journal entries
+// @Description Lists the journal entries recorded for a payment, with their postings, oldest first
+// @Tags ledger
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Payment ID"
+// @Success 200 {object} domain.LedgerEntryList "Journal entries"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/ledger/payments/{id} [get]
+func (h *ledgerHandler) ListPaymentEntries(c echo.Context) error {
+	res, err := h.svc.ListPaymentEntries(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
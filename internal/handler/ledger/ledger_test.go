@@ -0,0 +1,69 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	ldg "pgm/internal/handler/ledger"
+)
+
+type mockService struct {
+	domain.LedgerService
+	report *domain.IntegrityReport
+}
+
+func (m *mockService) CheckIntegrity(ctx context.Context) (*domain.IntegrityReport, error) {
+	return m.report, nil
+}
+
+func (m *mockService) ListPaymentEntries(ctx context.Context, id string) (*domain.LedgerEntryList, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.NewError(domain.ErrInvalidPaymentID, "Invalid payment ID format", "The provided payment ID is not a valid UUID format", err, nil)
+	}
+	return &domain.LedgerEntryList{Data: []domain.JournalEntry{}}, nil
+}
+
+func serve(svc domain.LedgerService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	ldg.NewLedgerHandler(e.Group("/admin"), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	svc := &mockService{report: &domain.IntegrityReport{
+		Unbalanced:         []domain.UnbalancedEntry{{JournalEntryID: uuid.New(), Currency: "ETB", Total: 0.01}},
+		UnrecordedPayments: []uuid.UUID{},
+	}}
+	rec := serve(svc, httptest.NewRequest(http.MethodGet, "/admin/ledger/integrity", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"ok":false`)
+	assert.Contains(t, rec.Body.String(), `"total":0.01`)
+}
+
+func TestListPaymentEntries(t *testing.T) {
+	tests := []struct {
+		name           string
+		id             string
+		expectedStatus int
+	}{
+		{"valid id", uuid.NewString(), http.StatusOK},
+		{"invalid id", "nope", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(&mockService{}, httptest.NewRequest(http.MethodGet, "/admin/ledger/payments/"+tt.id, nil))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Actor stores who makes the request, and from where, on the request context
+// so the audit log can record it. Requests are recorded as domain.ActorAPI
+// until an authentication middleware names the caller.
+func Actor() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			c.SetRequest(req.WithContext(domain.WithActor(req.Context(), domain.Actor{Name: domain.ActorAPI, SourceIP: c.RealIP()})))
+			return next(c)
+		}
+	}
+}
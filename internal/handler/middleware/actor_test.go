@@ -0,0 +1,34 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	mw "pgm/internal/handler/middleware"
+)
+
+func TestActor(t *testing.T) {
+	var actor domain.Actor
+	e := echo.New()
+	e.Use(mw.Actor())
+	e.POST("/v1/payments", func(c echo.Context) error {
+		actor = domain.ActorFromContext(c.Request().Context())
+		return c.NoContent(http.StatusCreated)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/payments", nil)
+	req.RemoteAddr = "203.0.113.7:4321"
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, domain.Actor{Name: domain.ActorAPI, SourceIP: "203.0.113.7"}, actor)
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/payments", nil)
+	req.Header.Set("X-Actor", "alice")
+	req.Header.Set(echo.HeaderXRealIP, "198.51.100.2")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, domain.Actor{Name: domain.ActorAPI, SourceIP: "198.51.100.2"}, actor)
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"strings"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ParseOperatorTokens parses a comma-separated list of name:token pairs, such
+// as "alice:t0ken,bob:s3cret", into tokens keyed by operator name.
+func ParseOperatorTokens(s string) (map[string]string, error) {
+	tokens, err := parseNamedTokens(s, "operator token")
+	if err != nil {
+		return nil, err
+	}
+	for name := range tokens {
+		switch name {
+		case domain.ActorAPI, domain.ActorWorker, domain.ActorSystem, domain.ReviewerSLA:
+			return nil, fmt.Errorf("operator name %q is reserved", name)
+		}
+	}
+	return tokens, nil
+}
+
+// AdminAuth admits requests that carry one of the operators' tokens as a
+// bearer token in the Authorization header. The request is recorded under the
+// name of the operator the token belongs to.
+func AdminAuth(tokens map[string]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			operator := ""
+			if given, ok := strings.CutPrefix(req.Header.Get(echo.HeaderAuthorization), "Bearer "); ok {
+				operator = matchToken(tokens, given)
+			}
+			if operator == "" {
+				c.Response().Header().Set(echo.HeaderWWWAuthenticate, `Bearer realm="admin"`)
+				return domain.NewError(
+					domain.ErrUnauthorized,
+					"unauthorized",
+					"a valid admin token is required",
+					nil,
+					nil,
+				)
+			}
+			actor := domain.ActorFromContext(req.Context())
+			actor.Name = operator
+			c.SetRequest(req.WithContext(domain.WithActor(req.Context(), actor)))
+			return next(c)
+		}
+	}
+}
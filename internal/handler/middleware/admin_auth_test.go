@@ -0,0 +1,77 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	mw "pgm/internal/handler/middleware"
+)
+
+func TestAdminAuth(t *testing.T) {
+	var actor domain.Actor
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	e.Use(mw.Actor())
+	g := e.Group("/admin", mw.AdminAuth(map[string]string{"alice": "s3cret", "bob": "t0ken"}))
+	g.GET("/payments/:id/attempts", func(c echo.Context) error {
+		actor = domain.ActorFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	do := func(authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/payments/1/attempts", nil)
+		if authorization != "" {
+			req.Header.Set(echo.HeaderAuthorization, authorization)
+		}
+		req.Header.Set("X-Actor", "mallory")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		name          string
+		authorization string
+	}{
+		{"missing token", ""},
+		{"wrong token", "Bearer guess"},
+		{"empty token", "Bearer "},
+		{"wrong scheme", "Basic s3cret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := do(tt.authorization)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.Contains(t, rec.Body.String(), `"code":"request.unauthorized"`)
+			assert.NotEmpty(t, rec.Header().Get(echo.HeaderWWWAuthenticate))
+		})
+	}
+
+	rec := do("Bearer s3cret")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "alice", actor.Name)
+
+	rec = do("Bearer t0ken")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "bob", actor.Name)
+}
+
+func TestParseOperatorTokens(t *testing.T) {
+	tokens, err := mw.ParseOperatorTokens(" alice:s3cret, bob:t0ken ,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"alice": "s3cret", "bob": "t0ken"}, tokens)
+
+	tokens, err = mw.ParseOperatorTokens("")
+	assert.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	for _, s := range []string{"s3cret", "alice:", ":s3cret", "alice:a,alice:b", "sla:s3cret", "worker:s3cret"} {
+		_, err := mw.ParseOperatorTokens(s)
+		assert.Error(t, err, s)
+	}
+}
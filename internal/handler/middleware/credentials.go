@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// parseNamedTokens parses a comma-separated list of name:token pairs into
+// tokens keyed by name. what names the kind of token in errors.
+func parseNamedTokens(s, what string) (map[string]string, error) {
+	tokens := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, token, ok := strings.Cut(pair, ":")
+		name, token = strings.TrimSpace(name), strings.TrimSpace(token)
+		if !ok || name == "" || token == "" {
+			return nil, fmt.Errorf("%s %q must be name:token", what, pair)
+		}
+		if _, dup := tokens[name]; dup {
+			return nil, fmt.Errorf("%q has more than one %s", name, what)
+		}
+		tokens[name] = token
+	}
+	return tokens, nil
+}
+
+// matchToken returns the name whose token is given, or "" if none is. Every
+// token is compared so the time taken does not reveal which one, if any,
+// matched.
+func matchToken(tokens map[string]string, given string) string {
+	matched := ""
+	for name, token := range tokens {
+		if subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+			matched = name
+		}
+	}
+	return matched
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderAPIKey carries a merchant's API key.
+const HeaderAPIKey = "X-API-Key"
+
+// ParseMerchantKeys parses a comma-separated list of merchant:key pairs, such
+// as "acme:k3y,globex:s3cret", into API keys keyed by merchant ID.
+func ParseMerchantKeys(s string) (map[string]string, error) {
+	return parseNamedTokens(s, "merchant API key")
+}
+
+// MerchantAuth authenticates requests that carry an X-API-Key header and
+// stores the key's merchant on the request context. Requests without the
+// header pass through anonymously; a key that matches no merchant is
+// rejected.
+func MerchantAuth(keys map[string]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			given := req.Header.Get(HeaderAPIKey)
+			if given == "" {
+				return next(c)
+			}
+			merchant := matchToken(keys, given)
+			if merchant == "" {
+				return domain.NewError(
+					domain.ErrUnauthorized,
+					"unauthorized",
+					"the API key is not valid",
+					nil,
+					nil,
+				)
+			}
+			c.SetRequest(req.WithContext(domain.WithMerchant(req.Context(), merchant)))
+			return next(c)
+		}
+	}
+}
+
+// RequireMerchant rejects requests MerchantAuth did not authenticate. It
+// guards routes that show a merchant's own records.
+func RequireMerchant() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := domain.MerchantFromContext(c.Request().Context()); !ok {
+				return domain.NewError(
+					domain.ErrUnauthorized,
+					"unauthorized",
+					"a merchant API key is required",
+					nil,
+					nil,
+				)
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	mw "pgm/internal/handler/middleware"
+)
+
+func TestMerchantAuth(t *testing.T) {
+	var merchant string
+	var authenticated bool
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	g := e.Group("/v1", mw.MerchantAuth(map[string]string{"acme": "k3y", "globex": "s3cret"}))
+	g.POST("/payments", func(c echo.Context) error {
+		merchant, authenticated = domain.MerchantFromContext(c.Request().Context())
+		return c.NoContent(http.StatusCreated)
+	})
+
+	do := func(apiKey string) *httptest.ResponseRecorder {
+		merchant, authenticated = "", false
+		req := httptest.NewRequest(http.MethodPost, "/v1/payments", nil)
+		if apiKey != "" {
+			req.Header.Set(mw.HeaderAPIKey, apiKey)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := do("")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.False(t, authenticated)
+
+	rec = do("s3cret")
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.True(t, authenticated)
+	assert.Equal(t, "globex", merchant)
+
+	rec = do("guess")
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"code":"request.unauthorized"`)
+	assert.False(t, authenticated)
+}
+
+func TestParseMerchantKeys(t *testing.T) {
+	keys, err := mw.ParseMerchantKeys("acme:k3y, globex:s3cret")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"acme": "k3y", "globex": "s3cret"}, keys)
+
+	for _, s := range []string{"k3y", "acme:", "acme:a,acme:b"} {
+		_, err := mw.ParseMerchantKeys(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"strconv"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+	"pgm/internal/ratelimit"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+)
+
+// RateLimit enforces per-route limits keyed by the merchant MerchantAuth
+// authenticated, falling back to the client IP. Routes are looked up as
+// "<METHOD> <path>" in rules; routes without a rule use def. If the limiter
+// itself fails the request is let through.
+func RateLimit(l ratelimit.Limiter, def ratelimit.Limit, rules map[string]ratelimit.Limit) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			route := c.Request().Method + " " + c.Path()
+			limit, ok := rules[route]
+			if !ok {
+				limit = def
+			}
+			if !limit.Enabled() {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+			res, err := l.Allow(ctx, route+"|"+clientKey(c), limit)
+			if err != nil {
+				logger.FromContext(ctx).Warn("rate limiter unavailable, allowing request",
+					slog.String("route", route),
+					slog.Any("error", err),
+				)
+				return next(c)
+			}
+
+			h := c.Response().Header()
+			h.Set(HeaderRateLimitLimit, strconv.Itoa(res.Limit))
+			h.Set(HeaderRateLimitRemaining, strconv.Itoa(res.Remaining))
+			h.Set(HeaderRateLimitReset, ceilSeconds(res.Reset))
+			if !res.Allowed {
+				retryAfter := ceilSeconds(res.RetryAfter)
+				h.Set(echo.HeaderRetryAfter, retryAfter)
+				return domain.NewError(
+					domain.ErrRateLimited,
+					"rate limit exceeded",
+					fmt.Sprintf("too many requests, retry after %s seconds", retryAfter),
+					nil,
+					nil,
+				)
+			}
+			return next(c)
+		}
+	}
+}
+
+// clientKey identifies the caller. Only a validated credential is trusted;
+// anything else the caller sends could be varied to get a fresh bucket.
+func clientKey(c echo.Context) string {
+	if merchantID, ok := domain.MerchantFromContext(c.Request().Context()); ok {
+		return "merchant:" + merchantID
+	}
+	return "ip:" + c.RealIP()
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
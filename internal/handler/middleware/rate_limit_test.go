@@ -0,0 +1,94 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	mw "pgm/internal/handler/middleware"
+	"pgm/internal/ratelimit"
+)
+
+func setupRateLimited(t *testing.T, routes string) *echo.Echo {
+	t.Helper()
+	rules, err := ratelimit.ParseRules(routes)
+	assert.NoError(t, err)
+
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	g := e.Group("/v1")
+	g.Use(mw.MerchantAuth(map[string]string{"acme": "key-a", "globex": "key-b"}))
+	g.Use(mw.RateLimit(ratelimit.NewMemoryLimiter(), ratelimit.Limit{}, rules))
+	g.POST("/payments", func(c echo.Context) error { return c.NoContent(http.StatusCreated) })
+	g.GET("/payments/:id", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	return e
+}
+
+func TestRateLimit(t *testing.T) {
+	e := setupRateLimited(t, "POST /v1/payments=2/1m")
+
+	do := func(method, path, apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if apiKey != "" {
+			req.Header.Set(mw.HeaderAPIKey, apiKey)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("allows requests within the limit", func(t *testing.T) {
+		rec := do(http.MethodPost, "/v1/payments", "key-a")
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "2", rec.Header().Get(mw.HeaderRateLimitLimit))
+		assert.Equal(t, "1", rec.Header().Get(mw.HeaderRateLimitRemaining))
+
+		rec = do(http.MethodPost, "/v1/payments", "key-a")
+		assert.Equal(t, http.StatusCreated, rec.Code)
+		assert.Equal(t, "0", rec.Header().Get(mw.HeaderRateLimitRemaining))
+	})
+
+	t.Run("rejects requests over the limit", func(t *testing.T) {
+		rec := do(http.MethodPost, "/v1/payments", "key-a")
+		assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+		assert.Equal(t, "30", rec.Header().Get(echo.HeaderRetryAfter))
+
+		assert.Equal(t, domain.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+		var body domain.ProblemDetails
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+		assert.Equal(t, http.StatusTooManyRequests, body.Status)
+		assert.Equal(t, domain.ErrRateLimited, body.Code)
+	})
+
+	t.Run("keys are isolated", func(t *testing.T) {
+		rec := do(http.MethodPost, "/v1/payments", "key-b")
+		assert.Equal(t, http.StatusCreated, rec.Code)
+	})
+
+	t.Run("unvalidated credentials share the client IP's bucket", func(t *testing.T) {
+		for i, authorization := range []string{"Bearer one", "Bearer two", "Bearer three"} {
+			req := httptest.NewRequest(http.MethodPost, "/v1/payments", nil)
+			req.Header.Set(echo.HeaderAuthorization, authorization)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+			if i < 2 {
+				assert.Equal(t, http.StatusCreated, rec.Code)
+			} else {
+				assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+			}
+		}
+	})
+
+	t.Run("routes without a rule are not limited", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			rec := do(http.MethodGet, "/v1/payments/abc", "key-a")
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Empty(t, rec.Header().Get(mw.HeaderRateLimitLimit))
+		}
+	})
+}
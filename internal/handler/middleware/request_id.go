@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"pgm/internal/logger"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// RequestID assigns every request an ID (or reuses the caller's X-Request-ID),
+// echoes it in the response header and stores it on the request context so
+// downstream logs carry it.
+func RequestID() echo.MiddlewareFunc {
+	return middleware.RequestIDWithConfig(middleware.RequestIDConfig{
+		RequestIDHandler: func(c echo.Context, rid string) {
+			req := c.Request()
+			c.SetRequest(req.WithContext(logger.WithRequestID(req.Context(), rid)))
+		},
+	})
+}
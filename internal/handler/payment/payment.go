@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"pgm/internal/domain"
+	mw "pgm/internal/handler/middleware"
 
 	"github.com/labstack/echo/v4"
 )
@@ -19,33 +20,38 @@ func NewPaymentHandler(g *echo.Group, uc domain.PaymentService) domain.PaymentHa
 		svc: uc,
 	}
 	g.POST("/payments", handler.CreatePayment)
+	g.GET("/payments", handler.ListPayments, mw.RequireMerchant())
 	g.GET("/payments/:id", handler.GetPaymentByID)
+	g.POST("/payments/:id/cancel", handler.CancelPayment, mw.RequireMerchant())
 	return handler
 }
 
 // CreatePayment handles the creation of a new payment
 // @Summary Create a new payment
-// @Description Creates a new payment with the provided details
+// @Description Creates a new payment with the provided details. The payment is screened against the fraud rules first; a blocked payment is stored as FAILED and never processed
 // @Tags payments
 // @Accept json
 // @Produce json
 // @Param payment body domain.PaymentRequest true "Payment details"
 // @Success 201 {object} domain.Payment "Payment created successfully"
-// @Failure 400 {object} ErrorResponse "Invalid request body or validation failed"
-// @Failure 409 {object} ErrorResponse "Payment with this reference already exists"
-// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 403 {object} domain.ProblemDetails "Metadata merchant_id is not the merchant of the API key"
+// @Failure 409 {object} domain.ProblemDetails "Payment with this reference already exists"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
 // @Router /v1/payments [post]
 func (h *paymentHandler) CreatePayment(c echo.Context) error {
 	var pr domain.PaymentRequest
 	if err := c.Bind(&pr); err != nil {
 		return domain.NewError(
-			http.StatusBadRequest,
+			domain.ErrInvalidRequestBody,
 			"invalid request body",
 			"failed to bind request body",
 			err,
 			nil,
 		)
 	}
+	// Velocity and blocklist rules match on the caller's address
+	pr.ClientIP = c.RealIP()
 
 	res, err := h.svc.CreatePayment(c.Request().Context(), &pr)
 	if err != nil {
@@ -62,9 +68,9 @@ func (h *paymentHandler) CreatePayment(c echo.Context) error {
 // @Produce json
 // @Param id path string true "Payment ID"
 // @Success 200 {object} domain.Payment "Payment found"
-// @Failure 400 {object} ErrorResponse "Invalid payment ID format"
-// @Failure 404 {object} ErrorResponse "Payment not found"
-// @Failure 500 {object} ErrorResponse "Internal server error"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment ID format"
+// @Failure 404 {object} domain.ProblemDetails "Payment not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
 // @Router /v1/payments/{id} [get]
 func (h *paymentHandler) GetPaymentByID(c echo.Context) error {
 	id := c.Param("id")
@@ -74,3 +80,57 @@ func (h *paymentHandler) GetPaymentByID(c echo.Context) error {
 	}
 	return c.JSON(http.StatusOK, res)
 }
+
+// ListPayments lists payments, newest first, optionally filtered by metadata
+// @Summary List payments
+// @Description Lists the merchant's payments, newest first. Filter by metadata with metadata[key]=value; all given pairs must match.
+// @Tags payments
+// @Security MerchantKey
+// @Produce json
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of payments to skip"
+// @Param metadata[key] query string false "Only payments whose metadata has this key/value"
+// @Success 200 {object} domain.PaymentList "Payments"
+// @Failure 400 {object} domain.ProblemDetails "Invalid pagination or filter parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid merchant API key"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/payments [get]
+func (h *paymentHandler) ListPayments(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+	filter, err := domain.ParsePaymentFilter(c.QueryParams())
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListPayments(c.Request().Context(), filter, page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// CancelPayment cancels a payment the worker has not charged yet
+// @Summary Cancel a pending payment
+// @Description Moves a PENDING payment to CANCELED so the worker never charges it. A cancel that arrives while the worker is charging the payment waits for the charge and then fails with 409. Only the merchant that owns the payment may cancel it.
+// @Tags payments
+// @Security MerchantKey
+// @Produce json
+// @Param id path string true "Payment ID"
+// @Success 200 {object} domain.Payment "Payment canceled"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid merchant API key"
+// @Failure 404 {object} domain.ProblemDetails "Payment not found"
+// @Failure 409 {object} domain.ProblemDetails "Payment is not pending"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/payments/{id}/cancel [post]
+func (h *paymentHandler) CancelPayment(c echo.Context) error {
+	id := c.Param("id")
+	res, err := h.svc.CancelPayment(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -14,11 +14,14 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"pgm/internal/domain"
+	mw "pgm/internal/handler/middleware"
 	pmt "pgm/internal/handler/payment"
 )
 
 type mockService struct {
 	domain.PaymentService
+	filter    domain.PaymentFilter
+	cancelErr error
 }
 
 func NewMockPaymentService() domain.PaymentService {
@@ -29,7 +32,7 @@ func (m *mockService) CreatePayment(ctx context.Context, req *domain.PaymentRequ
 	// Validate the request
 	if err := req.Validate(); err != nil {
 		return nil, domain.NewError(
-			http.StatusBadRequest,
+			domain.ErrValidationFailed,
 			"validation failed",
 			"payment request validation failed",
 			err,
@@ -53,7 +56,7 @@ func (m *mockService) GetPaymentByID(ctx context.Context, id string) (*domain.Pa
 	// Validate UUID format
 	if _, err := uuid.Parse(id); err != nil {
 		return nil, domain.NewError(
-			http.StatusBadRequest,
+			domain.ErrInvalidPaymentID,
 			"invalid payment ID format",
 			"payment ID must be a valid UUID",
 			err,
@@ -68,9 +71,27 @@ func (m *mockService) GetPaymentByID(ctx context.Context, id string) (*domain.Pa
 		Currency:  "USD",
 		Reference: "test-ref",
 		Status:    "SUCCESS",
+		PaymentMethod: &domain.PaymentMethod{
+			Type: domain.MethodCard,
+			Card: &domain.CardDetails{Token: "tok_secret", Brand: "visa", Last4: "4242", ExpMonth: 12, ExpYear: 2030},
+		},
+		ClientIP: "203.0.113.9",
+		Risk:     &domain.RiskAssessment{Score: 40, Outcome: domain.RiskAllow, Rules: []string{"new_device"}},
 	}, nil
 }
 
+func (m *mockService) ListPayments(ctx context.Context, filter domain.PaymentFilter, page domain.Page) (*domain.PaymentList, error) {
+	m.filter = filter
+	return &domain.PaymentList{Data: []domain.Payment{}, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (m *mockService) CancelPayment(ctx context.Context, id string) (*domain.Payment, error) {
+	if m.cancelErr != nil {
+		return nil, m.cancelErr
+	}
+	return &domain.Payment{ID: uuid.MustParse(id), Status: domain.StatusCanceled}, nil
+}
+
 type testPayment struct {
 	handler domain.PaymentHandler
 	echo    *echo.Echo
@@ -142,6 +163,60 @@ func TestCreatePayment(t *testing.T) {
 			expectError:    true,
 			expectedError:  "payment amount must be greater than 0.0",
 		},
+		{
+			name: "mobile money with invalid msisdn",
+			setup: func() ([]byte, int, *domain.Payment) {
+				reqBody, _ := json.Marshal(map[string]interface{}{
+					"amount":    100,
+					"currency":  "ETB",
+					"reference": "test-ref",
+					"payment_method": map[string]interface{}{
+						"type":         "mobile_money",
+						"mobile_money": map[string]interface{}{"provider": "telebirr", "msisdn": "0911234567"},
+					},
+				})
+				return reqBody, http.StatusBadRequest, nil
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectError:    true,
+			expectedError:  "must be a valid MSISDN in E.164 format",
+		},
+		{
+			name: "details do not match method type",
+			setup: func() ([]byte, int, *domain.Payment) {
+				reqBody, _ := json.Marshal(map[string]interface{}{
+					"amount":    100,
+					"currency":  "ETB",
+					"reference": "test-ref",
+					"payment_method": map[string]interface{}{
+						"type": "bank_transfer",
+						"card": map[string]interface{}{"token": "tok_123", "last4": "4242", "exp_month": 12, "exp_year": 2099},
+					},
+				})
+				return reqBody, http.StatusBadRequest, nil
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectError:    true,
+			expectedError:  "only the bank_transfer details may be given",
+		},
+		{
+			name: "missing bank account number",
+			setup: func() ([]byte, int, *domain.Payment) {
+				reqBody, _ := json.Marshal(map[string]interface{}{
+					"amount":    100,
+					"currency":  "ETB",
+					"reference": "test-ref",
+					"payment_method": map[string]interface{}{
+						"type":          "bank_transfer",
+						"bank_transfer": map[string]interface{}{"bank_code": "CBE"},
+					},
+				})
+				return reqBody, http.StatusBadRequest, nil
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectError:    true,
+			expectedError:  "account_number: cannot be blank",
+		},
 	}
 
 	for _, tt := range tests {
@@ -311,3 +386,165 @@ func TestGetPaymentByID(t *testing.T) {
 		})
 	}
 }
+
+func TestGetPaymentByIDMasksPaymentMethod(t *testing.T) {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	pmt.NewPaymentHandler(e.Group("/v1"), &mockService{})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/payments/"+uuid.NewString(), nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "tok_secret")
+	assert.Contains(t, rec.Body.String(), `"card":{"brand":"visa","last4":"4242"}`)
+	// Screening details are for operators only
+	assert.NotContains(t, rec.Body.String(), "203.0.113.9")
+	assert.NotContains(t, rec.Body.String(), "new_device")
+}
+
+func TestCreatePaymentProblemResponse(t *testing.T) {
+	m := setupTest()
+	m.echo.HTTPErrorHandler = domain.ErrorHandler
+
+	reqBody, _ := json.Marshal(map[string]interface{}{
+		"amount":    -100,
+		"currency":  "GBP",
+		"reference": "test-ref",
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/payments", bytes.NewReader(reqBody))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	m.echo.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, domain.MIMEApplicationProblemJSON, rec.Header().Get(echo.HeaderContentType))
+
+	var problem domain.ProblemDetails
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &problem))
+	assert.Equal(t, domain.ErrValidationFailed, problem.Code)
+	assert.Equal(t, "urn:pgm:error:validation.failed", problem.Type)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "/v1/payments", problem.Instance)
+	if assert.Len(t, problem.Errors, 2) {
+		assert.Equal(t, "amount", problem.Errors[0].Field)
+		assert.Equal(t, "currency", problem.Errors[1].Field)
+	}
+	assert.NotContains(t, rec.Body.String(), "params")
+}
+
+func TestListPaymentsMetadataFilter(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		expectedFilter domain.Metadata
+	}{
+		{
+			name:           "no filter",
+			query:          "",
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.Metadata{},
+		},
+		{
+			name:           "metadata pairs",
+			query:          "?metadata[order_id]=1234&metadata[channel]=web&limit=5",
+			expectedStatus: http.StatusOK,
+			expectedFilter: domain.Metadata{"order_id": "1234", "channel": "web"},
+		},
+		{
+			name:           "repeated key",
+			query:          "?metadata[channel]=web&metadata[channel]=pos",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "malformed key",
+			query:          "?metadata[channel=web",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{}
+			e := echo.New()
+			e.HTTPErrorHandler = domain.ErrorHandler
+			pmt.NewPaymentHandler(e.Group("/v1", mw.MerchantAuth(map[string]string{"acme": "k3y"})), svc)
+
+			req := httptest.NewRequest(http.MethodGet, "/v1/payments"+tt.query, nil)
+			req.Header.Set(mw.HeaderAPIKey, "k3y")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedFilter, svc.filter.Metadata)
+		})
+	}
+
+	t.Run("without a merchant key", func(t *testing.T) {
+		svc := &mockService{}
+		e := echo.New()
+		e.HTTPErrorHandler = domain.ErrorHandler
+		pmt.NewPaymentHandler(e.Group("/v1", mw.MerchantAuth(map[string]string{"acme": "k3y"})), svc)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/payments", nil))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		assert.Nil(t, svc.filter.Metadata)
+	})
+}
+
+func TestCancelPayment(t *testing.T) {
+	tests := []struct {
+		name           string
+		apiKey         string
+		err            error
+		expectedStatus int
+	}{
+		{
+			name:           "pending payment",
+			apiKey:         "k3y",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "without a merchant key",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "payment not pending",
+			apiKey:         "k3y",
+			err:            domain.NewError(domain.ErrPaymentNotPending, "Payment not pending", "already processed", nil, nil),
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:           "payment not found",
+			apiKey:         "k3y",
+			err:            domain.NewError(domain.ErrPaymentNotFound, "Payment not found", "missing", nil, nil),
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.HTTPErrorHandler = domain.ErrorHandler
+			pmt.NewPaymentHandler(e.Group("/v1", mw.MerchantAuth(map[string]string{"acme": "k3y"})), &mockService{cancelErr: tt.err})
+
+			id := uuid.New()
+			req := httptest.NewRequest(http.MethodPost, "/v1/payments/"+id.String()+"/cancel", nil)
+			if tt.apiKey != "" {
+				req.Header.Set(mw.HeaderAPIKey, tt.apiKey)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus == http.StatusOK {
+				var response domain.Payment
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+				assert.Equal(t, id, response.ID)
+				assert.Equal(t, domain.StatusCanceled, response.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package http
+
+import (
+	"embed"
+	"errors"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+//go:embed templates/link.html
+var templates embed.FS
+
+var page = template.Must(template.New("link.html").Funcs(template.FuncMap{
+	"deref": func(f *float64) float64 { return *f },
+}).ParseFS(templates, "templates/link.html"))
+
+// paymentLinkHandler serves the payment link API and the pages payers open the links on
+type paymentLinkHandler struct {
+	svc domain.PaymentLinkService
+}
+
+// pageData is rendered by the payment link page template
+type pageData struct {
+	Link  *domain.PaymentLink
+	Paid  bool
+	Error string
+}
+
+// NewPaymentLinkHandler initializes the payment link routes on api and the payment link pages on pages
+func NewPaymentLinkHandler(api *echo.Group, pages *echo.Group, svc domain.PaymentLinkService) domain.PaymentLinkHandler {
+	handler := &paymentLinkHandler{
+		svc: svc,
+	}
+	api.POST("/payment-links", handler.CreatePaymentLink)
+	api.GET("/payment-links", handler.ListPaymentLinks)
+	api.GET("/payment-links/:id", handler.GetPaymentLinkByID)
+	api.POST("/payment-links/:id/activate", handler.ActivatePaymentLink)
+	api.POST("/payment-links/:id/deactivate", handler.DeactivatePaymentLink)
+	api.GET("/payment-links/:id/payments", handler.ListPaymentLinkPayments)
+	pages.GET("/pay/:slug", handler.ShowPage)
+	pages.POST("/pay/:slug", handler.Pay)
+	return handler
+}
+
+// CreatePaymentLink handles the creation of a new payment link
+// @Summary Create a payment link
+// @Description Creates a shareable link payers can pay through without an API integration. Leave amount out to let the payer enter it.
+// @Tags payment-links
+// @Accept json
+// @Produce json
+// @Param link body domain.PaymentLinkRequest true "Payment link details"
+// @Success 201 {object} domain.PaymentLink "Payment link created"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 403 {object} domain.ProblemDetails "Metadata merchant_id is not the merchant of the API key"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/payment-links [post]
+func (h *paymentLinkHandler) CreatePaymentLink(c echo.Context) error {
+	var lr domain.PaymentLinkRequest
+	if err := c.Bind(&lr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.CreatePaymentLink(c.Request().Context(), &lr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// ListPaymentLinks lists payment links, newest first
+// @Summary List payment links
+// @Description Lists payment links, newest first
+// @Tags payment-links
+// @Produce json
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of payment links to skip"
+// @Success 200 {object} domain.PaymentLinkList "Payment links"
+// @Failure 400 {object} domain.ProblemDetails "Invalid pagination parameters"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/payment-links [get]
+func (h *paymentLinkHandler) ListPaymentLinks(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListPaymentLinks(c.Request().Context(), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetPaymentLinkByID retrieves a payment link by its ID
+// @Summary Get payment link by ID
+// @Description Retrieves a payment link with its use count and the total of the successful payments collected through it
+// @Tags payment-links
+// @Produce json
+// @Param id path string true "Payment link ID"
+// @Success 200 {object} domain.PaymentLink "Payment link found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment link ID format"
+// @Failure 404 {object} domain.ProblemDetails "Payment link not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/payment-links/{id} [get]
+func (h *paymentLinkHandler) GetPaymentLinkByID(c echo.Context) error {
+	res, err := h.svc.GetPaymentLinkByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ActivatePaymentLink makes a payment link usable again
+// @Summary Activate payment link
+// @Description Makes a deactivated payment link usable again. Expired and used-up links stay unusable.
+// @Tags payment-links
+// @Produce json
+// @Param id path string true "Payment link ID"
+// @Success 200 {object} domain.PaymentLink "Payment link activated"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment link ID format"
+// @Failure 404 {object} domain.ProblemDetails "Payment link not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/payment-links/{id}/activate [post]
+func (h *paymentLinkHandler) ActivatePaymentLink(c echo.Context) error {
+	res, err := h.svc.SetPaymentLinkActive(c.Request().Context(), c.Param("id"), true)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeactivatePaymentLink stops a payment link from accepting payments
+// @Summary Deactivate payment link
+// @Description Stops a payment link from accepting new payments. Payments already started through it are unaffected.
+// @Tags payment-links
+// @Produce json
+// @Param id path string true "Payment link ID"
+// @Success 200 {object} domain.PaymentLink "Payment link deactivated"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment link ID format"
+// @Failure 404 {object} domain.ProblemDetails "Payment link not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/payment-links/{id}/deactivate [post]
+func (h *paymentLinkHandler) DeactivatePaymentLink(c echo.Context) error {
+	res, err := h.svc.SetPaymentLinkActive(c.Request().Context(), c.Param("id"), false)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListPaymentLinkPayments lists the payments made through a payment link, newest first
+// @Summary List payment link payments
+// @Description Lists the payments made through a payment link, newest first
+// @Tags payment-links
+// @Produce json
+// @Param id path string true "Payment link ID"
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of payments to skip"
+// @Success 200 {object} domain.PaymentList "Payments"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment link ID or pagination parameters"
+// @Failure 404 {object} domain.ProblemDetails "Payment link not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/payment-links/{id}/payments [get]
+func (h *paymentLinkHandler) ListPaymentLinkPayments(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListPaymentLinkPayments(c.Request().Context(), c.Param("id"), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ShowPage renders the page a payment link points to
+func (h *paymentLinkHandler) ShowPage(c echo.Context) error {
+	link, err := h.svc.GetPaymentLinkBySlug(c.Request().Context(), c.Param("slug"))
+	if err != nil {
+		return h.renderError(c, nil, err)
+	}
+	return h.render(c, http.StatusOK, pageData{Link: link, Paid: c.QueryParam("status") == "paid"})
+}
+
+// Pay uses the link and redirects the payer to checkout
+func (h *paymentLinkHandler) Pay(c echo.Context) error {
+	ctx := c.Request().Context()
+	slug := c.Param("slug")
+	// An unparsable amount is left at zero and rejected by validation
+	amount, _ := strconv.ParseFloat(strings.TrimSpace(c.FormValue("amount")), 64)
+	session, err := h.svc.PayPaymentLink(ctx, slug, amount, c.RealIP())
+	if err == nil {
+		return c.Redirect(http.StatusSeeOther, session.URL)
+	}
+
+	link, lookupErr := h.svc.GetPaymentLinkBySlug(ctx, slug)
+	if lookupErr != nil {
+		return h.renderError(c, nil, lookupErr)
+	}
+	return h.renderError(c, link, err)
+}
+
+func (h *paymentLinkHandler) render(c echo.Context, status int, data pageData) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextHTMLCharsetUTF8)
+	c.Response().WriteHeader(status)
+	return page.Execute(c.Response(), data)
+}
+
+// renderError shows err on the page. Errors outside the catalog are left to
+// the error handler.
+func (h *paymentLinkHandler) renderError(c echo.Context, link *domain.PaymentLink, err error) error {
+	var derr domain.Error
+	if !errors.As(err, &derr) {
+		return err
+	}
+	data := pageData{Link: link, Error: derr.Description}
+	if derr.Type == domain.ErrValidationFailed {
+		data.Error = "Please enter an amount greater than zero."
+	}
+	return h.render(c, derr.Code, data)
+}
@@ -0,0 +1,189 @@
+package http_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	pl "pgm/internal/handler/paymentlink"
+)
+
+type mockService struct {
+	domain.PaymentLinkService
+	link   *domain.PaymentLink
+	amount float64
+}
+
+func newMockService(amount *float64, state domain.PaymentLinkState) *mockService {
+	return &mockService{link: &domain.PaymentLink{
+		ID:          uuid.New(),
+		Slug:        "abc",
+		Amount:      amount,
+		Currency:    "ETB",
+		Description: "Coffee beans",
+		State:       state,
+	}}
+}
+
+func (m *mockService) CreatePaymentLink(ctx context.Context, lr *domain.PaymentLinkRequest) (*domain.PaymentLink, error) {
+	if err := lr.Validate(); err != nil {
+		return nil, domain.NewError(domain.ErrValidationFailed, "validation failed", "payment link request validation failed", err, nil)
+	}
+	return m.link, nil
+}
+
+func (m *mockService) GetPaymentLinkBySlug(ctx context.Context, slug string) (*domain.PaymentLink, error) {
+	if slug != m.link.Slug {
+		return nil, domain.NewError(domain.ErrPaymentLinkNotFound, "Payment link not found", "The specified payment link could not be found", nil, nil)
+	}
+	return m.link, nil
+}
+
+func (m *mockService) SetPaymentLinkActive(ctx context.Context, id string, active bool) (*domain.PaymentLink, error) {
+	m.link.Active = active
+	return m.link, nil
+}
+
+func (m *mockService) PayPaymentLink(ctx context.Context, slug string, amount float64, clientIP string) (*domain.CheckoutSession, error) {
+	m.amount = amount
+	if m.link.Amount == nil && amount <= 0 {
+		return nil, domain.NewError(domain.ErrValidationFailed, "validation failed", "payment amount validation failed", nil, nil)
+	}
+	return &domain.CheckoutSession{URL: "https://pay.example.com/checkout/tok"}, nil
+}
+
+func serve(svc domain.PaymentLinkService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	pl.NewPaymentLinkHandler(e.Group("/v1"), e.Group(""), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreatePaymentLink(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           map[string]interface{}
+		expectedStatus int
+	}{
+		{"created", map[string]interface{}{"amount": 150, "currency": "ETB", "max_uses": 10}, http.StatusCreated},
+		{"missing currency", map[string]interface{}{"amount": 150}, http.StatusBadRequest},
+		{"negative amount", map[string]interface{}{"amount": -1, "currency": "ETB"}, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/v1/payment-links", bytes.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := serve(newMockService(nil, domain.LinkActive), req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestDeactivatePaymentLink(t *testing.T) {
+	svc := newMockService(nil, domain.LinkActive)
+	svc.link.Active = true
+	rec := serve(svc, httptest.NewRequest(http.MethodPost, "/v1/payment-links/"+svc.link.ID.String()+"/deactivate", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, svc.link.Active)
+}
+
+func TestShowPage(t *testing.T) {
+	amount := 150.0
+	tests := []struct {
+		name     string
+		svc      *mockService
+		target   string
+		status   int
+		contains []string
+		absent   []string
+	}{
+		{
+			name:     "fixed amount",
+			svc:      newMockService(&amount, domain.LinkActive),
+			target:   "/pay/abc",
+			status:   http.StatusOK,
+			contains: []string{"Coffee beans", "150.00 ETB", "<form"},
+			absent:   []string{`name="amount"`},
+		},
+		{
+			name:     "payer-entered amount",
+			svc:      newMockService(nil, domain.LinkActive),
+			target:   "/pay/abc",
+			status:   http.StatusOK,
+			contains: []string{`name="amount"`},
+		},
+		{
+			name:     "after payment",
+			svc:      newMockService(nil, domain.LinkActive),
+			target:   "/pay/abc?status=paid",
+			status:   http.StatusOK,
+			contains: []string{"Thank you"},
+		},
+		{
+			name:     "exhausted link",
+			svc:      newMockService(&amount, domain.LinkExhausted),
+			target:   "/pay/abc",
+			status:   http.StatusOK,
+			contains: []string{"exhausted"},
+			absent:   []string{"<form"},
+		},
+		{
+			name:   "unknown slug",
+			svc:    newMockService(nil, domain.LinkActive),
+			target: "/pay/nope",
+			status: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := serve(tt.svc, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Contains(t, rec.Header().Get(echo.HeaderContentType), "text/html")
+			for _, s := range tt.contains {
+				assert.Contains(t, rec.Body.String(), s)
+			}
+			for _, s := range tt.absent {
+				assert.NotContains(t, rec.Body.String(), s)
+			}
+		})
+	}
+}
+
+func TestPay(t *testing.T) {
+	post := func(form url.Values) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/pay/abc", strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		return req
+	}
+
+	t.Run("redirects to checkout", func(t *testing.T) {
+		svc := newMockService(nil, domain.LinkActive)
+		rec := serve(svc, post(url.Values{"amount": {" 42.50 "}}))
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "https://pay.example.com/checkout/tok", rec.Header().Get(echo.HeaderLocation))
+		assert.Equal(t, 42.5, svc.amount)
+	})
+
+	t.Run("invalid amount re-renders the page", func(t *testing.T) {
+		rec := serve(newMockService(nil, domain.LinkActive), post(url.Values{"amount": {"abc"}}))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "greater than zero")
+		assert.Contains(t, rec.Body.String(), "<form")
+	})
+}
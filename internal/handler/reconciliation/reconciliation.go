@@ -0,0 +1,240 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// reconciliationHandler handles HTTP requests for statement reconciliation
+type reconciliationHandler struct {
+	svc domain.ReconciliationService
+}
+
+// NewReconciliationHandler initializes the reconciliation routes
+func NewReconciliationHandler(g *echo.Group, svc domain.ReconciliationService) domain.ReconciliationHandler {
+	handler := &reconciliationHandler{
+		svc: svc,
+	}
+	g.POST("/reconciliations", handler.ImportStatement)
+	g.GET("/reconciliations", handler.ListRuns)
+	g.GET("/reconciliations/:id", handler.GetRunByID)
+	g.GET("/reconciliations/:id/items", handler.ListRunItems)
+	g.GET("/reconciliation-exceptions", handler.ListExceptions)
+	g.POST("/reconciliation-exceptions/:id/resolve", handler.ResolveException)
+	return handler
+}
+
+// contentTypeFormats is the statement format assumed for a request body of
+// each media type when no format is given.
+var contentTypeFormats = map[string]domain.StatementFormat{
+	"text/csv":        domain.FormatCSV,
+	"application/xml": domain.FormatCamt053,
+	"text/xml":        domain.FormatCamt053,
+}
+
+// ImportStatement reconciles an uploaded statement against the payments
+// @Summary Import a statement
+// @Description Reconciles a provider or bank statement, sent as the raw request body, against the payments. Each line is matched to a payment by its reference or the provider's reference and compared by amount and currency; successful payments within the statement period that are missing from it are reported too. The format defaults from the Content-Type: text/csv for csv, application/xml or text/xml for camt053
+// @Tags reconciliations
+// @Security ApiKeyAuth
+// @Accept plain
+// @Produce json
+// @Param format query string false "Statement format" Enums(csv, camt053)
+// @Param provider query string false "Only expect payments routed to this provider on the statement"
+// @Param statement body string true "Statement file"
+// @Success 201 {object} domain.ReconciliationRun "Statement reconciled"
+// @Failure 400 {object} domain.ProblemDetails "Unsupported format or unreadable statement"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 413 {object} domain.ProblemDetails "Statement too large"
+// @Failure 415 {object} domain.ProblemDetails "No format given and none implied by the Content-Type"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reconciliations [post]
+func (h *reconciliationHandler) ImportStatement(c echo.Context) error {
+	format := domain.StatementFormat(c.QueryParam("format"))
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+		var ok bool
+		if format, ok = contentTypeFormats[mediaType]; !ok {
+			return domain.NewError(
+				domain.ErrUnsupportedMediaType,
+				"unsupported statement media type",
+				"pass the format query parameter or send the statement as text/csv or application/xml",
+				nil,
+				map[string]interface{}{"content_type": mediaType},
+			)
+		}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request().Body, domain.MaxStatementSize+1))
+	if err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to read the statement",
+			err,
+			nil,
+		)
+	}
+	if len(body) > domain.MaxStatementSize {
+		return domain.NewError(
+			domain.ErrRequestTooLarge,
+			"statement too large",
+			"statements are limited to 10 MiB; split the file by period",
+			nil,
+			nil,
+		)
+	}
+
+	res, err := h.svc.ImportStatement(c.Request().Context(), format, c.QueryParam("provider"), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// ListRuns lists reconciliations, newest first
+// @Summary List reconciliations
+// @Description Lists imported statements with their reconciliation totals, newest first
+// @Tags reconciliations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of reconciliations to skip"
+// @Success 200 {object} domain.ReconciliationRunList "Reconciliations"
+// @Failure 400 {object} domain.ProblemDetails "Invalid pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reconciliations [get]
+func (h *reconciliationHandler) ListRuns(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListRuns(c.Request().Context(), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetRunByID retrieves a reconciliation report by its ID
+// @Summary Get reconciliation by ID
+// @Description Retrieves the report of an imported statement: how many lines matched, were missing internally, missing at the provider or mismatched in amount
+// @Tags reconciliations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Reconciliation ID"
+// @Success 200 {object} domain.ReconciliationRun "Reconciliation found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid reconciliation ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Reconciliation not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reconciliations/{id} [get]
+func (h *reconciliationHandler) GetRunByID(c echo.Context) error {
+	res, err := h.svc.GetRunByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListRunItems lists the lines of a reconciliation
+// @Summary List reconciliation lines
+// @Description Lists the statement lines and missing payments of a reconciliation, optionally with one result
+// @Tags reconciliations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Reconciliation ID"
+// @Param result query string false "Only lines with this result" Enums(matched, missing_internally, missing_at_provider, amount_mismatch)
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of lines to skip"
+// @Success 200 {object} domain.ReconciliationItemList "Reconciliation lines"
+// @Failure 400 {object} domain.ProblemDetails "Invalid reconciliation ID, result filter or pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Reconciliation not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reconciliations/{id}/items [get]
+func (h *reconciliationHandler) ListRunItems(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+	result, err := domain.ParseReconciliationResult(c.QueryParams())
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListRunItems(c.Request().Context(), c.Param("id"), result, page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListExceptions lists unresolved reconciliation exceptions
+// @Summary List reconciliation exceptions
+// @Description Lists the lines of every reconciliation that did not match and are not yet resolved, oldest first
+// @Tags reconciliations
+// @Security ApiKeyAuth
+// @Produce json
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of exceptions to skip"
+// @Success 200 {object} domain.ReconciliationItemList "Unresolved exceptions"
+// @Failure 400 {object} domain.ProblemDetails "Invalid pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reconciliation-exceptions [get]
+func (h *reconciliationHandler) ListExceptions(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListExceptions(c.Request().Context(), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ResolveException marks a reconciliation exception resolved
+// @Summary Resolve a reconciliation exception
+// @Description Closes an exception with a note on how it was settled, taking it off the unresolved list
+// @Tags reconciliations
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Reconciliation exception ID"
+// @Param resolution body domain.ResolveExceptionRequest true "Resolution note"
+// @Success 200 {object} domain.ReconciliationItem "Exception resolved"
+// @Failure 400 {object} domain.ProblemDetails "Invalid exception ID, request body or validation failed"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Exception not found"
+// @Failure 409 {object} domain.ProblemDetails "Exception already resolved"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reconciliation-exceptions/{id}/resolve [post]
+func (h *reconciliationHandler) ResolveException(c echo.Context) error {
+	var rr domain.ResolveExceptionRequest
+	if err := c.Bind(&rr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.ResolveException(c.Request().Context(), c.Param("id"), &rr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
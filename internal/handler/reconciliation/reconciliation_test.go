@@ -0,0 +1,88 @@
+package http_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	rcn "pgm/internal/handler/reconciliation"
+)
+
+type mockService struct {
+	domain.ReconciliationService
+	format   domain.StatementFormat
+	provider string
+	body     string
+	result   *domain.ReconciliationResult
+}
+
+func (m *mockService) ImportStatement(ctx context.Context, format domain.StatementFormat, provider string, body io.Reader) (*domain.ReconciliationRun, error) {
+	b, _ := io.ReadAll(body)
+	m.format, m.provider, m.body = format, provider, string(b)
+	return &domain.ReconciliationRun{Format: format, Provider: provider}, nil
+}
+
+func (m *mockService) ListRunItems(ctx context.Context, id string, result *domain.ReconciliationResult, page domain.Page) (*domain.ReconciliationItemList, error) {
+	m.result = result
+	return &domain.ReconciliationItemList{Data: []domain.ReconciliationItem{}, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func serve(svc domain.ReconciliationService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	rcn.NewReconciliationHandler(e.Group("/admin"), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestImportStatement(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		contentType    string
+		body           string
+		expectedStatus int
+		expectedFormat domain.StatementFormat
+	}{
+		{"format from the query", "?format=camt053&provider=chapa", "application/octet-stream", "<Document/>", http.StatusCreated, domain.FormatCamt053},
+		{"csv content type", "", "text/csv; charset=utf-8", "reference,amount,currency\n", http.StatusCreated, domain.FormatCSV},
+		{"xml content type", "", "text/xml", "<Document/>", http.StatusCreated, domain.FormatCamt053},
+		{"unknown content type", "", "application/json", "{}", http.StatusUnsupportedMediaType, ""},
+		{"too large", "?format=csv", "text/csv", strings.Repeat("x", domain.MaxStatementSize+1), http.StatusRequestEntityTooLarge, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{}
+			req := httptest.NewRequest(http.MethodPost, "/admin/reconciliations"+tt.query, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+			rec := serve(svc, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.expectedFormat, svc.format)
+			if tt.expectedStatus == http.StatusCreated {
+				assert.Equal(t, tt.body, svc.body)
+			}
+		})
+	}
+}
+
+func TestListRunItems(t *testing.T) {
+	svc := &mockService{}
+	rec := serve(svc, httptest.NewRequest(http.MethodGet, "/admin/reconciliations/abc/items?result=amount_mismatch", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if svc.result == nil {
+		t.Fatal("expected a result filter")
+	}
+	assert.Equal(t, domain.ResultAmountMismatch, *svc.result)
+
+	rec = serve(&mockService{}, httptest.NewRequest(http.MethodGet, "/admin/reconciliations/abc/items?result=lost", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,179 @@
+package http
+
+import (
+	"net/http"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// reviewHandler handles HTTP requests for the manual review queue
+type reviewHandler struct {
+	svc domain.ReviewService
+}
+
+// NewReviewHandler initializes the review queue routes
+func NewReviewHandler(g *echo.Group, svc domain.ReviewService) domain.ReviewHandler {
+	handler := &reviewHandler{
+		svc: svc,
+	}
+	g.GET("/reviews", handler.ListReviews)
+	g.GET("/reviews/:id", handler.GetReviewByID)
+	g.POST("/reviews/:id/claim", handler.ClaimReview)
+	g.POST("/reviews/:id/approve", handler.ApproveReview)
+	g.POST("/reviews/:id/decline", handler.DeclineReview)
+	return handler
+}
+
+// ListReviews lists the review queue
+// @Summary List payment reviews
+// @Description Lists reviews of payments held by fraud screening. Escalated reviews come first, then the oldest
+// @Tags reviews
+// @Produce json
+// @Security ApiKeyAuth
+// @Param status query string false "Only reviews in this status" Enums(open, claimed, approved, declined)
+// @Param escalated query bool false "Only escalated (true) or unescalated (false) reviews"
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of reviews to skip"
+// @Success 200 {object} domain.ReviewList "Reviews"
+// @Failure 400 {object} domain.ProblemDetails "Invalid filter or pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reviews [get]
+func (h *reviewHandler) ListReviews(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+	filter, err := domain.ParseReviewFilter(c.QueryParams())
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListReviews(c.Request().Context(), filter, page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetReviewByID retrieves a review by its ID
+// @Summary Get payment review by ID
+// @Description Retrieves a review with its reviewer, decision and notes
+// @Tags reviews
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Review ID"
+// @Success 200 {object} domain.PaymentReview "Review found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid review ID format"
+// @Failure 404 {object} domain.ProblemDetails "Review not found"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reviews/{id} [get]
+func (h *reviewHandler) GetReviewByID(c echo.Context) error {
+	res, err := h.svc.GetReviewByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ClaimReview assigns a review to the operator making the request
+// @Summary Claim a payment review
+// @Description Assigns an open review to the operator whose token is used, stopping its SLA timers. Claiming a review the operator already holds succeeds again
+// @Tags reviews
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Review ID"
+// @Success 200 {object} domain.PaymentReview "Review claimed"
+// @Failure 400 {object} domain.ProblemDetails "Invalid review ID"
+// @Failure 404 {object} domain.ProblemDetails "Review not found"
+// @Failure 409 {object} domain.ProblemDetails "Review claimed by another operator or already decided"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reviews/{id}/claim [post]
+func (h *reviewHandler) ClaimReview(c echo.Context) error {
+	ctx := c.Request().Context()
+	cr := domain.ClaimReviewRequest{Reviewer: domain.ActorFromContext(ctx).Name}
+
+	res, err := h.svc.ClaimReview(ctx, c.Param("id"), &cr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ApproveReview releases a held payment
+// @Summary Approve a payment review
+// @Description Approves a review claimed by the operator whose token is used. The payment goes back to PENDING and is queued for processing
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Review ID"
+// @Param decision body domain.ReviewDecisionRequest true "Optional notes"
+// @Success 200 {object} domain.PaymentReview "Review approved"
+// @Failure 400 {object} domain.ProblemDetails "Invalid review ID, request body or validation failed"
+// @Failure 404 {object} domain.ProblemDetails "Review not found"
+// @Failure 409 {object} domain.ProblemDetails "Review not claimed by this operator or already decided"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reviews/{id}/approve [post]
+func (h *reviewHandler) ApproveReview(c echo.Context) error {
+	var dr domain.ReviewDecisionRequest
+	if err := c.Bind(&dr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+	ctx := c.Request().Context()
+	dr.Reviewer = domain.ActorFromContext(ctx).Name
+
+	res, err := h.svc.ApproveReview(ctx, c.Param("id"), &dr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeclineReview fails a held payment
+// @Summary Decline a payment review
+// @Description Declines a review claimed by the operator whose token is used. Notes are required. The payment is failed and never processed
+// @Tags reviews
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param id path string true "Review ID"
+// @Param decision body domain.ReviewDecisionRequest true "Notes"
+// @Success 200 {object} domain.PaymentReview "Review declined"
+// @Failure 400 {object} domain.ProblemDetails "Invalid review ID, request body or validation failed"
+// @Failure 404 {object} domain.ProblemDetails "Review not found"
+// @Failure 409 {object} domain.ProblemDetails "Review not claimed by this operator or already decided"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/reviews/{id}/decline [post]
+func (h *reviewHandler) DeclineReview(c echo.Context) error {
+	var dr domain.ReviewDecisionRequest
+	if err := c.Bind(&dr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+	ctx := c.Request().Context()
+	dr.Reviewer = domain.ActorFromContext(ctx).Name
+
+	res, err := h.svc.DeclineReview(ctx, c.Param("id"), &dr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
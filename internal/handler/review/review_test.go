@@ -0,0 +1,89 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	review "pgm/internal/handler/review"
+)
+
+type mockService struct {
+	domain.ReviewService
+	filter   domain.ReviewFilter
+	declined *domain.ReviewDecisionRequest
+}
+
+func (m *mockService) ListReviews(ctx context.Context, filter domain.ReviewFilter, page domain.Page) (*domain.ReviewList, error) {
+	m.filter = filter
+	return &domain.ReviewList{Data: []domain.PaymentReview{}, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (m *mockService) DeclineReview(ctx context.Context, id string, dr *domain.ReviewDecisionRequest) (*domain.PaymentReview, error) {
+	m.declined = dr
+	return &domain.PaymentReview{ID: uuid.MustParse(id), Status: domain.ReviewDeclined, DecidedBy: dr.Reviewer, Notes: dr.Notes}, nil
+}
+
+func serve(svc domain.ReviewService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	e.Use(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			c.SetRequest(req.WithContext(domain.WithActor(req.Context(), domain.Actor{Name: "alice"})))
+			return next(c)
+		}
+	})
+	review.NewReviewHandler(e.Group("/admin"), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestListReviews(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+	}{
+		{"unfiltered", "", http.StatusOK},
+		{"open and escalated", "?status=open&escalated=true", http.StatusOK},
+		{"unknown status", "?status=pending", http.StatusBadRequest},
+		{"bad escalated", "?escalated=maybe", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{}
+			rec := serve(svc, httptest.NewRequest(http.MethodGet, "/admin/reviews"+tt.query, nil))
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+
+	svc := &mockService{}
+	serve(svc, httptest.NewRequest(http.MethodGet, "/admin/reviews?status=open&escalated=true", nil))
+	if assert.NotNil(t, svc.filter.Status) && assert.NotNil(t, svc.filter.Escalated) {
+		assert.Equal(t, domain.ReviewOpen, *svc.filter.Status)
+		assert.True(t, *svc.filter.Escalated)
+	}
+}
+
+func TestDeclineReview(t *testing.T) {
+	svc := &mockService{}
+	req := httptest.NewRequest(http.MethodPost, "/admin/reviews/"+uuid.NewString()+"/decline", strings.NewReader(`{"reviewer":"mallory","notes":"card testing"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := serve(svc, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.NotNil(t, svc.declined) {
+		assert.Equal(t, "alice", svc.declined.Reviewer)
+		assert.Equal(t, "card testing", svc.declined.Notes)
+	}
+	assert.Contains(t, rec.Body.String(), `"status":"declined"`)
+}
@@ -0,0 +1,269 @@
+package http
+
+import (
+	"net/http"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// riskHandler handles HTTP requests for fraud rules and the blocklist
+type riskHandler struct {
+	svc domain.RiskService
+}
+
+// NewRiskHandler initializes the fraud rule, blocklist and payment risk routes
+func NewRiskHandler(g *echo.Group, svc domain.RiskService) domain.RiskHandler {
+	handler := &riskHandler{
+		svc: svc,
+	}
+	g.POST("/risk-rules", handler.CreateRule)
+	g.GET("/risk-rules", handler.ListRules)
+	g.GET("/risk-rules/:id", handler.GetRuleByID)
+	g.PUT("/risk-rules/:id", handler.UpdateRule)
+	g.DELETE("/risk-rules/:id", handler.DeleteRule)
+	g.POST("/risk-blocklist", handler.CreateBlocklistEntry)
+	g.GET("/risk-blocklist", handler.ListBlocklistEntries)
+	g.DELETE("/risk-blocklist/:id", handler.DeleteBlocklistEntry)
+	g.GET("/payments/:id/risk", handler.GetPaymentRisk)
+	return handler
+}
+
+// CreateRule handles the creation of a new fraud rule
+// @Summary Create a fraud rule
+// @Description Creates a rule evaluated against every payment created through the API. The expression must evaluate to a boolean; see the README for the variables and functions available. A match adds score to the payment's risk score and applies action (review or block)
+// @Tags risk
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param rule body domain.RiskRuleRequest true "Rule details"
+// @Success 201 {object} domain.RiskRule "Rule created"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body, validation failed or the expression does not compile"
+// @Failure 409 {object} domain.ProblemDetails "A rule with this name already exists"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/risk-rules [post]
+func (h *riskHandler) CreateRule(c echo.Context) error {
+	var rr domain.RiskRuleRequest
+	if err := c.Bind(&rr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.CreateRule(c.Request().Context(), &rr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// ListRules lists fraud rules, oldest first
+// @Summary List fraud rules
+// @Description Lists fraud rules in evaluation order, oldest first
+// @Tags risk
+// @Security ApiKeyAuth
+// @Produce json
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of rules to skip"
+// @Success 200 {object} domain.RiskRuleList "Rules"
+// @Failure 400 {object} domain.ProblemDetails "Invalid pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/risk-rules [get]
+func (h *riskHandler) ListRules(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListRules(c.Request().Context(), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetRuleByID retrieves a fraud rule by its ID
+// @Summary Get fraud rule by ID
+// @Description Retrieves a fraud rule by its ID
+// @Tags risk
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} domain.RiskRule "Rule found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid rule ID format"
+// @Failure 404 {object} domain.ProblemDetails "Rule not found"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/risk-rules/{id} [get]
+func (h *riskHandler) GetRuleByID(c echo.Context) error {
+	res, err := h.svc.GetRuleByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// UpdateRule replaces a fraud rule
+// @Summary Update fraud rule
+// @Description Replaces a rule's name, expression, action and score. Leaving enabled out keeps the rule's current state
+// @Tags risk
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param rule body domain.RiskRuleRequest true "Rule details"
+// @Success 200 {object} domain.RiskRule "Rule updated"
+// @Failure 400 {object} domain.ProblemDetails "Invalid rule ID, request body, validation failed or the expression does not compile"
+// @Failure 404 {object} domain.ProblemDetails "Rule not found"
+// @Failure 409 {object} domain.ProblemDetails "A rule with this name already exists"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/risk-rules/{id} [put]
+func (h *riskHandler) UpdateRule(c echo.Context) error {
+	var rr domain.RiskRuleRequest
+	if err := c.Bind(&rr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.UpdateRule(c.Request().Context(), c.Param("id"), &rr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteRule deletes a fraud rule
+// @Summary Delete fraud rule
+// @Description Deletes a rule. Payments already screened keep the rule's name in their matched rules
+// @Tags risk
+// @Security ApiKeyAuth
+// @Param id path string true "Rule ID"
+// @Success 204 "Rule deleted"
+// @Failure 400 {object} domain.ProblemDetails "Invalid rule ID format"
+// @Failure 404 {object} domain.ProblemDetails "Rule not found"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/risk-rules/{id} [delete]
+func (h *riskHandler) DeleteRule(c echo.Context) error {
+	if err := h.svc.DeleteRule(c.Request().Context(), c.Param("id")); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// CreateBlocklistEntry adds a value to the blocklist
+// @Summary Add a blocklist entry
+// @Description Adds an IP address, email address or customer ID to the blocklist. Rules test entries with blocked(kind, value)
+// @Tags risk
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param entry body domain.BlocklistEntryRequest true "Blocklist entry"
+// @Success 201 {object} domain.BlocklistEntry "Entry created"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 409 {object} domain.ProblemDetails "Value already on the blocklist"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/risk-blocklist [post]
+func (h *riskHandler) CreateBlocklistEntry(c echo.Context) error {
+	var br domain.BlocklistEntryRequest
+	if err := c.Bind(&br); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.CreateBlocklistEntry(c.Request().Context(), &br)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// ListBlocklistEntries lists blocklist entries, newest first
+// @Summary List blocklist entries
+// @Description Lists blocklist entries, newest first, optionally of one kind
+// @Tags risk
+// @Security ApiKeyAuth
+// @Produce json
+// @Param kind query string false "Only entries of this kind" Enums(ip, email, customer)
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {object} domain.BlocklistEntryList "Entries"
+// @Failure 400 {object} domain.ProblemDetails "Invalid kind filter or pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/risk-blocklist [get]
+func (h *riskHandler) ListBlocklistEntries(c echo.Context) error {
+	kind, err := domain.ParseBlocklistKind(c.QueryParams())
+	if err != nil {
+		return err
+	}
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListBlocklistEntries(c.Request().Context(), kind, page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// DeleteBlocklistEntry removes a value from the blocklist
+// @Summary Delete blocklist entry
+// @Description Removes an entry from the blocklist
+// @Tags risk
+// @Security ApiKeyAuth
+// @Param id path string true "Blocklist entry ID"
+// @Success 204 "Entry deleted"
+// @Failure 400 {object} domain.ProblemDetails "Invalid blocklist entry ID format"
+// @Failure 404 {object} domain.ProblemDetails "Blocklist entry not found"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/risk-blocklist/{id} [delete]
+func (h *riskHandler) DeleteBlocklistEntry(c echo.Context) error {
+	if err := h.svc.DeleteBlocklistEntry(c.Request().Context(), c.Param("id")); err != nil {
+		return err
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetPaymentRisk shows how a payment was screened
+// @Summary Get a payment's risk assessment
+// @Description Returns the risk score, outcome and matched rules of a payment, and the client IP it was created from. These are not part of the payment merchants see
+// @Tags risk
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Payment ID"
+// @Success 200 {object} domain.PaymentRisk "Payment risk"
+// @Failure 400 {object} domain.ProblemDetails "Invalid payment ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Payment not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/payments/{id}/risk [get]
+func (h *riskHandler) GetPaymentRisk(c echo.Context) error {
+	res, err := h.svc.GetPaymentRisk(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,113 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	risk "pgm/internal/handler/risk"
+)
+
+type mockService struct {
+	domain.RiskService
+	created *domain.RiskRuleRequest
+	kind    *domain.BlocklistKind
+	deleted string
+}
+
+func (m *mockService) CreateRule(ctx context.Context, rr *domain.RiskRuleRequest) (*domain.RiskRule, error) {
+	m.created = rr
+	return &domain.RiskRule{ID: uuid.New(), Name: rr.Name, Expression: rr.Expression, Action: rr.Action, Score: rr.Score, Enabled: true}, nil
+}
+
+func (m *mockService) ListBlocklistEntries(ctx context.Context, kind *domain.BlocklistKind, page domain.Page) (*domain.BlocklistEntryList, error) {
+	m.kind = kind
+	return &domain.BlocklistEntryList{Data: []domain.BlocklistEntry{}, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (m *mockService) DeleteRule(ctx context.Context, id string) error {
+	m.deleted = id
+	return nil
+}
+
+func (m *mockService) GetPaymentRisk(ctx context.Context, paymentID string) (*domain.PaymentRisk, error) {
+	return &domain.PaymentRisk{
+		PaymentID: uuid.MustParse(paymentID),
+		ClientIP:  "203.0.113.9",
+		Risk:      &domain.RiskAssessment{Score: 40, Outcome: domain.RiskAllow, Rules: []string{"new_device"}},
+	}, nil
+}
+
+func serve(svc domain.RiskService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	risk.NewRiskHandler(e.Group("/admin"), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreateRule(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"created", `{"name":"large-usd","expression":"currency == \"USD\" && amount > 5000","action":"review","score":40}`, http.StatusCreated},
+		{"malformed body", `{"name":`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{}
+			req := httptest.NewRequest(http.MethodPost, "/admin/risk-rules", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := serve(svc, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			if tt.expectedStatus != http.StatusCreated {
+				return
+			}
+			if svc.created == nil {
+				t.Fatal("request not bound")
+			}
+			assert.Equal(t, `currency == "USD" && amount > 5000`, svc.created.Expression)
+			assert.Equal(t, domain.RiskReview, svc.created.Action)
+			assert.Nil(t, svc.created.Enabled)
+		})
+	}
+}
+
+func TestListBlocklistEntries(t *testing.T) {
+	svc := &mockService{}
+	rec := serve(svc, httptest.NewRequest(http.MethodGet, "/admin/risk-blocklist?kind=email", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	if assert.NotNil(t, svc.kind) {
+		assert.Equal(t, domain.BlockEmail, *svc.kind)
+	}
+
+	rec = serve(svc, httptest.NewRequest(http.MethodGet, "/admin/risk-blocklist?kind=phone", nil))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestDeleteRule(t *testing.T) {
+	svc := &mockService{}
+	id := uuid.NewString()
+	rec := serve(svc, httptest.NewRequest(http.MethodDelete, "/admin/risk-rules/"+id, nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, id, svc.deleted)
+}
+
+func TestGetPaymentRisk(t *testing.T) {
+	rec := serve(&mockService{}, httptest.NewRequest(http.MethodGet, "/admin/payments/"+uuid.NewString()+"/risk", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"client_ip":"203.0.113.9"`)
+	assert.Contains(t, rec.Body.String(), `"rules":["new_device"]`)
+}
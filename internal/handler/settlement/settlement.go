@@ -0,0 +1,172 @@
+package http
+
+import (
+	"net/http"
+
+	"pgm/internal/domain"
+	mw "pgm/internal/handler/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+// settlementHandler handles HTTP requests for settlement batches
+type settlementHandler struct {
+	svc domain.SettlementService
+}
+
+// NewSettlementHandler initializes the settlement routes. Merchants read
+// their own batches on g; operators read every batch and pay them on admin.
+func NewSettlementHandler(g, admin *echo.Group, svc domain.SettlementService) domain.SettlementHandler {
+	handler := &settlementHandler{
+		svc: svc,
+	}
+	g.GET("/settlements", handler.ListBatches, mw.RequireMerchant())
+	g.GET("/settlements/:id", handler.GetBatchByID, mw.RequireMerchant())
+	g.GET("/settlements/:id/payments", handler.ListBatchPayments, mw.RequireMerchant())
+	g.GET("/settlements/:id/adjustments", handler.ListBatchAdjustments, mw.RequireMerchant())
+	admin.GET("/settlements", handler.ListBatches)
+	admin.GET("/settlements/:id", handler.GetBatchByID)
+	admin.GET("/settlements/:id/payments", handler.ListBatchPayments)
+	admin.GET("/settlements/:id/adjustments", handler.ListBatchAdjustments)
+	admin.POST("/settlements/:id/pay", handler.PayBatch)
+	return handler
+}
+
+// ListBatches lists settlement batches, latest cutoff first
+// @Summary List settlement batches
+// @Description Lists settlement batches, latest cutoff first, optionally for one merchant or in one status. A merchant API key sees only its own batches
+// @Tags settlements
+// @Security MerchantKey
+// @Security ApiKeyAuth
+// @Produce json
+// @Param merchant_id query string false "Only batches of this merchant; empty for payments without merchant_id metadata. Ignored for a merchant API key"
+// @Param status query string false "Only batches in this status" Enums(open, closed, paid)
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of batches to skip"
+// @Success 200 {object} domain.SettlementBatchList "Settlement batches"
+// @Failure 400 {object} domain.ProblemDetails "Invalid filter or pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing merchant API key or admin token"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/settlements [get]
+// @Router /admin/settlements [get]
+func (h *settlementHandler) ListBatches(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+	filter, err := domain.ParseSettlementFilter(c.QueryParams())
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListBatches(c.Request().Context(), filter, page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetBatchByID retrieves a settlement batch by its ID
+// @Summary Get settlement batch by ID
+// @Description Retrieves a settlement batch with its totals
+// @Tags settlements
+// @Security MerchantKey
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Settlement batch ID"
+// @Success 200 {object} domain.SettlementBatch "Settlement batch found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid settlement batch ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing merchant API key or admin token"
+// @Failure 404 {object} domain.ProblemDetails "Settlement batch not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/settlements/{id} [get]
+// @Router /admin/settlements/{id} [get]
+func (h *settlementHandler) GetBatchByID(c echo.Context) error {
+	res, err := h.svc.GetBatchByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListBatchPayments lists the payments in a settlement batch
+// @Summary List settlement batch payments
+// @Description Lists the payments settled in a batch with the fee and refunds taken off each
+// @Tags settlements
+// @Security MerchantKey
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Settlement batch ID"
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of payments to skip"
+// @Success 200 {object} domain.SettlementItemList "Settled payments"
+// @Failure 400 {object} domain.ProblemDetails "Invalid settlement batch ID or pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing merchant API key or admin token"
+// @Failure 404 {object} domain.ProblemDetails "Settlement batch not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/settlements/{id}/payments [get]
+// @Router /admin/settlements/{id}/payments [get]
+func (h *settlementHandler) ListBatchPayments(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListBatchPayments(c.Request().Context(), c.Param("id"), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// ListBatchAdjustments lists the adjustments in a settlement batch
+// @Summary List settlement batch adjustments
+// @Description Lists the refunds and chargebacks on payments settled in earlier batches that this batch takes off its payout
+// @Tags settlements
+// @Security MerchantKey
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Settlement batch ID"
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of adjustments to skip"
+// @Success 200 {object} domain.SettlementAdjustmentList "Settlement adjustments"
+// @Failure 400 {object} domain.ProblemDetails "Invalid settlement batch ID or pagination parameters"
+// @Failure 401 {object} domain.ProblemDetails "Missing merchant API key or admin token"
+// @Failure 404 {object} domain.ProblemDetails "Settlement batch not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/settlements/{id}/adjustments [get]
+// @Router /admin/settlements/{id}/adjustments [get]
+func (h *settlementHandler) ListBatchAdjustments(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListBatchAdjustments(c.Request().Context(), c.Param("id"), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// PayBatch marks a closed settlement batch paid
+// @Summary Mark a settlement batch paid
+// @Description Records that a closed batch's net amount has been paid out to the merchant and posts the payout to the ledger
+// @Tags settlements
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "Settlement batch ID"
+// @Success 200 {object} domain.SettlementBatch "Settlement batch paid"
+// @Failure 400 {object} domain.ProblemDetails "Invalid settlement batch ID format"
+// @Failure 401 {object} domain.ProblemDetails "Missing or invalid admin token"
+// @Failure 404 {object} domain.ProblemDetails "Settlement batch not found"
+// @Failure 409 {object} domain.ProblemDetails "Settlement batch is still open or already paid"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /admin/settlements/{id}/pay [post]
+func (h *settlementHandler) PayBatch(c echo.Context) error {
+	res, err := h.svc.PayBatch(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,84 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	mw "pgm/internal/handler/middleware"
+	stl "pgm/internal/handler/settlement"
+)
+
+type mockService struct {
+	domain.SettlementService
+	filter domain.SettlementFilter
+	paid   bool
+}
+
+func (m *mockService) ListBatches(ctx context.Context, filter domain.SettlementFilter, page domain.Page) (*domain.SettlementBatchList, error) {
+	m.filter = filter
+	return &domain.SettlementBatchList{Data: []domain.SettlementBatch{}, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (m *mockService) PayBatch(ctx context.Context, id string) (*domain.SettlementBatch, error) {
+	m.paid = true
+	return &domain.SettlementBatch{Status: domain.SettlementPaid}, nil
+}
+
+func serve(svc domain.SettlementService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	stl.NewSettlementHandler(e.Group("/v1", mw.MerchantAuth(map[string]string{"acme": "k3y"})), e.Group("/admin"), svc)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestListBatches(t *testing.T) {
+	tests := []struct {
+		name           string
+		query          string
+		expectedStatus int
+		merchantID     *string
+	}{
+		{"unfiltered", "", http.StatusOK, nil},
+		{"by merchant and status", "?merchant_id=acme&status=closed", http.StatusOK, func() *string { s := "acme"; return &s }()},
+		{"without a merchant", "?merchant_id=", http.StatusOK, func() *string { s := ""; return &s }()},
+		{"unknown status", "?status=settled", http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &mockService{}
+			req := httptest.NewRequest(http.MethodGet, "/v1/settlements"+tt.query, nil)
+			req.Header.Set(mw.HeaderAPIKey, "k3y")
+			rec := serve(svc, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			assert.Equal(t, tt.merchantID, svc.filter.MerchantID)
+		})
+	}
+
+	rec := serve(&mockService{}, httptest.NewRequest(http.MethodGet, "/v1/settlements", nil))
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	rec = serve(&mockService{}, httptest.NewRequest(http.MethodGet, "/admin/settlements", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestPayBatch(t *testing.T) {
+	svc := &mockService{}
+	req := httptest.NewRequest(http.MethodPost, "/v1/settlements/abc/pay", nil)
+	req.Header.Set(mw.HeaderAPIKey, "k3y")
+	rec := serve(svc, req)
+	assert.NotEqual(t, http.StatusOK, rec.Code)
+	assert.False(t, svc.paid)
+
+	rec = serve(svc, httptest.NewRequest(http.MethodPost, "/admin/settlements/abc/pay", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, svc.paid)
+}
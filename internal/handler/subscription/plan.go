@@ -0,0 +1,98 @@
+package http
+
+import (
+	"net/http"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// planHandler handles HTTP requests for subscription plans
+type planHandler struct {
+	svc domain.PlanService
+}
+
+// NewPlanHandler initializes the plan routes
+func NewPlanHandler(g *echo.Group, svc domain.PlanService) domain.PlanHandler {
+	handler := &planHandler{
+		svc: svc,
+	}
+	g.POST("/plans", handler.CreatePlan)
+	g.GET("/plans", handler.ListPlans)
+	g.GET("/plans/:id", handler.GetPlanByID)
+	return handler
+}
+
+// CreatePlan handles the creation of a new plan
+// @Summary Create a plan
+// @Description Creates a plan that subscriptions bill: an amount every interval_count intervals, optionally after a free trial
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param plan body domain.PlanRequest true "Plan details"
+// @Success 201 {object} domain.Plan "Plan created"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/plans [post]
+func (h *planHandler) CreatePlan(c echo.Context) error {
+	var pr domain.PlanRequest
+	if err := c.Bind(&pr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.CreatePlan(c.Request().Context(), &pr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// ListPlans lists plans, newest first
+// @Summary List plans
+// @Description Lists plans, newest first
+// @Tags subscriptions
+// @Produce json
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of plans to skip"
+// @Success 200 {object} domain.PlanList "Plans"
+// @Failure 400 {object} domain.ProblemDetails "Invalid pagination parameters"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/plans [get]
+func (h *planHandler) ListPlans(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListPlans(c.Request().Context(), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetPlanByID retrieves a plan by its ID
+// @Summary Get plan by ID
+// @Description Retrieves a plan by its ID
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Plan ID"
+// @Success 200 {object} domain.Plan "Plan found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid plan ID format"
+// @Failure 404 {object} domain.ProblemDetails "Plan not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/plans/{id} [get]
+func (h *planHandler) GetPlanByID(c echo.Context) error {
+	res, err := h.svc.GetPlanByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
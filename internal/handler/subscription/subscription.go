@@ -0,0 +1,134 @@
+package http
+
+import (
+	"net/http"
+
+	"pgm/internal/domain"
+
+	"github.com/labstack/echo/v4"
+)
+
+// subscriptionHandler handles HTTP requests for subscriptions
+type subscriptionHandler struct {
+	svc domain.SubscriptionService
+}
+
+// NewSubscriptionHandler initializes the subscription routes
+func NewSubscriptionHandler(g *echo.Group, svc domain.SubscriptionService) domain.SubscriptionHandler {
+	handler := &subscriptionHandler{
+		svc: svc,
+	}
+	g.POST("/subscriptions", handler.CreateSubscription)
+	g.GET("/subscriptions", handler.ListSubscriptions)
+	g.GET("/subscriptions/:id", handler.GetSubscriptionByID)
+	g.POST("/subscriptions/:id/cancel", handler.CancelSubscription)
+	return handler
+}
+
+// CreateSubscription subscribes a customer to a plan
+// @Summary Create a subscription
+// @Description Subscribes a customer to a plan. The worker charges the payment method once per cycle, starting at the billing anchor, which defaults to the end of the plan's trial.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param subscription body domain.SubscriptionRequest true "Subscription details"
+// @Success 201 {object} domain.Subscription "Subscription created"
+// @Failure 400 {object} domain.ProblemDetails "Invalid request body or validation failed"
+// @Failure 403 {object} domain.ProblemDetails "Metadata merchant_id is not the merchant of the API key"
+// @Failure 404 {object} domain.ProblemDetails "Plan or customer not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/subscriptions [post]
+func (h *subscriptionHandler) CreateSubscription(c echo.Context) error {
+	var sr domain.SubscriptionRequest
+	if err := c.Bind(&sr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.CreateSubscription(c.Request().Context(), &sr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, res)
+}
+
+// ListSubscriptions lists subscriptions, newest first
+// @Summary List subscriptions
+// @Description Lists subscriptions, newest first
+// @Tags subscriptions
+// @Produce json
+// @Param limit query int false "Page size (default 20, capped at 100)"
+// @Param offset query int false "Number of subscriptions to skip"
+// @Success 200 {object} domain.SubscriptionList "Subscriptions"
+// @Failure 400 {object} domain.ProblemDetails "Invalid pagination parameters"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/subscriptions [get]
+func (h *subscriptionHandler) ListSubscriptions(c echo.Context) error {
+	page, err := domain.ParsePage(c.QueryParam("limit"), c.QueryParam("offset"))
+	if err != nil {
+		return err
+	}
+
+	res, err := h.svc.ListSubscriptions(c.Request().Context(), page)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// GetSubscriptionByID retrieves a subscription by its ID
+// @Summary Get subscription by ID
+// @Description Retrieves a subscription with its current period and billing state
+// @Tags subscriptions
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} domain.Subscription "Subscription found"
+// @Failure 400 {object} domain.ProblemDetails "Invalid subscription ID format"
+// @Failure 404 {object} domain.ProblemDetails "Subscription not found"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/subscriptions/{id} [get]
+func (h *subscriptionHandler) GetSubscriptionByID(c echo.Context) error {
+	res, err := h.svc.GetSubscriptionByID(c.Request().Context(), c.Param("id"))
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
+
+// CancelSubscription cancels a subscription now or at the end of its period
+// @Summary Cancel subscription
+// @Description Cancels a subscription right away or, with at_period_end, once the current period ends. A cycle payment already being processed is not recalled.
+// @Tags subscriptions
+// @Accept json
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param cancel body domain.CancelSubscriptionRequest false "Cancellation options"
+// @Success 200 {object} domain.Subscription "Subscription canceled or set to cancel"
+// @Failure 400 {object} domain.ProblemDetails "Invalid subscription ID format or request body"
+// @Failure 404 {object} domain.ProblemDetails "Subscription not found"
+// @Failure 409 {object} domain.ProblemDetails "Subscription already canceled"
+// @Failure 500 {object} domain.ProblemDetails "Internal server error"
+// @Router /v1/subscriptions/{id}/cancel [post]
+func (h *subscriptionHandler) CancelSubscription(c echo.Context) error {
+	var cr domain.CancelSubscriptionRequest
+	if err := c.Bind(&cr); err != nil {
+		return domain.NewError(
+			domain.ErrInvalidRequestBody,
+			"invalid request body",
+			"failed to bind request body",
+			err,
+			nil,
+		)
+	}
+
+	res, err := h.svc.CancelSubscription(c.Request().Context(), c.Param("id"), &cr)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,97 @@
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	sub "pgm/internal/handler/subscription"
+)
+
+type mockPlanService struct {
+	domain.PlanService
+}
+
+func (m *mockPlanService) CreatePlan(ctx context.Context, pr *domain.PlanRequest) (*domain.Plan, error) {
+	if err := pr.Validate(); err != nil {
+		return nil, domain.NewError(domain.ErrValidationFailed, "validation failed", "plan request validation failed", err, nil)
+	}
+	return &domain.Plan{ID: uuid.New(), Name: pr.Name, Amount: pr.Amount, Currency: pr.Currency, Interval: pr.Interval, IntervalCount: 1}, nil
+}
+
+type mockSubscriptionService struct {
+	domain.SubscriptionService
+	cancel *domain.CancelSubscriptionRequest
+}
+
+func (m *mockSubscriptionService) CancelSubscription(ctx context.Context, id string, cr *domain.CancelSubscriptionRequest) (*domain.Subscription, error) {
+	m.cancel = cr
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, domain.NewError(domain.ErrInvalidSubscriptionID, "Invalid subscription ID format", "The provided subscription ID is not a valid UUID format", err, nil)
+	}
+	return &domain.Subscription{Status: domain.SubscriptionActive, CancelAtPeriodEnd: cr.AtPeriodEnd}, nil
+}
+
+func serve(subs domain.SubscriptionService, req *http.Request) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = domain.ErrorHandler
+	g := e.Group("/v1")
+	sub.NewPlanHandler(g, &mockPlanService{})
+	sub.NewSubscriptionHandler(g, subs)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCreatePlan(t *testing.T) {
+	tests := []struct {
+		name           string
+		body           string
+		expectedStatus int
+	}{
+		{"created", `{"name":"Gold","amount":300,"currency":"ETB","interval":"month"}`, http.StatusCreated},
+		{"unknown interval", `{"name":"Gold","amount":300,"currency":"ETB","interval":"fortnight"}`, http.StatusBadRequest},
+		{"malformed body", `{"name":`, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/plans", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := serve(&mockSubscriptionService{}, req)
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+		})
+	}
+}
+
+func TestCancelSubscription(t *testing.T) {
+	t.Run("at period end", func(t *testing.T) {
+		svc := &mockSubscriptionService{}
+		req := httptest.NewRequest(http.MethodPost, "/v1/subscriptions/"+uuid.NewString()+"/cancel", strings.NewReader(`{"at_period_end":true}`))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := serve(svc, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, svc.cancel.AtPeriodEnd)
+		assert.Contains(t, rec.Body.String(), `"cancel_at_period_end":true`)
+	})
+
+	t.Run("without a body", func(t *testing.T) {
+		svc := &mockSubscriptionService{}
+		rec := serve(svc, httptest.NewRequest(http.MethodPost, "/v1/subscriptions/"+uuid.NewString()+"/cancel", nil))
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, svc.cancel.AtPeriodEnd)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		rec := serve(&mockSubscriptionService{}, httptest.NewRequest(http.MethodPost, "/v1/subscriptions/nope/cancel", nil))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,136 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusUp          = "up"
+	StatusDown        = "down"
+)
+
+// Checker reports whether a dependency is usable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a plain function to a Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+// CheckResult is the status of a single dependency.
+type CheckResult struct {
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Latency  string `json:"latency"`
+	Optional bool   `json:"optional,omitempty"`
+}
+
+// Response is the body returned by the health endpoints.
+type Response struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Handler serves liveness and readiness probes.
+type Handler struct {
+	timeout  time.Duration
+	names    []string
+	checks   map[string]Checker
+	optional map[string]bool
+}
+
+// NewHandler creates a Handler whose readiness checks each run with the given timeout.
+func NewHandler(timeout time.Duration) *Handler {
+	return &Handler{
+		timeout:  timeout,
+		checks:   make(map[string]Checker),
+		optional: make(map[string]bool),
+	}
+}
+
+// Add registers a named readiness check.
+func (h *Handler) Add(name string, c Checker) {
+	if _, ok := h.checks[name]; !ok {
+		h.names = append(h.names, name)
+	}
+	h.checks[name] = c
+	delete(h.optional, name)
+}
+
+// AddOptional registers a check that is reported but does not fail readiness.
+// A failing optional check turns the overall status to degraded.
+func (h *Handler) AddOptional(name string, c Checker) {
+	h.Add(name, c)
+	h.optional[name] = true
+}
+
+// Register mounts /healthz and /readyz on e.
+func Register(e *echo.Echo, h *Handler) {
+	e.GET("/healthz", h.Liveness)
+	e.GET("/readyz", h.Readiness)
+}
+
+// Liveness reports that the process is running.
+func (h *Handler) Liveness(c echo.Context) error {
+	return c.JSON(http.StatusOK, Response{Status: StatusOK})
+}
+
+// Readiness runs every registered check and reports per-dependency status.
+func (h *Handler) Readiness(c echo.Context) error {
+	res := h.Run(c.Request().Context())
+	code := http.StatusOK
+	if res.Status == StatusUnavailable {
+		code = http.StatusServiceUnavailable
+	}
+	return c.JSON(code, res)
+}
+
+// Run executes all checks concurrently and aggregates the result.
+func (h *Handler) Run(ctx context.Context) Response {
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		res = Response{Status: StatusOK, Checks: make(map[string]CheckResult, len(h.names))}
+	)
+	for _, name := range h.names {
+		wg.Add(1)
+		go func(name string, chk Checker, optional bool) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := chk.Check(cctx)
+			r := CheckResult{Status: StatusUp, Latency: time.Since(start).String(), Optional: optional}
+			if err != nil {
+				r.Status = StatusDown
+				r.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			res.Checks[name] = r
+			switch {
+			case err == nil:
+			case !optional:
+				res.Status = StatusUnavailable
+			case res.Status == StatusOK:
+				res.Status = StatusDegraded
+			}
+		}(name, h.checks[name], h.optional[name])
+	}
+	wg.Wait()
+	return res
+}
@@ -0,0 +1,124 @@
+package health_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/health"
+)
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name           string
+		checks         map[string]health.Checker
+		optional       map[string]health.Checker
+		expectedStatus int
+		expectedBody   string
+		expectedChecks map[string]string
+	}{
+		{
+			name: "all dependencies up",
+			checks: map[string]health.Checker{
+				"postgres": health.CheckerFunc(func(ctx context.Context) error { return nil }),
+				"rabbitmq": health.CheckerFunc(func(ctx context.Context) error { return nil }),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   health.StatusOK,
+			expectedChecks: map[string]string{"postgres": health.StatusUp, "rabbitmq": health.StatusUp},
+		},
+		{
+			name: "one dependency down",
+			checks: map[string]health.Checker{
+				"postgres": health.CheckerFunc(func(ctx context.Context) error { return nil }),
+				"rabbitmq": health.CheckerFunc(func(ctx context.Context) error { return errors.New("amqp connection is closed") }),
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   health.StatusUnavailable,
+			expectedChecks: map[string]string{"postgres": health.StatusUp, "rabbitmq": health.StatusDown},
+		},
+		{
+			name: "optional dependency down",
+			checks: map[string]health.Checker{
+				"postgres": health.CheckerFunc(func(ctx context.Context) error { return nil }),
+			},
+			optional: map[string]health.Checker{
+				"provider:primary": health.CheckerFunc(func(ctx context.Context) error { return errors.New("circuit open") }),
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   health.StatusDegraded,
+			expectedChecks: map[string]string{"postgres": health.StatusUp, "provider:primary": health.StatusDown},
+		},
+		{
+			name: "required dependency down outranks optional",
+			checks: map[string]health.Checker{
+				"postgres": health.CheckerFunc(func(ctx context.Context) error { return errors.New("connection refused") }),
+			},
+			optional: map[string]health.Checker{
+				"provider:primary": health.CheckerFunc(func(ctx context.Context) error { return errors.New("circuit open") }),
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   health.StatusUnavailable,
+			expectedChecks: map[string]string{"postgres": health.StatusDown, "provider:primary": health.StatusDown},
+		},
+		{
+			name: "check exceeding timeout",
+			checks: map[string]health.Checker{
+				"postgres": health.CheckerFunc(func(ctx context.Context) error {
+					<-ctx.Done()
+					return ctx.Err()
+				}),
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   health.StatusUnavailable,
+			expectedChecks: map[string]string{"postgres": health.StatusDown},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := health.NewHandler(50 * time.Millisecond)
+			for name, c := range tt.checks {
+				h.Add(name, c)
+			}
+			for name, c := range tt.optional {
+				h.AddOptional(name, c)
+			}
+			e := echo.New()
+			health.Register(e, h)
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedStatus, rec.Code)
+			var res health.Response
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			assert.Equal(t, tt.expectedBody, res.Status)
+			for name, status := range tt.expectedChecks {
+				assert.Equal(t, status, res.Checks[name].Status, name)
+			}
+		})
+	}
+}
+
+func TestLiveness(t *testing.T) {
+	h := health.NewHandler(time.Second)
+	h.Add("postgres", health.CheckerFunc(func(ctx context.Context) error { return errors.New("down") }))
+	e := echo.New()
+	health.Register(e, h)
+
+	req := httptest.NewRequest(http.MethodGet, "/healthz", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
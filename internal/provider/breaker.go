@@ -0,0 +1,277 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"pgm/internal/domain"
+)
+
+type BreakerState int
+
+const (
+	StateClosed BreakerState = iota
+	StateHalfOpen
+	StateOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateHalfOpen:
+		return "half-open"
+	case StateOpen:
+		return "open"
+	}
+	return "unknown"
+}
+
+// BreakerConfig controls when a circuit breaker trips and recovers.
+type BreakerConfig struct {
+	// FailureThreshold is the number of consecutive failures that opens the breaker.
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before letting probes through.
+	OpenTimeout time.Duration
+	// HalfOpenMaxCalls is the number of concurrent probes allowed while half-open.
+	// That many successes in a row close the breaker; any failure reopens it.
+	HalfOpenMaxCalls int
+}
+
+// DefaultBreakerConfig is used for settings left at zero.
+var DefaultBreakerConfig = BreakerConfig{
+	FailureThreshold: 5,
+	OpenTimeout:      30 * time.Second,
+	HalfOpenMaxCalls: 1,
+}
+
+// Breaker is a closed/open/half-open circuit breaker. Only outcomes passed to
+// Done count; callers decide what a failure is.
+type Breaker struct {
+	cfg BreakerConfig
+	now func() time.Time
+
+	mu        sync.Mutex
+	state     BreakerState
+	failures  int
+	inFlight  int // probes running while half-open
+	successes int // probe successes while half-open
+	openedAt  time.Time
+	opens     uint64
+	rejected  uint64
+}
+
+func NewBreaker(cfg BreakerConfig) *Breaker {
+	if cfg.FailureThreshold < 1 {
+		cfg.FailureThreshold = DefaultBreakerConfig.FailureThreshold
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = DefaultBreakerConfig.OpenTimeout
+	}
+	if cfg.HalfOpenMaxCalls < 1 {
+		cfg.HalfOpenMaxCalls = DefaultBreakerConfig.HalfOpenMaxCalls
+	}
+	return &Breaker{cfg: cfg, now: time.Now}
+}
+
+// Allow reserves a call. It returns domain.ErrCircuitOpen while the breaker is
+// open or its half-open probes are taken; otherwise the caller must report the
+// outcome with Done.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		b.state = StateHalfOpen
+		b.inFlight, b.successes = 0, 0
+	}
+
+	switch b.state {
+	case StateOpen:
+		b.rejected++
+		return fmt.Errorf("%w: retry in %s", domain.ErrCircuitOpen, b.retryIn().Round(time.Second))
+	case StateHalfOpen:
+		if b.inFlight >= b.cfg.HalfOpenMaxCalls {
+			b.rejected++
+			return fmt.Errorf("%w: half-open probe in progress", domain.ErrCircuitOpen)
+		}
+		b.inFlight++
+	}
+	return nil
+}
+
+// Done records the outcome of a call admitted by Allow.
+func (b *Breaker) Done(success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateClosed:
+		if success {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.cfg.FailureThreshold {
+			b.open()
+		}
+	case StateHalfOpen:
+		b.inFlight--
+		if !success {
+			b.open()
+			return
+		}
+		b.successes++
+		if b.successes >= b.cfg.HalfOpenMaxCalls {
+			b.state = StateClosed
+			b.failures = 0
+		}
+	}
+}
+
+// release returns a half-open probe slot without recording an outcome.
+func (b *Breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateHalfOpen && b.inFlight > 0 {
+		b.inFlight--
+	}
+}
+
+func (b *Breaker) open() {
+	b.state = StateOpen
+	b.openedAt = b.now()
+	b.opens++
+}
+
+func (b *Breaker) retryIn() time.Duration {
+	return b.cfg.OpenTimeout - b.now().Sub(b.openedAt)
+}
+
+// State reports the current state, moving an expired open breaker to half-open
+// lazily like Allow does.
+func (b *Breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == StateOpen && b.now().Sub(b.openedAt) >= b.cfg.OpenTimeout {
+		return StateHalfOpen
+	}
+	return b.state
+}
+
+// BreakerStats is a snapshot of a breaker for metrics.
+type BreakerStats struct {
+	State    BreakerState
+	Opens    uint64
+	Rejected uint64
+}
+
+func (b *Breaker) Stats() BreakerStats {
+	state := b.State()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BreakerStats{State: state, Opens: b.opens, Rejected: b.rejected}
+}
+
+// Check implements health.Checker: a breaker that is not closed is reported down.
+func (b *Breaker) Check(ctx context.Context) error {
+	if s := b.State(); s != StateClosed {
+		return fmt.Errorf("circuit %s", s)
+	}
+	return nil
+}
+
+// Guarded wraps a Provider with a circuit breaker and a hard per-call timeout.
+type Guarded struct {
+	domain.Provider
+	breaker *Breaker
+	timeout time.Duration
+}
+
+var _ domain.Provider = (*Guarded)(nil)
+
+// NewGuarded protects p. Each call runs with the given timeout on top of the
+// caller's context, so the message deadline still wins when it is sooner.
+func NewGuarded(p domain.Provider, cfg BreakerConfig, timeout time.Duration) *Guarded {
+	return &Guarded{Provider: p, breaker: NewBreaker(cfg), timeout: timeout}
+}
+
+func (g *Guarded) Breaker() *Breaker {
+	return g.breaker
+}
+
+func (g *Guarded) Charge(ctx context.Context, p *domain.Payment) (*domain.ChargeResult, error) {
+	if err := g.breaker.Allow(); err != nil {
+		return nil, fmt.Errorf("%s: %w: %w", g.Name(), domain.ErrProviderUnavailable, err)
+	}
+
+	res, err := g.call(ctx, p)
+	if ctx.Err() != nil {
+		// The caller gave up, e.g. on shutdown; that says nothing about the
+		// provider, so free the slot without counting a failure
+		g.breaker.release()
+		return res, err
+	}
+	g.breaker.Done(!errors.Is(err, domain.ErrProviderUnavailable))
+	return res, err
+}
+
+// call runs the provider under the timeout and abandons it once the timeout
+// passes, even if the provider does not honour its context.
+func (g *Guarded) call(ctx context.Context, p *domain.Payment) (*domain.ChargeResult, error) {
+	if g.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+	}
+
+	type outcome struct {
+		res *domain.ChargeResult
+		err error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		res, err := g.Provider.Charge(ctx, p)
+		done <- outcome{res, err}
+	}()
+
+	select {
+	case o := <-done:
+		return o.res, o.err
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%s: %w: %w", g.Name(), domain.ErrProviderUnavailable, ctx.Err())
+	}
+}
+
+// BreakerConfigFromEnv reads BREAKER_FAILURE_THRESHOLD, BREAKER_OPEN_TIMEOUT
+// and BREAKER_HALF_OPEN_MAX_CALLS. Unset values fall back to DefaultBreakerConfig.
+func BreakerConfigFromEnv() (BreakerConfig, error) {
+	cfg := DefaultBreakerConfig
+	if v := os.Getenv("BREAKER_FAILURE_THRESHOLD"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return cfg, fmt.Errorf("invalid BREAKER_FAILURE_THRESHOLD value: %s", v)
+		}
+		cfg.FailureThreshold = n
+	}
+	if v := os.Getenv("BREAKER_OPEN_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid BREAKER_OPEN_TIMEOUT value: %s", v)
+		}
+		cfg.OpenTimeout = d
+	}
+	if v := os.Getenv("BREAKER_HALF_OPEN_MAX_CALLS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return cfg, fmt.Errorf("invalid BREAKER_HALF_OPEN_MAX_CALLS value: %s", v)
+		}
+		cfg.HalfOpenMaxCalls = n
+	}
+	return cfg, nil
+}
@@ -0,0 +1,128 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"pgm/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestBreaker(cfg BreakerConfig) (*Breaker, *clock) {
+	c := &clock{t: time.Unix(0, 0)}
+	b := NewBreaker(cfg)
+	b.now = c.now
+	return b, c
+}
+
+func TestBreakerTransitions(t *testing.T) {
+	b, clk := newTestBreaker(BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenMaxCalls: 1})
+
+	// Successes reset the consecutive failure count
+	for _, ok := range []bool{false, true, false} {
+		assert.NoError(t, b.Allow())
+		b.Done(ok)
+	}
+	assert.Equal(t, StateClosed, b.State())
+
+	assert.NoError(t, b.Allow())
+	b.Done(false)
+	assert.Equal(t, StateOpen, b.State())
+	assert.ErrorIs(t, b.Allow(), domain.ErrCircuitOpen)
+
+	// After the open timeout a single probe is let through
+	clk.advance(time.Minute)
+	assert.Equal(t, StateHalfOpen, b.State())
+	assert.NoError(t, b.Allow())
+	assert.ErrorIs(t, b.Allow(), domain.ErrCircuitOpen)
+
+	// A failed probe reopens the breaker
+	b.Done(false)
+	assert.Equal(t, StateOpen, b.State())
+
+	// A successful probe closes it
+	clk.advance(time.Minute)
+	assert.NoError(t, b.Allow())
+	b.Done(true)
+	assert.Equal(t, StateClosed, b.State())
+
+	assert.Equal(t, BreakerStats{State: StateClosed, Opens: 2, Rejected: 2}, b.Stats())
+}
+
+type stubProvider struct {
+	delay time.Duration
+	err   error
+}
+
+func (s *stubProvider) Name() string { return "stub" }
+
+func (s *stubProvider) Charge(ctx context.Context, p *domain.Payment) (*domain.ChargeResult, error) {
+	// Deliberately ignores ctx to prove the timeout is enforced anyway
+	time.Sleep(s.delay)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &domain.ChargeResult{Status: domain.StatusSuccess}, nil
+}
+
+func TestGuarded(t *testing.T) {
+	cfg := BreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute, HalfOpenMaxCalls: 1}
+
+	t.Run("timeout is hard and counts as unavailable", func(t *testing.T) {
+		g := NewGuarded(&stubProvider{delay: time.Second}, cfg, 10*time.Millisecond)
+		start := time.Now()
+		_, err := g.Charge(context.Background(), &domain.Payment{})
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.ErrorIs(t, err, domain.ErrProviderUnavailable)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("opens after unavailable errors and short-circuits", func(t *testing.T) {
+		stub := &stubProvider{err: fmt.Errorf("%w: outage", domain.ErrProviderUnavailable)}
+		g := NewGuarded(stub, cfg, time.Second)
+		for i := 0; i < 2; i++ {
+			_, _ = g.Charge(context.Background(), &domain.Payment{})
+		}
+		assert.Equal(t, StateOpen, g.Breaker().State())
+
+		_, err := g.Charge(context.Background(), &domain.Payment{})
+		assert.ErrorIs(t, err, domain.ErrCircuitOpen)
+		assert.ErrorIs(t, err, domain.ErrProviderUnavailable)
+	})
+
+	t.Run("declines and other errors keep the breaker closed", func(t *testing.T) {
+		g := NewGuarded(&stubProvider{err: errors.New("invalid account")}, cfg, time.Second)
+		for i := 0; i < 3; i++ {
+			_, _ = g.Charge(context.Background(), &domain.Payment{})
+		}
+		assert.Equal(t, StateClosed, g.Breaker().State())
+	})
+
+	t.Run("canceled caller does not count", func(t *testing.T) {
+		g := NewGuarded(&stubProvider{delay: 50 * time.Millisecond}, cfg, time.Second)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		for i := 0; i < 3; i++ {
+			_, _ = g.Charge(ctx, &domain.Payment{})
+		}
+		assert.Equal(t, StateClosed, g.Breaker().State())
+	})
+}
+
+func TestWriteMetrics(t *testing.T) {
+	g := NewGuarded(&stubProvider{}, BreakerConfig{}, 0)
+	var sb strings.Builder
+	assert.NoError(t, WriteMetrics(&sb, []*Guarded{g}))
+	assert.Contains(t, sb.String(), `pgm_provider_circuit_state{provider="stub"} 0`)
+	assert.Contains(t, sb.String(), "# TYPE pgm_provider_circuit_opens_total counter")
+}
@@ -0,0 +1,38 @@
+package provider
+
+import (
+	"fmt"
+	"io"
+)
+
+// WriteMetrics writes breaker state for each provider in the Prometheus text
+// exposition format.
+func WriteMetrics(w io.Writer, providers []*Guarded) error {
+	stats := make([]BreakerStats, len(providers))
+	for i, g := range providers {
+		stats[i] = g.Breaker().Stats()
+	}
+
+	metrics := []struct {
+		name, help, kind string
+		value            func(BreakerStats) float64
+	}{
+		{"pgm_provider_circuit_state", "Circuit breaker state per provider (0 closed, 1 half-open, 2 open).", "gauge",
+			func(s BreakerStats) float64 { return float64(s.State) }},
+		{"pgm_provider_circuit_opens_total", "Times the provider's circuit breaker opened.", "counter",
+			func(s BreakerStats) float64 { return float64(s.Opens) }},
+		{"pgm_provider_circuit_rejected_total", "Calls short-circuited by the provider's circuit breaker.", "counter",
+			func(s BreakerStats) float64 { return float64(s.Rejected) }},
+	}
+	for _, m := range metrics {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind); err != nil {
+			return err
+		}
+		for i, g := range providers {
+			if _, err := fmt.Fprintf(w, "%s{provider=%q} %g\n", m.name, g.Name(), m.value(stats[i])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
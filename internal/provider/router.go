@@ -0,0 +1,107 @@
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"pgm/internal/domain"
+)
+
+// MerchantMetadataKey is the payment metadata key a rule's Merchant is matched
+// against.
+const MerchantMetadataKey = domain.MerchantMetadataKey
+
+// Rule routes the payments it matches to Providers, tried in order. Empty
+// criteria match everything.
+type Rule struct {
+	Currency string `json:"currency,omitempty"`
+	// MinAmount is inclusive; MaxAmount is exclusive and unbounded when zero.
+	MinAmount float64                    `json:"min_amount,omitempty"`
+	MaxAmount float64                    `json:"max_amount,omitempty"`
+	Methods   []domain.PaymentMethodType `json:"methods,omitempty"`
+	Merchant  string                     `json:"merchant,omitempty"`
+	Providers []string                   `json:"providers"`
+}
+
+// Matches reports whether p satisfies every criterion of the rule.
+func (r Rule) Matches(p *domain.Payment) bool {
+	if r.Currency != "" && !strings.EqualFold(r.Currency, p.Currency) {
+		return false
+	}
+	if p.Amount < r.MinAmount || (r.MaxAmount > 0 && p.Amount >= r.MaxAmount) {
+		return false
+	}
+	if len(r.Methods) > 0 && (p.PaymentMethod == nil || !slices.Contains(r.Methods, p.PaymentMethod.Type)) {
+		return false
+	}
+	if r.Merchant != "" && p.Metadata[MerchantMetadataKey] != r.Merchant {
+		return false
+	}
+	return true
+}
+
+// RuleRouter picks providers from the first rule matching a payment.
+type RuleRouter struct {
+	rules     []Rule
+	providers map[string]domain.Provider
+}
+
+var _ domain.Router = (*RuleRouter)(nil)
+
+// NewRuleRouter checks that every provider named in rules is registered. With
+// no rules, all providers are tried in the order given.
+func NewRuleRouter(providers []domain.Provider, rules []Rule) (*RuleRouter, error) {
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("at least one provider is required")
+	}
+	byName := make(map[string]domain.Provider, len(providers))
+	names := make([]string, 0, len(providers))
+	for _, p := range providers {
+		byName[p.Name()] = p
+		names = append(names, p.Name())
+	}
+	if len(rules) == 0 {
+		rules = []Rule{{Providers: names}}
+	}
+	for i, r := range rules {
+		if len(r.Providers) == 0 {
+			return nil, fmt.Errorf("routing rule %d has no providers", i)
+		}
+		for _, name := range r.Providers {
+			if _, ok := byName[name]; !ok {
+				return nil, fmt.Errorf("routing rule %d references unknown provider %q", i, name)
+			}
+		}
+	}
+	return &RuleRouter{rules: rules, providers: byName}, nil
+}
+
+// Route returns the candidates of the first matching rule, or domain.ErrNoRoute.
+func (r *RuleRouter) Route(p *domain.Payment) ([]domain.Provider, error) {
+	for _, rule := range r.rules {
+		if !rule.Matches(p) {
+			continue
+		}
+		candidates := make([]domain.Provider, 0, len(rule.Providers))
+		for _, name := range rule.Providers {
+			candidates = append(candidates, r.providers[name])
+		}
+		return candidates, nil
+	}
+	return nil, domain.ErrNoRoute
+}
+
+// ParseRules parses routing rules from a JSON array. An empty string yields no
+// rules.
+func ParseRules(s string) ([]Rule, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	var rules []Rule
+	if err := json.Unmarshal([]byte(s), &rules); err != nil {
+		return nil, fmt.Errorf("invalid routing rules: %w", err)
+	}
+	return rules, nil
+}
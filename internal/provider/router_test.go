@@ -0,0 +1,100 @@
+package provider_test
+
+import (
+	"testing"
+
+	"pgm/internal/domain"
+	"pgm/internal/provider"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func names(providers []domain.Provider) []string {
+	var out []string
+	for _, p := range providers {
+		out = append(out, p.Name())
+	}
+	return out
+}
+
+func TestRuleRouter(t *testing.T) {
+	rules, err := provider.ParseRules(`[
+		{"currency": "ETB", "methods": ["mobile_money"], "providers": ["telebirr", "chapa"]},
+		{"merchant": "acme", "providers": ["acme_acquirer"]},
+		{"currency": "USD", "max_amount": 1000, "providers": ["small", "big"]},
+		{"currency": "USD", "min_amount": 1000, "providers": ["big"]}
+	]`)
+	assert.NoError(t, err)
+
+	var providers []domain.Provider
+	for _, name := range []string{"telebirr", "chapa", "acme_acquirer", "small", "big"} {
+		providers = append(providers, provider.NewSimulated(name, 0))
+	}
+	router, err := provider.NewRuleRouter(providers, rules)
+	assert.NoError(t, err)
+
+	mobileMoney := &domain.PaymentMethod{Type: domain.MethodMobileMoney}
+
+	tests := []struct {
+		name     string
+		payment  *domain.Payment
+		expected []string
+		err      error
+	}{
+		{
+			name:     "currency and method",
+			payment:  &domain.Payment{Amount: 50, Currency: "ETB", PaymentMethod: mobileMoney},
+			expected: []string{"telebirr", "chapa"},
+		},
+		{
+			name:     "merchant",
+			payment:  &domain.Payment{Amount: 50, Currency: "ETB", Metadata: domain.Metadata{provider.MerchantMetadataKey: "acme"}},
+			expected: []string{"acme_acquirer"},
+		},
+		{
+			name:     "below amount band limit",
+			payment:  &domain.Payment{Amount: 999.99, Currency: "USD"},
+			expected: []string{"small", "big"},
+		},
+		{
+			name:     "amount band lower bound is inclusive",
+			payment:  &domain.Payment{Amount: 1000, Currency: "USD"},
+			expected: []string{"big"},
+		},
+		{
+			name:    "no matching rule",
+			payment: &domain.Payment{Amount: 50, Currency: "ETB"},
+			err:     domain.ErrNoRoute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidates, err := router.Route(tt.payment)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, names(candidates))
+		})
+	}
+}
+
+func TestNewRuleRouter(t *testing.T) {
+	providers := []domain.Provider{provider.NewSimulated("a", 0), provider.NewSimulated("b", 0)}
+
+	t.Run("defaults to all providers", func(t *testing.T) {
+		router, err := provider.NewRuleRouter(providers, nil)
+		assert.NoError(t, err)
+		candidates, err := router.Route(&domain.Payment{Amount: 1, Currency: "USD"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, names(candidates))
+	})
+
+	t.Run("unknown provider", func(t *testing.T) {
+		_, err := provider.NewRuleRouter(providers, []provider.Rule{{Providers: []string{"c"}}})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		_, err := provider.ParseRules(`{"providers": "a"}`)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,72 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"pgm/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// profile is the simulated behaviour of a payment method.
+type profile struct {
+	latency     time.Duration
+	failureRate float32
+}
+
+// defaultProfile applies to payments without a recorded payment method.
+var defaultProfile = profile{latency: 2 * time.Second, failureRate: 0.3}
+
+// methodProfiles approximates how each method behaves: bank transfers take
+// longest, mobile money waits on the payer's confirmation and fails most.
+var methodProfiles = map[domain.PaymentMethodType]profile{
+	domain.MethodCard:         {latency: 1 * time.Second, failureRate: 0.2},
+	domain.MethodBankTransfer: {latency: 3 * time.Second, failureRate: 0.1},
+	domain.MethodMobileMoney:  {latency: 2 * time.Second, failureRate: 0.35},
+	domain.MethodWallet:       {latency: 1 * time.Second, failureRate: 0.15},
+}
+
+// Simulated stands in for a real acquirer. It declines a share of payments
+// per method and is unavailable for a further share of calls.
+type Simulated struct {
+	name            string
+	unavailableRate float32
+}
+
+var _ domain.Provider = (*Simulated)(nil)
+
+func NewSimulated(name string, unavailableRate float32) *Simulated {
+	return &Simulated{name: name, unavailableRate: unavailableRate}
+}
+
+func (s *Simulated) Name() string {
+	return s.name
+}
+
+func (s *Simulated) Charge(ctx context.Context, p *domain.Payment) (*domain.ChargeResult, error) {
+	pr := defaultProfile
+	if p.PaymentMethod != nil {
+		if mp, ok := methodProfiles[p.PaymentMethod.Type]; ok {
+			pr = mp
+		}
+	}
+
+	select {
+	case <-time.After(pr.latency):
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%s: %w: %w", s.name, domain.ErrProviderUnavailable, ctx.Err())
+	}
+
+	if rand.Float32() < s.unavailableRate {
+		return nil, fmt.Errorf("%s: %w: simulated outage", s.name, domain.ErrProviderUnavailable)
+	}
+
+	res := &domain.ChargeResult{Status: domain.StatusSuccess, Reference: s.name + "_" + uuid.NewString()}
+	if rand.Float32() < pr.failureRate {
+		res.Status = domain.StatusFailed
+	}
+	return res, nil
+}
@@ -0,0 +1,132 @@
+package rabbitmq
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"pgm/internal/domain"
+
+	"github.com/streadway/amqp"
+)
+
+// RabbitMQConsumer feeds a Consumer from a RabbitMQ queue.
+type RabbitMQConsumer struct {
+	*connState
+	*Consumer
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+func NewRabbitMQConsumer(svc domain.PaymentService) (*RabbitMQConsumer, error) {
+	// read env variables and set default values
+	var (
+		url          string
+		messageQueue string
+	)
+	url = os.Getenv("RABBITMQ_URL")
+	messageQueue = os.Getenv("MESSAGE_QUEUE")
+	if messageQueue == "" {
+		messageQueue = "payment_processing"
+	}
+
+	cfg, err := ConsumerConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open a channel: %w", err)
+	}
+
+	q, err := ch.QueueDeclare(
+		messageQueue, // name
+		true,         // durable
+		false,        // delete when unused
+		false,        // exclusive
+		false,        // no-wait
+		nil,          // arguments
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to declare a queue: %w", err)
+	}
+
+	// Set QoS to ensure fair dispatch among multiple workers
+	err = ch.Qos(
+		max(cfg.WorkerCount, 1), // prefetch count
+		0,                       // prefetch size
+		false,                   // global
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set QoS: %w", err)
+	}
+
+	consumer, err := NewConsumer(&amqpSource{channel: ch, queue: q.Name}, svc, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RabbitMQConsumer{
+		connState: newConnState(conn, ch),
+		Consumer:  consumer,
+		conn:      conn,
+		channel:   ch,
+	}, nil
+}
+
+func (c *RabbitMQConsumer) Close() {
+	c.channel.Close()
+	c.conn.Close()
+}
+
+// amqpSource adapts an AMQP queue subscription to a DeliverySource.
+type amqpSource struct {
+	channel *amqp.Channel
+	queue   string
+}
+
+func (s *amqpSource) Deliveries(ctx context.Context) (<-chan Delivery, error) {
+	msgs, err := s.channel.Consume(
+		s.queue,
+		"",
+		false, // manual ack
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(chan Delivery)
+	go func() {
+		defer close(out)
+		for d := range msgs {
+			select {
+			case out <- amqpDelivery{d}:
+			case <-ctx.Done():
+				// Not handed to a worker; let the broker redeliver it
+				_ = d.Nack(false, true)
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+type amqpDelivery struct {
+	d amqp.Delivery
+}
+
+func (a amqpDelivery) Body() []byte                    { return a.d.Body }
+func (a amqpDelivery) Headers() map[string]interface{} { return a.d.Headers }
+func (a amqpDelivery) Ack() error                      { return a.d.Ack(false) }
+func (a amqpDelivery) Nack() error                     { return a.d.Nack(false, false) }
+func (a amqpDelivery) Requeue() error                  { return a.d.Nack(false, true) }
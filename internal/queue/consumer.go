@@ -3,38 +3,51 @@ package rabbitmq
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"pgm/internal/domain"
+	"pgm/internal/logger"
 
 	"github.com/avast/retry-go"
-	"github.com/streadway/amqp"
 )
 
-type RabbitMQConsumer struct {
-	conn        *amqp.Connection
-	channel     *amqp.Channel
-	queue       string
-	svc         domain.PaymentService
-	retryOpts   []retry.Option
-	maxAttempts uint
-	workerCount int
+// Delivery is a single message handed to the consumer by a DeliverySource.
+type Delivery interface {
+	Body() []byte
+	Headers() map[string]interface{}
+	// Ack confirms the message was handled.
+	Ack() error
+	// Nack rejects the message without requeueing it (dead-lettering it, if configured).
+	Nack() error
+	// Requeue rejects the message and returns it to the queue for redelivery.
+	Requeue() error
 }
 
-func NewRabbitMQConsumer(svc domain.PaymentService) (*RabbitMQConsumer, error) {
-	// read env variables and set default values
-	var (
-		url          string
-		messageQueue string
-	)
-	url = os.Getenv("RABBITMQ_URL")
-	messageQueue = os.Getenv("MESSAGE_QUEUE")
-	if messageQueue == "" {
-		messageQueue = "payment_processing"
-	}
+// DeliverySource produces deliveries for the consumer. The returned channel is
+// closed when the source stops.
+type DeliverySource interface {
+	Deliveries(ctx context.Context) (<-chan Delivery, error)
+}
+
+// ConsumerConfig controls worker concurrency and retries.
+type ConsumerConfig struct {
+	RetryAttempts uint
+	RetryDelay    time.Duration
+	RetryMaxDelay time.Duration
+	// DelayType is "fixed" or "backoff".
+	DelayType   string
+	WorkerCount int
+	// RetryLaterDelay is how long a worker holds a message that cannot be
+	// processed yet, e.g. while provider circuits are open, before requeueing it.
+	RetryLaterDelay time.Duration
+}
+
+// ConsumerConfigFromEnv reads the consumer configuration, applying defaults.
+func ConsumerConfigFromEnv() (ConsumerConfig, error) {
 	// Parse retry attempts
 	attemptsStr := os.Getenv("RETRY_ATTEMPTS")
 	if attemptsStr == "" {
@@ -42,7 +55,7 @@ func NewRabbitMQConsumer(svc domain.PaymentService) (*RabbitMQConsumer, error) {
 	}
 	attempts, err := strconv.Atoi(attemptsStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid RETRY_ATTEMPTS value: %v", err)
+		return ConsumerConfig{}, fmt.Errorf("invalid RETRY_ATTEMPTS value: %v", err)
 	}
 
 	// Parse delay type
@@ -58,7 +71,7 @@ func NewRabbitMQConsumer(svc domain.PaymentService) (*RabbitMQConsumer, error) {
 	}
 	delay, err := time.ParseDuration(delayStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid RETRY_DELAY value: %v", err)
+		return ConsumerConfig{}, fmt.Errorf("invalid RETRY_DELAY value: %v", err)
 	}
 
 	// Parse max delay
@@ -68,7 +81,17 @@ func NewRabbitMQConsumer(svc domain.PaymentService) (*RabbitMQConsumer, error) {
 	}
 	maxDelay, err := time.ParseDuration(maxDelayStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid RETRY_MAX_DELAY value: %v", err)
+		return ConsumerConfig{}, fmt.Errorf("invalid RETRY_MAX_DELAY value: %v", err)
+	}
+
+	// Parse retry-later delay
+	retryLaterStr := os.Getenv("RETRY_LATER_DELAY")
+	if retryLaterStr == "" {
+		retryLaterStr = "5s" // Default to 5s if not specified
+	}
+	retryLater, err := time.ParseDuration(retryLaterStr)
+	if err != nil {
+		return ConsumerConfig{}, fmt.Errorf("invalid RETRY_LATER_DELAY value: %v", err)
 	}
 
 	// Parse worker count
@@ -78,148 +101,166 @@ func NewRabbitMQConsumer(svc domain.PaymentService) (*RabbitMQConsumer, error) {
 	}
 	workerCount, err := strconv.Atoi(workerCountStr)
 	if err != nil {
-		return nil, fmt.Errorf("invalid WORKER_COUNT value: %v", err)
+		return ConsumerConfig{}, fmt.Errorf("invalid WORKER_COUNT value: %v", err)
 	}
-	if workerCount < 1 {
-		workerCount = 1
+
+	return ConsumerConfig{
+		RetryAttempts:   uint(attempts),
+		RetryDelay:      delay,
+		RetryMaxDelay:   maxDelay,
+		DelayType:       delayType,
+		WorkerCount:     workerCount,
+		RetryLaterDelay: retryLater,
+	}, nil
+}
+
+// Consumer runs a pool of workers that process payments from a DeliverySource.
+type Consumer struct {
+	source      DeliverySource
+	svc         domain.PaymentService
+	retryOpts   []retry.Option
+	maxAttempts uint
+	workerCount int
+	retryLater  time.Duration
+}
+
+func NewConsumer(source DeliverySource, svc domain.PaymentService, cfg ConsumerConfig) (*Consumer, error) {
+	if cfg.RetryAttempts < 1 {
+		cfg.RetryAttempts = 1
+	}
+	if cfg.WorkerCount < 1 {
+		cfg.WorkerCount = 1
 	}
 
 	// Configure retry options
 	retryOpts := []retry.Option{
-		retry.Attempts(uint(attempts)),
+		retry.Attempts(cfg.RetryAttempts),
+		retry.LastErrorOnly(true),
 	}
 
-	switch delayType {
+	switch cfg.DelayType {
 	case "fixed":
-		retryOpts = append(retryOpts, retry.Delay(delay))
+		retryOpts = append(retryOpts, retry.Delay(cfg.RetryDelay))
 	case "backoff":
 		retryOpts = append(retryOpts,
-			retry.Delay(delay),
+			retry.Delay(cfg.RetryDelay),
 			retry.DelayType(retry.BackOffDelay),
 		)
 	default:
-		return nil, fmt.Errorf("invalid RETRY_DELAY_TYPE: %s. Must be 'fixed' or 'backoff'", delayType)
-	}
-
-	if maxDelay > 0 {
-		retryOpts = append(retryOpts, retry.MaxDelay(maxDelay))
-	}
-
-	conn, err := amqp.Dial(url)
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
-	}
-
-	ch, err := conn.Channel()
-	if err != nil {
-		return nil, fmt.Errorf("failed to open a channel: %w", err)
+		return nil, fmt.Errorf("invalid RETRY_DELAY_TYPE: %s. Must be 'fixed' or 'backoff'", cfg.DelayType)
 	}
 
-	q, err := ch.QueueDeclare(
-		messageQueue, // name
-		true,         // durable
-		false,        // delete when unused
-		false,        // exclusive
-		false,        // no-wait
-		nil,          // arguments
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to declare a queue: %w", err)
+	if cfg.RetryMaxDelay > 0 {
+		retryOpts = append(retryOpts, retry.MaxDelay(cfg.RetryMaxDelay))
 	}
 
-	// Set QoS to ensure fair dispatch among multiple workers
-	err = ch.Qos(
-		workerCount, // prefetch count
-		0,           // prefetch size
-		false,       // global
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to set QoS: %w", err)
-	}
-
-	return &RabbitMQConsumer{
-		conn:        conn,
-		channel:     ch,
-		queue:       q.Name,
+	return &Consumer{
+		source:      source,
 		svc:         svc,
 		retryOpts:   retryOpts,
-		maxAttempts: uint(attempts),
-		workerCount: workerCount,
+		maxAttempts: cfg.RetryAttempts,
+		workerCount: cfg.WorkerCount,
+		retryLater:  cfg.RetryLaterDelay,
 	}, nil
 }
 
-func (c *RabbitMQConsumer) Start(ctx context.Context) error {
-	msgs, err := c.channel.Consume(
-		c.queue,
-		"",
-		false, // manual ack
-		false,
-		false,
-		false,
-		nil,
-	)
+// Start consumes until ctx is canceled or the source closes, then waits for
+// in-flight deliveries to be settled before returning.
+func (c *Consumer) Start(ctx context.Context) error {
+	msgs, err := c.source.Deliveries(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to register a consumer: %w", err)
 	}
 
+	var wg sync.WaitGroup
 	for i := 0; i < c.workerCount; i++ {
-		workerID := i + 1
+		wg.Add(1)
 		go func(id int) {
-			log.Printf("Worker %d starting", id)
-			for d := range msgs {
-				paymentID := string(d.Body)
-				log.Printf("Worker %d processing payment %s", id, paymentID)
-
-				// Create a copy of retry options and add the dynamic ones
-				opts := make([]retry.Option, len(c.retryOpts), len(c.retryOpts)+2)
-				copy(opts, c.retryOpts)
-
-				// Add dynamic options
-				opts = append(opts,
-					retry.RetryIf(IsRetryable),
-					retry.OnRetry(func(n uint, err error) {
-						log.Printf(
-							"Worker %d: retry %d/%d for payment %s failed: %v",
-							id,
-							n+1,
-							c.maxAttempts,
-							paymentID,
-							err,
-						)
-					}),
-				)
-
-				err := retry.Do(
-					func() error {
-						return c.svc.ProcessPayment(ctx, paymentID)
-					},
-					opts...,
-				)
-
-				if err != nil {
-					log.Printf("Worker %d: payment %s failed permanently: %v", id, paymentID, err)
-
-					//Fatal or retries exhausted → send to DLQ
-					_ = d.Nack(false, false)
-					continue
+			defer wg.Done()
+			wlog := slog.Default().With(slog.Int("worker_id", id))
+			wlog.Info("worker starting")
+			for {
+				select {
+				case <-ctx.Done():
+					wlog.Info("worker stopping")
+					return
+				case d, ok := <-msgs:
+					if !ok {
+						wlog.Info("worker stopping")
+						return
+					}
+					c.handle(ctx, wlog, d)
 				}
-
-				//Success
-				_ = d.Ack(false)
-				log.Printf("Worker %d: payment %s processed successfully", id, paymentID)
 			}
-			log.Printf("Worker %d stopping", id)
-		}(workerID)
+		}(i + 1)
 	}
 
-	log.Println(" [*] Waiting for messages")
-	<-ctx.Done()
+	slog.Info("waiting for messages", slog.Int("workers", c.workerCount))
+	wg.Wait()
 	return nil
 }
 
-func (c *RabbitMQConsumer) Close() {
-	c.channel.Close()
-	c.conn.Close()
+// handle processes a single delivery and settles it.
+func (c *Consumer) handle(ctx context.Context, wlog *slog.Logger, d Delivery) {
+	paymentID := string(d.Body())
+	log := wlog.With(slog.String("payment_id", paymentID))
+	msgCtx := logger.NewContext(ctx, log)
+	log.Info("processing payment")
+
+	// Create a copy of retry options and add the dynamic ones
+	opts := make([]retry.Option, len(c.retryOpts), len(c.retryOpts)+3)
+	copy(opts, c.retryOpts)
+
+	// Add dynamic options
+	opts = append(opts,
+		retry.Context(ctx),
+		retry.RetryIf(IsRetryable),
+		retry.OnRetry(func(n uint, err error) {
+			log.Warn("payment processing attempt failed",
+				slog.Uint64("attempt", uint64(n+1)),
+				slog.Uint64("max_attempts", uint64(c.maxAttempts)),
+				slog.Any("error", err),
+			)
+		}),
+	)
+
+	err := retry.Do(
+		func() error {
+			return c.svc.ProcessPayment(msgCtx, paymentID)
+		},
+		opts...,
+	)
+
+	switch {
+	case err == nil:
+		//Success
+		settle(log, "ack", d.Ack())
+		log.Info("payment processed successfully")
+	case ctx.Err() != nil:
+		// Shutting down mid-processing: hand the message back so it is not lost
+		log.Warn("shutdown interrupted processing, requeueing payment", slog.Any("error", err))
+		settle(log, "requeue", d.Requeue())
+	case IsRetryLater(err):
+		// Nothing was attempted; wait before handing the message back so it
+		// is not redelivered in a tight loop
+		log.Warn("payment cannot be processed yet, requeueing", slog.Duration("delay", c.retryLater), slog.Any("error", err))
+		select {
+		case <-time.After(c.retryLater):
+		case <-ctx.Done():
+		}
+		settle(log, "requeue", d.Requeue())
+	default:
+		log.Error("payment failed permanently", slog.Any("error", err))
+
+		//Fatal or retries exhausted → send to DLQ
+		settle(log, "nack", d.Nack())
+	}
+}
+
+func settle(log *slog.Logger, action string, err error) {
+	if err != nil {
+		log.Error("failed to settle delivery", slog.String("action", action), slog.Any("error", err))
+	}
 }
 
 func IsRetryable(err error) bool {
@@ -233,8 +274,14 @@ func IsRetryable(err error) bool {
 		return false
 	}
 
-	if e.Code == 500 {
-		return true
-	}
-	return false
+	// Server-side failures (database errors, serialization conflicts,
+	// unavailable dependencies) may succeed on a later attempt.
+	return e.Code >= 500 && !IsRetryLater(err)
+}
+
+// IsRetryLater reports whether err asks for the message to be retried after a
+// pause rather than immediately, e.g. because every provider's circuit is open.
+func IsRetryLater(err error) bool {
+	e, ok := err.(domain.Error)
+	return ok && e.Type == domain.ErrProviderCircuitOpen
 }
@@ -0,0 +1,192 @@
+package rabbitmq_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	rabbitmq "pgm/internal/queue"
+)
+
+type fakeDelivery struct {
+	body    []byte
+	settled chan string
+}
+
+func (d *fakeDelivery) Body() []byte                    { return d.body }
+func (d *fakeDelivery) Headers() map[string]interface{} { return nil }
+func (d *fakeDelivery) Ack() error                      { d.settled <- "ack"; return nil }
+func (d *fakeDelivery) Nack() error                     { d.settled <- "nack"; return nil }
+func (d *fakeDelivery) Requeue() error                  { d.settled <- "requeue"; return nil }
+
+type fakeSource struct {
+	ch chan rabbitmq.Delivery
+}
+
+func newFakeSource() *fakeSource {
+	return &fakeSource{ch: make(chan rabbitmq.Delivery)}
+}
+
+func (s *fakeSource) Deliveries(ctx context.Context) (<-chan rabbitmq.Delivery, error) {
+	return s.ch, nil
+}
+
+// publish sends a delivery and returns the channel its settlement is reported on.
+func (s *fakeSource) publish(body string) chan string {
+	settled := make(chan string, 1)
+	s.ch <- &fakeDelivery{body: []byte(body), settled: settled}
+	return settled
+}
+
+type fakeService struct {
+	domain.PaymentService
+	process func(ctx context.Context, id string) error
+}
+
+func (s *fakeService) ProcessPayment(ctx context.Context, id string) error {
+	return s.process(ctx, id)
+}
+
+func newTestConsumer(t *testing.T, src rabbitmq.DeliverySource, workers int, process func(ctx context.Context, id string) error) *rabbitmq.Consumer {
+	t.Helper()
+	c, err := rabbitmq.NewConsumer(src, &fakeService{process: process}, rabbitmq.ConsumerConfig{
+		RetryAttempts: 3,
+		RetryDelay:    time.Millisecond,
+		DelayType:     "fixed",
+		WorkerCount:   workers,
+	})
+	assert.NoError(t, err)
+	return c
+}
+
+// run starts c and returns a function that stops it and waits for Start to return.
+func run(c *rabbitmq.Consumer) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		_ = c.Start(ctx)
+		close(done)
+	}()
+	return ctx, func() {
+		cancel()
+		<-done
+	}
+}
+
+func waitSettled(t *testing.T, settled chan string) string {
+	t.Helper()
+	select {
+	case s := <-settled:
+		return s
+	case <-time.After(2 * time.Second):
+		t.Fatal("delivery was not settled")
+		return ""
+	}
+}
+
+func TestConsumer(t *testing.T) {
+	internalErr := domain.NewError(domain.ErrInternal, "db down", "", nil, nil)
+	conflictErr := domain.NewError(domain.ErrPaymentAlreadyProcessed, "already processed", "", nil, nil)
+	circuitOpenErr := domain.NewError(domain.ErrProviderCircuitOpen, "providers disabled", "", nil, nil)
+
+	tests := []struct {
+		name             string
+		failures         int
+		err              error
+		expectedAttempts int32
+		expectedSettle   string
+	}{
+		{name: "success is acked", expectedAttempts: 1, expectedSettle: "ack"},
+		{name: "transient failure is retried then acked", failures: 2, err: internalErr, expectedAttempts: 3, expectedSettle: "ack"},
+		{name: "retry exhaustion is nacked", failures: 10, err: internalErr, expectedAttempts: 3, expectedSettle: "nack"},
+		{name: "non-retryable error is nacked immediately", failures: 10, err: conflictErr, expectedAttempts: 1, expectedSettle: "nack"},
+		{name: "open circuits requeue without retrying", failures: 10, err: circuitOpenErr, expectedAttempts: 1, expectedSettle: "requeue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			src := newFakeSource()
+			c := newTestConsumer(t, src, 1, func(ctx context.Context, id string) error {
+				assert.Equal(t, "payment-1", id)
+				if int(attempts.Add(1)) <= tt.failures {
+					return tt.err
+				}
+				return nil
+			})
+			_, stop := run(c)
+			defer stop()
+
+			settled := src.publish("payment-1")
+			assert.Equal(t, tt.expectedSettle, waitSettled(t, settled))
+			assert.Equal(t, tt.expectedAttempts, attempts.Load())
+		})
+	}
+}
+
+func TestConsumerShutdownMidProcessing(t *testing.T) {
+	src := newFakeSource()
+	started := make(chan struct{})
+	c := newTestConsumer(t, src, 1, func(ctx context.Context, id string) error {
+		close(started)
+		<-ctx.Done()
+		return domain.NewError(domain.ErrInternal, "canceled", "", ctx.Err(), nil)
+	})
+	_, stop := run(c)
+
+	settled := src.publish("payment-1")
+	<-started
+	stop()
+
+	assert.Equal(t, "requeue", waitSettled(t, settled))
+}
+
+func TestConsumerConcurrency(t *testing.T) {
+	const workers = 4
+	var (
+		inFlight atomic.Int32
+		peak     atomic.Int32
+		release  = make(chan struct{})
+	)
+	src := newFakeSource()
+	c := newTestConsumer(t, src, workers, func(ctx context.Context, id string) error {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		inFlight.Add(-1)
+		return nil
+	})
+	_, stop := run(c)
+	defer stop()
+
+	var wg sync.WaitGroup
+	results := make(chan string, workers*2)
+	for i := 0; i < workers*2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			settled := src.publish("payment")
+			results <- waitSettled(t, settled)
+		}()
+	}
+
+	assert.Eventually(t, func() bool { return peak.Load() == workers }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for r := range results {
+		assert.Equal(t, "ack", r)
+	}
+	assert.Equal(t, int32(workers), peak.Load())
+}
@@ -0,0 +1,35 @@
+package rabbitmq
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/streadway/amqp"
+)
+
+// connState tracks whether an AMQP connection and its channel are still usable.
+type connState struct {
+	conn          *amqp.Connection
+	channelClosed atomic.Bool
+}
+
+func newConnState(conn *amqp.Connection, ch *amqp.Channel) *connState {
+	s := &connState{conn: conn}
+	closed := ch.NotifyClose(make(chan *amqp.Error, 1))
+	go func() {
+		<-closed
+		s.channelClosed.Store(true)
+	}()
+	return s
+}
+
+func (s *connState) Check(ctx context.Context) error {
+	if s.conn.IsClosed() {
+		return errors.New("amqp connection is closed")
+	}
+	if s.channelClosed.Load() {
+		return errors.New("amqp channel is closed")
+	}
+	return nil
+}
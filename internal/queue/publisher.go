@@ -2,6 +2,7 @@ package rabbitmq
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 
@@ -10,13 +11,24 @@ import (
 	"github.com/streadway/amqp"
 )
 
-type rabbitMQPublisher struct {
+type RabbitMQPublisher struct {
+	*connState
 	conn    *amqp.Connection
 	channel *amqp.Channel
 	queue   string
+	// events is the queue resource events are published to.
+	events string
 }
 
-func NewRabbitMQPublisher() (domain.MessagePublisher, error) {
+var (
+	_ domain.MessagePublisher = (*RabbitMQPublisher)(nil)
+	_ domain.EventPublisher   = (*RabbitMQPublisher)(nil)
+)
+
+// DefaultEventsQueue is used when EVENTS_QUEUE is unset.
+const DefaultEventsQueue = "payment_gateway.events"
+
+func NewRabbitMQPublisher() (*RabbitMQPublisher, error) {
 	url := os.Getenv("RABBITMQ_URL")
 	conn, err := amqp.Dial(url)
 	if err != nil {
@@ -44,14 +56,25 @@ func NewRabbitMQPublisher() (domain.MessagePublisher, error) {
 		return nil, fmt.Errorf("failed to declare a queue: %w", err)
 	}
 
-	return &rabbitMQPublisher{
-		conn:    conn,
-		channel: ch,
-		queue:   q.Name,
+	eventsName := os.Getenv("EVENTS_QUEUE")
+	if eventsName == "" {
+		eventsName = DefaultEventsQueue
+	}
+	events, err := ch.QueueDeclare(eventsName, true, false, false, false, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to declare the events queue: %w", err)
+	}
+
+	return &RabbitMQPublisher{
+		connState: newConnState(conn, ch),
+		conn:      conn,
+		channel:   ch,
+		queue:     q.Name,
+		events:    events.Name,
 	}, nil
 }
 
-func (p *rabbitMQPublisher) PublishPaymentCreated(ctx context.Context, paymentID string) error {
+func (p *RabbitMQPublisher) PublishPaymentCreated(ctx context.Context, paymentID string) error {
 	err := p.channel.Publish(
 		"",      // exchange
 		p.queue, // routing key
@@ -67,7 +90,33 @@ func (p *rabbitMQPublisher) PublishPaymentCreated(ctx context.Context, paymentID
 	return nil
 }
 
-func (p *rabbitMQPublisher) Close() {
+// PublishEvent publishes event as JSON to the events queue, with its type in
+// the message type so consumers can route without decoding the body.
+func (p *RabbitMQPublisher) PublishEvent(ctx context.Context, event *domain.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	err = p.channel.Publish(
+		"",       // exchange
+		p.events, // routing key
+		false,    // mandatory
+		false,    // immediate
+		amqp.Publishing{
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    event.ID.String(),
+			Type:         event.Type,
+			Timestamp:    event.CreatedAt,
+			Body:         body,
+		})
+	if err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+func (p *RabbitMQPublisher) Close() {
 	p.channel.Close()
 	p.conn.Close()
 }
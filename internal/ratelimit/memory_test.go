@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestLimiter() (*MemoryLimiter, *clock) {
+	c := &clock{t: time.Unix(0, 0)}
+	m := NewMemoryLimiter()
+	m.now = c.now
+	return m, c
+}
+
+// twoPerTwoSeconds refills one token a second.
+var twoPerTwoSeconds = Limit{Requests: 2, Period: 2 * time.Second}
+
+func TestMemoryLimiterExhaustion(t *testing.T) {
+	m, _ := newTestLimiter()
+	ctx := context.Background()
+
+	res, err := m.Allow(ctx, "a", twoPerTwoSeconds)
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, res)
+
+	res, _ = m.Allow(ctx, "a", twoPerTwoSeconds)
+	assert.Equal(t, Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second}, res)
+
+	res, _ = m.Allow(ctx, "a", twoPerTwoSeconds)
+	assert.Equal(t, Result{Allowed: false, Limit: 2, Remaining: 0, Reset: 2 * time.Second, RetryAfter: time.Second}, res)
+
+	// Other keys have buckets of their own
+	res, _ = m.Allow(ctx, "b", twoPerTwoSeconds)
+	assert.True(t, res.Allowed)
+}
+
+func TestMemoryLimiterRefill(t *testing.T) {
+	m, clk := newTestLimiter()
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		res, _ := m.Allow(ctx, "a", twoPerTwoSeconds)
+		assert.True(t, res.Allowed)
+	}
+
+	// Half a token is not enough, and the wait shrinks accordingly
+	clk.advance(500 * time.Millisecond)
+	res, _ := m.Allow(ctx, "a", twoPerTwoSeconds)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 500*time.Millisecond, res.RetryAfter)
+
+	clk.advance(500 * time.Millisecond)
+	res, _ = m.Allow(ctx, "a", twoPerTwoSeconds)
+	assert.True(t, res.Allowed)
+	res, _ = m.Allow(ctx, "a", twoPerTwoSeconds)
+	assert.False(t, res.Allowed)
+
+	// A long idle period refills the bucket only up to its capacity
+	clk.advance(time.Hour)
+	for i := 0; i < 2; i++ {
+		res, _ = m.Allow(ctx, "a", twoPerTwoSeconds)
+		assert.True(t, res.Allowed)
+	}
+	res, _ = m.Allow(ctx, "a", twoPerTwoSeconds)
+	assert.False(t, res.Allowed)
+}
+
+func TestMemoryLimiterSweep(t *testing.T) {
+	m, clk := newTestLimiter()
+	ctx := context.Background()
+
+	_, _ = m.Allow(ctx, "idle", twoPerTwoSeconds)
+	clk.advance(2 * time.Minute)
+	_, _ = m.Allow(ctx, "busy", twoPerTwoSeconds)
+
+	assert.NotContains(t, m.buckets, "idle")
+	assert.Contains(t, m.buckets, "busy")
+}
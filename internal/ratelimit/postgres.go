@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"pgm/internal/repo/db"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PostgresLimiter stores token buckets in Postgres so every API replica
+// draws from the same budget.
+type PostgresLimiter struct {
+	queries db.Querier
+}
+
+func NewPostgresLimiter(q db.Querier) *PostgresLimiter {
+	return &PostgresLimiter{queries: q}
+}
+
+func (p *PostgresLimiter) Allow(ctx context.Context, key string, limit Limit) (Result, error) {
+	row, err := p.queries.TakeRateLimitToken(ctx, db.TakeRateLimitTokenParams{
+		Key:        key,
+		Capacity:   float64(limit.Requests),
+		RefillRate: limit.refillRate(),
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to take rate limit token: %w", err)
+	}
+	return newResult(row.LastAllowed, row.Tokens, limit), nil
+}
+
+// RunCleanup periodically deletes buckets idle for longer than maxIdle until
+// ctx is canceled.
+func (p *PostgresLimiter) RunCleanup(ctx context.Context, interval, maxIdle time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			cutoff := pgtype.Timestamptz{Time: time.Now().Add(-maxIdle), Valid: true}
+			if err := p.queries.DeleteStaleRateLimitBuckets(ctx, cutoff); err != nil {
+				slog.Error("failed to delete stale rate limit buckets", slog.Any("error", err))
+			}
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit describes a token bucket that holds Requests tokens and refills
+// completely over Period.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// Enabled reports whether the limit should be enforced.
+func (l Limit) Enabled() bool {
+	return l.Requests > 0 && l.Period > 0
+}
+
+// refillRate is the number of tokens added per second.
+func (l Limit) refillRate() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+// Result is the outcome of taking a token from a bucket.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration // until the bucket is full again
+	RetryAfter time.Duration // until the next token is available; zero when allowed
+}
+
+// Limiter takes tokens from the bucket identified by key.
+type Limiter interface {
+	Allow(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// newResult derives the response metadata from the tokens left in a bucket.
+func newResult(allowed bool, tokens float64, limit Limit) Result {
+	rate := limit.refillRate()
+	res := Result{
+		Allowed:   allowed,
+		Limit:     limit.Requests,
+		Remaining: int(math.Max(0, math.Floor(tokens))),
+		Reset:     seconds((float64(limit.Requests) - tokens) / rate),
+	}
+	if !allowed {
+		res.RetryAfter = seconds((1 - tokens) / rate)
+	}
+	return res
+}
+
+func seconds(s float64) time.Duration {
+	if s <= 0 {
+		return 0
+	}
+	return time.Duration(s * float64(time.Second))
+}
+
+// ParseLimit parses a limit of the form "<requests>/<period>", e.g. "20/1m".
+// An empty string or "0" disables limiting.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "0" {
+		return Limit{}, nil
+	}
+	reqStr, periodStr, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("invalid rate limit %q: expected <requests>/<period>", s)
+	}
+	requests, err := strconv.Atoi(strings.TrimSpace(reqStr))
+	if err != nil || requests < 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit requests %q", reqStr)
+	}
+	period, err := time.ParseDuration(strings.TrimSpace(periodStr))
+	if err != nil || period <= 0 {
+		return Limit{}, fmt.Errorf("invalid rate limit period %q", periodStr)
+	}
+	return Limit{Requests: requests, Period: period}, nil
+}
+
+// ParseRules parses per-route limits of the form
+// "POST /v1/payments=20/1m;GET /v1/payments/:id=100/1m".
+func ParseRules(s string) (map[string]Limit, error) {
+	rules := make(map[string]Limit)
+	for _, rule := range strings.Split(s, ";") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		route, limitStr, ok := strings.Cut(rule, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid rate limit rule %q: expected <METHOD> <path>=<limit>", rule)
+		}
+		limit, err := ParseLimit(limitStr)
+		if err != nil {
+			return nil, err
+		}
+		rules[strings.Join(strings.Fields(route), " ")] = limit
+	}
+	return rules, nil
+}
@@ -0,0 +1,51 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in          string
+		expected    Limit
+		expectError bool
+	}{
+		{in: "20/1m", expected: Limit{Requests: 20, Period: time.Minute}},
+		{in: " 5 / 1s ", expected: Limit{Requests: 5, Period: time.Second}},
+		{in: "", expected: Limit{}},
+		{in: "0", expected: Limit{}},
+		{in: "20", expectError: true},
+		{in: "x/1m", expectError: true},
+		{in: "-1/1m", expectError: true},
+		{in: "20/forever", expectError: true},
+		{in: "20/0s", expectError: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLimit(tt.in)
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("POST  /v1/payments=20/1m; GET /v1/payments/:id=100/1m;")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]Limit{
+		"POST /v1/payments":    {Requests: 20, Period: time.Minute},
+		"GET /v1/payments/:id": {Requests: 100, Period: time.Minute},
+	}, rules)
+
+	_, err = ParseRules("POST /v1/payments")
+	assert.Error(t, err)
+	_, err = ParseRules("POST /v1/payments=often")
+	assert.Error(t, err)
+}
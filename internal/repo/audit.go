@@ -0,0 +1,142 @@
+package repo
+
+import (
+	"context"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// auditRepo is the Postgres implementation of domain.AuditRepo.
+type auditRepo struct {
+	queries db.Querier
+}
+
+func NewAuditRepo(q db.Querier) domain.AuditRepo {
+	return &auditRepo{queries: q}
+}
+
+func (r *auditRepo) AppendEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	e, err := r.queries.CreateAuditEntry(ctx, db.CreateAuditEntryParams{
+		Actor:      entry.Actor,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		RequestID:  entry.RequestID,
+		SourceIp:   entry.SourceIP,
+		Before:     entry.Before,
+		After:      entry.After,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*entry = *toDomainAuditEntry(e)
+	return nil
+}
+
+func (r *auditRepo) ListEntries(ctx context.Context, filter domain.AuditFilter, page domain.Page) ([]domain.AuditEntry, error) {
+	rows, err := r.queries.ListAuditEntries(ctx, db.ListAuditEntriesParams{
+		Actor:       textOrNull(filter.Actor),
+		Action:      textOrNull(filter.Action),
+		TargetType:  textOrNull(filter.TargetType),
+		TargetID:    textOrNull(filter.TargetID),
+		RequestID:   textOrNull(filter.RequestID),
+		CreatedFrom: timestamptzOrNull(filter.From),
+		CreatedTo:   timestamptzOrNull(filter.To),
+		LimitCount:  int32(page.Limit),
+		OffsetCount: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAuditEntries(rows), nil
+}
+
+func (r *auditRepo) LockChain(ctx context.Context) (bool, error) {
+	locked, err := r.queries.TryLockAuditChain(ctx)
+	if err != nil {
+		return false, translateError(err)
+	}
+	return locked, nil
+}
+
+func (r *auditRepo) GetChainHead(ctx context.Context) (*domain.AuditEntry, error) {
+	e, err := r.queries.GetAuditChainHead(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAuditEntry(e), nil
+}
+
+func (r *auditRepo) ListUnsealedEntries(ctx context.Context, limit int) ([]domain.AuditEntry, error) {
+	rows, err := r.queries.ListUnsealedAuditEntries(ctx, int32(limit))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAuditEntries(rows), nil
+}
+
+func (r *auditRepo) SealEntry(ctx context.Context, id, seq int64, prevHash, hash string) (*domain.AuditEntry, error) {
+	e, err := r.queries.SealAuditEntry(ctx, db.SealAuditEntryParams{
+		ID:       id,
+		Seq:      pgtype.Int8{Int64: seq, Valid: true},
+		PrevHash: textOrNull(prevHash),
+		Hash:     textOrNull(hash),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAuditEntry(e), nil
+}
+
+func (r *auditRepo) ListSealedEntries(ctx context.Context, afterSeq int64, limit int) ([]domain.AuditEntry, error) {
+	rows, err := r.queries.ListSealedAuditEntries(ctx, db.ListSealedAuditEntriesParams{
+		Seq:   pgtype.Int8{Int64: afterSeq, Valid: true},
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainAuditEntries(rows), nil
+}
+
+func (r *auditRepo) CountUnsealedEntries(ctx context.Context) (int64, error) {
+	count, err := r.queries.CountUnsealedAuditEntries(ctx)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return count, nil
+}
+
+func toDomainAuditEntry(e db.AuditLog) *domain.AuditEntry {
+	entry := &domain.AuditEntry{
+		ID:         e.ID,
+		Actor:      e.Actor,
+		Action:     e.Action,
+		TargetType: e.TargetType,
+		TargetID:   e.TargetID,
+		RequestID:  e.RequestID,
+		SourceIP:   e.SourceIp,
+		Before:     e.Before,
+		After:      e.After,
+		CreatedAt:  e.CreatedAt.Time,
+		PrevHash:   e.PrevHash.String,
+		Hash:       e.Hash.String,
+		SealedAt:   timePtr(e.SealedAt),
+	}
+	if e.Seq.Valid {
+		seq := e.Seq.Int64
+		entry.Seq = &seq
+	}
+	return entry
+}
+
+func toDomainAuditEntries(rows []db.AuditLog) []domain.AuditEntry {
+	entries := make([]domain.AuditEntry, 0, len(rows))
+	for _, e := range rows {
+		entries = append(entries, *toDomainAuditEntry(e))
+	}
+	return entries
+}
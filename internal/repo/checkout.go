@@ -0,0 +1,93 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// checkoutSessionRepo is the Postgres implementation of domain.CheckoutSessionRepo.
+type checkoutSessionRepo struct {
+	queries db.Querier
+}
+
+func NewCheckoutSessionRepo(q db.Querier) domain.CheckoutSessionRepo {
+	return &checkoutSessionRepo{queries: q}
+}
+
+func (r *checkoutSessionRepo) CreateCheckoutSession(ctx context.Context, session *domain.CheckoutSession) error {
+	s, err := r.queries.CreateCheckoutSession(ctx, db.CreateCheckoutSessionParams{
+		PaymentID:  session.PaymentID,
+		Token:      session.Token,
+		SuccessURL: session.SuccessURL,
+		CancelURL:  session.CancelURL,
+		ExpiresAt:  pgtype.Timestamptz{Time: session.ExpiresAt, Valid: true},
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*session = *toDomainCheckoutSession(s)
+	return nil
+}
+
+func (r *checkoutSessionRepo) GetCheckoutSessionByID(ctx context.Context, id uuid.UUID) (*domain.CheckoutSession, error) {
+	s, err := r.queries.GetCheckoutSessionByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainCheckoutSession(s), nil
+}
+
+func (r *checkoutSessionRepo) GetCheckoutSessionByToken(ctx context.Context, token string) (*domain.CheckoutSession, error) {
+	s, err := r.queries.GetCheckoutSessionByToken(ctx, token)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainCheckoutSession(s), nil
+}
+
+func (r *checkoutSessionRepo) GetCheckoutSessionByPaymentID(ctx context.Context, paymentID uuid.UUID) (*domain.CheckoutSession, error) {
+	s, err := r.queries.GetCheckoutSessionByPaymentID(ctx, paymentID)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainCheckoutSession(s), nil
+}
+
+func (r *checkoutSessionRepo) CloseCheckoutSession(ctx context.Context, id uuid.UUID, status domain.CheckoutStatus) (*domain.CheckoutSession, error) {
+	s, err := r.queries.CloseCheckoutSession(ctx, db.CloseCheckoutSessionParams{
+		ID:     id,
+		Status: string(status),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainCheckoutSession(s), nil
+}
+
+func (r *checkoutSessionRepo) ClaimExpiredCheckoutSession(ctx context.Context, now time.Time) (*domain.CheckoutSession, error) {
+	s, err := r.queries.ClaimExpiredCheckoutSession(ctx, timestamptz(now))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainCheckoutSession(s), nil
+}
+
+func toDomainCheckoutSession(s db.CheckoutSession) *domain.CheckoutSession {
+	return &domain.CheckoutSession{
+		ID:         s.ID,
+		PaymentID:  s.PaymentID,
+		Token:      s.Token,
+		Status:     domain.CheckoutStatus(s.Status),
+		SuccessURL: s.SuccessURL,
+		CancelURL:  s.CancelURL,
+		ExpiresAt:  s.ExpiresAt.Time,
+		CreatedAt:  s.CreatedAt.Time,
+		UpdatedAt:  s.UpdatedAt.Time,
+	}
+}
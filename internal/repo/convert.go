@@ -0,0 +1,162 @@
+package repo
+
+import (
+	"encoding/json"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// Helpers converting between domain values and nullable sqlc column types.
+
+func textOrNull(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: s != ""}
+}
+
+func uuidOrNull(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
+
+func uuidPtr(id pgtype.UUID) *uuid.UUID {
+	if !id.Valid {
+		return nil
+	}
+	u := uuid.UUID(id.Bytes)
+	return &u
+}
+
+func decimalOrNull(f *float64) decimal.NullDecimal {
+	if f == nil {
+		return decimal.NullDecimal{}
+	}
+	return decimal.NullDecimal{Decimal: decimal.NewFromFloat(*f), Valid: true}
+}
+
+func floatPtr(d decimal.NullDecimal) *float64 {
+	if !d.Valid {
+		return nil
+	}
+	f := d.Decimal.InexactFloat64()
+	return &f
+}
+
+func int4OrNull(i *int) pgtype.Int4 {
+	if i == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: int32(*i), Valid: true}
+}
+
+func intPtr(i pgtype.Int4) *int {
+	if !i.Valid {
+		return nil
+	}
+	n := int(i.Int32)
+	return &n
+}
+
+func timestamptz(t time.Time) pgtype.Timestamptz {
+	return pgtype.Timestamptz{Time: t, Valid: true}
+}
+
+func timestamptzOrNull(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func timePtr(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+// encodeMetadata renders metadata for a JSONB column. Marshalling a string map
+// cannot fail.
+func encodeMetadata(m domain.Metadata) []byte {
+	if m == nil {
+		return []byte("{}")
+	}
+	b, _ := json.Marshal(m)
+	return b
+}
+
+// decodeMetadata reads a JSONB column written by encodeMetadata. Values that
+// are not strings, e.g. from manual edits, are dropped rather than failing the
+// whole read.
+func decodeMetadata(b []byte) domain.Metadata {
+	m := domain.Metadata{}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return m
+	}
+	for k, v := range raw {
+		if s, ok := v.(string); ok {
+			m[k] = s
+		}
+	}
+	return m
+}
+
+func methodTypeOrNull(t domain.PaymentMethodType) db.NullPaymentMethodType {
+	return db.NullPaymentMethodType{PaymentMethodType: db.PaymentMethodType(t), Valid: t != ""}
+}
+
+// encodePaymentMethod splits a payment method into its type column and the
+// JSONB details of that type.
+func encodePaymentMethod(pm *domain.PaymentMethod) (db.NullPaymentMethodType, []byte) {
+	if pm == nil {
+		return db.NullPaymentMethodType{}, nil
+	}
+	var details interface{}
+	switch pm.Type {
+	case domain.MethodCard:
+		details = pm.Card
+	case domain.MethodBankTransfer:
+		details = pm.BankTransfer
+	case domain.MethodMobileMoney:
+		details = pm.MobileMoney
+	case domain.MethodWallet:
+		details = pm.Wallet
+	}
+	b, _ := json.Marshal(details)
+	return db.NullPaymentMethodType{PaymentMethodType: db.PaymentMethodType(pm.Type), Valid: true}, b
+}
+
+// decodePaymentMethod reverses encodePaymentMethod. Details that cannot be
+// decoded are left out so the payment itself still loads.
+func decodePaymentMethod(t db.NullPaymentMethodType, details []byte) *domain.PaymentMethod {
+	if !t.Valid {
+		return nil
+	}
+	pm := &domain.PaymentMethod{Type: domain.PaymentMethodType(t.PaymentMethodType)}
+	var target interface{}
+	switch pm.Type {
+	case domain.MethodCard:
+		pm.Card = &domain.CardDetails{}
+		target = pm.Card
+	case domain.MethodBankTransfer:
+		pm.BankTransfer = &domain.BankTransferDetails{}
+		target = pm.BankTransfer
+	case domain.MethodMobileMoney:
+		pm.MobileMoney = &domain.MobileMoneyDetails{}
+		target = pm.MobileMoney
+	case domain.MethodWallet:
+		pm.Wallet = &domain.WalletDetails{}
+		target = pm.Wallet
+	default:
+		return pm
+	}
+	_ = json.Unmarshal(details, target)
+	return pm
+}
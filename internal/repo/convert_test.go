@@ -0,0 +1,45 @@
+package repo
+
+import (
+	"testing"
+
+	"pgm/internal/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentMethodRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		method *domain.PaymentMethod
+	}{
+		{name: "none", method: nil},
+		{name: "card", method: &domain.PaymentMethod{
+			Type: domain.MethodCard,
+			Card: &domain.CardDetails{Token: "tok_123", Brand: "visa", Last4: "4242", ExpMonth: 12, ExpYear: 2030},
+		}},
+		{name: "bank transfer", method: &domain.PaymentMethod{
+			Type:         domain.MethodBankTransfer,
+			BankTransfer: &domain.BankTransferDetails{BankCode: "CBE", AccountNumber: "1000123456789"},
+		}},
+		{name: "mobile money", method: &domain.PaymentMethod{
+			Type:        domain.MethodMobileMoney,
+			MobileMoney: &domain.MobileMoneyDetails{Provider: "telebirr", MSISDN: "+251911234567"},
+		}},
+		{name: "wallet", method: &domain.PaymentMethod{
+			Type:   domain.MethodWallet,
+			Wallet: &domain.WalletDetails{Provider: "paypal", WalletID: "payer@example.com"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.method, decodePaymentMethod(encodePaymentMethod(tt.method)))
+		})
+	}
+}
+
+func TestDecodeMetadataSkipsNonStrings(t *testing.T) {
+	m := decodeMetadata([]byte(`{"order_id":"1234","items":3}`))
+	assert.Equal(t, domain.Metadata{"order_id": "1234"}, m)
+}
@@ -0,0 +1,108 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// customerRepo is the Postgres implementation of domain.CustomerRepo.
+type customerRepo struct {
+	queries db.Querier
+}
+
+func NewCustomerRepo(q db.Querier) domain.CustomerRepo {
+	return &customerRepo{queries: q}
+}
+
+func (r *customerRepo) CreateCustomer(ctx context.Context, customer *domain.Customer) error {
+	c, err := r.queries.CreateCustomer(ctx, db.CreateCustomerParams{
+		Name:       customer.Name,
+		Email:      textOrNull(customer.Email),
+		Phone:      textOrNull(customer.Phone),
+		ExternalID: textOrNull(customer.ExternalID),
+		MerchantID: customer.MerchantID,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*customer = *toDomainCustomer(c)
+	return nil
+}
+
+func (r *customerRepo) GetCustomerByID(ctx context.Context, merchantID string, id uuid.UUID) (*domain.Customer, error) {
+	c, err := r.queries.GetCustomerByID(ctx, db.GetCustomerByIDParams{ID: id, MerchantID: merchantID})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainCustomer(c), nil
+}
+
+func (r *customerRepo) GetCustomerByExternalID(ctx context.Context, merchantID, externalID string) (*domain.Customer, error) {
+	c, err := r.queries.GetCustomerByExternalID(ctx, db.GetCustomerByExternalIDParams{ExternalID: textOrNull(externalID), MerchantID: merchantID})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainCustomer(c), nil
+}
+
+func (r *customerRepo) ListCustomers(ctx context.Context, merchantID string, page domain.Page) ([]domain.Customer, error) {
+	rows, err := r.queries.ListCustomers(ctx, db.ListCustomersParams{
+		MerchantID: merchantID,
+		Limit:      int32(page.Limit),
+		Offset:     int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	customers := make([]domain.Customer, 0, len(rows))
+	for _, c := range rows {
+		customers = append(customers, *toDomainCustomer(c))
+	}
+	return customers, nil
+}
+
+func (r *customerRepo) UpdateCustomer(ctx context.Context, customer *domain.Customer) error {
+	c, err := r.queries.UpdateCustomer(ctx, db.UpdateCustomerParams{
+		ID:         customer.ID,
+		Name:       customer.Name,
+		Email:      textOrNull(customer.Email),
+		Phone:      textOrNull(customer.Phone),
+		ExternalID: textOrNull(customer.ExternalID),
+		MerchantID: customer.MerchantID,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*customer = *toDomainCustomer(c)
+	return nil
+}
+
+func (r *customerRepo) DeleteCustomer(ctx context.Context, merchantID string, id uuid.UUID) error {
+	n, err := r.queries.DeleteCustomer(ctx, db.DeleteCustomerParams{ID: id, MerchantID: merchantID})
+	if err != nil {
+		return translateError(err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %w", domain.ErrNotFound, pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func toDomainCustomer(c db.Customer) *domain.Customer {
+	return &domain.Customer{
+		ID:         c.ID,
+		Name:       c.Name,
+		Email:      c.Email.String,
+		Phone:      c.Phone.String,
+		ExternalID: c.ExternalID.String,
+		MerchantID: c.MerchantID,
+		CreatedAt:  c.CreatedAt.Time,
+		UpdatedAt:  c.UpdatedAt.Time,
+	}
+}
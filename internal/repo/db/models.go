@@ -16,9 +16,11 @@ import (
 type Paymentstatus string
 
 const (
-	PaymentstatusPENDING Paymentstatus = "PENDING"
-	PaymentstatusSUCCESS Paymentstatus = "SUCCESS"
-	PaymentstatusFAILED  Paymentstatus = "FAILED"
+	PaymentstatusPENDING  Paymentstatus = "PENDING"
+	PaymentstatusSUCCESS  Paymentstatus = "SUCCESS"
+	PaymentstatusFAILED   Paymentstatus = "FAILED"
+	PaymentstatusINREVIEW Paymentstatus = "IN_REVIEW"
+	PaymentstatusCANCELED Paymentstatus = "CANCELED"
 )
 
 func (e *Paymentstatus) Scan(src interface{}) error {
@@ -56,12 +58,338 @@ func (ns NullPaymentstatus) Value() (driver.Value, error) {
 	return string(ns.Paymentstatus), nil
 }
 
+type PaymentMethodType string
+
+const (
+	PaymentMethodTypeCard         PaymentMethodType = "card"
+	PaymentMethodTypeBankTransfer PaymentMethodType = "bank_transfer"
+	PaymentMethodTypeMobileMoney  PaymentMethodType = "mobile_money"
+	PaymentMethodTypeWallet       PaymentMethodType = "wallet"
+)
+
+func (e *PaymentMethodType) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = PaymentMethodType(s)
+	case string:
+		*e = PaymentMethodType(s)
+	default:
+		return fmt.Errorf("unsupported scan type for PaymentMethodType: %T", src)
+	}
+	return nil
+}
+
+type NullPaymentMethodType struct {
+	PaymentMethodType PaymentMethodType `json:"payment_method_type"`
+	Valid             bool              `json:"valid"` // Valid is true if PaymentMethodType is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullPaymentMethodType) Scan(value interface{}) error {
+	if value == nil {
+		ns.PaymentMethodType, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.PaymentMethodType.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullPaymentMethodType) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.PaymentMethodType), nil
+}
+
+type AuditLog struct {
+	ID         int64              `json:"id"`
+	Actor      string             `json:"actor"`
+	Action     string             `json:"action"`
+	TargetType string             `json:"target_type"`
+	TargetID   string             `json:"target_id"`
+	RequestID  string             `json:"request_id"`
+	SourceIp   string             `json:"source_ip"`
+	Before     []byte             `json:"before"`
+	After      []byte             `json:"after"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	Seq        pgtype.Int8        `json:"seq"`
+	PrevHash   pgtype.Text        `json:"prev_hash"`
+	Hash       pgtype.Text        `json:"hash"`
+	SealedAt   pgtype.Timestamptz `json:"sealed_at"`
+}
+
+type CheckoutSession struct {
+	ID         uuid.UUID          `json:"id"`
+	PaymentID  uuid.UUID          `json:"payment_id"`
+	Token      string             `json:"token"`
+	Status     string             `json:"status"`
+	SuccessURL string             `json:"success_url"`
+	CancelURL  string             `json:"cancel_url"`
+	ExpiresAt  pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Customer struct {
+	ID         uuid.UUID          `json:"id"`
+	Name       string             `json:"name"`
+	Email      pgtype.Text        `json:"email"`
+	Phone      pgtype.Text        `json:"phone"`
+	ExternalID pgtype.Text        `json:"external_id"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+	MerchantID string             `json:"merchant_id"`
+}
+
+type Dispute struct {
+	ID                  uuid.UUID          `json:"id"`
+	PaymentID           uuid.UUID          `json:"payment_id"`
+	ProviderReference   pgtype.Text        `json:"provider_reference"`
+	ReasonCode          string             `json:"reason_code"`
+	Amount              decimal.Decimal    `json:"amount"`
+	Currency            string             `json:"currency"`
+	Status              string             `json:"status"`
+	EvidenceDueBy       pgtype.Timestamptz `json:"evidence_due_by"`
+	EvidenceDescription pgtype.Text        `json:"evidence_description"`
+	EvidenceSubmittedAt pgtype.Timestamptz `json:"evidence_submitted_at"`
+	ClosedAt            pgtype.Timestamptz `json:"closed_at"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt           pgtype.Timestamptz `json:"updated_at"`
+}
+
+type DisputeEvidence struct {
+	ID          uuid.UUID          `json:"id"`
+	DisputeID   uuid.UUID          `json:"dispute_id"`
+	FileName    string             `json:"file_name"`
+	ContentType string             `json:"content_type"`
+	Size        int64              `json:"size"`
+	StorageKey  string             `json:"storage_key"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type FeeSchedule struct {
+	ID            uuid.UUID             `json:"id"`
+	Currency      string                `json:"currency"`
+	PaymentMethod NullPaymentMethodType `json:"payment_method"`
+	MerchantID    pgtype.Text           `json:"merchant_id"`
+	Percentage    decimal.Decimal       `json:"percentage"`
+	FixedAmount   decimal.Decimal       `json:"fixed_amount"`
+	MinFee        decimal.NullDecimal   `json:"min_fee"`
+	MaxFee        decimal.NullDecimal   `json:"max_fee"`
+	EffectiveFrom pgtype.Timestamptz    `json:"effective_from"`
+	EffectiveTo   pgtype.Timestamptz    `json:"effective_to"`
+	CreatedAt     pgtype.Timestamptz    `json:"created_at"`
+}
+
+type JournalEntry struct {
+	ID          uuid.UUID          `json:"id"`
+	Kind        string             `json:"kind"`
+	PaymentID   pgtype.UUID        `json:"payment_id"`
+	Description string             `json:"description"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+}
+
+type LedgerAccount struct {
+	ID        uuid.UUID          `json:"id"`
+	Code      string             `json:"code"`
+	Type      string             `json:"type"`
+	Currency  string             `json:"currency"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type LedgerPosting struct {
+	ID             uuid.UUID          `json:"id"`
+	JournalEntryID uuid.UUID          `json:"journal_entry_id"`
+	AccountID      uuid.UUID          `json:"account_id"`
+	Amount         decimal.Decimal    `json:"amount"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
 type Payment struct {
+	ID                   uuid.UUID             `json:"id"`
+	Amount               decimal.Decimal       `json:"amount"`
+	Currency             string                `json:"currency"`
+	Reference            string                `json:"reference"`
+	Status               Paymentstatus         `json:"status"`
+	CreatedAt            pgtype.Timestamptz    `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz    `json:"updated_at"`
+	CustomerID           pgtype.UUID           `json:"customer_id"`
+	Metadata             []byte                `json:"metadata"`
+	PaymentMethod        NullPaymentMethodType `json:"payment_method"`
+	PaymentMethodDetails []byte                `json:"payment_method_details"`
+	Provider             pgtype.Text           `json:"provider"`
+	PaymentLinkID        pgtype.UUID           `json:"payment_link_id"`
+	FeeAmount            decimal.NullDecimal   `json:"fee_amount"`
+	NetAmount            decimal.NullDecimal   `json:"net_amount"`
+	ClientIP             pgtype.Text           `json:"client_ip"`
+	RiskScore            pgtype.Int4           `json:"risk_score"`
+	RiskOutcome          pgtype.Text           `json:"risk_outcome"`
+	RiskRules            []string              `json:"risk_rules"`
+}
+
+type PaymentAttempt struct {
+	ID                uuid.UUID          `json:"id"`
+	PaymentID         uuid.UUID          `json:"payment_id"`
+	Provider          string             `json:"provider"`
+	Status            string             `json:"status"`
+	ProviderReference pgtype.Text        `json:"provider_reference"`
+	Error             pgtype.Text        `json:"error"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+}
+
+type PaymentLink struct {
+	ID          uuid.UUID           `json:"id"`
+	Slug        string              `json:"slug"`
+	Amount      decimal.NullDecimal `json:"amount"`
+	Currency    string              `json:"currency"`
+	Description string              `json:"description"`
+	MaxUses     pgtype.Int4         `json:"max_uses"`
+	Uses        int32               `json:"uses"`
+	ExpiresAt   pgtype.Timestamptz  `json:"expires_at"`
+	Active      bool                `json:"active"`
+	SuccessURL  pgtype.Text         `json:"success_url"`
+	CancelURL   pgtype.Text         `json:"cancel_url"`
+	Metadata    []byte              `json:"metadata"`
+	CreatedAt   pgtype.Timestamptz  `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz  `json:"updated_at"`
+}
+
+type PaymentReview struct {
+	ID          uuid.UUID          `json:"id"`
+	PaymentID   uuid.UUID          `json:"payment_id"`
+	Status      string             `json:"status"`
+	ClaimedBy   pgtype.Text        `json:"claimed_by"`
+	ClaimedAt   pgtype.Timestamptz `json:"claimed_at"`
+	DecidedBy   pgtype.Text        `json:"decided_by"`
+	DecidedAt   pgtype.Timestamptz `json:"decided_at"`
+	Notes       pgtype.Text        `json:"notes"`
+	EscalatedAt pgtype.Timestamptz `json:"escalated_at"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type Plan struct {
+	ID              uuid.UUID          `json:"id"`
+	Name            string             `json:"name"`
+	Amount          decimal.Decimal    `json:"amount"`
+	Currency        string             `json:"currency"`
+	BillingInterval string             `json:"billing_interval"`
+	IntervalCount   int32              `json:"interval_count"`
+	TrialDays       int32              `json:"trial_days"`
+	CreatedAt       pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt       pgtype.Timestamptz `json:"updated_at"`
+}
+
+type RateLimitBucket struct {
+	Key         string             `json:"key"`
+	Tokens      float64            `json:"tokens"`
+	LastAllowed bool               `json:"last_allowed"`
+	UpdatedAt   pgtype.Timestamptz `json:"updated_at"`
+}
+
+type ReconciliationItem struct {
+	ID                uuid.UUID           `json:"id"`
+	RunID             uuid.UUID           `json:"run_id"`
+	Result            string              `json:"result"`
+	Reference         string              `json:"reference"`
+	PaymentID         pgtype.UUID         `json:"payment_id"`
+	StatementAmount   decimal.NullDecimal `json:"statement_amount"`
+	StatementCurrency pgtype.Text         `json:"statement_currency"`
+	BookedAt          pgtype.Timestamptz  `json:"booked_at"`
+	PaymentAmount     decimal.NullDecimal `json:"payment_amount"`
+	PaymentCurrency   pgtype.Text         `json:"payment_currency"`
+	ResolvedAt        pgtype.Timestamptz  `json:"resolved_at"`
+	ResolutionNote    pgtype.Text         `json:"resolution_note"`
+	CreatedAt         pgtype.Timestamptz  `json:"created_at"`
+}
+
+type ReconciliationRun struct {
+	ID                     uuid.UUID          `json:"id"`
+	Format                 string             `json:"format"`
+	Provider               string             `json:"provider"`
+	PeriodStart            pgtype.Timestamptz `json:"period_start"`
+	PeriodEnd              pgtype.Timestamptz `json:"period_end"`
+	LineCount              int32              `json:"line_count"`
+	MatchedCount           int32              `json:"matched_count"`
+	MissingInternallyCount int32              `json:"missing_internally_count"`
+	MissingAtProviderCount int32              `json:"missing_at_provider_count"`
+	AmountMismatchCount    int32              `json:"amount_mismatch_count"`
+	CreatedAt              pgtype.Timestamptz `json:"created_at"`
+}
+
+type RiskBlocklist struct {
 	ID        uuid.UUID          `json:"id"`
+	Kind      string             `json:"kind"`
+	Value     string             `json:"value"`
+	Reason    pgtype.Text        `json:"reason"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type RiskRule struct {
+	ID         uuid.UUID          `json:"id"`
+	Name       string             `json:"name"`
+	Expression string             `json:"expression"`
+	Action     string             `json:"action"`
+	Score      int32              `json:"score"`
+	Enabled    bool               `json:"enabled"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type SettlementAdjustment struct {
+	ID        uuid.UUID          `json:"id"`
+	PaymentID uuid.UUID          `json:"payment_id"`
+	BatchID   uuid.UUID          `json:"batch_id"`
 	Amount    decimal.Decimal    `json:"amount"`
-	Currency  string             `json:"currency"`
-	Reference string             `json:"reference"`
-	Status    Paymentstatus      `json:"status"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+}
+
+type SettlementBatch struct {
+	ID               uuid.UUID          `json:"id"`
+	MerchantID       string             `json:"merchant_id"`
+	Currency         string             `json:"currency"`
+	Status           string             `json:"status"`
+	CutoffAt         pgtype.Timestamptz `json:"cutoff_at"`
+	PaymentCount     int32              `json:"payment_count"`
+	GrossAmount      decimal.Decimal    `json:"gross_amount"`
+	FeeAmount        decimal.Decimal    `json:"fee_amount"`
+	RefundedAmount   decimal.Decimal    `json:"refunded_amount"`
+	NetAmount        decimal.Decimal    `json:"net_amount"`
+	ClosedAt         pgtype.Timestamptz `json:"closed_at"`
+	PaidAt           pgtype.Timestamptz `json:"paid_at"`
+	CreatedAt        pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt        pgtype.Timestamptz `json:"updated_at"`
+	AdjustmentAmount decimal.Decimal    `json:"adjustment_amount"`
+}
+
+type SettlementItem struct {
+	PaymentID      uuid.UUID          `json:"payment_id"`
+	BatchID        uuid.UUID          `json:"batch_id"`
+	Amount         decimal.Decimal    `json:"amount"`
+	FeeAmount      decimal.Decimal    `json:"fee_amount"`
+	RefundedAmount decimal.Decimal    `json:"refunded_amount"`
+	NetAmount      decimal.Decimal    `json:"net_amount"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+}
+
+type Subscription struct {
+	ID                   uuid.UUID          `json:"id"`
+	PlanID               uuid.UUID          `json:"plan_id"`
+	CustomerID           uuid.UUID          `json:"customer_id"`
+	Status               string             `json:"status"`
+	PaymentMethod        PaymentMethodType  `json:"payment_method"`
+	PaymentMethodDetails []byte             `json:"payment_method_details"`
+	BillingAnchor        pgtype.Timestamptz `json:"billing_anchor"`
+	TrialEnd             pgtype.Timestamptz `json:"trial_end"`
+	CurrentPeriodStart   pgtype.Timestamptz `json:"current_period_start"`
+	CurrentPeriodEnd     pgtype.Timestamptz `json:"current_period_end"`
+	NextBillingAt        pgtype.Timestamptz `json:"next_billing_at"`
+	CancelAtPeriodEnd    bool               `json:"cancel_at_period_end"`
+	CanceledAt           pgtype.Timestamptz `json:"canceled_at"`
+	PendingPaymentID     pgtype.UUID        `json:"pending_payment_id"`
+	FailedAttempts       int32              `json:"failed_attempts"`
+	Metadata             []byte             `json:"metadata"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
 }
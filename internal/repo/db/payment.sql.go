@@ -25,19 +25,29 @@ func (q *Queries) CheckExistence(ctx context.Context, reference string) (bool, e
 }
 
 const createPayment = `-- name: CreatePayment :one
-INSERT INTO payments (amount, currency, reference)
-		VALUES ($1, $2, $3)
-		RETURNING id, amount, currency, reference, status, created_at, updated_at
+INSERT INTO payments (amount, currency, reference, customer_id, metadata, payment_method, payment_method_details, payment_link_id, status, client_ip, risk_score, risk_outcome, risk_rules)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules
 `
 
 type CreatePaymentParams struct {
-	Amount    decimal.Decimal `json:"amount"`
-	Currency  string          `json:"currency"`
-	Reference string          `json:"reference"`
+	Amount               decimal.Decimal       `json:"amount"`
+	Currency             string                `json:"currency"`
+	Reference            string                `json:"reference"`
+	CustomerID           pgtype.UUID           `json:"customer_id"`
+	Metadata             []byte                `json:"metadata"`
+	PaymentMethod        NullPaymentMethodType `json:"payment_method"`
+	PaymentMethodDetails []byte                `json:"payment_method_details"`
+	PaymentLinkID        pgtype.UUID           `json:"payment_link_id"`
+	Status               Paymentstatus         `json:"status"`
+	ClientIP             pgtype.Text           `json:"client_ip"`
+	RiskScore            pgtype.Int4           `json:"risk_score"`
+	RiskOutcome          pgtype.Text           `json:"risk_outcome"`
+	RiskRules            []string              `json:"risk_rules"`
 }
 
 func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error) {
-	row := q.db.QueryRow(ctx, createPayment, arg.Amount, arg.Currency, arg.Reference)
+	row := q.db.QueryRow(ctx, createPayment, arg.Amount, arg.Currency, arg.Reference, arg.CustomerID, arg.Metadata, arg.PaymentMethod, arg.PaymentMethodDetails, arg.PaymentLinkID, arg.Status, arg.ClientIP, arg.RiskScore, arg.RiskOutcome, arg.RiskRules)
 	var i Payment
 	err := row.Scan(
 		&i.ID,
@@ -47,12 +57,24 @@ func (q *Queries) CreatePayment(ctx context.Context, arg CreatePaymentParams) (P
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CustomerID,
+		&i.Metadata,
+		&i.PaymentMethod,
+		&i.PaymentMethodDetails,
+		&i.Provider,
+		&i.PaymentLinkID,
+		&i.FeeAmount,
+		&i.NetAmount,
+		&i.ClientIP,
+		&i.RiskScore,
+		&i.RiskOutcome,
+		&i.RiskRules,
 	)
 	return i, err
 }
 
 const getPaymentByID = `-- name: GetPaymentByID :one
-SELECT id, amount, currency, reference, status, created_at, updated_at FROM payments WHERE id = $1
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments WHERE id = $1
 `
 
 func (q *Queries) GetPaymentByID(ctx context.Context, id uuid.UUID) (Payment, error) {
@@ -66,12 +88,24 @@ func (q *Queries) GetPaymentByID(ctx context.Context, id uuid.UUID) (Payment, er
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CustomerID,
+		&i.Metadata,
+		&i.PaymentMethod,
+		&i.PaymentMethodDetails,
+		&i.Provider,
+		&i.PaymentLinkID,
+		&i.FeeAmount,
+		&i.NetAmount,
+		&i.ClientIP,
+		&i.RiskScore,
+		&i.RiskOutcome,
+		&i.RiskRules,
 	)
 	return i, err
 }
 
 const getPaymentByIDWithLock = `-- name: GetPaymentByIDWithLock :one
-SELECT id, amount, currency, reference, status, created_at, updated_at FROM payments WHERE id = $1 FOR UPDATE
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments WHERE id = $1 FOR NO KEY UPDATE
 `
 
 func (q *Queries) GetPaymentByIDWithLock(ctx context.Context, id uuid.UUID) (Payment, error) {
@@ -85,12 +119,24 @@ func (q *Queries) GetPaymentByIDWithLock(ctx context.Context, id uuid.UUID) (Pay
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CustomerID,
+		&i.Metadata,
+		&i.PaymentMethod,
+		&i.PaymentMethodDetails,
+		&i.Provider,
+		&i.PaymentLinkID,
+		&i.FeeAmount,
+		&i.NetAmount,
+		&i.ClientIP,
+		&i.RiskScore,
+		&i.RiskOutcome,
+		&i.RiskRules,
 	)
 	return i, err
 }
 
 const getPaymentByReference = `-- name: GetPaymentByReference :one
-SELECT id, amount, currency, reference, status, created_at, updated_at FROM payments WHERE reference = $1
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments WHERE reference = $1
 `
 
 func (q *Queries) GetPaymentByReference(ctx context.Context, reference string) (Payment, error) {
@@ -104,12 +150,131 @@ func (q *Queries) GetPaymentByReference(ctx context.Context, reference string) (
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CustomerID,
+		&i.Metadata,
+		&i.PaymentMethod,
+		&i.PaymentMethodDetails,
+		&i.Provider,
+		&i.PaymentLinkID,
+		&i.FeeAmount,
+		&i.NetAmount,
+		&i.ClientIP,
+		&i.RiskScore,
+		&i.RiskOutcome,
+		&i.RiskRules,
 	)
 	return i, err
 }
 
+const listPayments = `-- name: ListPayments :many
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments
+		WHERE metadata->>'merchant_id' = $1 AND metadata @> $2
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+`
+
+type ListPaymentsParams struct {
+	MerchantID string `json:"merchant_id"`
+	Metadata   []byte `json:"metadata"`
+	Limit      int32  `json:"limit"`
+	Offset     int32  `json:"offset"`
+}
+
+func (q *Queries) ListPayments(ctx context.Context, arg ListPaymentsParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPayments, arg.MerchantID, arg.Metadata, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.Currency,
+			&i.Reference,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CustomerID,
+			&i.Metadata,
+			&i.PaymentMethod,
+			&i.PaymentMethodDetails,
+			&i.Provider,
+			&i.PaymentLinkID,
+			&i.FeeAmount,
+			&i.NetAmount,
+			&i.ClientIP,
+			&i.RiskScore,
+			&i.RiskOutcome,
+			&i.RiskRules,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPaymentsByCustomer = `-- name: ListPaymentsByCustomer :many
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments
+		WHERE customer_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+`
+
+type ListPaymentsByCustomerParams struct {
+	CustomerID pgtype.UUID `json:"customer_id"`
+	Limit      int32       `json:"limit"`
+	Offset     int32       `json:"offset"`
+}
+
+func (q *Queries) ListPaymentsByCustomer(ctx context.Context, arg ListPaymentsByCustomerParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPaymentsByCustomer, arg.CustomerID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.Currency,
+			&i.Reference,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CustomerID,
+			&i.Metadata,
+			&i.PaymentMethod,
+			&i.PaymentMethodDetails,
+			&i.Provider,
+			&i.PaymentLinkID,
+			&i.FeeAmount,
+			&i.NetAmount,
+			&i.ClientIP,
+			&i.RiskScore,
+			&i.RiskOutcome,
+			&i.RiskRules,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const updatePaymentStatus = `-- name: UpdatePaymentStatus :one
-UPDATE payments SET status = $1, updated_at = $2 WHERE id = $3 RETURNING id, amount, currency, reference, status, created_at, updated_at
+UPDATE payments SET status = $1, updated_at = $2 WHERE id = $3 RETURNING id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules
 `
 
 type UpdatePaymentStatusParams struct {
@@ -129,6 +294,246 @@ func (q *Queries) UpdatePaymentStatus(ctx context.Context, arg UpdatePaymentStat
 		&i.Status,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.CustomerID,
+		&i.Metadata,
+		&i.PaymentMethod,
+		&i.PaymentMethodDetails,
+		&i.Provider,
+		&i.PaymentLinkID,
+		&i.FeeAmount,
+		&i.NetAmount,
+		&i.ClientIP,
+		&i.RiskScore,
+		&i.RiskOutcome,
+		&i.RiskRules,
+	)
+	return i, err
+}
+
+const updatePaymentResult = `-- name: UpdatePaymentResult :one
+UPDATE payments SET status = $1, provider = $2, updated_at = $3 WHERE id = $4 RETURNING id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules
+`
+
+type UpdatePaymentResultParams struct {
+	Status    Paymentstatus      `json:"status"`
+	Provider  pgtype.Text        `json:"provider"`
+	UpdatedAt pgtype.Timestamptz `json:"updated_at"`
+	ID        uuid.UUID          `json:"id"`
+}
+
+func (q *Queries) UpdatePaymentResult(ctx context.Context, arg UpdatePaymentResultParams) (Payment, error) {
+	row := q.db.QueryRow(ctx, updatePaymentResult, arg.Status, arg.Provider, arg.UpdatedAt, arg.ID)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.Amount,
+		&i.Currency,
+		&i.Reference,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CustomerID,
+		&i.Metadata,
+		&i.PaymentMethod,
+		&i.PaymentMethodDetails,
+		&i.Provider,
+		&i.PaymentLinkID,
+		&i.FeeAmount,
+		&i.NetAmount,
+		&i.ClientIP,
+		&i.RiskScore,
+		&i.RiskOutcome,
+		&i.RiskRules,
+	)
+	return i, err
+}
+
+const setPaymentFee = `-- name: SetPaymentFee :one
+UPDATE payments SET fee_amount = $2, net_amount = amount - $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules
+`
+
+type SetPaymentFeeParams struct {
+	ID        uuid.UUID           `json:"id"`
+	FeeAmount decimal.NullDecimal `json:"fee_amount"`
+}
+
+func (q *Queries) SetPaymentFee(ctx context.Context, arg SetPaymentFeeParams) (Payment, error) {
+	row := q.db.QueryRow(ctx, setPaymentFee, arg.ID, arg.FeeAmount)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.Amount,
+		&i.Currency,
+		&i.Reference,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CustomerID,
+		&i.Metadata,
+		&i.PaymentMethod,
+		&i.PaymentMethodDetails,
+		&i.Provider,
+		&i.PaymentLinkID,
+		&i.FeeAmount,
+		&i.NetAmount,
+		&i.ClientIP,
+		&i.RiskScore,
+		&i.RiskOutcome,
+		&i.RiskRules,
 	)
 	return i, err
 }
+
+const setPaymentMethod = `-- name: SetPaymentMethod :one
+UPDATE payments SET payment_method = $2, payment_method_details = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status IN ('PENDING', 'IN_REVIEW')
+		RETURNING id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules
+`
+
+type SetPaymentMethodParams struct {
+	ID                   uuid.UUID             `json:"id"`
+	PaymentMethod        NullPaymentMethodType `json:"payment_method"`
+	PaymentMethodDetails []byte                `json:"payment_method_details"`
+}
+
+func (q *Queries) SetPaymentMethod(ctx context.Context, arg SetPaymentMethodParams) (Payment, error) {
+	row := q.db.QueryRow(ctx, setPaymentMethod, arg.ID, arg.PaymentMethod, arg.PaymentMethodDetails)
+	var i Payment
+	err := row.Scan(
+		&i.ID,
+		&i.Amount,
+		&i.Currency,
+		&i.Reference,
+		&i.Status,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CustomerID,
+		&i.Metadata,
+		&i.PaymentMethod,
+		&i.PaymentMethodDetails,
+		&i.Provider,
+		&i.PaymentLinkID,
+		&i.FeeAmount,
+		&i.NetAmount,
+		&i.ClientIP,
+		&i.RiskScore,
+		&i.RiskOutcome,
+		&i.RiskRules,
+	)
+	return i, err
+}
+
+const listPaymentsByLink = `-- name: ListPaymentsByLink :many
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments
+		WHERE payment_link_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+`
+
+type ListPaymentsByLinkParams struct {
+	PaymentLinkID pgtype.UUID `json:"payment_link_id"`
+	Limit         int32       `json:"limit"`
+	Offset        int32       `json:"offset"`
+}
+
+func (q *Queries) ListPaymentsByLink(ctx context.Context, arg ListPaymentsByLinkParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPaymentsByLink, arg.PaymentLinkID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.Currency,
+			&i.Reference,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CustomerID,
+			&i.Metadata,
+			&i.PaymentMethod,
+			&i.PaymentMethodDetails,
+			&i.Provider,
+			&i.PaymentLinkID,
+			&i.FeeAmount,
+			&i.NetAmount,
+			&i.ClientIP,
+			&i.RiskScore,
+			&i.RiskOutcome,
+			&i.RiskRules,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingPayments = `-- name: ListPendingPayments :many
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments
+		WHERE status = 'PENDING'
+			AND metadata @> $1
+			AND ($2::TEXT IS NULL OR currency = $2)
+			AND ($3::TIMESTAMPTZ IS NULL OR created_at >= $3)
+			AND ($4::TIMESTAMPTZ IS NULL OR created_at < $4)
+			AND NOT EXISTS (SELECT 1 FROM checkout_sessions cs WHERE cs.payment_id = payments.id AND cs.status <> 'confirmed')
+		ORDER BY created_at, id
+		LIMIT $5
+`
+
+type ListPendingPaymentsParams struct {
+	Metadata    []byte             `json:"metadata"`
+	Currency    pgtype.Text        `json:"currency"`
+	CreatedFrom pgtype.Timestamptz `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz `json:"created_to"`
+	LimitCount  int32              `json:"limit_count"`
+}
+
+func (q *Queries) ListPendingPayments(ctx context.Context, arg ListPendingPaymentsParams) ([]Payment, error) {
+	rows, err := q.db.Query(ctx, listPendingPayments, arg.Metadata, arg.Currency, arg.CreatedFrom, arg.CreatedTo, arg.LimitCount)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Payment
+	for rows.Next() {
+		var i Payment
+		if err := rows.Scan(
+			&i.ID,
+			&i.Amount,
+			&i.Currency,
+			&i.Reference,
+			&i.Status,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CustomerID,
+			&i.Metadata,
+			&i.PaymentMethod,
+			&i.PaymentMethodDetails,
+			&i.Provider,
+			&i.PaymentLinkID,
+			&i.FeeAmount,
+			&i.NetAmount,
+			&i.ClientIP,
+			&i.RiskScore,
+			&i.RiskOutcome,
+			&i.RiskRules,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -8,15 +8,134 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
 )
 
 type Querier interface {
+	AddAdjustmentToSettlementBatch(ctx context.Context, arg AddAdjustmentToSettlementBatchParams) (SettlementBatch, error)
+	AddToSettlementBatch(ctx context.Context, arg AddToSettlementBatchParams) (SettlementBatch, error)
 	CheckExistence(ctx context.Context, reference string) (bool, error)
+	ClaimDueSubscription(ctx context.Context, nextBillingAt pgtype.Timestamptz) (Subscription, error)
+	ClaimExpiredCheckoutSession(ctx context.Context, expiresAt pgtype.Timestamptz) (CheckoutSession, error)
+	ClaimPaymentReview(ctx context.Context, arg ClaimPaymentReviewParams) (PaymentReview, error)
+	ClaimSettledSubscription(ctx context.Context) (ClaimSettledSubscriptionRow, error)
+	ClaimUnsettledPayment(ctx context.Context) (ClaimUnsettledPaymentRow, error)
+	ClaimUnsettledRefund(ctx context.Context) (ClaimUnsettledRefundRow, error)
+	CloseCheckoutSession(ctx context.Context, arg CloseCheckoutSessionParams) (CheckoutSession, error)
+	CloseDispute(ctx context.Context, arg CloseDisputeParams) (Dispute, error)
+	CloseDueSettlementBatches(ctx context.Context, cutoffAt pgtype.Timestamptz) ([]SettlementBatch, error)
+	CloseSettlementBatch(ctx context.Context, id uuid.UUID) (SettlementBatch, error)
+	CountPaymentsByClientIPSince(ctx context.Context, arg CountPaymentsByClientIPSinceParams) (int64, error)
+	CountPaymentsByCustomerSince(ctx context.Context, arg CountPaymentsByCustomerSinceParams) (int64, error)
+	CountPaymentsByReferencePrefixSince(ctx context.Context, arg CountPaymentsByReferencePrefixSinceParams) (int64, error)
+	CountUnsealedAuditEntries(ctx context.Context) (int64, error)
+	CreateAuditEntry(ctx context.Context, arg CreateAuditEntryParams) (AuditLog, error)
+	CreateCheckoutSession(ctx context.Context, arg CreateCheckoutSessionParams) (CheckoutSession, error)
+	CreateCustomer(ctx context.Context, arg CreateCustomerParams) (Customer, error)
+	CreateDispute(ctx context.Context, arg CreateDisputeParams) (Dispute, error)
+	CreateDisputeEvidence(ctx context.Context, arg CreateDisputeEvidenceParams) (DisputeEvidence, error)
+	CreateFeeSchedule(ctx context.Context, arg CreateFeeScheduleParams) (FeeSchedule, error)
+	CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (JournalEntry, error)
+	CreateLedgerPosting(ctx context.Context, arg CreateLedgerPostingParams) (LedgerPosting, error)
 	CreatePayment(ctx context.Context, arg CreatePaymentParams) (Payment, error)
+	CreatePaymentAttempt(ctx context.Context, arg CreatePaymentAttemptParams) (PaymentAttempt, error)
+	CreatePaymentLink(ctx context.Context, arg CreatePaymentLinkParams) (PaymentLink, error)
+	CreatePaymentReview(ctx context.Context, paymentID uuid.UUID) (PaymentReview, error)
+	CreatePlan(ctx context.Context, arg CreatePlanParams) (Plan, error)
+	CreateReconciliationItem(ctx context.Context, arg CreateReconciliationItemParams) (ReconciliationItem, error)
+	CreateReconciliationRun(ctx context.Context, arg CreateReconciliationRunParams) (ReconciliationRun, error)
+	CreateRiskBlocklistEntry(ctx context.Context, arg CreateRiskBlocklistEntryParams) (RiskBlocklist, error)
+	CreateRiskRule(ctx context.Context, arg CreateRiskRuleParams) (RiskRule, error)
+	CreateSettlementAdjustment(ctx context.Context, arg CreateSettlementAdjustmentParams) (SettlementAdjustment, error)
+	CreateSettlementItem(ctx context.Context, arg CreateSettlementItemParams) (SettlementItem, error)
+	CreateSubscription(ctx context.Context, arg CreateSubscriptionParams) (Subscription, error)
+	DecidePaymentReview(ctx context.Context, arg DecidePaymentReviewParams) (PaymentReview, error)
+	DeleteCustomer(ctx context.Context, arg DeleteCustomerParams) (int64, error)
+	DeleteRiskBlocklistEntry(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteRiskRule(ctx context.Context, id uuid.UUID) (int64, error)
+	DeleteStaleRateLimitBuckets(ctx context.Context, updatedAt pgtype.Timestamptz) error
+	EnsureLedgerAccount(ctx context.Context, arg EnsureLedgerAccountParams) (LedgerAccount, error)
+	EscalateOverduePaymentReviews(ctx context.Context, createdAt pgtype.Timestamptz) ([]PaymentReview, error)
+	FindFeeSchedule(ctx context.Context, arg FindFeeScheduleParams) (FeeSchedule, error)
+	FindPaymentForStatementLine(ctx context.Context, reference string) (FindPaymentForStatementLineRow, error)
+	GetAuditChainHead(ctx context.Context) (AuditLog, error)
+	GetCheckoutSessionByID(ctx context.Context, id uuid.UUID) (CheckoutSession, error)
+	GetCheckoutSessionByPaymentID(ctx context.Context, paymentID uuid.UUID) (CheckoutSession, error)
+	GetCheckoutSessionByToken(ctx context.Context, token string) (CheckoutSession, error)
+	GetCustomerByExternalID(ctx context.Context, arg GetCustomerByExternalIDParams) (Customer, error)
+	GetCustomerByID(ctx context.Context, arg GetCustomerByIDParams) (Customer, error)
+	GetDisputeByID(ctx context.Context, id uuid.UUID) (Dispute, error)
+	GetDisputeByIDForUpdate(ctx context.Context, id uuid.UUID) (Dispute, error)
+	GetDisputeEvidenceByID(ctx context.Context, arg GetDisputeEvidenceByIDParams) (DisputeEvidence, error)
+	GetDisputedAmount(ctx context.Context, paymentID uuid.UUID) (decimal.Decimal, error)
+	GetFeeScheduleByID(ctx context.Context, id uuid.UUID) (FeeSchedule, error)
 	GetPaymentByID(ctx context.Context, id uuid.UUID) (Payment, error)
 	GetPaymentByIDWithLock(ctx context.Context, id uuid.UUID) (Payment, error)
 	GetPaymentByReference(ctx context.Context, reference string) (Payment, error)
+	GetPaymentLinkByID(ctx context.Context, id uuid.UUID) (PaymentLink, error)
+	GetPaymentLinkBySlug(ctx context.Context, slug string) (PaymentLink, error)
+	GetPaymentLinkTotals(ctx context.Context, paymentLinkID pgtype.UUID) (GetPaymentLinkTotalsRow, error)
+	GetPaymentReviewByID(ctx context.Context, id uuid.UUID) (PaymentReview, error)
+	GetPaymentReviewByIDForUpdate(ctx context.Context, id uuid.UUID) (PaymentReview, error)
+	GetPlanByID(ctx context.Context, id uuid.UUID) (Plan, error)
+	GetReconciliationItemByID(ctx context.Context, id uuid.UUID) (ReconciliationItem, error)
+	GetReconciliationRunByID(ctx context.Context, id uuid.UUID) (ReconciliationRun, error)
+	GetRiskRuleByID(ctx context.Context, id uuid.UUID) (RiskRule, error)
+	GetSettlementBatchByID(ctx context.Context, id uuid.UUID) (SettlementBatch, error)
+	GetSettlementBatchByIDForUpdate(ctx context.Context, id uuid.UUID) (SettlementBatch, error)
+	GetSubscriptionByID(ctx context.Context, id uuid.UUID) (Subscription, error)
+	GetSubscriptionByIDForUpdate(ctx context.Context, id uuid.UUID) (Subscription, error)
+	IsBlocklisted(ctx context.Context, arg IsBlocklistedParams) (bool, error)
+	ListAuditEntries(ctx context.Context, arg ListAuditEntriesParams) ([]AuditLog, error)
+	ListCustomers(ctx context.Context, arg ListCustomersParams) ([]Customer, error)
+	ListDisputeEvidence(ctx context.Context, disputeID uuid.UUID) ([]DisputeEvidence, error)
+	ListDisputes(ctx context.Context, arg ListDisputesParams) ([]Dispute, error)
+	ListEnabledRiskRules(ctx context.Context) ([]RiskRule, error)
+	ListFeeSchedules(ctx context.Context, arg ListFeeSchedulesParams) ([]FeeSchedule, error)
+	ListJournalEntriesByPayment(ctx context.Context, paymentID pgtype.UUID) ([]JournalEntry, error)
+	ListLedgerBalances(ctx context.Context) ([]ListLedgerBalancesRow, error)
+	ListLedgerPostingsByPayment(ctx context.Context, paymentID pgtype.UUID) ([]ListLedgerPostingsByPaymentRow, error)
+	ListPaymentAttempts(ctx context.Context, paymentID uuid.UUID) ([]PaymentAttempt, error)
+	ListPaymentLinks(ctx context.Context, arg ListPaymentLinksParams) ([]PaymentLink, error)
+	ListPaymentReviews(ctx context.Context, arg ListPaymentReviewsParams) ([]PaymentReview, error)
+	ListPayments(ctx context.Context, arg ListPaymentsParams) ([]Payment, error)
+	ListPaymentsByCustomer(ctx context.Context, arg ListPaymentsByCustomerParams) ([]Payment, error)
+	ListPaymentsByLink(ctx context.Context, arg ListPaymentsByLinkParams) ([]Payment, error)
+	ListPaymentsMissingCapture(ctx context.Context, limit int32) ([]uuid.UUID, error)
+	ListPendingPayments(ctx context.Context, arg ListPendingPaymentsParams) ([]Payment, error)
+	ListPlans(ctx context.Context, arg ListPlansParams) ([]Plan, error)
+	ListReconciliationItems(ctx context.Context, arg ListReconciliationItemsParams) ([]ReconciliationItem, error)
+	ListReconciliationRuns(ctx context.Context, arg ListReconciliationRunsParams) ([]ReconciliationRun, error)
+	ListRiskBlocklistEntries(ctx context.Context, arg ListRiskBlocklistEntriesParams) ([]RiskBlocklist, error)
+	ListRiskRules(ctx context.Context, arg ListRiskRulesParams) ([]RiskRule, error)
+	ListSealedAuditEntries(ctx context.Context, arg ListSealedAuditEntriesParams) ([]AuditLog, error)
+	ListSettlementAdjustments(ctx context.Context, arg ListSettlementAdjustmentsParams) ([]SettlementAdjustment, error)
+	ListSettlementBatches(ctx context.Context, arg ListSettlementBatchesParams) ([]SettlementBatch, error)
+	ListSettlementItems(ctx context.Context, arg ListSettlementItemsParams) ([]SettlementItem, error)
+	ListSubscriptions(ctx context.Context, arg ListSubscriptionsParams) ([]Subscription, error)
+	ListSuccessfulPaymentsInPeriod(ctx context.Context, arg ListSuccessfulPaymentsInPeriodParams) ([]ListSuccessfulPaymentsInPeriodRow, error)
+	ListUnbalancedJournalEntries(ctx context.Context, limit int32) ([]ListUnbalancedJournalEntriesRow, error)
+	ListUnresolvedReconciliationItems(ctx context.Context, arg ListUnresolvedReconciliationItemsParams) ([]ReconciliationItem, error)
+	ListUnsealedAuditEntries(ctx context.Context, limit int32) ([]AuditLog, error)
+	LockOverduePaymentReview(ctx context.Context, createdAt pgtype.Timestamptz) (PaymentReview, error)
+	MarkSettlementBatchPaid(ctx context.Context, id uuid.UUID) (SettlementBatch, error)
+	OpenSettlementBatch(ctx context.Context, arg OpenSettlementBatchParams) (SettlementBatch, error)
+	ReleasePaymentLink(ctx context.Context, id uuid.UUID) (PaymentLink, error)
+	ResolveReconciliationItem(ctx context.Context, arg ResolveReconciliationItemParams) (ReconciliationItem, error)
+	SealAuditEntry(ctx context.Context, arg SealAuditEntryParams) (AuditLog, error)
+	SetPaymentFee(ctx context.Context, arg SetPaymentFeeParams) (Payment, error)
+	SetPaymentLinkActive(ctx context.Context, arg SetPaymentLinkActiveParams) (PaymentLink, error)
+	SetPaymentMethod(ctx context.Context, arg SetPaymentMethodParams) (Payment, error)
+	SubmitDisputeEvidence(ctx context.Context, arg SubmitDisputeEvidenceParams) (Dispute, error)
+	TakeRateLimitToken(ctx context.Context, arg TakeRateLimitTokenParams) (TakeRateLimitTokenRow, error)
+	TryLockAuditChain(ctx context.Context) (bool, error)
+	UpdateCustomer(ctx context.Context, arg UpdateCustomerParams) (Customer, error)
+	UpdatePaymentResult(ctx context.Context, arg UpdatePaymentResultParams) (Payment, error)
 	UpdatePaymentStatus(ctx context.Context, arg UpdatePaymentStatusParams) (Payment, error)
+	UpdateRiskRule(ctx context.Context, arg UpdateRiskRuleParams) (RiskRule, error)
+	UpdateSubscription(ctx context.Context, arg UpdateSubscriptionParams) (Subscription, error)
+	UsePaymentLink(ctx context.Context, id uuid.UUID) (PaymentLink, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,175 @@
+package repo
+
+import (
+	"context"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// disputeRepo is the Postgres implementation of domain.DisputeRepo.
+type disputeRepo struct {
+	queries db.Querier
+}
+
+func NewDisputeRepo(q db.Querier) domain.DisputeRepo {
+	return &disputeRepo{queries: q}
+}
+
+func (r *disputeRepo) CreateDispute(ctx context.Context, dispute *domain.Dispute) error {
+	d, err := r.queries.CreateDispute(ctx, db.CreateDisputeParams{
+		PaymentID:         dispute.PaymentID,
+		ProviderReference: textOrNull(dispute.ProviderReference),
+		ReasonCode:        dispute.ReasonCode,
+		Amount:            decimal.NewFromFloat(dispute.Amount),
+		Currency:          dispute.Currency,
+		EvidenceDueBy:     timestamptz(dispute.EvidenceDueBy),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*dispute = *toDomainDispute(d)
+	return nil
+}
+
+func (r *disputeRepo) GetDisputeByID(ctx context.Context, id uuid.UUID) (*domain.Dispute, error) {
+	d, err := r.queries.GetDisputeByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainDispute(d), nil
+}
+
+func (r *disputeRepo) GetDisputeByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Dispute, error) {
+	d, err := r.queries.GetDisputeByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainDispute(d), nil
+}
+
+func (r *disputeRepo) DisputedAmount(ctx context.Context, paymentID uuid.UUID) (float64, error) {
+	amount, err := r.queries.GetDisputedAmount(ctx, paymentID)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return amount.InexactFloat64(), nil
+}
+
+func (r *disputeRepo) ListDisputes(ctx context.Context, filter domain.DisputeFilter, page domain.Page) ([]domain.Dispute, error) {
+	params := db.ListDisputesParams{
+		PaymentID:   uuidOrNull(filter.PaymentID),
+		LimitCount:  int32(page.Limit),
+		OffsetCount: int32(page.Offset),
+	}
+	if filter.Status != nil {
+		params.Status = pgtype.Text{String: string(*filter.Status), Valid: true}
+	}
+	if filter.MerchantID != nil {
+		params.MerchantID = pgtype.Text{String: *filter.MerchantID, Valid: true}
+	}
+	rows, err := r.queries.ListDisputes(ctx, params)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	disputes := make([]domain.Dispute, 0, len(rows))
+	for _, d := range rows {
+		disputes = append(disputes, *toDomainDispute(d))
+	}
+	return disputes, nil
+}
+
+func (r *disputeRepo) SubmitEvidence(ctx context.Context, id uuid.UUID, description string) (*domain.Dispute, error) {
+	d, err := r.queries.SubmitDisputeEvidence(ctx, db.SubmitDisputeEvidenceParams{
+		ID:                  id,
+		EvidenceDescription: textOrNull(description),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainDispute(d), nil
+}
+
+func (r *disputeRepo) CloseDispute(ctx context.Context, id uuid.UUID, outcome domain.DisputeStatus) (*domain.Dispute, error) {
+	d, err := r.queries.CloseDispute(ctx, db.CloseDisputeParams{
+		ID:     id,
+		Status: string(outcome),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainDispute(d), nil
+}
+
+func (r *disputeRepo) CreateEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	e, err := r.queries.CreateDisputeEvidence(ctx, db.CreateDisputeEvidenceParams{
+		DisputeID:   evidence.DisputeID,
+		FileName:    evidence.FileName,
+		ContentType: evidence.ContentType,
+		Size:        evidence.Size,
+		StorageKey:  evidence.StorageKey,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*evidence = toDomainDisputeEvidence(e)
+	return nil
+}
+
+func (r *disputeRepo) ListEvidence(ctx context.Context, disputeID uuid.UUID) ([]domain.DisputeEvidence, error) {
+	rows, err := r.queries.ListDisputeEvidence(ctx, disputeID)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	evidence := make([]domain.DisputeEvidence, 0, len(rows))
+	for _, e := range rows {
+		evidence = append(evidence, toDomainDisputeEvidence(e))
+	}
+	return evidence, nil
+}
+
+func (r *disputeRepo) GetEvidenceByID(ctx context.Context, disputeID, id uuid.UUID) (*domain.DisputeEvidence, error) {
+	e, err := r.queries.GetDisputeEvidenceByID(ctx, db.GetDisputeEvidenceByIDParams{
+		ID:        id,
+		DisputeID: disputeID,
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	evidence := toDomainDisputeEvidence(e)
+	return &evidence, nil
+}
+
+func toDomainDispute(d db.Dispute) *domain.Dispute {
+	return &domain.Dispute{
+		ID:                  d.ID,
+		PaymentID:           d.PaymentID,
+		ProviderReference:   d.ProviderReference.String,
+		ReasonCode:          d.ReasonCode,
+		Amount:              d.Amount.InexactFloat64(),
+		Currency:            d.Currency,
+		Status:              domain.DisputeStatus(d.Status),
+		EvidenceDueBy:       d.EvidenceDueBy.Time,
+		EvidenceDescription: d.EvidenceDescription.String,
+		EvidenceSubmittedAt: timePtr(d.EvidenceSubmittedAt),
+		ClosedAt:            timePtr(d.ClosedAt),
+		CreatedAt:           d.CreatedAt.Time,
+		UpdatedAt:           d.UpdatedAt.Time,
+	}
+}
+
+func toDomainDisputeEvidence(e db.DisputeEvidence) domain.DisputeEvidence {
+	return domain.DisputeEvidence{
+		ID:          e.ID,
+		DisputeID:   e.DisputeID,
+		FileName:    e.FileName,
+		ContentType: e.ContentType,
+		Size:        e.Size,
+		StorageKey:  e.StorageKey,
+		CreatedAt:   e.CreatedAt.Time,
+	}
+}
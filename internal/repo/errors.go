@@ -0,0 +1,64 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"pgm/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Postgres SQLSTATE codes we translate.
+const (
+	pgForeignKeyViolation  = "23503"
+	pgUniqueViolation      = "23505"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgTooManyConnections   = "53300"
+	pgAdminShutdown        = "57P01"
+	pgCrashShutdown        = "57P02"
+	pgCannotConnectNow     = "57P03"
+)
+
+// translateError maps driver errors onto the domain repository errors,
+// keeping the original error in the chain.
+func translateError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: %w", domain.ErrNotFound, err)
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch {
+		case pgErr.Code == pgUniqueViolation:
+			return fmt.Errorf("%w: %w", domain.ErrConflict, err)
+		case pgErr.Code == pgForeignKeyViolation:
+			return fmt.Errorf("%w: %w", domain.ErrInvalidReference, err)
+		case pgErr.Code == pgSerializationFailure, pgErr.Code == pgDeadlockDetected:
+			return fmt.Errorf("%w: %w", domain.ErrSerializationFailure, err)
+		case pgErr.Code == pgTooManyConnections, pgErr.Code == pgAdminShutdown,
+			pgErr.Code == pgCrashShutdown, pgErr.Code == pgCannotConnectNow,
+			strings.HasPrefix(pgErr.Code, "08"): // connection exception
+			return fmt.Errorf("%w: %w", domain.ErrUnavailable, err)
+		}
+		return err
+	}
+
+	var (
+		connectErr *pgconn.ConnectError
+		netErr     net.Error
+	)
+	if errors.As(err, &connectErr) || errors.As(err, &netErr) ||
+		errors.Is(err, context.DeadlineExceeded) || pgconn.SafeToRetry(err) {
+		return fmt.Errorf("%w: %w", domain.ErrUnavailable, err)
+	}
+	return err
+}
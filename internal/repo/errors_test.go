@@ -0,0 +1,52 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"pgm/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslateError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected error
+	}{
+		{name: "no rows", err: pgx.ErrNoRows, expected: domain.ErrNotFound},
+		{name: "wrapped no rows", err: fmt.Errorf("query: %w", pgx.ErrNoRows), expected: domain.ErrNotFound},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, expected: domain.ErrConflict},
+		{name: "foreign key violation", err: &pgconn.PgError{Code: "23503"}, expected: domain.ErrInvalidReference},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, expected: domain.ErrSerializationFailure},
+		{name: "deadlock", err: &pgconn.PgError{Code: "40P01"}, expected: domain.ErrSerializationFailure},
+		{name: "connection failure", err: &pgconn.PgError{Code: "08006"}, expected: domain.ErrUnavailable},
+		{name: "admin shutdown", err: &pgconn.PgError{Code: "57P01"}, expected: domain.ErrUnavailable},
+		{name: "connect error", err: &pgconn.ConnectError{}, expected: domain.ErrUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err)
+			assert.ErrorIs(t, got, tt.expected)
+			assert.ErrorIs(t, got, tt.err, "original error must stay in the chain")
+		})
+	}
+
+	t.Run("unknown errors pass through", func(t *testing.T) {
+		err := &pgconn.PgError{Code: "22001"}
+		got := translateError(err)
+		assert.Same(t, err, got)
+		for _, sentinel := range []error{domain.ErrNotFound, domain.ErrConflict, domain.ErrInvalidReference, domain.ErrSerializationFailure, domain.ErrUnavailable} {
+			assert.False(t, errors.Is(got, sentinel))
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		assert.NoError(t, translateError(nil))
+	})
+}
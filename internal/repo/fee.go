@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// feeRepo is the Postgres implementation of domain.FeeRepo.
+type feeRepo struct {
+	queries db.Querier
+}
+
+func NewFeeRepo(q db.Querier) domain.FeeRepo {
+	return &feeRepo{queries: q}
+}
+
+func (r *feeRepo) CreateFeeSchedule(ctx context.Context, schedule *domain.FeeSchedule) error {
+	s, err := r.queries.CreateFeeSchedule(ctx, db.CreateFeeScheduleParams{
+		Currency:      schedule.Currency,
+		PaymentMethod: methodTypeOrNull(schedule.PaymentMethod),
+		MerchantID:    textOrNull(schedule.MerchantID),
+		Percentage:    decimal.NewFromFloat(schedule.Percentage),
+		FixedAmount:   decimal.NewFromFloat(schedule.FixedAmount),
+		MinFee:        decimalOrNull(schedule.MinFee),
+		MaxFee:        decimalOrNull(schedule.MaxFee),
+		EffectiveFrom: timestamptz(schedule.EffectiveFrom),
+		EffectiveTo:   timestamptzOrNull(schedule.EffectiveTo),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*schedule = *toDomainFeeSchedule(s)
+	return nil
+}
+
+func (r *feeRepo) GetFeeScheduleByID(ctx context.Context, id uuid.UUID) (*domain.FeeSchedule, error) {
+	s, err := r.queries.GetFeeScheduleByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainFeeSchedule(s), nil
+}
+
+func (r *feeRepo) ListFeeSchedules(ctx context.Context, page domain.Page) ([]domain.FeeSchedule, error) {
+	rows, err := r.queries.ListFeeSchedules(ctx, db.ListFeeSchedulesParams{
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	schedules := make([]domain.FeeSchedule, 0, len(rows))
+	for _, s := range rows {
+		schedules = append(schedules, *toDomainFeeSchedule(s))
+	}
+	return schedules, nil
+}
+
+func (r *feeRepo) FindFeeSchedule(ctx context.Context, payment *domain.Payment, at time.Time) (*domain.FeeSchedule, error) {
+	var method domain.PaymentMethodType
+	if payment.PaymentMethod != nil {
+		method = payment.PaymentMethod.Type
+	}
+	s, err := r.queries.FindFeeSchedule(ctx, db.FindFeeScheduleParams{
+		Currency:      payment.Currency,
+		PaymentMethod: methodTypeOrNull(method),
+		MerchantID:    textOrNull(payment.Metadata[domain.MerchantMetadataKey]),
+		EffectiveFrom: timestamptz(at),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainFeeSchedule(s), nil
+}
+
+func toDomainFeeSchedule(s db.FeeSchedule) *domain.FeeSchedule {
+	return &domain.FeeSchedule{
+		ID:            s.ID,
+		Currency:      s.Currency,
+		PaymentMethod: domain.PaymentMethodType(s.PaymentMethod.PaymentMethodType),
+		MerchantID:    s.MerchantID.String,
+		Percentage:    s.Percentage.InexactFloat64(),
+		FixedAmount:   s.FixedAmount.InexactFloat64(),
+		MinFee:        floatPtr(s.MinFee),
+		MaxFee:        floatPtr(s.MaxFee),
+		EffectiveFrom: s.EffectiveFrom.Time,
+		EffectiveTo:   timePtr(s.EffectiveTo),
+		CreatedAt:     s.CreatedAt.Time,
+	}
+}
@@ -3,7 +3,7 @@ package repo
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"runtime"
 	"time"
 
@@ -32,7 +32,10 @@ func NewPool(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	// You can also set connection-level settings
 	config.ConnConfig.ConnectTimeout = 5 * time.Second
 
-	log.Printf("Creating pool with MaxConns=%d, MinConns=%d", config.MaxConns, config.MinConns)
+	slog.Info("creating database pool",
+		slog.Int("max_conns", int(config.MaxConns)),
+		slog.Int("min_conns", int(config.MinConns)),
+	)
 
 	// 3. Create the pool using the modified config
 	pool, err := pgxpool.NewWithConfig(ctx, config)
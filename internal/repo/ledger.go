@@ -0,0 +1,140 @@
+package repo
+
+import (
+	"context"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ledgerRepo is the Postgres implementation of domain.LedgerRepo.
+type ledgerRepo struct {
+	queries db.Querier
+}
+
+func NewLedgerRepo(q db.Querier) domain.LedgerRepo {
+	return &ledgerRepo{queries: q}
+}
+
+func (r *ledgerRepo) PostJournalEntry(ctx context.Context, entry *domain.JournalEntry) error {
+	e, err := r.queries.CreateJournalEntry(ctx, db.CreateJournalEntryParams{
+		Kind:        string(entry.Kind),
+		PaymentID:   uuidOrNull(entry.PaymentID),
+		Description: entry.Description,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	entry.ID = e.ID
+	entry.CreatedAt = e.CreatedAt.Time
+
+	for i, p := range entry.Postings {
+		account, err := r.queries.EnsureLedgerAccount(ctx, db.EnsureLedgerAccountParams{
+			Code:     p.Account,
+			Type:     string(domain.LedgerAccountTypes[p.Account]),
+			Currency: p.Currency,
+		})
+		if err != nil {
+			return translateError(err)
+		}
+		posting, err := r.queries.CreateLedgerPosting(ctx, db.CreateLedgerPostingParams{
+			JournalEntryID: e.ID,
+			AccountID:      account.ID,
+			Amount:         decimal.NewFromFloat(p.Amount),
+		})
+		if err != nil {
+			return translateError(err)
+		}
+		entry.Postings[i].ID = posting.ID
+	}
+	return nil
+}
+
+func (r *ledgerRepo) ListJournalEntriesByPayment(ctx context.Context, paymentID uuid.UUID) ([]domain.JournalEntry, error) {
+	rows, err := r.queries.ListJournalEntriesByPayment(ctx, uuidOrNull(&paymentID))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	postings, err := r.queries.ListLedgerPostingsByPayment(ctx, uuidOrNull(&paymentID))
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	entries := make([]domain.JournalEntry, 0, len(rows))
+	index := make(map[uuid.UUID]int, len(rows))
+	for _, e := range rows {
+		index[e.ID] = len(entries)
+		entries = append(entries, domain.JournalEntry{
+			ID:          e.ID,
+			Kind:        domain.EntryKind(e.Kind),
+			PaymentID:   uuidPtr(e.PaymentID),
+			Description: e.Description,
+			Postings:    []domain.Posting{},
+			CreatedAt:   e.CreatedAt.Time,
+		})
+	}
+	for _, p := range postings {
+		i, ok := index[p.JournalEntryID]
+		if !ok {
+			continue
+		}
+		entries[i].Postings = append(entries[i].Postings, domain.Posting{
+			ID:       p.ID,
+			Account:  p.AccountCode,
+			Currency: p.Currency,
+			Amount:   p.Amount.InexactFloat64(),
+		})
+	}
+	return entries, nil
+}
+
+func (r *ledgerRepo) ListBalances(ctx context.Context) ([]domain.AccountBalance, error) {
+	rows, err := r.queries.ListLedgerBalances(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	balances := make([]domain.AccountBalance, 0, len(rows))
+	for _, b := range rows {
+		balance := domain.AccountBalance{
+			Account:  b.Code,
+			Type:     domain.AccountType(b.Type),
+			Currency: b.Currency,
+			Balance:  b.Balance.InexactFloat64(),
+		}
+		if balance.Type == domain.AccountLiability || balance.Type == domain.AccountRevenue {
+			balance.Balance = -balance.Balance
+		}
+		balances = append(balances, balance)
+	}
+	return balances, nil
+}
+
+func (r *ledgerRepo) ListUnbalancedEntries(ctx context.Context, limit int) ([]domain.UnbalancedEntry, error) {
+	rows, err := r.queries.ListUnbalancedJournalEntries(ctx, int32(limit))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	entries := make([]domain.UnbalancedEntry, 0, len(rows))
+	for _, e := range rows {
+		entries = append(entries, domain.UnbalancedEntry{
+			JournalEntryID: e.JournalEntryID,
+			Currency:       e.Currency,
+			Total:          e.Total.InexactFloat64(),
+		})
+	}
+	return entries, nil
+}
+
+func (r *ledgerRepo) ListUnrecordedPayments(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	ids, err := r.queries.ListPaymentsMissingCapture(ctx, int32(limit))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	if ids == nil {
+		ids = []uuid.UUID{}
+	}
+	return ids, nil
+}
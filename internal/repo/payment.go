@@ -0,0 +1,272 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// paymentRepo is the Postgres implementation of domain.PaymentRepo.
+type paymentRepo struct {
+	queries db.Querier
+}
+
+func NewPaymentRepo(q db.Querier) domain.PaymentRepo {
+	return &paymentRepo{queries: q}
+}
+
+func (r *paymentRepo) CreatePayment(ctx context.Context, payment *domain.Payment) error {
+	method, details := encodePaymentMethod(payment.PaymentMethod)
+	status := payment.Status
+	if status == "" {
+		status = domain.StatusPending
+	}
+	var (
+		riskScore   pgtype.Int4
+		riskOutcome pgtype.Text
+		riskRules   []string
+	)
+	if payment.Risk != nil {
+		riskScore = pgtype.Int4{Int32: int32(payment.Risk.Score), Valid: true}
+		riskOutcome = textOrNull(string(payment.Risk.Outcome))
+		// An empty array rather than NULL, which marks unscreened payments
+		riskRules = append([]string{}, payment.Risk.Rules...)
+	}
+	p, err := r.queries.CreatePayment(ctx, db.CreatePaymentParams{
+		Amount:               decimal.NewFromFloat(payment.Amount),
+		Currency:             payment.Currency,
+		Reference:            payment.Reference,
+		CustomerID:           uuidOrNull(payment.CustomerID),
+		Metadata:             encodeMetadata(payment.Metadata),
+		PaymentMethod:        method,
+		PaymentMethodDetails: details,
+		PaymentLinkID:        uuidOrNull(payment.PaymentLinkID),
+		Status:               db.Paymentstatus(status),
+		ClientIP:             textOrNull(payment.ClientIP),
+		RiskScore:            riskScore,
+		RiskOutcome:          riskOutcome,
+		RiskRules:            riskRules,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*payment = *toDomainPayment(p)
+	return nil
+}
+
+func (r *paymentRepo) GetPaymentByID(ctx context.Context, id uuid.UUID) (*domain.Payment, error) {
+	p, err := r.queries.GetPaymentByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPayment(p), nil
+}
+
+func (r *paymentRepo) GetPaymentByReference(ctx context.Context, reference string) (*domain.Payment, error) {
+	p, err := r.queries.GetPaymentByReference(ctx, reference)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPayment(p), nil
+}
+
+func (r *paymentRepo) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status domain.PaymentStatus) (*domain.Payment, error) {
+	p, err := r.queries.UpdatePaymentStatus(ctx, db.UpdatePaymentStatusParams{
+		ID:        id,
+		Status:    db.Paymentstatus(status),
+		UpdatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPayment(p), nil
+}
+
+func (r *paymentRepo) UpdatePaymentResult(ctx context.Context, id uuid.UUID, status domain.PaymentStatus, provider string) (*domain.Payment, error) {
+	p, err := r.queries.UpdatePaymentResult(ctx, db.UpdatePaymentResultParams{
+		ID:        id,
+		Status:    db.Paymentstatus(status),
+		Provider:  textOrNull(provider),
+		UpdatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPayment(p), nil
+}
+
+func (r *paymentRepo) GetPaymentByIDWithLock(ctx context.Context, id uuid.UUID) (*domain.Payment, error) {
+	p, err := r.queries.GetPaymentByIDWithLock(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPayment(p), nil
+}
+
+func (r *paymentRepo) ListPaymentsByCustomer(ctx context.Context, customerID uuid.UUID, page domain.Page) ([]domain.Payment, error) {
+	rows, err := r.queries.ListPaymentsByCustomer(ctx, db.ListPaymentsByCustomerParams{
+		CustomerID: uuidOrNull(&customerID),
+		Limit:      int32(page.Limit),
+		Offset:     int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	payments := make([]domain.Payment, 0, len(rows))
+	for _, p := range rows {
+		payments = append(payments, *toDomainPayment(p))
+	}
+	return payments, nil
+}
+
+func (r *paymentRepo) ListPayments(ctx context.Context, filter domain.PaymentFilter, page domain.Page) ([]domain.Payment, error) {
+	rows, err := r.queries.ListPayments(ctx, db.ListPaymentsParams{
+		MerchantID: filter.MerchantID,
+		Metadata:   encodeMetadata(filter.Metadata),
+		Limit:      int32(page.Limit),
+		Offset:     int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	payments := make([]domain.Payment, 0, len(rows))
+	for _, p := range rows {
+		payments = append(payments, *toDomainPayment(p))
+	}
+	return payments, nil
+}
+
+func (r *paymentRepo) ListPaymentsByLink(ctx context.Context, linkID uuid.UUID, page domain.Page) ([]domain.Payment, error) {
+	rows, err := r.queries.ListPaymentsByLink(ctx, db.ListPaymentsByLinkParams{
+		PaymentLinkID: uuidOrNull(&linkID),
+		Limit:         int32(page.Limit),
+		Offset:        int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	payments := make([]domain.Payment, 0, len(rows))
+	for _, p := range rows {
+		payments = append(payments, *toDomainPayment(p))
+	}
+	return payments, nil
+}
+
+func (r *paymentRepo) ListPendingPayments(ctx context.Context, filter domain.PendingPaymentFilter, limit int) ([]domain.Payment, error) {
+	rows, err := r.queries.ListPendingPayments(ctx, db.ListPendingPaymentsParams{
+		Metadata:    encodeMetadata(filter.Metadata),
+		Currency:    textOrNull(filter.Currency),
+		CreatedFrom: timestamptzOrNull(filter.CreatedFrom),
+		CreatedTo:   timestamptzOrNull(filter.CreatedTo),
+		LimitCount:  int32(limit),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	payments := make([]domain.Payment, 0, len(rows))
+	for _, p := range rows {
+		payments = append(payments, *toDomainPayment(p))
+	}
+	return payments, nil
+}
+
+func (r *paymentRepo) SetPaymentMethod(ctx context.Context, id uuid.UUID, method *domain.PaymentMethod) (*domain.Payment, error) {
+	m, details := encodePaymentMethod(method)
+	p, err := r.queries.SetPaymentMethod(ctx, db.SetPaymentMethodParams{
+		ID:                   id,
+		PaymentMethod:        m,
+		PaymentMethodDetails: details,
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPayment(p), nil
+}
+
+func (r *paymentRepo) SetPaymentFee(ctx context.Context, id uuid.UUID, fee float64) (*domain.Payment, error) {
+	p, err := r.queries.SetPaymentFee(ctx, db.SetPaymentFeeParams{
+		ID:        id,
+		FeeAmount: decimalOrNull(&fee),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPayment(p), nil
+}
+
+func (r *paymentRepo) CreatePaymentAttempt(ctx context.Context, attempt *domain.PaymentAttempt) error {
+	a, err := r.queries.CreatePaymentAttempt(ctx, db.CreatePaymentAttemptParams{
+		PaymentID:         attempt.PaymentID,
+		Provider:          attempt.Provider,
+		Status:            string(attempt.Status),
+		ProviderReference: textOrNull(attempt.ProviderReference),
+		Error:             textOrNull(attempt.Error),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*attempt = toDomainPaymentAttempt(a)
+	return nil
+}
+
+func (r *paymentRepo) ListPaymentAttempts(ctx context.Context, paymentID uuid.UUID) ([]domain.PaymentAttempt, error) {
+	rows, err := r.queries.ListPaymentAttempts(ctx, paymentID)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	attempts := make([]domain.PaymentAttempt, 0, len(rows))
+	for _, a := range rows {
+		attempts = append(attempts, toDomainPaymentAttempt(a))
+	}
+	return attempts, nil
+}
+
+func toDomainPayment(p db.Payment) *domain.Payment {
+	return &domain.Payment{
+		ID:            p.ID,
+		Amount:        p.Amount.InexactFloat64(),
+		Currency:      p.Currency,
+		Reference:     p.Reference,
+		Status:        domain.PaymentStatus(p.Status),
+		CustomerID:    uuidPtr(p.CustomerID),
+		Metadata:      decodeMetadata(p.Metadata),
+		PaymentMethod: decodePaymentMethod(p.PaymentMethod, p.PaymentMethodDetails),
+		PaymentLinkID: uuidPtr(p.PaymentLinkID),
+		Provider:      p.Provider.String,
+		FeeAmount:     floatPtr(p.FeeAmount),
+		NetAmount:     floatPtr(p.NetAmount),
+		ClientIP:      p.ClientIP.String,
+		Risk:          toDomainRiskAssessment(p),
+		CreatedAt:     p.CreatedAt.Time,
+		UpdatedAt:     p.UpdatedAt.Time,
+	}
+}
+
+func toDomainRiskAssessment(p db.Payment) *domain.RiskAssessment {
+	if !p.RiskOutcome.Valid {
+		return nil
+	}
+	return &domain.RiskAssessment{
+		Score:   int(p.RiskScore.Int32),
+		Outcome: domain.RiskOutcome(p.RiskOutcome.String),
+		Rules:   append([]string{}, p.RiskRules...),
+	}
+}
+
+func toDomainPaymentAttempt(a db.PaymentAttempt) domain.PaymentAttempt {
+	return domain.PaymentAttempt{
+		ID:                a.ID,
+		PaymentID:         a.PaymentID,
+		Provider:          a.Provider,
+		Status:            domain.AttemptStatus(a.Status),
+		ProviderReference: a.ProviderReference.String,
+		Error:             a.Error.String,
+		CreatedAt:         a.CreatedAt.Time,
+	}
+}
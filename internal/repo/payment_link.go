@@ -0,0 +1,126 @@
+package repo
+
+import (
+	"context"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+)
+
+// paymentLinkRepo is the Postgres implementation of domain.PaymentLinkRepo.
+type paymentLinkRepo struct {
+	queries db.Querier
+}
+
+func NewPaymentLinkRepo(q db.Querier) domain.PaymentLinkRepo {
+	return &paymentLinkRepo{queries: q}
+}
+
+func (r *paymentLinkRepo) CreatePaymentLink(ctx context.Context, link *domain.PaymentLink) error {
+	l, err := r.queries.CreatePaymentLink(ctx, db.CreatePaymentLinkParams{
+		Slug:        link.Slug,
+		Amount:      decimalOrNull(link.Amount),
+		Currency:    link.Currency,
+		Description: link.Description,
+		MaxUses:     int4OrNull(link.MaxUses),
+		ExpiresAt:   timestamptzOrNull(link.ExpiresAt),
+		SuccessURL:  textOrNull(link.SuccessURL),
+		CancelURL:   textOrNull(link.CancelURL),
+		Metadata:    encodeMetadata(link.Metadata),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*link = *toDomainPaymentLink(l)
+	return nil
+}
+
+func (r *paymentLinkRepo) GetPaymentLinkByID(ctx context.Context, id uuid.UUID) (*domain.PaymentLink, error) {
+	l, err := r.queries.GetPaymentLinkByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPaymentLink(l), nil
+}
+
+func (r *paymentLinkRepo) GetPaymentLinkBySlug(ctx context.Context, slug string) (*domain.PaymentLink, error) {
+	l, err := r.queries.GetPaymentLinkBySlug(ctx, slug)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPaymentLink(l), nil
+}
+
+func (r *paymentLinkRepo) ListPaymentLinks(ctx context.Context, page domain.Page) ([]domain.PaymentLink, error) {
+	rows, err := r.queries.ListPaymentLinks(ctx, db.ListPaymentLinksParams{
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	links := make([]domain.PaymentLink, 0, len(rows))
+	for _, l := range rows {
+		links = append(links, *toDomainPaymentLink(l))
+	}
+	return links, nil
+}
+
+func (r *paymentLinkRepo) SetPaymentLinkActive(ctx context.Context, id uuid.UUID, active bool) (*domain.PaymentLink, error) {
+	l, err := r.queries.SetPaymentLinkActive(ctx, db.SetPaymentLinkActiveParams{
+		ID:     id,
+		Active: active,
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPaymentLink(l), nil
+}
+
+func (r *paymentLinkRepo) UsePaymentLink(ctx context.Context, id uuid.UUID) (*domain.PaymentLink, error) {
+	l, err := r.queries.UsePaymentLink(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPaymentLink(l), nil
+}
+
+func (r *paymentLinkRepo) ReleasePaymentLink(ctx context.Context, id uuid.UUID) (*domain.PaymentLink, error) {
+	l, err := r.queries.ReleasePaymentLink(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPaymentLink(l), nil
+}
+
+func (r *paymentLinkRepo) GetPaymentLinkTotals(ctx context.Context, id uuid.UUID) (*domain.PaymentLinkTotals, error) {
+	t, err := r.queries.GetPaymentLinkTotals(ctx, uuidOrNull(&id))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &domain.PaymentLinkTotals{
+		Count:  int(t.SucceededCount),
+		Amount: t.SucceededAmount.InexactFloat64(),
+	}, nil
+}
+
+func toDomainPaymentLink(l db.PaymentLink) *domain.PaymentLink {
+	return &domain.PaymentLink{
+		ID:          l.ID,
+		Slug:        l.Slug,
+		Amount:      floatPtr(l.Amount),
+		Currency:    l.Currency,
+		Description: l.Description,
+		MaxUses:     intPtr(l.MaxUses),
+		Uses:        int(l.Uses),
+		ExpiresAt:   timePtr(l.ExpiresAt),
+		Active:      l.Active,
+		SuccessURL:  l.SuccessURL.String,
+		CancelURL:   l.CancelURL.String,
+		Metadata:    decodeMetadata(l.Metadata),
+		CreatedAt:   l.CreatedAt.Time,
+		UpdatedAt:   l.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,73 @@
+package repo
+
+import (
+	"context"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// planRepo is the Postgres implementation of domain.PlanRepo.
+type planRepo struct {
+	queries db.Querier
+}
+
+func NewPlanRepo(q db.Querier) domain.PlanRepo {
+	return &planRepo{queries: q}
+}
+
+func (r *planRepo) CreatePlan(ctx context.Context, plan *domain.Plan) error {
+	p, err := r.queries.CreatePlan(ctx, db.CreatePlanParams{
+		Name:            plan.Name,
+		Amount:          decimal.NewFromFloat(plan.Amount),
+		Currency:        plan.Currency,
+		BillingInterval: string(plan.Interval),
+		IntervalCount:   int32(plan.IntervalCount),
+		TrialDays:       int32(plan.TrialDays),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*plan = *toDomainPlan(p)
+	return nil
+}
+
+func (r *planRepo) GetPlanByID(ctx context.Context, id uuid.UUID) (*domain.Plan, error) {
+	p, err := r.queries.GetPlanByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainPlan(p), nil
+}
+
+func (r *planRepo) ListPlans(ctx context.Context, page domain.Page) ([]domain.Plan, error) {
+	rows, err := r.queries.ListPlans(ctx, db.ListPlansParams{
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	plans := make([]domain.Plan, 0, len(rows))
+	for _, p := range rows {
+		plans = append(plans, *toDomainPlan(p))
+	}
+	return plans, nil
+}
+
+func toDomainPlan(p db.Plan) *domain.Plan {
+	return &domain.Plan{
+		ID:            p.ID,
+		Name:          p.Name,
+		Amount:        p.Amount.InexactFloat64(),
+		Currency:      p.Currency,
+		Interval:      domain.BillingInterval(p.BillingInterval),
+		IntervalCount: int(p.IntervalCount),
+		TrialDays:     int(p.TrialDays),
+		CreatedAt:     p.CreatedAt.Time,
+		UpdatedAt:     p.UpdatedAt.Time,
+	}
+}
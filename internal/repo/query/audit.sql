@@ -0,0 +1,38 @@
+-- name: CountUnsealedAuditEntries :one
+SELECT COUNT(*) FROM audit_log WHERE seq IS NULL;
+-- name: CreateAuditEntry :one
+INSERT INTO audit_log (actor, action, target_type, target_id, request_id, source_ip, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING *;
+-- name: GetAuditChainHead :one
+SELECT id, actor, action, target_type, target_id, request_id, source_ip, before, after, created_at, seq, prev_hash, hash, sealed_at FROM audit_log
+		WHERE seq IS NOT NULL
+		ORDER BY seq DESC
+		LIMIT 1;
+-- name: ListAuditEntries :many
+SELECT id, actor, action, target_type, target_id, request_id, source_ip, before, after, created_at, seq, prev_hash, hash, sealed_at FROM audit_log
+		WHERE (sqlc.narg(actor)::TEXT IS NULL OR actor = sqlc.narg(actor))
+			AND (sqlc.narg(action)::TEXT IS NULL OR action = sqlc.narg(action))
+			AND (sqlc.narg(target_type)::TEXT IS NULL OR target_type = sqlc.narg(target_type))
+			AND (sqlc.narg(target_id)::TEXT IS NULL OR target_id = sqlc.narg(target_id))
+			AND (sqlc.narg(request_id)::TEXT IS NULL OR request_id = sqlc.narg(request_id))
+			AND (sqlc.narg(created_from)::TIMESTAMPTZ IS NULL OR created_at >= sqlc.narg(created_from))
+			AND (sqlc.narg(created_to)::TIMESTAMPTZ IS NULL OR created_at < sqlc.narg(created_to))
+		ORDER BY id DESC
+		LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+-- name: ListSealedAuditEntries :many
+SELECT id, actor, action, target_type, target_id, request_id, source_ip, before, after, created_at, seq, prev_hash, hash, sealed_at FROM audit_log
+		WHERE seq > $1
+		ORDER BY seq
+		LIMIT $2;
+-- name: ListUnsealedAuditEntries :many
+SELECT id, actor, action, target_type, target_id, request_id, source_ip, before, after, created_at, seq, prev_hash, hash, sealed_at FROM audit_log
+		WHERE seq IS NULL
+		ORDER BY id
+		LIMIT $1;
+-- name: SealAuditEntry :one
+UPDATE audit_log SET seq = $2, prev_hash = $3, hash = $4, sealed_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND seq IS NULL
+		RETURNING *;
+-- name: TryLockAuditChain :one
+SELECT pg_try_advisory_xact_lock(hashtext('audit_log')) AS locked;
@@ -0,0 +1,20 @@
+-- name: CreateCheckoutSession :one
+INSERT INTO checkout_sessions (payment_id, token, success_url, cancel_url, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING *;
+-- name: GetCheckoutSessionByID :one
+SELECT id, payment_id, token, status, success_url, cancel_url, expires_at, created_at, updated_at FROM checkout_sessions WHERE id = $1;
+-- name: GetCheckoutSessionByToken :one
+SELECT id, payment_id, token, status, success_url, cancel_url, expires_at, created_at, updated_at FROM checkout_sessions WHERE token = $1;
+-- name: GetCheckoutSessionByPaymentID :one
+SELECT id, payment_id, token, status, success_url, cancel_url, expires_at, created_at, updated_at FROM checkout_sessions WHERE payment_id = $1;
+-- name: CloseCheckoutSession :one
+UPDATE checkout_sessions SET status = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'open'
+		RETURNING *;
+-- name: ClaimExpiredCheckoutSession :one
+SELECT id, payment_id, token, status, success_url, cancel_url, expires_at, created_at, updated_at FROM checkout_sessions
+		WHERE status = 'open' AND expires_at <= $1
+		ORDER BY expires_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED;
@@ -0,0 +1,19 @@
+-- name: CreateCustomer :one
+INSERT INTO customers (name, email, phone, external_id, merchant_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING *;
+-- name: GetCustomerByID :one
+SELECT id, name, email, phone, external_id, created_at, updated_at, merchant_id FROM customers WHERE id = $1 AND merchant_id = $2;
+-- name: GetCustomerByExternalID :one
+SELECT id, name, email, phone, external_id, created_at, updated_at, merchant_id FROM customers WHERE external_id = $1 AND merchant_id = $2;
+-- name: ListCustomers :many
+SELECT id, name, email, phone, external_id, created_at, updated_at, merchant_id FROM customers
+		WHERE merchant_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3;
+-- name: UpdateCustomer :one
+UPDATE customers SET name = $2, email = $3, phone = $4, external_id = $5, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND merchant_id = $6
+		RETURNING *;
+-- name: DeleteCustomer :execrows
+DELETE FROM customers WHERE id = $1 AND merchant_id = $2;
@@ -0,0 +1,37 @@
+-- name: CloseDispute :one
+UPDATE disputes SET status = $2, closed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *;
+-- name: CreateDispute :one
+INSERT INTO disputes (payment_id, provider_reference, reason_code, amount, currency, evidence_due_by)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING *;
+-- name: CreateDisputeEvidence :one
+INSERT INTO dispute_evidence (dispute_id, file_name, content_type, size, storage_key)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING *;
+-- name: GetDisputeByID :one
+SELECT id, payment_id, provider_reference, reason_code, amount, currency, status, evidence_due_by, evidence_description, evidence_submitted_at, closed_at, created_at, updated_at FROM disputes WHERE id = $1;
+-- name: GetDisputeByIDForUpdate :one
+SELECT id, payment_id, provider_reference, reason_code, amount, currency, status, evidence_due_by, evidence_description, evidence_submitted_at, closed_at, created_at, updated_at FROM disputes WHERE id = $1 FOR UPDATE;
+-- name: GetDisputeEvidenceByID :one
+SELECT id, dispute_id, file_name, content_type, size, storage_key, created_at FROM dispute_evidence WHERE id = $1 AND dispute_id = $2;
+-- name: GetDisputedAmount :one
+SELECT COALESCE(SUM(amount), 0)::DECIMAL(12, 2) AS amount FROM disputes
+		WHERE payment_id = $1 AND status <> 'won';
+-- name: ListDisputeEvidence :many
+SELECT id, dispute_id, file_name, content_type, size, storage_key, created_at FROM dispute_evidence
+		WHERE dispute_id = $1
+		ORDER BY created_at, id;
+-- name: ListDisputes :many
+SELECT id, payment_id, provider_reference, reason_code, amount, currency, status, evidence_due_by, evidence_description, evidence_submitted_at, closed_at, created_at, updated_at FROM disputes
+		WHERE (sqlc.narg(payment_id)::UUID IS NULL OR payment_id = sqlc.narg(payment_id))
+			AND (sqlc.narg(status)::TEXT IS NULL OR status = sqlc.narg(status))
+			AND (sqlc.narg(merchant_id)::TEXT IS NULL OR payment_id IN (
+				SELECT id FROM payments WHERE metadata @> jsonb_build_object('merchant_id', sqlc.narg(merchant_id)::TEXT)))
+		ORDER BY created_at DESC
+		LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+-- name: SubmitDisputeEvidence :one
+UPDATE disputes SET status = 'under_review', evidence_description = $2, evidence_submitted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *;
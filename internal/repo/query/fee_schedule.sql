@@ -0,0 +1,19 @@
+-- name: CreateFeeSchedule :one
+INSERT INTO fee_schedules (currency, payment_method, merchant_id, percentage, fixed_amount, min_fee, max_fee, effective_from, effective_to)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING *;
+-- name: GetFeeScheduleByID :one
+SELECT id, currency, payment_method, merchant_id, percentage, fixed_amount, min_fee, max_fee, effective_from, effective_to, created_at FROM fee_schedules WHERE id = $1;
+-- name: ListFeeSchedules :many
+SELECT id, currency, payment_method, merchant_id, percentage, fixed_amount, min_fee, max_fee, effective_from, effective_to, created_at FROM fee_schedules
+		ORDER BY currency, effective_from DESC
+		LIMIT $1 OFFSET $2;
+-- name: FindFeeSchedule :one
+SELECT id, currency, payment_method, merchant_id, percentage, fixed_amount, min_fee, max_fee, effective_from, effective_to, created_at FROM fee_schedules
+		WHERE currency = $1
+			AND (payment_method IS NULL OR payment_method = $2)
+			AND (merchant_id IS NULL OR merchant_id = $3)
+			AND effective_from <= $4
+			AND (effective_to IS NULL OR effective_to > $4)
+		ORDER BY merchant_id IS NULL, payment_method IS NULL, effective_from DESC
+		LIMIT 1;
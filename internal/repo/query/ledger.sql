@@ -0,0 +1,48 @@
+-- name: EnsureLedgerAccount :one
+INSERT INTO ledger_accounts (code, type, currency)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (code, currency) DO UPDATE SET code = EXCLUDED.code
+		RETURNING *;
+-- name: CreateJournalEntry :one
+INSERT INTO journal_entries (kind, payment_id, description)
+		VALUES ($1, $2, $3)
+		RETURNING *;
+-- name: CreateLedgerPosting :one
+INSERT INTO ledger_postings (journal_entry_id, account_id, amount)
+		VALUES ($1, $2, $3)
+		RETURNING *;
+-- name: ListJournalEntriesByPayment :many
+SELECT id, kind, payment_id, description, created_at FROM journal_entries
+		WHERE payment_id = $1
+		ORDER BY created_at, id;
+-- name: ListLedgerPostingsByPayment :many
+SELECT lp.id, lp.journal_entry_id, a.code AS account_code, a.currency, lp.amount
+		FROM ledger_postings lp
+		JOIN ledger_accounts a ON a.id = lp.account_id
+		JOIN journal_entries e ON e.id = lp.journal_entry_id
+		WHERE e.payment_id = $1
+		ORDER BY lp.created_at, lp.id;
+-- name: ListLedgerBalances :many
+SELECT a.code, a.type, a.currency, COALESCE(SUM(lp.amount), 0)::DECIMAL(14, 2) AS balance
+		FROM ledger_accounts a
+		LEFT JOIN ledger_postings lp ON lp.account_id = a.id
+		GROUP BY a.id
+		ORDER BY a.code, a.currency;
+-- name: ListUnbalancedJournalEntries :many
+SELECT e.id AS journal_entry_id, a.currency, SUM(lp.amount)::DECIMAL(14, 2) AS total
+		FROM journal_entries e
+		JOIN ledger_postings lp ON lp.journal_entry_id = e.id
+		JOIN ledger_accounts a ON a.id = lp.account_id
+		GROUP BY e.id, a.currency
+		HAVING SUM(lp.amount) <> 0
+		UNION ALL
+		SELECT e.id, '', 0
+		FROM journal_entries e
+		WHERE NOT EXISTS (SELECT 1 FROM ledger_postings lp WHERE lp.journal_entry_id = e.id)
+		LIMIT $1;
+-- name: ListPaymentsMissingCapture :many
+SELECT p.id FROM payments p
+		WHERE p.status = 'SUCCESS'
+		AND NOT EXISTS (SELECT 1 FROM journal_entries e WHERE e.payment_id = p.id AND e.kind = 'payment_captured')
+		ORDER BY p.created_at
+		LIMIT $1;
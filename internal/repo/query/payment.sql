@@ -1,15 +1,51 @@
 -- name: CreatePayment :one
-INSERT INTO payments (amount, currency, reference)
-		VALUES ($1, $2, $3)
+INSERT INTO payments (amount, currency, reference, customer_id, metadata, payment_method, payment_method_details, payment_link_id, status, client_ip, risk_score, risk_outcome, risk_rules)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
 		RETURNING *;
 -- name: GetPaymentByID :one
-SELECT id, amount, currency, reference, status, created_at, updated_at FROM payments WHERE id = $1;
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments WHERE id = $1;
 
 -- name: GetPaymentByReference :one
-SELECT id, amount, currency, reference, status, created_at, updated_at FROM payments WHERE reference = $1;
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments WHERE reference = $1;
 -- name: GetPaymentByIDWithLock :one
-SELECT id, amount, currency, reference, status, created_at, updated_at FROM payments WHERE id = $1 FOR UPDATE;
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments WHERE id = $1 FOR NO KEY UPDATE;
 -- name: UpdatePaymentStatus :one
 UPDATE payments SET status = $1, updated_at = $2 WHERE id = $3 RETURNING *;
 -- name: CheckExistence :one
-SELECT EXISTS(SELECT 1 FROM payments WHERE reference = $1) AS exists;
\ No newline at end of file
+SELECT EXISTS(SELECT 1 FROM payments WHERE reference = $1) AS exists;
+-- name: ListPaymentsByCustomer :many
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments
+		WHERE customer_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3;
+-- name: ListPayments :many
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments
+		WHERE metadata->>'merchant_id' = $1 AND metadata @> $2
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4;
+-- name: UpdatePaymentResult :one
+UPDATE payments SET status = $1, provider = $2, updated_at = $3 WHERE id = $4 RETURNING *;
+-- name: SetPaymentMethod :one
+UPDATE payments SET payment_method = $2, payment_method_details = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status IN ('PENDING', 'IN_REVIEW')
+		RETURNING *;
+-- name: ListPaymentsByLink :many
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments
+		WHERE payment_link_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3;
+-- name: SetPaymentFee :one
+UPDATE payments SET fee_amount = $2, net_amount = amount - $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *;
+-- name: ListPendingPayments :many
+SELECT id, amount, currency, reference, status, created_at, updated_at, customer_id, metadata, payment_method, payment_method_details, provider, payment_link_id, fee_amount, net_amount, client_ip, risk_score, risk_outcome, risk_rules FROM payments
+		WHERE status = 'PENDING'
+			AND metadata @> sqlc.arg(metadata)
+			AND (sqlc.narg(currency)::TEXT IS NULL OR currency = sqlc.narg(currency))
+			AND (sqlc.narg(created_from)::TIMESTAMPTZ IS NULL OR created_at >= sqlc.narg(created_from))
+			AND (sqlc.narg(created_to)::TIMESTAMPTZ IS NULL OR created_at < sqlc.narg(created_to))
+			-- Checkout payments are queued only once the payer confirms them
+			AND NOT EXISTS (SELECT 1 FROM checkout_sessions cs WHERE cs.payment_id = payments.id AND cs.status <> 'confirmed')
+		ORDER BY created_at, id
+		LIMIT sqlc.arg(limit_count);
@@ -0,0 +1,8 @@
+-- name: CreatePaymentAttempt :one
+INSERT INTO payment_attempts (payment_id, provider, status, provider_reference, error)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING *;
+-- name: ListPaymentAttempts :many
+SELECT id, payment_id, provider, status, provider_reference, error, created_at FROM payment_attempts
+		WHERE payment_id = $1
+		ORDER BY created_at;
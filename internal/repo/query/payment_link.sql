@@ -0,0 +1,31 @@
+-- name: CreatePaymentLink :one
+INSERT INTO payment_links (slug, amount, currency, description, max_uses, expires_at, success_url, cancel_url, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING *;
+-- name: GetPaymentLinkByID :one
+SELECT id, slug, amount, currency, description, max_uses, uses, expires_at, active, success_url, cancel_url, metadata, created_at, updated_at FROM payment_links WHERE id = $1;
+-- name: GetPaymentLinkBySlug :one
+SELECT id, slug, amount, currency, description, max_uses, uses, expires_at, active, success_url, cancel_url, metadata, created_at, updated_at FROM payment_links WHERE slug = $1;
+-- name: ListPaymentLinks :many
+SELECT id, slug, amount, currency, description, max_uses, uses, expires_at, active, success_url, cancel_url, metadata, created_at, updated_at FROM payment_links
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2;
+-- name: SetPaymentLinkActive :one
+UPDATE payment_links SET active = $2, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *;
+-- name: UsePaymentLink :one
+UPDATE payment_links SET uses = uses + 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+			AND active
+			AND (expires_at IS NULL OR expires_at > CURRENT_TIMESTAMP)
+			AND (max_uses IS NULL OR uses < max_uses)
+		RETURNING *;
+-- name: ReleasePaymentLink :one
+UPDATE payment_links SET uses = uses - 1, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND uses > 0
+		RETURNING *;
+-- name: GetPaymentLinkTotals :one
+SELECT COUNT(*) FILTER (WHERE status = 'SUCCESS') AS succeeded_count,
+		COALESCE(SUM(amount) FILTER (WHERE status = 'SUCCESS'), 0)::DECIMAL(12, 2) AS succeeded_amount
+		FROM payments WHERE payment_link_id = $1;
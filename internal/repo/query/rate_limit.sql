@@ -0,0 +1,11 @@
+-- name: TakeRateLimitToken :one
+INSERT INTO rate_limit_buckets AS b (key, tokens, last_allowed, updated_at)
+		VALUES (sqlc.arg(key), sqlc.arg(capacity)::float8 - 1, TRUE, now())
+		ON CONFLICT (key) DO UPDATE SET
+			tokens = LEAST(sqlc.arg(capacity)::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * sqlc.arg(refill_rate)::float8)
+				- CASE WHEN LEAST(sqlc.arg(capacity)::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * sqlc.arg(refill_rate)::float8) >= 1 THEN 1 ELSE 0 END,
+			last_allowed = LEAST(sqlc.arg(capacity)::float8, b.tokens + EXTRACT(EPOCH FROM now() - b.updated_at)::float8 * sqlc.arg(refill_rate)::float8) >= 1,
+			updated_at = now()
+		RETURNING tokens, last_allowed;
+-- name: DeleteStaleRateLimitBuckets :exec
+DELETE FROM rate_limit_buckets WHERE updated_at < $1;
@@ -0,0 +1,42 @@
+-- name: CreateReconciliationItem :one
+INSERT INTO reconciliation_items (run_id, result, reference, payment_id, statement_amount, statement_currency, booked_at, payment_amount, payment_currency)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING *;
+-- name: CreateReconciliationRun :one
+INSERT INTO reconciliation_runs (format, provider, period_start, period_end, line_count, matched_count, missing_internally_count, missing_at_provider_count, amount_mismatch_count)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING *;
+-- name: FindPaymentForStatementLine :one
+SELECT p.id, p.reference, p.amount, p.currency, p.status FROM payments p
+		WHERE p.reference = $1
+			OR EXISTS (SELECT 1 FROM payment_attempts a WHERE a.payment_id = p.id AND a.provider_reference = $1)
+		ORDER BY p.reference = $1 DESC
+		LIMIT 1;
+-- name: GetReconciliationItemByID :one
+SELECT id, run_id, result, reference, payment_id, statement_amount, statement_currency, booked_at, payment_amount, payment_currency, resolved_at, resolution_note, created_at FROM reconciliation_items WHERE id = $1;
+-- name: GetReconciliationRunByID :one
+SELECT id, format, provider, period_start, period_end, line_count, matched_count, missing_internally_count, missing_at_provider_count, amount_mismatch_count, created_at FROM reconciliation_runs WHERE id = $1;
+-- name: ListReconciliationItems :many
+SELECT id, run_id, result, reference, payment_id, statement_amount, statement_currency, booked_at, payment_amount, payment_currency, resolved_at, resolution_note, created_at FROM reconciliation_items
+		WHERE run_id = sqlc.arg(run_id) AND (sqlc.narg(result)::TEXT IS NULL OR result = sqlc.narg(result))
+		ORDER BY created_at, id
+		LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+-- name: ListReconciliationRuns :many
+SELECT id, format, provider, period_start, period_end, line_count, matched_count, missing_internally_count, missing_at_provider_count, amount_mismatch_count, created_at FROM reconciliation_runs
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2;
+-- name: ListSuccessfulPaymentsInPeriod :many
+SELECT id, reference, amount, currency FROM payments
+		WHERE status = 'SUCCESS'
+			AND updated_at >= sqlc.arg(period_start) AND updated_at < sqlc.arg(period_end)
+			AND (sqlc.narg(provider)::TEXT IS NULL OR provider = sqlc.narg(provider))
+		ORDER BY updated_at;
+-- name: ListUnresolvedReconciliationItems :many
+SELECT id, run_id, result, reference, payment_id, statement_amount, statement_currency, booked_at, payment_amount, payment_currency, resolved_at, resolution_note, created_at FROM reconciliation_items
+		WHERE result <> 'matched' AND resolved_at IS NULL
+		ORDER BY created_at, id
+		LIMIT $1 OFFSET $2;
+-- name: ResolveReconciliationItem :one
+UPDATE reconciliation_items SET resolved_at = CURRENT_TIMESTAMP, resolution_note = $2
+		WHERE id = $1 AND result <> 'matched' AND resolved_at IS NULL
+		RETURNING *;
@@ -0,0 +1,32 @@
+-- name: ClaimPaymentReview :one
+UPDATE payment_reviews SET status = 'claimed', claimed_by = $2, claimed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *;
+-- name: CreatePaymentReview :one
+INSERT INTO payment_reviews (payment_id)
+		VALUES ($1)
+		RETURNING *;
+-- name: DecidePaymentReview :one
+UPDATE payment_reviews SET status = $2, decided_by = $3, notes = $4, decided_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *;
+-- name: EscalateOverduePaymentReviews :many
+UPDATE payment_reviews SET escalated_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'open' AND escalated_at IS NULL AND created_at <= $1
+		RETURNING *;
+-- name: GetPaymentReviewByID :one
+SELECT id, payment_id, status, claimed_by, claimed_at, decided_by, decided_at, notes, escalated_at, created_at, updated_at FROM payment_reviews WHERE id = $1;
+-- name: GetPaymentReviewByIDForUpdate :one
+SELECT id, payment_id, status, claimed_by, claimed_at, decided_by, decided_at, notes, escalated_at, created_at, updated_at FROM payment_reviews WHERE id = $1 FOR UPDATE;
+-- name: ListPaymentReviews :many
+SELECT id, payment_id, status, claimed_by, claimed_at, decided_by, decided_at, notes, escalated_at, created_at, updated_at FROM payment_reviews
+		WHERE (sqlc.narg(status)::TEXT IS NULL OR status = sqlc.narg(status))
+			AND (sqlc.narg(escalated)::BOOLEAN IS NULL OR (escalated_at IS NOT NULL) = sqlc.narg(escalated))
+		ORDER BY escalated_at IS NULL, created_at, id
+		LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+-- name: LockOverduePaymentReview :one
+SELECT id, payment_id, status, claimed_by, claimed_at, decided_by, decided_at, notes, escalated_at, created_at, updated_at FROM payment_reviews
+		WHERE status = 'open' AND created_at <= $1
+		ORDER BY created_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED;
@@ -0,0 +1,42 @@
+-- name: CountPaymentsByClientIPSince :one
+SELECT COUNT(*) FROM payments
+		WHERE client_ip = sqlc.arg(client_ip) AND created_at >= sqlc.arg(since);
+-- name: CountPaymentsByCustomerSince :one
+SELECT COUNT(*) FROM payments
+		WHERE customer_id = sqlc.arg(customer_id) AND created_at >= sqlc.arg(since);
+-- name: CountPaymentsByReferencePrefixSince :one
+SELECT COUNT(*) FROM payments
+		WHERE starts_with(reference, sqlc.arg(prefix)) AND created_at >= sqlc.arg(since);
+-- name: CreateRiskBlocklistEntry :one
+INSERT INTO risk_blocklist (kind, value, reason)
+		VALUES ($1, $2, $3)
+		RETURNING *;
+-- name: CreateRiskRule :one
+INSERT INTO risk_rules (name, expression, action, score, enabled)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING *;
+-- name: DeleteRiskBlocklistEntry :execrows
+DELETE FROM risk_blocklist WHERE id = $1;
+-- name: DeleteRiskRule :execrows
+DELETE FROM risk_rules WHERE id = $1;
+-- name: GetRiskRuleByID :one
+SELECT id, name, expression, action, score, enabled, created_at, updated_at FROM risk_rules WHERE id = $1;
+-- name: IsBlocklisted :one
+SELECT EXISTS(SELECT 1 FROM risk_blocklist WHERE kind = $1 AND value = $2) AS exists;
+-- name: ListEnabledRiskRules :many
+SELECT id, name, expression, action, score, enabled, created_at, updated_at FROM risk_rules
+		WHERE enabled
+		ORDER BY created_at, id;
+-- name: ListRiskBlocklistEntries :many
+SELECT id, kind, value, reason, created_at FROM risk_blocklist
+		WHERE (sqlc.narg(kind)::TEXT IS NULL OR kind = sqlc.narg(kind))
+		ORDER BY created_at DESC
+		LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+-- name: ListRiskRules :many
+SELECT id, name, expression, action, score, enabled, created_at, updated_at FROM risk_rules
+		ORDER BY created_at, id
+		LIMIT $1 OFFSET $2;
+-- name: UpdateRiskRule :one
+UPDATE risk_rules SET name = $2, expression = $3, action = $4, score = $5, enabled = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *;
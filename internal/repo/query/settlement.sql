@@ -0,0 +1,88 @@
+-- name: ClaimUnsettledPayment :one
+SELECT p.id, p.amount, p.currency, p.metadata, p.fee_amount, p.net_amount, p.updated_at,
+		COALESCE((SELECT SUM(lp.amount) FROM journal_entries e
+			JOIN ledger_postings lp ON lp.journal_entry_id = e.id
+			JOIN ledger_accounts a ON a.id = lp.account_id
+			WHERE e.payment_id = p.id AND e.kind = 'refund' AND a.code = 'merchant_payable'), 0)::DECIMAL(10, 2) AS refunded_amount
+		FROM payments p
+		WHERE p.status = 'SUCCESS'
+			AND NOT EXISTS (SELECT 1 FROM settlement_items si WHERE si.payment_id = p.id)
+		ORDER BY p.updated_at
+		LIMIT 1
+		FOR UPDATE OF p SKIP LOCKED;
+-- name: ClaimUnsettledRefund :one
+SELECT p.id, p.currency, p.metadata,
+		((SELECT COALESCE(SUM(lp.amount), 0) FROM journal_entries e
+			JOIN ledger_postings lp ON lp.journal_entry_id = e.id
+			JOIN ledger_accounts a ON a.id = lp.account_id
+			WHERE e.payment_id = p.id AND e.kind = 'refund' AND a.code = 'merchant_payable')
+			- si.refunded_amount
+			+ (SELECT COALESCE(SUM(sa.amount), 0) FROM settlement_adjustments sa WHERE sa.payment_id = p.id))::DECIMAL(10, 2) AS unsettled_amount
+		FROM settlement_items si
+		JOIN payments p ON p.id = si.payment_id
+		WHERE (SELECT COALESCE(SUM(lp.amount), 0) FROM journal_entries e
+			JOIN ledger_postings lp ON lp.journal_entry_id = e.id
+			JOIN ledger_accounts a ON a.id = lp.account_id
+			WHERE e.payment_id = p.id AND e.kind = 'refund' AND a.code = 'merchant_payable')
+			- si.refunded_amount
+			+ (SELECT COALESCE(SUM(sa.amount), 0) FROM settlement_adjustments sa WHERE sa.payment_id = p.id) > 0
+		ORDER BY si.created_at
+		LIMIT 1
+		FOR UPDATE OF p SKIP LOCKED;
+-- name: OpenSettlementBatch :one
+INSERT INTO settlement_batches (merchant_id, currency, cutoff_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (merchant_id, currency) WHERE status = 'open' DO UPDATE SET updated_at = settlement_batches.updated_at
+		RETURNING *;
+-- name: CreateSettlementItem :one
+INSERT INTO settlement_items (payment_id, batch_id, amount, fee_amount, refunded_amount, net_amount)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING *;
+-- name: AddToSettlementBatch :one
+UPDATE settlement_batches SET payment_count = payment_count + 1,
+		gross_amount = gross_amount + sqlc.arg(amount), fee_amount = fee_amount + sqlc.arg(fee_amount),
+		refunded_amount = refunded_amount + sqlc.arg(refunded_amount), net_amount = net_amount + sqlc.arg(net_amount),
+		updated_at = CURRENT_TIMESTAMP
+		WHERE id = sqlc.arg(id)
+		RETURNING *;
+-- name: CreateSettlementAdjustment :one
+INSERT INTO settlement_adjustments (payment_id, batch_id, amount)
+		VALUES ($1, $2, $3)
+		RETURNING *;
+-- name: AddAdjustmentToSettlementBatch :one
+UPDATE settlement_batches SET adjustment_amount = adjustment_amount + sqlc.arg(amount), net_amount = net_amount + sqlc.arg(amount),
+		updated_at = CURRENT_TIMESTAMP
+		WHERE id = sqlc.arg(id)
+		RETURNING *;
+-- name: CloseSettlementBatch :one
+UPDATE settlement_batches SET status = 'closed', closed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'open'
+		RETURNING *;
+-- name: CloseDueSettlementBatches :many
+UPDATE settlement_batches SET status = 'closed', closed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE status = 'open' AND cutoff_at <= $1
+		RETURNING *;
+-- name: MarkSettlementBatchPaid :one
+UPDATE settlement_batches SET status = 'paid', paid_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND status = 'closed'
+		RETURNING *;
+-- name: GetSettlementBatchByID :one
+SELECT id, merchant_id, currency, status, cutoff_at, payment_count, gross_amount, fee_amount, refunded_amount, net_amount, closed_at, paid_at, created_at, updated_at, adjustment_amount FROM settlement_batches WHERE id = $1;
+-- name: GetSettlementBatchByIDForUpdate :one
+SELECT id, merchant_id, currency, status, cutoff_at, payment_count, gross_amount, fee_amount, refunded_amount, net_amount, closed_at, paid_at, created_at, updated_at, adjustment_amount FROM settlement_batches WHERE id = $1 FOR UPDATE;
+-- name: ListSettlementBatches :many
+SELECT id, merchant_id, currency, status, cutoff_at, payment_count, gross_amount, fee_amount, refunded_amount, net_amount, closed_at, paid_at, created_at, updated_at, adjustment_amount FROM settlement_batches
+		WHERE (sqlc.narg(merchant_id)::TEXT IS NULL OR merchant_id = sqlc.narg(merchant_id))
+			AND (sqlc.narg(status)::TEXT IS NULL OR status = sqlc.narg(status))
+		ORDER BY cutoff_at DESC, merchant_id, currency
+		LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+-- name: ListSettlementItems :many
+SELECT payment_id, batch_id, amount, fee_amount, refunded_amount, net_amount, created_at FROM settlement_items
+		WHERE batch_id = $1
+		ORDER BY created_at, payment_id
+		LIMIT $2 OFFSET $3;
+-- name: ListSettlementAdjustments :many
+SELECT id, payment_id, batch_id, amount, created_at FROM settlement_adjustments
+		WHERE batch_id = $1
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3;
@@ -0,0 +1,40 @@
+-- name: CreatePlan :one
+INSERT INTO plans (name, amount, currency, billing_interval, interval_count, trial_days)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING *;
+-- name: GetPlanByID :one
+SELECT id, name, amount, currency, billing_interval, interval_count, trial_days, created_at, updated_at FROM plans WHERE id = $1;
+-- name: ListPlans :many
+SELECT id, name, amount, currency, billing_interval, interval_count, trial_days, created_at, updated_at FROM plans
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2;
+-- name: CreateSubscription :one
+INSERT INTO subscriptions (plan_id, customer_id, status, payment_method, payment_method_details, billing_anchor, trial_end, current_period_start, current_period_end, next_billing_at, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING *;
+-- name: GetSubscriptionByID :one
+SELECT id, plan_id, customer_id, status, payment_method, payment_method_details, billing_anchor, trial_end, current_period_start, current_period_end, next_billing_at, cancel_at_period_end, canceled_at, pending_payment_id, failed_attempts, metadata, created_at, updated_at FROM subscriptions WHERE id = $1;
+-- name: GetSubscriptionByIDForUpdate :one
+SELECT id, plan_id, customer_id, status, payment_method, payment_method_details, billing_anchor, trial_end, current_period_start, current_period_end, next_billing_at, cancel_at_period_end, canceled_at, pending_payment_id, failed_attempts, metadata, created_at, updated_at FROM subscriptions WHERE id = $1 FOR UPDATE;
+-- name: ListSubscriptions :many
+SELECT id, plan_id, customer_id, status, payment_method, payment_method_details, billing_anchor, trial_end, current_period_start, current_period_end, next_billing_at, cancel_at_period_end, canceled_at, pending_payment_id, failed_attempts, metadata, created_at, updated_at FROM subscriptions
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2;
+-- name: UpdateSubscription :one
+UPDATE subscriptions SET status = $2, current_period_start = $3, current_period_end = $4, next_billing_at = $5,
+		cancel_at_period_end = $6, canceled_at = $7, pending_payment_id = $8, failed_attempts = $9, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1
+		RETURNING *;
+-- name: ClaimDueSubscription :one
+SELECT id, plan_id, customer_id, status, payment_method, payment_method_details, billing_anchor, trial_end, current_period_start, current_period_end, next_billing_at, cancel_at_period_end, canceled_at, pending_payment_id, failed_attempts, metadata, created_at, updated_at FROM subscriptions
+		WHERE next_billing_at <= $1 AND pending_payment_id IS NULL AND status <> 'canceled'
+		ORDER BY next_billing_at
+		LIMIT 1
+		FOR UPDATE SKIP LOCKED;
+-- name: ClaimSettledSubscription :one
+SELECT s.id, s.plan_id, s.customer_id, s.status, s.payment_method, s.payment_method_details, s.billing_anchor, s.trial_end, s.current_period_start, s.current_period_end, s.next_billing_at, s.cancel_at_period_end, s.canceled_at, s.pending_payment_id, s.failed_attempts, s.metadata, s.created_at, s.updated_at, p.status AS payment_status
+		FROM subscriptions s
+		JOIN payments p ON p.id = s.pending_payment_id
+		WHERE p.status IN ('SUCCESS', 'FAILED', 'CANCELED')
+		LIMIT 1
+		FOR UPDATE OF s SKIP LOCKED;
@@ -0,0 +1,209 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// reconciliationRepo is the Postgres implementation of
+// domain.ReconciliationRepo.
+type reconciliationRepo struct {
+	queries db.Querier
+}
+
+func NewReconciliationRepo(q db.Querier) domain.ReconciliationRepo {
+	return &reconciliationRepo{queries: q}
+}
+
+func (r *reconciliationRepo) FindPayment(ctx context.Context, reference string) (*domain.ReconciledPayment, error) {
+	row, err := r.queries.FindPaymentForStatementLine(ctx, reference)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &domain.ReconciledPayment{
+		ID:        row.ID,
+		Reference: row.Reference,
+		Amount:    row.Amount.InexactFloat64(),
+		Currency:  row.Currency,
+		Status:    domain.PaymentStatus(row.Status),
+	}, nil
+}
+
+func (r *reconciliationRepo) ListSuccessfulPayments(ctx context.Context, provider string, from, to time.Time) ([]domain.ReconciledPayment, error) {
+	rows, err := r.queries.ListSuccessfulPaymentsInPeriod(ctx, db.ListSuccessfulPaymentsInPeriodParams{
+		PeriodStart: timestamptz(from),
+		PeriodEnd:   timestamptz(to),
+		Provider:    textOrNull(provider),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	payments := make([]domain.ReconciledPayment, 0, len(rows))
+	for _, p := range rows {
+		payments = append(payments, domain.ReconciledPayment{
+			ID:        p.ID,
+			Reference: p.Reference,
+			Amount:    p.Amount.InexactFloat64(),
+			Currency:  p.Currency,
+			Status:    domain.StatusSuccess,
+		})
+	}
+	return payments, nil
+}
+
+func (r *reconciliationRepo) CreateRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	row, err := r.queries.CreateReconciliationRun(ctx, db.CreateReconciliationRunParams{
+		Format:                 string(run.Format),
+		Provider:               run.Provider,
+		PeriodStart:            timestamptzOrNull(run.PeriodStart),
+		PeriodEnd:              timestamptzOrNull(run.PeriodEnd),
+		LineCount:              int32(run.LineCount),
+		MatchedCount:           int32(run.MatchedCount),
+		MissingInternallyCount: int32(run.MissingInternallyCount),
+		MissingAtProviderCount: int32(run.MissingAtProviderCount),
+		AmountMismatchCount:    int32(run.AmountMismatchCount),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*run = *toDomainReconciliationRun(row)
+	return nil
+}
+
+func (r *reconciliationRepo) CreateItem(ctx context.Context, item *domain.ReconciliationItem) error {
+	row, err := r.queries.CreateReconciliationItem(ctx, db.CreateReconciliationItemParams{
+		RunID:             item.RunID,
+		Result:            string(item.Result),
+		Reference:         item.Reference,
+		PaymentID:         uuidOrNull(item.PaymentID),
+		StatementAmount:   decimalOrNull(item.StatementAmount),
+		StatementCurrency: textOrNull(item.StatementCurrency),
+		BookedAt:          timestamptzOrNull(item.BookedAt),
+		PaymentAmount:     decimalOrNull(item.PaymentAmount),
+		PaymentCurrency:   textOrNull(item.PaymentCurrency),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*item = toDomainReconciliationItem(row)
+	return nil
+}
+
+func (r *reconciliationRepo) GetRunByID(ctx context.Context, id uuid.UUID) (*domain.ReconciliationRun, error) {
+	row, err := r.queries.GetReconciliationRunByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainReconciliationRun(row), nil
+}
+
+func (r *reconciliationRepo) ListRuns(ctx context.Context, page domain.Page) ([]domain.ReconciliationRun, error) {
+	rows, err := r.queries.ListReconciliationRuns(ctx, db.ListReconciliationRunsParams{
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	runs := make([]domain.ReconciliationRun, 0, len(rows))
+	for _, row := range rows {
+		runs = append(runs, *toDomainReconciliationRun(row))
+	}
+	return runs, nil
+}
+
+func (r *reconciliationRepo) ListItems(ctx context.Context, runID uuid.UUID, result *domain.ReconciliationResult, page domain.Page) ([]domain.ReconciliationItem, error) {
+	params := db.ListReconciliationItemsParams{
+		RunID:       runID,
+		LimitCount:  int32(page.Limit),
+		OffsetCount: int32(page.Offset),
+	}
+	if result != nil {
+		params.Result = pgtype.Text{String: string(*result), Valid: true}
+	}
+	rows, err := r.queries.ListReconciliationItems(ctx, params)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainReconciliationItems(rows), nil
+}
+
+func (r *reconciliationRepo) GetItemByID(ctx context.Context, id uuid.UUID) (*domain.ReconciliationItem, error) {
+	row, err := r.queries.GetReconciliationItemByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	item := toDomainReconciliationItem(row)
+	return &item, nil
+}
+
+func (r *reconciliationRepo) ListUnresolvedItems(ctx context.Context, page domain.Page) ([]domain.ReconciliationItem, error) {
+	rows, err := r.queries.ListUnresolvedReconciliationItems(ctx, db.ListUnresolvedReconciliationItemsParams{
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainReconciliationItems(rows), nil
+}
+
+func (r *reconciliationRepo) ResolveItem(ctx context.Context, id uuid.UUID, note string) (*domain.ReconciliationItem, error) {
+	row, err := r.queries.ResolveReconciliationItem(ctx, db.ResolveReconciliationItemParams{
+		ID:             id,
+		ResolutionNote: textOrNull(note),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	item := toDomainReconciliationItem(row)
+	return &item, nil
+}
+
+func toDomainReconciliationRun(r db.ReconciliationRun) *domain.ReconciliationRun {
+	return &domain.ReconciliationRun{
+		ID:                     r.ID,
+		Format:                 domain.StatementFormat(r.Format),
+		Provider:               r.Provider,
+		PeriodStart:            timePtr(r.PeriodStart),
+		PeriodEnd:              timePtr(r.PeriodEnd),
+		LineCount:              int(r.LineCount),
+		MatchedCount:           int(r.MatchedCount),
+		MissingInternallyCount: int(r.MissingInternallyCount),
+		MissingAtProviderCount: int(r.MissingAtProviderCount),
+		AmountMismatchCount:    int(r.AmountMismatchCount),
+		CreatedAt:              r.CreatedAt.Time,
+	}
+}
+
+func toDomainReconciliationItems(rows []db.ReconciliationItem) []domain.ReconciliationItem {
+	items := make([]domain.ReconciliationItem, 0, len(rows))
+	for _, i := range rows {
+		items = append(items, toDomainReconciliationItem(i))
+	}
+	return items
+}
+
+func toDomainReconciliationItem(i db.ReconciliationItem) domain.ReconciliationItem {
+	return domain.ReconciliationItem{
+		ID:                i.ID,
+		RunID:             i.RunID,
+		Result:            domain.ReconciliationResult(i.Result),
+		Reference:         i.Reference,
+		PaymentID:         uuidPtr(i.PaymentID),
+		StatementAmount:   floatPtr(i.StatementAmount),
+		StatementCurrency: i.StatementCurrency.String,
+		BookedAt:          timePtr(i.BookedAt),
+		PaymentAmount:     floatPtr(i.PaymentAmount),
+		PaymentCurrency:   i.PaymentCurrency.String,
+		ResolvedAt:        timePtr(i.ResolvedAt),
+		ResolutionNote:    i.ResolutionNote.String,
+		CreatedAt:         i.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,128 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// reviewRepo is the Postgres implementation of domain.ReviewRepo.
+type reviewRepo struct {
+	queries db.Querier
+}
+
+func NewReviewRepo(q db.Querier) domain.ReviewRepo {
+	return &reviewRepo{queries: q}
+}
+
+func (r *reviewRepo) CreateReview(ctx context.Context, review *domain.PaymentReview) error {
+	rv, err := r.queries.CreatePaymentReview(ctx, review.PaymentID)
+	if err != nil {
+		return translateError(err)
+	}
+	*review = *toDomainReview(rv)
+	return nil
+}
+
+func (r *reviewRepo) GetReviewByID(ctx context.Context, id uuid.UUID) (*domain.PaymentReview, error) {
+	rv, err := r.queries.GetPaymentReviewByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainReview(rv), nil
+}
+
+func (r *reviewRepo) GetReviewByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.PaymentReview, error) {
+	rv, err := r.queries.GetPaymentReviewByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainReview(rv), nil
+}
+
+func (r *reviewRepo) ListReviews(ctx context.Context, filter domain.ReviewFilter, page domain.Page) ([]domain.PaymentReview, error) {
+	params := db.ListPaymentReviewsParams{
+		LimitCount:  int32(page.Limit),
+		OffsetCount: int32(page.Offset),
+	}
+	if filter.Status != nil {
+		params.Status = pgtype.Text{String: string(*filter.Status), Valid: true}
+	}
+	if filter.Escalated != nil {
+		params.Escalated = pgtype.Bool{Bool: *filter.Escalated, Valid: true}
+	}
+	rows, err := r.queries.ListPaymentReviews(ctx, params)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainReviews(rows), nil
+}
+
+func (r *reviewRepo) ClaimReview(ctx context.Context, id uuid.UUID, reviewer string) (*domain.PaymentReview, error) {
+	rv, err := r.queries.ClaimPaymentReview(ctx, db.ClaimPaymentReviewParams{
+		ID:        id,
+		ClaimedBy: textOrNull(reviewer),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainReview(rv), nil
+}
+
+func (r *reviewRepo) DecideReview(ctx context.Context, id uuid.UUID, decision domain.ReviewStatus, reviewer, notes string) (*domain.PaymentReview, error) {
+	rv, err := r.queries.DecidePaymentReview(ctx, db.DecidePaymentReviewParams{
+		ID:        id,
+		Status:    string(decision),
+		DecidedBy: textOrNull(reviewer),
+		Notes:     textOrNull(notes),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainReview(rv), nil
+}
+
+func (r *reviewRepo) EscalateOverdueReviews(ctx context.Context, before time.Time) ([]domain.PaymentReview, error) {
+	rows, err := r.queries.EscalateOverduePaymentReviews(ctx, timestamptz(before))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainReviews(rows), nil
+}
+
+func (r *reviewRepo) ClaimOverdueReview(ctx context.Context, before time.Time) (*domain.PaymentReview, error) {
+	rv, err := r.queries.LockOverduePaymentReview(ctx, timestamptz(before))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainReview(rv), nil
+}
+
+func toDomainReviews(rows []db.PaymentReview) []domain.PaymentReview {
+	reviews := make([]domain.PaymentReview, 0, len(rows))
+	for _, rv := range rows {
+		reviews = append(reviews, *toDomainReview(rv))
+	}
+	return reviews
+}
+
+func toDomainReview(rv db.PaymentReview) *domain.PaymentReview {
+	return &domain.PaymentReview{
+		ID:          rv.ID,
+		PaymentID:   rv.PaymentID,
+		Status:      domain.ReviewStatus(rv.Status),
+		ClaimedBy:   rv.ClaimedBy.String,
+		ClaimedAt:   timePtr(rv.ClaimedAt),
+		DecidedBy:   rv.DecidedBy.String,
+		DecidedAt:   timePtr(rv.DecidedAt),
+		Notes:       rv.Notes.String,
+		EscalatedAt: timePtr(rv.EscalatedAt),
+		CreatedAt:   rv.CreatedAt.Time,
+		UpdatedAt:   rv.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,211 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// riskRepo is the Postgres implementation of domain.RiskRepo.
+type riskRepo struct {
+	queries db.Querier
+}
+
+func NewRiskRepo(q db.Querier) domain.RiskRepo {
+	return &riskRepo{queries: q}
+}
+
+func (r *riskRepo) CreateRule(ctx context.Context, rule *domain.RiskRule) error {
+	rr, err := r.queries.CreateRiskRule(ctx, db.CreateRiskRuleParams{
+		Name:       rule.Name,
+		Expression: rule.Expression,
+		Action:     string(rule.Action),
+		Score:      int32(rule.Score),
+		Enabled:    rule.Enabled,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*rule = toDomainRiskRule(rr)
+	return nil
+}
+
+func (r *riskRepo) GetRuleByID(ctx context.Context, id uuid.UUID) (*domain.RiskRule, error) {
+	rr, err := r.queries.GetRiskRuleByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	rule := toDomainRiskRule(rr)
+	return &rule, nil
+}
+
+func (r *riskRepo) ListRules(ctx context.Context, page domain.Page) ([]domain.RiskRule, error) {
+	rows, err := r.queries.ListRiskRules(ctx, db.ListRiskRulesParams{
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainRiskRules(rows), nil
+}
+
+func (r *riskRepo) ListEnabledRules(ctx context.Context) ([]domain.RiskRule, error) {
+	rows, err := r.queries.ListEnabledRiskRules(ctx)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainRiskRules(rows), nil
+}
+
+func (r *riskRepo) UpdateRule(ctx context.Context, rule *domain.RiskRule) error {
+	rr, err := r.queries.UpdateRiskRule(ctx, db.UpdateRiskRuleParams{
+		ID:         rule.ID,
+		Name:       rule.Name,
+		Expression: rule.Expression,
+		Action:     string(rule.Action),
+		Score:      int32(rule.Score),
+		Enabled:    rule.Enabled,
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*rule = toDomainRiskRule(rr)
+	return nil
+}
+
+func (r *riskRepo) DeleteRule(ctx context.Context, id uuid.UUID) error {
+	n, err := r.queries.DeleteRiskRule(ctx, id)
+	if err != nil {
+		return translateError(err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %w", domain.ErrNotFound, pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *riskRepo) CreateBlocklistEntry(ctx context.Context, entry *domain.BlocklistEntry) error {
+	e, err := r.queries.CreateRiskBlocklistEntry(ctx, db.CreateRiskBlocklistEntryParams{
+		Kind:   string(entry.Kind),
+		Value:  entry.Value,
+		Reason: textOrNull(entry.Reason),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*entry = toDomainBlocklistEntry(e)
+	return nil
+}
+
+func (r *riskRepo) ListBlocklistEntries(ctx context.Context, kind *domain.BlocklistKind, page domain.Page) ([]domain.BlocklistEntry, error) {
+	params := db.ListRiskBlocklistEntriesParams{
+		LimitCount:  int32(page.Limit),
+		OffsetCount: int32(page.Offset),
+	}
+	if kind != nil {
+		params.Kind = pgtype.Text{String: string(*kind), Valid: true}
+	}
+	rows, err := r.queries.ListRiskBlocklistEntries(ctx, params)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	entries := make([]domain.BlocklistEntry, 0, len(rows))
+	for _, e := range rows {
+		entries = append(entries, toDomainBlocklistEntry(e))
+	}
+	return entries, nil
+}
+
+func (r *riskRepo) DeleteBlocklistEntry(ctx context.Context, id uuid.UUID) error {
+	n, err := r.queries.DeleteRiskBlocklistEntry(ctx, id)
+	if err != nil {
+		return translateError(err)
+	}
+	if n == 0 {
+		return fmt.Errorf("%w: %w", domain.ErrNotFound, pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (r *riskRepo) IsBlocklisted(ctx context.Context, kind domain.BlocklistKind, value string) (bool, error) {
+	blocked, err := r.queries.IsBlocklisted(ctx, db.IsBlocklistedParams{
+		Kind:  string(kind),
+		Value: value,
+	})
+	if err != nil {
+		return false, translateError(err)
+	}
+	return blocked, nil
+}
+
+func (r *riskRepo) CountPaymentsByClientIP(ctx context.Context, ip string, since time.Time) (int, error) {
+	n, err := r.queries.CountPaymentsByClientIPSince(ctx, db.CountPaymentsByClientIPSinceParams{
+		ClientIP: textOrNull(ip),
+		Since:    timestamptz(since),
+	})
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return int(n), nil
+}
+
+func (r *riskRepo) CountPaymentsByCustomer(ctx context.Context, customerID uuid.UUID, since time.Time) (int, error) {
+	n, err := r.queries.CountPaymentsByCustomerSince(ctx, db.CountPaymentsByCustomerSinceParams{
+		CustomerID: uuidOrNull(&customerID),
+		Since:      timestamptz(since),
+	})
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return int(n), nil
+}
+
+func (r *riskRepo) CountPaymentsByReferencePrefix(ctx context.Context, prefix string, since time.Time) (int, error) {
+	n, err := r.queries.CountPaymentsByReferencePrefixSince(ctx, db.CountPaymentsByReferencePrefixSinceParams{
+		Prefix: prefix,
+		Since:  timestamptz(since),
+	})
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return int(n), nil
+}
+
+func toDomainRiskRules(rows []db.RiskRule) []domain.RiskRule {
+	rules := make([]domain.RiskRule, 0, len(rows))
+	for _, rr := range rows {
+		rules = append(rules, toDomainRiskRule(rr))
+	}
+	return rules
+}
+
+func toDomainRiskRule(rr db.RiskRule) domain.RiskRule {
+	return domain.RiskRule{
+		ID:         rr.ID,
+		Name:       rr.Name,
+		Expression: rr.Expression,
+		Action:     domain.RiskOutcome(rr.Action),
+		Score:      int(rr.Score),
+		Enabled:    rr.Enabled,
+		CreatedAt:  rr.CreatedAt.Time,
+		UpdatedAt:  rr.UpdatedAt.Time,
+	}
+}
+
+func toDomainBlocklistEntry(e db.RiskBlocklist) domain.BlocklistEntry {
+	return domain.BlocklistEntry{
+		ID:        e.ID,
+		Kind:      domain.BlocklistKind(e.Kind),
+		Value:     e.Value,
+		Reason:    e.Reason.String,
+		CreatedAt: e.CreatedAt.Time,
+	}
+}
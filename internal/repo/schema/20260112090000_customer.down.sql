@@ -0,0 +1,2 @@
+ALTER TABLE payments DROP COLUMN customer_id;
+DROP TABLE customers;
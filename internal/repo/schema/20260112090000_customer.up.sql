@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS customers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    phone VARCHAR(32),
+    external_id VARCHAR(255) UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_customers_email ON customers(email);
+
+ALTER TABLE payments ADD COLUMN customer_id UUID REFERENCES customers(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_payments_customer_id ON payments(customer_id, created_at DESC);
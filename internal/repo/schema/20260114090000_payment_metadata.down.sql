@@ -0,0 +1,2 @@
+DROP INDEX idx_payments_metadata;
+ALTER TABLE payments DROP COLUMN metadata;
@@ -0,0 +1,4 @@
+ALTER TABLE payments ADD COLUMN metadata JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+-- jsonb_path_ops supports the @> containment used by metadata filters
+CREATE INDEX idx_payments_metadata ON payments USING GIN (metadata jsonb_path_ops);
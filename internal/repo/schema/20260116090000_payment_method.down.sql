@@ -0,0 +1,3 @@
+ALTER TABLE payments DROP COLUMN payment_method_details;
+ALTER TABLE payments DROP COLUMN payment_method;
+DROP TYPE payment_method_type;
@@ -0,0 +1,5 @@
+CREATE TYPE payment_method_type AS ENUM ('card', 'bank_transfer', 'mobile_money', 'wallet');
+
+-- Details are kept per method type; card details hold a vault token, never the PAN.
+ALTER TABLE payments ADD COLUMN payment_method payment_method_type;
+ALTER TABLE payments ADD COLUMN payment_method_details JSONB;
@@ -0,0 +1,2 @@
+DROP TABLE payment_attempts;
+ALTER TABLE payments DROP COLUMN provider;
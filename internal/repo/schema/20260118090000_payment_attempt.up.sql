@@ -0,0 +1,13 @@
+ALTER TABLE payments ADD COLUMN provider VARCHAR(64);
+
+CREATE TABLE IF NOT EXISTS payment_attempts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE CASCADE,
+    provider VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('succeeded', 'declined', 'failed', 'unavailable')),
+    provider_reference VARCHAR(255),
+    error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_attempts_payment_id ON payment_attempts(payment_id, created_at);
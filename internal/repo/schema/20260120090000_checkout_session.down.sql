@@ -0,0 +1 @@
+DROP TABLE checkout_sessions;
@@ -0,0 +1,2 @@
+ALTER TABLE payments DROP COLUMN payment_link_id;
+DROP TABLE payment_links;
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS payment_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    slug VARCHAR(32) NOT NULL UNIQUE,
+    -- NULL lets the payer enter the amount
+    amount DECIMAL(10, 2),
+    currency VARCHAR(3) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    max_uses INTEGER,
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    success_url TEXT,
+    cancel_url TEXT,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE payments ADD COLUMN payment_link_id UUID REFERENCES payment_links(id) ON DELETE RESTRICT;
+
+CREATE INDEX idx_payments_payment_link_id ON payments(payment_link_id, created_at DESC);
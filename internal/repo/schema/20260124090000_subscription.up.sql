@@ -0,0 +1,39 @@
+CREATE TABLE IF NOT EXISTS plans (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    billing_interval VARCHAR(8) NOT NULL CHECK (billing_interval IN ('day', 'week', 'month', 'year')),
+    interval_count INTEGER NOT NULL DEFAULT 1 CHECK (interval_count > 0),
+    trial_days INTEGER NOT NULL DEFAULT 0 CHECK (trial_days >= 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS subscriptions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    plan_id UUID NOT NULL REFERENCES plans(id) ON DELETE RESTRICT,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE RESTRICT,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('trialing', 'active', 'past_due', 'canceled')),
+    payment_method payment_method_type NOT NULL,
+    payment_method_details JSONB NOT NULL,
+    -- Cycles are billed at billing_anchor plus whole intervals
+    billing_anchor TIMESTAMP WITH TIME ZONE NOT NULL,
+    trial_end TIMESTAMP WITH TIME ZONE,
+    current_period_start TIMESTAMP WITH TIME ZONE NOT NULL,
+    current_period_end TIMESTAMP WITH TIME ZONE NOT NULL,
+    -- NULL once canceled
+    next_billing_at TIMESTAMP WITH TIME ZONE,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT FALSE,
+    canceled_at TIMESTAMP WITH TIME ZONE,
+    -- The cycle payment the worker has not settled yet
+    pending_payment_id UUID REFERENCES payments(id) ON DELETE SET NULL,
+    failed_attempts INTEGER NOT NULL DEFAULT 0,
+    metadata JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_subscriptions_customer_id ON subscriptions(customer_id, created_at DESC);
+CREATE INDEX idx_subscriptions_due ON subscriptions(next_billing_at) WHERE pending_payment_id IS NULL;
+CREATE INDEX idx_subscriptions_pending_payment_id ON subscriptions(pending_payment_id) WHERE pending_payment_id IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP TABLE ledger_postings;
+DROP TABLE journal_entries;
+DROP TABLE ledger_accounts;
+DROP FUNCTION ledger_append_only();
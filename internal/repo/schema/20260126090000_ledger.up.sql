@@ -0,0 +1,64 @@
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(64) NOT NULL,
+    type VARCHAR(16) NOT NULL CHECK (type IN ('asset', 'liability', 'revenue', 'expense')),
+    currency VARCHAR(3) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (code, currency)
+);
+
+CREATE TABLE IF NOT EXISTS journal_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(32) NOT NULL,
+    payment_id UUID REFERENCES payments(id) ON DELETE RESTRICT,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Debits are positive and credits negative, so a balanced entry sums to
+-- zero in each currency
+CREATE TABLE IF NOT EXISTS ledger_postings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    journal_entry_id UUID NOT NULL REFERENCES journal_entries(id) ON DELETE RESTRICT,
+    account_id UUID NOT NULL REFERENCES ledger_accounts(id) ON DELETE RESTRICT,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount <> 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_journal_entries_payment_id ON journal_entries(payment_id) WHERE payment_id IS NOT NULL;
+-- A payment is captured at most once
+CREATE UNIQUE INDEX idx_journal_entries_capture ON journal_entries(payment_id) WHERE kind = 'payment_captured';
+CREATE INDEX idx_ledger_postings_journal_entry_id ON ledger_postings(journal_entry_id);
+CREATE INDEX idx_ledger_postings_account_id ON ledger_postings(account_id);
+
+-- The ledger is append-only: mistakes are corrected with new entries
+CREATE FUNCTION ledger_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER journal_entries_append_only BEFORE UPDATE OR DELETE ON journal_entries
+    FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+CREATE TRIGGER ledger_postings_append_only BEFORE UPDATE OR DELETE ON ledger_postings
+    FOR EACH ROW EXECUTE FUNCTION ledger_append_only();
+
+-- Record the payments that succeeded before the ledger existed
+INSERT INTO ledger_accounts (code, type, currency)
+SELECT DISTINCT a.code, a.type, p.currency
+    FROM payments p
+    CROSS JOIN (VALUES ('provider_receivable', 'asset'), ('merchant_payable', 'liability')) AS a(code, type)
+    WHERE p.status = 'SUCCESS';
+
+WITH captured AS (
+    INSERT INTO journal_entries (kind, payment_id, description, created_at)
+    SELECT 'payment_captured', id, 'Payment ' || reference || ' captured', updated_at
+        FROM payments
+        WHERE status = 'SUCCESS'
+    RETURNING id, payment_id, created_at
+)
+INSERT INTO ledger_postings (journal_entry_id, account_id, amount, created_at)
+SELECT c.id, a.id, CASE a.code WHEN 'provider_receivable' THEN p.amount ELSE -p.amount END, c.created_at
+    FROM captured c
+    JOIN payments p ON p.id = c.payment_id
+    JOIN ledger_accounts a ON a.currency = p.currency AND a.code IN ('provider_receivable', 'merchant_payable');
@@ -0,0 +1,2 @@
+ALTER TABLE payments DROP COLUMN net_amount, DROP COLUMN fee_amount;
+DROP TABLE fee_schedules;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS fee_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    currency VARCHAR(3) NOT NULL,
+    -- NULL matches every payment method
+    payment_method payment_method_type,
+    -- NULL matches every merchant
+    merchant_id TEXT,
+    -- Percent of the amount, e.g. 2.9 for 2.9%
+    percentage DECIMAL(7, 4) NOT NULL DEFAULT 0 CHECK (percentage >= 0 AND percentage <= 100),
+    fixed_amount DECIMAL(10, 2) NOT NULL DEFAULT 0 CHECK (fixed_amount >= 0),
+    min_fee DECIMAL(10, 2) CHECK (min_fee >= 0),
+    max_fee DECIMAL(10, 2) CHECK (max_fee >= 0),
+    effective_from TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- NULL keeps the schedule in force until a newer one replaces it
+    effective_to TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CHECK (min_fee IS NULL OR max_fee IS NULL OR min_fee <= max_fee),
+    CHECK (effective_to IS NULL OR effective_to > effective_from)
+);
+
+CREATE INDEX idx_fee_schedules_currency ON fee_schedules(currency, effective_from DESC);
+
+-- Set when a payment succeeds; NULL for payments that have not
+ALTER TABLE payments ADD COLUMN fee_amount DECIMAL(10, 2), ADD COLUMN net_amount DECIMAL(10, 2);
@@ -0,0 +1,35 @@
+CREATE TABLE IF NOT EXISTS settlement_batches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    -- Empty for payments without merchant_id metadata
+    merchant_id TEXT NOT NULL DEFAULT '',
+    currency VARCHAR(3) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed', 'paid')),
+    -- The batch takes payments that succeed before its cutoff
+    cutoff_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    payment_count INTEGER NOT NULL DEFAULT 0,
+    gross_amount DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    fee_amount DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    refunded_amount DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    net_amount DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- A merchant has at most one open batch per currency
+CREATE UNIQUE INDEX idx_settlement_batches_open ON settlement_batches(merchant_id, currency) WHERE status = 'open';
+CREATE INDEX idx_settlement_batches_cutoff ON settlement_batches(cutoff_at DESC);
+
+CREATE TABLE IF NOT EXISTS settlement_items (
+    -- The primary key keeps a payment from landing in two batches
+    payment_id UUID PRIMARY KEY REFERENCES payments(id) ON DELETE RESTRICT,
+    batch_id UUID NOT NULL REFERENCES settlement_batches(id) ON DELETE RESTRICT,
+    amount DECIMAL(10, 2) NOT NULL,
+    fee_amount DECIMAL(10, 2) NOT NULL,
+    refunded_amount DECIMAL(10, 2) NOT NULL,
+    net_amount DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_settlement_items_batch_id ON settlement_items(batch_id, created_at);
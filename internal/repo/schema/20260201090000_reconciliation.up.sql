@@ -0,0 +1,38 @@
+CREATE TABLE IF NOT EXISTS reconciliation_runs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    format VARCHAR(16) NOT NULL,
+    -- Empty when the statement covers every provider
+    provider TEXT NOT NULL DEFAULT '',
+    -- NULL when the statement has no dates to bound it
+    period_start TIMESTAMP WITH TIME ZONE,
+    period_end TIMESTAMP WITH TIME ZONE,
+    line_count INTEGER NOT NULL,
+    matched_count INTEGER NOT NULL,
+    missing_internally_count INTEGER NOT NULL,
+    missing_at_provider_count INTEGER NOT NULL,
+    amount_mismatch_count INTEGER NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_reconciliation_runs_created_at ON reconciliation_runs(created_at DESC);
+
+CREATE TABLE IF NOT EXISTS reconciliation_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    run_id UUID NOT NULL REFERENCES reconciliation_runs(id) ON DELETE CASCADE,
+    result VARCHAR(24) NOT NULL CHECK (result IN ('matched', 'missing_internally', 'missing_at_provider', 'amount_mismatch')),
+    reference TEXT NOT NULL,
+    payment_id UUID REFERENCES payments(id) ON DELETE RESTRICT,
+    -- NULL for payments missing from the statement
+    statement_amount DECIMAL(12, 2),
+    statement_currency VARCHAR(3),
+    booked_at TIMESTAMP WITH TIME ZONE,
+    -- NULL for lines without a payment
+    payment_amount DECIMAL(10, 2),
+    payment_currency VARCHAR(3),
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolution_note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_reconciliation_items_run_id ON reconciliation_items(run_id, result);
+CREATE INDEX idx_reconciliation_items_unresolved ON reconciliation_items(created_at) WHERE result <> 'matched' AND resolved_at IS NULL;
@@ -0,0 +1,2 @@
+DROP TABLE dispute_evidence;
+DROP TABLE disputes;
@@ -0,0 +1,5 @@
+DROP INDEX idx_payments_created_at;
+DROP INDEX idx_payments_client_ip;
+ALTER TABLE payments DROP COLUMN risk_rules, DROP COLUMN risk_outcome, DROP COLUMN risk_score, DROP COLUMN client_ip;
+DROP TABLE risk_blocklist;
+DROP TABLE risk_rules;
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS risk_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE,
+    -- A boolean expression in the rule language, checked when the rule is saved
+    expression TEXT NOT NULL,
+    -- What a match does to the payment
+    action VARCHAR(16) NOT NULL CHECK (action IN ('review', 'block')),
+    -- Added to the payment's risk score on a match
+    score INT NOT NULL DEFAULT 0 CHECK (score >= 0 AND score <= 100),
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS risk_blocklist (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind VARCHAR(16) NOT NULL CHECK (kind IN ('ip', 'email', 'customer')),
+    -- Emails are stored lowercased
+    value VARCHAR(255) NOT NULL,
+    reason TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (kind, value)
+);
+
+-- NULL on payments created before screening or without it
+ALTER TABLE payments ADD COLUMN client_ip VARCHAR(45),
+    ADD COLUMN risk_score INT,
+    ADD COLUMN risk_outcome VARCHAR(16) CHECK (risk_outcome IN ('allow', 'review', 'block')),
+    ADD COLUMN risk_rules TEXT[];
+
+-- Velocity rules count recent payments by IP, customer or reference prefix
+CREATE INDEX idx_payments_client_ip ON payments(client_ip, created_at);
+CREATE INDEX idx_payments_created_at ON payments(created_at);
@@ -0,0 +1,10 @@
+DROP TABLE payment_reviews;
+
+-- Enum values cannot be dropped, so the type is rebuilt without IN_REVIEW
+UPDATE payments SET status = 'FAILED' WHERE status = 'IN_REVIEW';
+ALTER TYPE paymentStatus RENAME TO paymentStatus_old;
+CREATE TYPE paymentStatus AS ENUM ('PENDING', 'SUCCESS', 'FAILED');
+ALTER TABLE payments ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE paymentStatus USING status::TEXT::paymentStatus,
+    ALTER COLUMN status SET DEFAULT 'PENDING';
+DROP TYPE paymentStatus_old;
@@ -0,0 +1,19 @@
+-- Payments held for a person to review before the worker charges them
+ALTER TYPE paymentStatus ADD VALUE IF NOT EXISTS 'IN_REVIEW';
+
+CREATE TABLE IF NOT EXISTS payment_reviews (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id UUID NOT NULL UNIQUE REFERENCES payments(id) ON DELETE RESTRICT,
+    status VARCHAR(16) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'claimed', 'approved', 'declined')),
+    claimed_by VARCHAR(100),
+    claimed_at TIMESTAMP WITH TIME ZONE,
+    -- 'sla' when the review was declined for being left untouched
+    decided_by VARCHAR(100),
+    decided_at TIMESTAMP WITH TIME ZONE,
+    notes TEXT,
+    escalated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_payment_reviews_status ON payment_reviews(status, created_at);
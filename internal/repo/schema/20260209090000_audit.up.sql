@@ -0,0 +1,49 @@
+-- Entries are appended by the transaction making the change. A sealer later
+-- numbers them and chains each one to the previous by hash; a serializable
+-- transaction cannot read the chain head reliably while other writers are
+-- still open, so the chain is built after they commit
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor VARCHAR(128) NOT NULL,
+    action VARCHAR(64) NOT NULL,
+    target_type VARCHAR(32) NOT NULL,
+    target_id VARCHAR(64) NOT NULL,
+    request_id VARCHAR(128) NOT NULL DEFAULT '',
+    source_ip VARCHAR(64) NOT NULL DEFAULT '',
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    seq BIGINT UNIQUE,
+    prev_hash CHAR(64),
+    hash CHAR(64) UNIQUE,
+    sealed_at TIMESTAMP WITH TIME ZONE,
+    CHECK ((seq IS NULL) = (hash IS NULL) AND (seq IS NULL) = (prev_hash IS NULL))
+);
+
+CREATE INDEX idx_audit_log_target ON audit_log(target_type, target_id, id);
+CREATE INDEX idx_audit_log_actor ON audit_log(actor, id);
+CREATE INDEX idx_audit_log_request_id ON audit_log(request_id) WHERE request_id <> '';
+CREATE INDEX idx_audit_log_unsealed ON audit_log(id) WHERE seq IS NULL;
+
+-- The log is append-only. The one update allowed seals an entry, setting
+-- its chain columns once and leaving everything else as it was
+CREATE FUNCTION audit_log_append_only() RETURNS trigger AS $$
+BEGIN
+    IF TG_OP = 'UPDATE' THEN
+        IF OLD.seq IS NULL
+            AND (NEW.id, NEW.actor, NEW.action, NEW.target_type, NEW.target_id, NEW.request_id,
+                 NEW.source_ip, NEW.before, NEW.after, NEW.created_at)
+            IS NOT DISTINCT FROM
+                (OLD.id, OLD.actor, OLD.action, OLD.target_type, OLD.target_id, OLD.request_id,
+                 OLD.source_ip, OLD.before, OLD.after, OLD.created_at) THEN
+            RETURN NEW;
+        END IF;
+    END IF;
+    RAISE EXCEPTION '% is append-only', TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_append_only BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_append_only();
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log
+    FOR EACH STATEMENT EXECUTE FUNCTION audit_log_append_only();
@@ -0,0 +1,8 @@
+-- Enum values cannot be dropped, so the type is rebuilt without CANCELED
+UPDATE payments SET status = 'FAILED' WHERE status = 'CANCELED';
+ALTER TYPE paymentStatus RENAME TO paymentStatus_old;
+CREATE TYPE paymentStatus AS ENUM ('PENDING', 'SUCCESS', 'FAILED', 'IN_REVIEW');
+ALTER TABLE payments ALTER COLUMN status DROP DEFAULT,
+    ALTER COLUMN status TYPE paymentStatus USING status::TEXT::paymentStatus,
+    ALTER COLUMN status SET DEFAULT 'PENDING';
+DROP TYPE paymentStatus_old;
@@ -0,0 +1,2 @@
+-- Payments stopped by the merchant before the worker charged them
+ALTER TYPE paymentStatus ADD VALUE IF NOT EXISTS 'CANCELED';
@@ -0,0 +1,4 @@
+DROP INDEX idx_customers_merchant_id;
+ALTER TABLE customers DROP CONSTRAINT customers_merchant_external_id_key;
+ALTER TABLE customers ADD CONSTRAINT customers_external_id_key UNIQUE (external_id);
+ALTER TABLE customers DROP COLUMN merchant_id;
@@ -0,0 +1,17 @@
+-- Empty for payers of payments made without a merchant API key
+ALTER TABLE customers ADD COLUMN merchant_id TEXT NOT NULL DEFAULT '';
+
+-- Existing customers take the merchant of their payments or subscriptions
+UPDATE customers c SET merchant_id = m.merchant_id
+    FROM (SELECT customer_id, metadata->>'merchant_id' AS merchant_id FROM payments
+            WHERE customer_id IS NOT NULL AND metadata ? 'merchant_id'
+          UNION
+          SELECT customer_id, metadata->>'merchant_id' FROM subscriptions
+            WHERE metadata ? 'merchant_id') m
+    WHERE m.customer_id = c.id;
+
+-- External IDs come from each merchant's own system, so they are unique per merchant
+ALTER TABLE customers DROP CONSTRAINT customers_external_id_key;
+ALTER TABLE customers ADD CONSTRAINT customers_merchant_external_id_key UNIQUE (merchant_id, external_id);
+
+CREATE INDEX idx_customers_merchant_id ON customers(merchant_id, created_at DESC);
@@ -0,0 +1,2 @@
+DROP TABLE settlement_adjustments;
+ALTER TABLE settlement_batches DROP COLUMN adjustment_amount;
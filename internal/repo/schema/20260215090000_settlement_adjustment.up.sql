@@ -0,0 +1,15 @@
+-- Refunds and chargebacks posted after a payment was batched, carried into
+-- the merchant's next open batch
+ALTER TABLE settlement_batches ADD COLUMN adjustment_amount DECIMAL(14, 2) NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS settlement_adjustments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id UUID NOT NULL REFERENCES payments(id) ON DELETE RESTRICT,
+    batch_id UUID NOT NULL REFERENCES settlement_batches(id) ON DELETE RESTRICT,
+    -- Negative: what the batch pays out less
+    amount DECIMAL(10, 2) NOT NULL CHECK (amount < 0),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_settlement_adjustments_payment_id ON settlement_adjustments(payment_id);
+CREATE INDEX idx_settlement_adjustments_batch_id ON settlement_adjustments(batch_id, created_at);
@@ -0,0 +1 @@
+DROP INDEX idx_checkout_sessions_open_expires_at;
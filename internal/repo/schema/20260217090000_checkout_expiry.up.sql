@@ -0,0 +1,2 @@
+-- The worker looks up open sessions past their expiry
+CREATE INDEX idx_checkout_sessions_open_expires_at ON checkout_sessions(expires_at) WHERE status = 'open';
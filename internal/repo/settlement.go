@@ -0,0 +1,253 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/shopspring/decimal"
+)
+
+// settlementRepo is the Postgres implementation of domain.SettlementRepo.
+type settlementRepo struct {
+	queries db.Querier
+}
+
+func NewSettlementRepo(q db.Querier) domain.SettlementRepo {
+	return &settlementRepo{queries: q}
+}
+
+func (r *settlementRepo) ClaimUnsettledPayment(ctx context.Context) (*domain.Payment, float64, error) {
+	row, err := r.queries.ClaimUnsettledPayment(ctx)
+	if err != nil {
+		return nil, 0, translateError(err)
+	}
+	p := &domain.Payment{
+		ID:        row.ID,
+		Amount:    row.Amount.InexactFloat64(),
+		Currency:  row.Currency,
+		Status:    domain.StatusSuccess,
+		Metadata:  decodeMetadata(row.Metadata),
+		FeeAmount: floatPtr(row.FeeAmount),
+		NetAmount: floatPtr(row.NetAmount),
+		UpdatedAt: row.UpdatedAt.Time,
+	}
+	return p, row.RefundedAmount.InexactFloat64(), nil
+}
+
+func (r *settlementRepo) ClaimUnsettledRefund(ctx context.Context) (*domain.Payment, float64, error) {
+	row, err := r.queries.ClaimUnsettledRefund(ctx)
+	if err != nil {
+		return nil, 0, translateError(err)
+	}
+	p := &domain.Payment{
+		ID:       row.ID,
+		Currency: row.Currency,
+		Metadata: decodeMetadata(row.Metadata),
+	}
+	return p, row.UnsettledAmount.InexactFloat64(), nil
+}
+
+func (r *settlementRepo) OpenBatch(ctx context.Context, merchantID, currency string, cutoff time.Time) (*domain.SettlementBatch, error) {
+	b, err := r.queries.OpenSettlementBatch(ctx, db.OpenSettlementBatchParams{
+		MerchantID: merchantID,
+		Currency:   currency,
+		CutoffAt:   timestamptz(cutoff),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSettlementBatch(b), nil
+}
+
+func (r *settlementRepo) AddItem(ctx context.Context, item *domain.SettlementItem) (*domain.SettlementBatch, error) {
+	i, err := r.queries.CreateSettlementItem(ctx, db.CreateSettlementItemParams{
+		PaymentID:      item.PaymentID,
+		BatchID:        item.BatchID,
+		Amount:         decimal.NewFromFloat(item.Amount),
+		FeeAmount:      decimal.NewFromFloat(item.FeeAmount),
+		RefundedAmount: decimal.NewFromFloat(item.RefundedAmount),
+		NetAmount:      decimal.NewFromFloat(item.NetAmount),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	*item = toDomainSettlementItem(i)
+
+	b, err := r.queries.AddToSettlementBatch(ctx, db.AddToSettlementBatchParams{
+		Amount:         i.Amount,
+		FeeAmount:      i.FeeAmount,
+		RefundedAmount: i.RefundedAmount,
+		NetAmount:      i.NetAmount,
+		ID:             i.BatchID,
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSettlementBatch(b), nil
+}
+
+func (r *settlementRepo) AddAdjustment(ctx context.Context, adjustment *domain.SettlementAdjustment) (*domain.SettlementBatch, error) {
+	a, err := r.queries.CreateSettlementAdjustment(ctx, db.CreateSettlementAdjustmentParams{
+		PaymentID: adjustment.PaymentID,
+		BatchID:   adjustment.BatchID,
+		Amount:    decimal.NewFromFloat(adjustment.Amount),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	*adjustment = toDomainSettlementAdjustment(a)
+
+	b, err := r.queries.AddAdjustmentToSettlementBatch(ctx, db.AddAdjustmentToSettlementBatchParams{
+		Amount: a.Amount,
+		ID:     a.BatchID,
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSettlementBatch(b), nil
+}
+
+func (r *settlementRepo) CloseBatch(ctx context.Context, id uuid.UUID) (*domain.SettlementBatch, error) {
+	b, err := r.queries.CloseSettlementBatch(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSettlementBatch(b), nil
+}
+
+func (r *settlementRepo) CloseDueBatches(ctx context.Context, now time.Time) ([]domain.SettlementBatch, error) {
+	rows, err := r.queries.CloseDueSettlementBatches(ctx, timestamptz(now))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSettlementBatches(rows), nil
+}
+
+func (r *settlementRepo) MarkBatchPaid(ctx context.Context, id uuid.UUID) (*domain.SettlementBatch, error) {
+	b, err := r.queries.MarkSettlementBatchPaid(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSettlementBatch(b), nil
+}
+
+func (r *settlementRepo) GetBatchByID(ctx context.Context, id uuid.UUID) (*domain.SettlementBatch, error) {
+	b, err := r.queries.GetSettlementBatchByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSettlementBatch(b), nil
+}
+
+func (r *settlementRepo) GetBatchByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.SettlementBatch, error) {
+	b, err := r.queries.GetSettlementBatchByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSettlementBatch(b), nil
+}
+
+func (r *settlementRepo) ListBatches(ctx context.Context, filter domain.SettlementFilter, page domain.Page) ([]domain.SettlementBatch, error) {
+	params := db.ListSettlementBatchesParams{
+		LimitCount:  int32(page.Limit),
+		OffsetCount: int32(page.Offset),
+	}
+	if filter.MerchantID != nil {
+		params.MerchantID = pgtype.Text{String: *filter.MerchantID, Valid: true}
+	}
+	if filter.Status != nil {
+		params.Status = pgtype.Text{String: string(*filter.Status), Valid: true}
+	}
+	rows, err := r.queries.ListSettlementBatches(ctx, params)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSettlementBatches(rows), nil
+}
+
+func (r *settlementRepo) ListItems(ctx context.Context, batchID uuid.UUID, page domain.Page) ([]domain.SettlementItem, error) {
+	rows, err := r.queries.ListSettlementItems(ctx, db.ListSettlementItemsParams{
+		BatchID: batchID,
+		Limit:   int32(page.Limit),
+		Offset:  int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	items := make([]domain.SettlementItem, 0, len(rows))
+	for _, i := range rows {
+		items = append(items, toDomainSettlementItem(i))
+	}
+	return items, nil
+}
+
+func (r *settlementRepo) ListAdjustments(ctx context.Context, batchID uuid.UUID, page domain.Page) ([]domain.SettlementAdjustment, error) {
+	rows, err := r.queries.ListSettlementAdjustments(ctx, db.ListSettlementAdjustmentsParams{
+		BatchID: batchID,
+		Limit:   int32(page.Limit),
+		Offset:  int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	adjustments := make([]domain.SettlementAdjustment, 0, len(rows))
+	for _, a := range rows {
+		adjustments = append(adjustments, toDomainSettlementAdjustment(a))
+	}
+	return adjustments, nil
+}
+
+func toDomainSettlementBatches(rows []db.SettlementBatch) []domain.SettlementBatch {
+	batches := make([]domain.SettlementBatch, 0, len(rows))
+	for _, b := range rows {
+		batches = append(batches, *toDomainSettlementBatch(b))
+	}
+	return batches
+}
+
+func toDomainSettlementBatch(b db.SettlementBatch) *domain.SettlementBatch {
+	return &domain.SettlementBatch{
+		ID:               b.ID,
+		MerchantID:       b.MerchantID,
+		Currency:         b.Currency,
+		Status:           domain.SettlementStatus(b.Status),
+		CutoffAt:         b.CutoffAt.Time,
+		PaymentCount:     int(b.PaymentCount),
+		GrossAmount:      b.GrossAmount.InexactFloat64(),
+		FeeAmount:        b.FeeAmount.InexactFloat64(),
+		RefundedAmount:   b.RefundedAmount.InexactFloat64(),
+		AdjustmentAmount: b.AdjustmentAmount.InexactFloat64(),
+		NetAmount:        b.NetAmount.InexactFloat64(),
+		ClosedAt:         timePtr(b.ClosedAt),
+		PaidAt:           timePtr(b.PaidAt),
+		CreatedAt:        b.CreatedAt.Time,
+		UpdatedAt:        b.UpdatedAt.Time,
+	}
+}
+
+func toDomainSettlementItem(i db.SettlementItem) domain.SettlementItem {
+	return domain.SettlementItem{
+		PaymentID:      i.PaymentID,
+		BatchID:        i.BatchID,
+		Amount:         i.Amount.InexactFloat64(),
+		FeeAmount:      i.FeeAmount.InexactFloat64(),
+		RefundedAmount: i.RefundedAmount.InexactFloat64(),
+		NetAmount:      i.NetAmount.InexactFloat64(),
+		CreatedAt:      i.CreatedAt.Time,
+	}
+}
+
+func toDomainSettlementAdjustment(a db.SettlementAdjustment) domain.SettlementAdjustment {
+	return domain.SettlementAdjustment{
+		ID:        a.ID,
+		PaymentID: a.PaymentID,
+		BatchID:   a.BatchID,
+		Amount:    a.Amount.InexactFloat64(),
+		CreatedAt: a.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,153 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/google/uuid"
+)
+
+// subscriptionRepo is the Postgres implementation of domain.SubscriptionRepo.
+type subscriptionRepo struct {
+	queries db.Querier
+}
+
+func NewSubscriptionRepo(q db.Querier) domain.SubscriptionRepo {
+	return &subscriptionRepo{queries: q}
+}
+
+func (r *subscriptionRepo) CreateSubscription(ctx context.Context, sub *domain.Subscription) error {
+	method, details := encodePaymentMethod(sub.PaymentMethod)
+	s, err := r.queries.CreateSubscription(ctx, db.CreateSubscriptionParams{
+		PlanID:               sub.PlanID,
+		CustomerID:           sub.CustomerID,
+		Status:               string(sub.Status),
+		PaymentMethod:        method.PaymentMethodType,
+		PaymentMethodDetails: details,
+		BillingAnchor:        timestamptz(sub.BillingAnchor),
+		TrialEnd:             timestamptzOrNull(sub.TrialEnd),
+		CurrentPeriodStart:   timestamptz(sub.CurrentPeriodStart),
+		CurrentPeriodEnd:     timestamptz(sub.CurrentPeriodEnd),
+		NextBillingAt:        timestamptzOrNull(sub.NextBillingAt),
+		Metadata:             encodeMetadata(sub.Metadata),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*sub = *toDomainSubscription(s)
+	return nil
+}
+
+func (r *subscriptionRepo) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	s, err := r.queries.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSubscription(s), nil
+}
+
+func (r *subscriptionRepo) GetSubscriptionByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	s, err := r.queries.GetSubscriptionByIDForUpdate(ctx, id)
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSubscription(s), nil
+}
+
+func (r *subscriptionRepo) ListSubscriptions(ctx context.Context, page domain.Page) ([]domain.Subscription, error) {
+	rows, err := r.queries.ListSubscriptions(ctx, db.ListSubscriptionsParams{
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	subs := make([]domain.Subscription, 0, len(rows))
+	for _, s := range rows {
+		subs = append(subs, *toDomainSubscription(s))
+	}
+	return subs, nil
+}
+
+func (r *subscriptionRepo) UpdateSubscription(ctx context.Context, sub *domain.Subscription) error {
+	s, err := r.queries.UpdateSubscription(ctx, db.UpdateSubscriptionParams{
+		ID:                 sub.ID,
+		Status:             string(sub.Status),
+		CurrentPeriodStart: timestamptz(sub.CurrentPeriodStart),
+		CurrentPeriodEnd:   timestamptz(sub.CurrentPeriodEnd),
+		NextBillingAt:      timestamptzOrNull(sub.NextBillingAt),
+		CancelAtPeriodEnd:  sub.CancelAtPeriodEnd,
+		CanceledAt:         timestamptzOrNull(sub.CanceledAt),
+		PendingPaymentID:   uuidOrNull(sub.PendingPaymentID),
+		FailedAttempts:     int32(sub.FailedAttempts),
+	})
+	if err != nil {
+		return translateError(err)
+	}
+	*sub = *toDomainSubscription(s)
+	return nil
+}
+
+func (r *subscriptionRepo) ClaimDueSubscription(ctx context.Context, now time.Time) (*domain.Subscription, error) {
+	s, err := r.queries.ClaimDueSubscription(ctx, timestamptz(now))
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return toDomainSubscription(s), nil
+}
+
+func (r *subscriptionRepo) ClaimSettledSubscription(ctx context.Context) (*domain.Subscription, domain.PaymentStatus, error) {
+	row, err := r.queries.ClaimSettledSubscription(ctx)
+	if err != nil {
+		return nil, "", translateError(err)
+	}
+	sub := toDomainSubscription(db.Subscription{
+		ID:                   row.ID,
+		PlanID:               row.PlanID,
+		CustomerID:           row.CustomerID,
+		Status:               row.Status,
+		PaymentMethod:        row.PaymentMethod,
+		PaymentMethodDetails: row.PaymentMethodDetails,
+		BillingAnchor:        row.BillingAnchor,
+		TrialEnd:             row.TrialEnd,
+		CurrentPeriodStart:   row.CurrentPeriodStart,
+		CurrentPeriodEnd:     row.CurrentPeriodEnd,
+		NextBillingAt:        row.NextBillingAt,
+		CancelAtPeriodEnd:    row.CancelAtPeriodEnd,
+		CanceledAt:           row.CanceledAt,
+		PendingPaymentID:     row.PendingPaymentID,
+		FailedAttempts:       row.FailedAttempts,
+		Metadata:             row.Metadata,
+		CreatedAt:            row.CreatedAt,
+		UpdatedAt:            row.UpdatedAt,
+	})
+	return sub, domain.PaymentStatus(row.PaymentStatus), nil
+}
+
+func toDomainSubscription(s db.Subscription) *domain.Subscription {
+	return &domain.Subscription{
+		ID:         s.ID,
+		PlanID:     s.PlanID,
+		CustomerID: s.CustomerID,
+		Status:     domain.SubscriptionStatus(s.Status),
+		PaymentMethod: decodePaymentMethod(
+			db.NullPaymentMethodType{PaymentMethodType: s.PaymentMethod, Valid: true},
+			s.PaymentMethodDetails,
+		),
+		BillingAnchor:      s.BillingAnchor.Time,
+		TrialEnd:           timePtr(s.TrialEnd),
+		CurrentPeriodStart: s.CurrentPeriodStart.Time,
+		CurrentPeriodEnd:   s.CurrentPeriodEnd.Time,
+		NextBillingAt:      timePtr(s.NextBillingAt),
+		CancelAtPeriodEnd:  s.CancelAtPeriodEnd,
+		CanceledAt:         timePtr(s.CanceledAt),
+		PendingPaymentID:   uuidPtr(s.PendingPaymentID),
+		FailedAttempts:     int(s.FailedAttempts),
+		Metadata:           decodeMetadata(s.Metadata),
+		CreatedAt:          s.CreatedAt.Time,
+		UpdatedAt:          s.UpdatedAt.Time,
+	}
+}
@@ -0,0 +1,105 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"pgm/internal/domain"
+	"pgm/internal/repo/db"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// unitOfWork hands out repositories bound either to the pool or, inside
+// WithinTx, to a single transaction.
+type unitOfWork struct {
+	pool    *pgxpool.Pool
+	queries *db.Queries
+}
+
+func NewUnitOfWork(pool *pgxpool.Pool) domain.UnitOfWork {
+	return &unitOfWork{
+		pool:    pool,
+		queries: db.New(pool),
+	}
+}
+
+func (u *unitOfWork) Payments() domain.PaymentRepo {
+	return NewPaymentRepo(u.queries)
+}
+
+func (u *unitOfWork) Customers() domain.CustomerRepo {
+	return NewCustomerRepo(u.queries)
+}
+
+func (u *unitOfWork) CheckoutSessions() domain.CheckoutSessionRepo {
+	return NewCheckoutSessionRepo(u.queries)
+}
+
+func (u *unitOfWork) PaymentLinks() domain.PaymentLinkRepo {
+	return NewPaymentLinkRepo(u.queries)
+}
+
+func (u *unitOfWork) Plans() domain.PlanRepo {
+	return NewPlanRepo(u.queries)
+}
+
+func (u *unitOfWork) Subscriptions() domain.SubscriptionRepo {
+	return NewSubscriptionRepo(u.queries)
+}
+
+func (u *unitOfWork) Ledger() domain.LedgerRepo {
+	return NewLedgerRepo(u.queries)
+}
+
+func (u *unitOfWork) Fees() domain.FeeRepo {
+	return NewFeeRepo(u.queries)
+}
+
+func (u *unitOfWork) Settlements() domain.SettlementRepo {
+	return NewSettlementRepo(u.queries)
+}
+
+func (u *unitOfWork) Reconciliations() domain.ReconciliationRepo {
+	return NewReconciliationRepo(u.queries)
+}
+
+func (u *unitOfWork) Disputes() domain.DisputeRepo {
+	return NewDisputeRepo(u.queries)
+}
+
+func (u *unitOfWork) Risk() domain.RiskRepo {
+	return NewRiskRepo(u.queries)
+}
+
+func (u *unitOfWork) Reviews() domain.ReviewRepo {
+	return NewReviewRepo(u.queries)
+}
+
+func (u *unitOfWork) Audit() domain.AuditRepo {
+	return NewAuditRepo(u.queries)
+}
+
+func (u *unitOfWork) WithinTx(ctx context.Context, fn func(tx domain.UnitOfWork) error) error {
+	if u.pool == nil {
+		// Already inside a transaction; join it.
+		return fn(u)
+	}
+
+	tx, err := u.pool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.Serializable,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", translateError(err))
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&unitOfWork{queries: u.queries.WithTx(tx)}); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", translateError(err))
+	}
+	return nil
+}
@@ -0,0 +1,344 @@
+// Package risk implements the small expression language fraud rules are
+// written in. It is modelled on CEL: a rule such as
+//
+//	currency == "USD" && amount > 5000 || velocity_ip("1h") >= 10
+//
+// is compiled once against the variables and functions the caller declares
+// and then evaluated against each payment.
+//
+// Values are numbers (float64), strings, booleans and lists. && and || short
+// circuit, so a costly function on the right is only called when needed.
+package risk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Value is a float64, string, bool or []Value.
+type Value = interface{}
+
+// Func implements a function callable from an expression.
+type Func func(args []Value) (Value, error)
+
+// builtins are available to every expression, keyed by name with their
+// number of arguments.
+var builtins = map[string]struct {
+	arity int
+	fn    Func
+}{
+	"starts_with": {2, stringFunc(strings.HasPrefix)},
+	"ends_with":   {2, stringFunc(strings.HasSuffix)},
+	"contains":    {2, stringFunc(strings.Contains)},
+	"lower": {1, func(args []Value) (Value, error) {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("lower expects a string, got %s", typeName(args[0]))
+		}
+		return strings.ToLower(s), nil
+	}},
+}
+
+func stringFunc(f func(s, sub string) bool) Func {
+	return func(args []Value) (Value, error) {
+		s, ok1 := args[0].(string)
+		sub, ok2 := args[1].(string)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("expected two strings, got %s and %s", typeName(args[0]), typeName(args[1]))
+		}
+		return f(s, sub), nil
+	}
+}
+
+// Declarations lists the variables and functions, with their number of
+// arguments, an expression may refer to besides the builtins.
+type Declarations struct {
+	Vars  []string
+	Funcs map[string]int
+}
+
+func (d Declarations) hasVar(name string) bool {
+	for _, v := range d.Vars {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (d Declarations) arity(name string) (int, bool) {
+	if n, ok := d.Funcs[name]; ok {
+		return n, true
+	}
+	b, ok := builtins[name]
+	return b.arity, ok
+}
+
+// Env supplies the declared variables and functions during evaluation.
+type Env struct {
+	Vars  map[string]Value
+	Funcs map[string]Func
+}
+
+// Program is a compiled expression.
+type Program struct {
+	src  string
+	root node
+}
+
+// Compile parses src and checks that it only refers to the declared
+// variables and functions, called with the right number of arguments.
+func Compile(src string, decls Declarations) (*Program, error) {
+	toks, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks, decls: decls}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, unexpected(t, "end of expression")
+	}
+	return &Program{src: src, root: root}, nil
+}
+
+func (p *Program) String() string { return p.src }
+
+// Eval runs the program in env. The expression must produce a boolean.
+func (p *Program) Eval(env Env) (bool, error) {
+	v, err := p.root.eval(&env)
+	if err != nil {
+		return false, err
+	}
+	b, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("expression produced a %s, not a bool", typeName(v))
+	}
+	return b, nil
+}
+
+type node interface {
+	eval(env *Env) (Value, error)
+}
+
+type literal struct{ v Value }
+
+func (n *literal) eval(*Env) (Value, error) { return n.v, nil }
+
+type ident struct{ name string }
+
+func (n *ident) eval(env *Env) (Value, error) {
+	v, ok := env.Vars[n.name]
+	if !ok {
+		return nil, fmt.Errorf("variable %q is not set", n.name)
+	}
+	return v, nil
+}
+
+type list struct{ elems []node }
+
+func (n *list) eval(env *Env) (Value, error) {
+	vs := make([]Value, 0, len(n.elems))
+	for _, e := range n.elems {
+		v, err := e.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		vs = append(vs, v)
+	}
+	return vs, nil
+}
+
+type call struct {
+	name string
+	args []node
+}
+
+func (n *call) eval(env *Env) (Value, error) {
+	fn, ok := env.Funcs[n.name]
+	if !ok {
+		b, ok := builtins[n.name]
+		if !ok {
+			return nil, fmt.Errorf("function %q is not set", n.name)
+		}
+		fn = b.fn
+	}
+	args := make([]Value, 0, len(n.args))
+	for _, a := range n.args {
+		v, err := a.eval(env)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, v)
+	}
+	v, err := fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", n.name, err)
+	}
+	return v, nil
+}
+
+type unary struct {
+	op string
+	x  node
+}
+
+func (n *unary) eval(env *Env) (Value, error) {
+	v, err := n.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	switch n.op {
+	case "!":
+		if b, ok := v.(bool); ok {
+			return !b, nil
+		}
+	case "-":
+		if f, ok := v.(float64); ok {
+			return -f, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot apply %s to a %s", n.op, typeName(v))
+}
+
+type binary struct {
+	op   string
+	x, y node
+}
+
+func (n *binary) eval(env *Env) (Value, error) {
+	x, err := n.x.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	if n.op == "&&" || n.op == "||" {
+		return n.logical(env, x)
+	}
+	y, err := n.y.eval(env)
+	if err != nil {
+		return nil, err
+	}
+
+	switch n.op {
+	case "==", "!=":
+		eq, err := equal(x, y)
+		if err != nil {
+			return nil, err
+		}
+		return eq == (n.op == "=="), nil
+	case "in":
+		vs, ok := y.([]Value)
+		if !ok {
+			return nil, fmt.Errorf("in expects a list, got %s", typeName(y))
+		}
+		for _, v := range vs {
+			if eq, err := equal(x, v); err == nil && eq {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	a, ok1 := x.(float64)
+	b, ok2 := y.(float64)
+	if !ok1 || !ok2 {
+		// Strings order lexically, e.g. for ISO dates
+		sa, ok1 := x.(string)
+		sb, ok2 := y.(string)
+		if ok1 && ok2 {
+			switch n.op {
+			case "<":
+				return sa < sb, nil
+			case "<=":
+				return sa <= sb, nil
+			case ">":
+				return sa > sb, nil
+			case ">=":
+				return sa >= sb, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot apply %s to a %s and a %s", n.op, typeName(x), typeName(y))
+	}
+	switch n.op {
+	case "<":
+		return a < b, nil
+	case "<=":
+		return a <= b, nil
+	case ">":
+		return a > b, nil
+	case ">=":
+		return a >= b, nil
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return a / b, nil
+	}
+	return nil, fmt.Errorf("unknown operator %s", n.op)
+}
+
+func (n *binary) logical(env *Env, x Value) (Value, error) {
+	a, ok := x.(bool)
+	if !ok {
+		return nil, fmt.Errorf("cannot apply %s to a %s", n.op, typeName(x))
+	}
+	if (n.op == "&&" && !a) || (n.op == "||" && a) {
+		return a, nil
+	}
+	y, err := n.y.eval(env)
+	if err != nil {
+		return nil, err
+	}
+	b, ok := y.(bool)
+	if !ok {
+		return nil, fmt.Errorf("cannot apply %s to a %s", n.op, typeName(y))
+	}
+	return b, nil
+}
+
+func equal(x, y Value) (bool, error) {
+	switch a := x.(type) {
+	case float64, string, bool:
+		if typeName(x) != typeName(y) {
+			return false, fmt.Errorf("cannot compare a %s with a %s", typeName(x), typeName(y))
+		}
+		return x == y, nil
+	case []Value:
+		b, ok := y.([]Value)
+		if !ok {
+			return false, fmt.Errorf("cannot compare a list with a %s", typeName(y))
+		}
+		if len(a) != len(b) {
+			return false, nil
+		}
+		for i := range a {
+			if eq, err := equal(a[i], b[i]); err != nil || !eq {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	return false, fmt.Errorf("cannot compare a %s", typeName(x))
+}
+
+func typeName(v Value) string {
+	switch v.(type) {
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case []Value:
+		return "list"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,120 @@
+package risk_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/risk"
+)
+
+var decls = risk.Declarations{
+	Vars:  []string{"amount", "currency", "reference", "ip"},
+	Funcs: map[string]int{"velocity": 1, "fail": 0},
+}
+
+func env(calls *int) risk.Env {
+	return risk.Env{
+		Vars: map[string]risk.Value{"amount": 6000.0, "currency": "USD", "reference": "ORD-1", "ip": "10.0.0.1"},
+		Funcs: map[string]risk.Func{
+			"velocity": func(args []risk.Value) (risk.Value, error) {
+				*calls++
+				return 12.0, nil
+			},
+			"fail": func([]risk.Value) (risk.Value, error) { return nil, errors.New("boom") },
+		},
+	}
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{`currency == "USD" && amount > 5000`, true},
+		{`currency == 'ETB' && amount > 5000`, false},
+		{`amount / 2 + 1 == 3001`, true},
+		{`-amount < 0`, true},
+		{`!(amount >= 6000)`, false},
+		{`currency in ["USD", "EUR"]`, true},
+		{`currency in []`, false},
+		{`starts_with(reference, "ORD-") && !ends_with(reference, "-test")`, true},
+		{`contains(lower("ABC"), "b")`, true},
+		{`velocity("1h") >= 10 || false`, true},
+		{`"2026-03-01" < "2026-03-02"`, true},
+		{`[1, "a"] == [1, "a"]`, true},
+		{`true`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			p, err := risk.Compile(tt.expr, decls)
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			var calls int
+			got, err := p.Eval(env(&calls))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvalShortCircuits(t *testing.T) {
+	p, err := risk.Compile(`currency == "ETB" && velocity("1h") > 1 || amount > 1 || fail()`, decls)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	var calls int
+	got, err := p.Eval(env(&calls))
+	assert.NoError(t, err)
+	assert.True(t, got)
+	assert.Equal(t, 0, calls)
+}
+
+func TestEvalErrors(t *testing.T) {
+	for _, expr := range []string{
+		`amount`,
+		`currency > 1`,
+		`currency == 1`,
+		`amount / 0 > 1`,
+		`fail()`,
+		`amount in "USD"`,
+		`!currency`,
+	} {
+		t.Run(expr, func(t *testing.T) {
+			p, err := risk.Compile(expr, decls)
+			if err != nil {
+				t.Fatalf("compile: %v", err)
+			}
+			var calls int
+			_, err = p.Eval(env(&calls))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{`country == "US"`, `unknown variable "country" at column 1`},
+		{`geo(ip)`, `unknown function "geo" at column 1`},
+		{`velocity()`, `velocity takes 1 arguments but was given 0 at column 1`},
+		{`amount >`, `expected a value at end of expression`},
+		{`(amount > 1`, `expected ) at end of expression`},
+		{`amount > 1 amount`, `expected end of expression but found "amount" at column 12`},
+		{`currency == "USD`, `unterminated string at column 13`},
+		{`amount # 1`, `unexpected character '#' at column 8`},
+		{`amount > 1.2.3`, `invalid number "1.2.3" at column 10`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := risk.Compile(tt.expr, decls)
+			if assert.Error(t, err) {
+				assert.Equal(t, tt.want, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type AdminService struct {
+	uow       domain.UnitOfWork
+	publisher domain.MessagePublisher
+}
+
+func NewAdminService(uow domain.UnitOfWork, publisher domain.MessagePublisher) domain.AdminService {
+	return &AdminService{uow: uow, publisher: publisher}
+}
+
+// forcedFailure is the audit snapshot of a payment failed by an operator,
+// with the reason they gave.
+type forcedFailure struct {
+	*domain.Payment
+	Reason string `json:"reason"`
+}
+
+// RequeuePayment publishes a pending payment for the worker again, e.g. after
+// its message was lost. Processing is idempotent, so a payment queued twice is
+// still charged once.
+func (s *AdminService) RequeuePayment(ctx context.Context, id string) (*domain.Payment, error) {
+	paymentID, err := parsePaymentID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var payment *domain.Payment
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		var err error
+		payment, err = s.requeue(ctx, tx, paymentID)
+		return err
+	})
+	if err != nil {
+		return nil, adminPaymentError(err, "Failed to requeue payment", paymentID)
+	}
+
+	logger.FromContext(ctx).Info("payment requeued", slog.String("payment_id", id))
+	return payment, nil
+}
+
+// requeue publishes the payment while holding its lock, so the audit entry
+// is only kept when the message was sent.
+func (s *AdminService) requeue(ctx context.Context, tx domain.UnitOfWork, paymentID uuid.UUID) (*domain.Payment, error) {
+	p, err := lockPayment(ctx, tx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != domain.StatusPending {
+		return nil, paymentNotPendingError(p)
+	}
+	if err := checkoutConfirmed(ctx, tx, p); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, tx, "payment.requeued", domain.AuditPayment, p.ID.String(), nil, p); err != nil {
+		return nil, err
+	}
+	if err := s.publisher.PublishPaymentCreated(ctx, p.ID.String()); err != nil {
+		return nil, domain.NewError(
+			domain.ErrServiceUnavailable,
+			"Failed to queue payment",
+			"The payment could not be published to the processing queue",
+			err,
+			map[string]interface{}{"PaymentID": p.ID},
+		)
+	}
+	return p, nil
+}
+
+// ForceFailPayment fails a payment stuck in PENDING. A worker that picks up
+// the payment afterwards finds it processed and does not charge it.
+func (s *AdminService) ForceFailPayment(ctx context.Context, id string, fr *domain.ForceFailRequest) (*domain.Payment, error) {
+	paymentID, err := parsePaymentID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := fr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"force fail request validation failed",
+			err,
+			map[string]interface{}{"req": fr},
+		)
+	}
+
+	var payment *domain.Payment
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		p, err := lockPayment(ctx, tx, paymentID)
+		if err != nil {
+			return err
+		}
+		if p.Status != domain.StatusPending {
+			return paymentNotPendingError(p)
+		}
+		before := *p
+		if payment, err = tx.Payments().UpdatePaymentStatus(ctx, paymentID, domain.StatusFailed); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "payment.force_failed", domain.AuditPayment, id, before, forcedFailure{payment, fr.Reason})
+	})
+	if err != nil {
+		return nil, adminPaymentError(err, "Failed to fail payment", paymentID)
+	}
+
+	logger.FromContext(ctx).Info("payment failed by operator",
+		slog.String("payment_id", id),
+		slog.String("reason", fr.Reason),
+	)
+	return payment, nil
+}
+
+// ListPaymentAttempts returns every provider call recorded for the payment,
+// including the errors of failed calls. It takes no lock, so attempts of a
+// payment being charged show up as they are made.
+func (s *AdminService) ListPaymentAttempts(ctx context.Context, id string) (*domain.PaymentAttemptList, error) {
+	paymentID, err := parsePaymentID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.uow.Payments().GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewError(
+				domain.ErrPaymentNotFound,
+				"Payment not found",
+				"The specified payment could not be found",
+				err,
+				map[string]interface{}{"PaymentID": id},
+			)
+		}
+		return nil, adminPaymentError(err, "Failed to fetch payment", paymentID)
+	}
+	attempts, err := s.uow.Payments().ListPaymentAttempts(ctx, paymentID)
+	if err != nil {
+		return nil, adminPaymentError(err, "Failed to fetch payment attempts", paymentID)
+	}
+	if attempts == nil {
+		attempts = []domain.PaymentAttempt{}
+	}
+	return &domain.PaymentAttemptList{PaymentID: p.ID, Status: p.Status, Data: attempts}, nil
+}
+
+// RedrivePayments requeues the pending payments the request selects, each
+// in its own transaction under the payment's lock. Payments processed since
+// they were selected are skipped. It stops at the first payment that cannot
+// be queued and returns what it requeued so far along with the error.
+func (s *AdminService) RedrivePayments(ctx context.Context, rr *domain.RedriveRequest) (*domain.RedriveResult, error) {
+	if err := rr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"redrive request validation failed",
+			err,
+			map[string]interface{}{"req": rr},
+		)
+	}
+
+	payments, err := s.uow.Payments().ListPendingPayments(ctx, rr.Filter(), rr.PageSize())
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list pending payments",
+			"Error occurred while selecting the payments to re-drive",
+			err,
+			map[string]interface{}{"req": rr},
+		)
+	}
+	res := &domain.RedriveResult{
+		Matched:     len(payments),
+		DryRun:      rr.DryRun,
+		PaymentIDs:  make([]uuid.UUID, 0, len(payments)),
+		RequeuedIDs: []uuid.UUID{},
+	}
+	for _, p := range payments {
+		res.PaymentIDs = append(res.PaymentIDs, p.ID)
+	}
+	if rr.DryRun {
+		return res, nil
+	}
+
+	log := logger.FromContext(ctx)
+	for _, p := range payments {
+		err := s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+			_, err := s.requeue(ctx, tx, p.ID)
+			return err
+		})
+		var derr domain.Error
+		if errors.As(err, &derr) && derr.Type == domain.ErrPaymentNotPending {
+			log.Info("payment processed since selected, skipping re-drive", slog.String("payment_id", p.ID.String()))
+			continue
+		}
+		if err != nil {
+			log.Error("payment re-drive stopped",
+				slog.String("payment_id", p.ID.String()),
+				slog.Int("requeued", res.Requeued),
+				slog.Any("error", err),
+			)
+			err = adminPaymentError(err, "Failed to re-drive payments", p.ID)
+			res.StoppedAt = &p.ID
+			res.Error = err.Error()
+			return res, err
+		}
+		res.Requeued++
+		res.RequeuedIDs = append(res.RequeuedIDs, p.ID)
+	}
+
+	log.Info("payments re-driven",
+		slog.Int("matched", res.Matched),
+		slog.Int("requeued", res.Requeued),
+	)
+	return res, nil
+}
+
+func parsePaymentID(id string) (uuid.UUID, error) {
+	paymentID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewError(
+			domain.ErrInvalidPaymentID,
+			"Invalid payment ID format",
+			"The provided payment ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"PaymentID": id},
+		)
+	}
+	return paymentID, nil
+}
+
+func paymentNotPendingError(p *domain.Payment) error {
+	return domain.NewError(
+		domain.ErrPaymentNotPending,
+		"Payment not pending",
+		"Only a pending payment can be changed; this one is "+string(p.Status),
+		nil,
+		map[string]interface{}{"PaymentID": p.ID, "status": p.Status},
+	)
+}
+
+// checkoutConfirmed rejects a checkout payment the payer has not confirmed.
+// Such a payment is pending on purpose and must not be queued by an operator.
+func checkoutConfirmed(ctx context.Context, tx domain.UnitOfWork, p *domain.Payment) error {
+	session, err := tx.CheckoutSessions().GetCheckoutSessionByPaymentID(ctx, p.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if session.Status != domain.CheckoutConfirmed {
+		return domain.NewError(
+			domain.ErrPaymentNotConfirmed,
+			"Payment not confirmed by the payer",
+			"The payment belongs to a checkout session that is "+string(session.Status)+"; it is queued only when the payer confirms it",
+			nil,
+			map[string]interface{}{"PaymentID": p.ID, "CheckoutSessionID": session.ID},
+		)
+	}
+	return nil
+}
+
+// adminPaymentError passes domain errors through and wraps anything else as
+// a storage failure.
+func adminPaymentError(err error, title string, paymentID uuid.UUID) error {
+	var derr domain.Error
+	if errors.As(err, &derr) {
+		return derr
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		title,
+		"Error occurred while changing the payment",
+		err,
+		map[string]interface{}{"PaymentID": paymentID},
+	)
+}
@@ -0,0 +1,208 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+// ListPendingPayments ignores the metadata filter. Unlike the query it does
+// not skip unconfirmed checkout payments, so tests can see them rejected.
+func (r *fakeRepo) ListPendingPayments(ctx context.Context, filter domain.PendingPaymentFilter, limit int) ([]domain.Payment, error) {
+	var payments []domain.Payment
+	for _, p := range r.byID {
+		if p.Status != domain.StatusPending || (filter.Currency != "" && p.Currency != filter.Currency) {
+			continue
+		}
+		if len(payments) < limit {
+			payments = append(payments, *p)
+		}
+	}
+	return payments, nil
+}
+
+type adminFixture struct {
+	svc       domain.AdminService
+	repo      *fakeRepo
+	pub       *fakePublisher
+	audit     *fakeAuditRepo
+	checkouts *fakeCheckoutRepo
+}
+
+func setupAdmin() *adminFixture {
+	f := &adminFixture{
+		repo:      &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)},
+		pub:       &fakePublisher{},
+		audit:     &fakeAuditRepo{},
+		checkouts: &fakeCheckoutRepo{byID: make(map[uuid.UUID]*domain.CheckoutSession)},
+	}
+	uow := &fakeUnitOfWork{repo: f.repo, audit: f.audit, checkouts: f.checkouts}
+	f.svc = service.NewAdminService(uow, f.pub)
+	return f
+}
+
+func (f *adminFixture) payment(status domain.PaymentStatus, currency string) *domain.Payment {
+	p := &domain.Payment{Amount: 10, Currency: currency, Reference: uuid.NewString(), Status: status}
+	_ = f.repo.CreatePayment(context.Background(), p)
+	return p
+}
+
+// checkout attaches a checkout session in status to p.
+func (f *adminFixture) checkout(p *domain.Payment, status domain.CheckoutStatus) {
+	s := &domain.CheckoutSession{PaymentID: p.ID}
+	_ = f.checkouts.CreateCheckoutSession(context.Background(), s)
+	f.checkouts.byID[s.ID].Status = status
+}
+
+func TestRequeuePayment(t *testing.T) {
+	f := setupAdmin()
+	pending := f.payment(domain.StatusPending, "USD")
+
+	p, err := f.svc.RequeuePayment(context.Background(), pending.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusPending, p.Status)
+	assert.Equal(t, []string{pending.ID.String()}, f.pub.published)
+	assert.Equal(t, []string{"payment.requeued"}, f.audit.actions())
+
+	for _, status := range []domain.PaymentStatus{domain.StatusSuccess, domain.StatusFailed, domain.StatusInReview} {
+		_, err = f.svc.RequeuePayment(context.Background(), f.payment(status, "USD").ID.String())
+		assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+	}
+	assert.Len(t, f.pub.published, 1)
+
+	_, err = f.svc.RequeuePayment(context.Background(), uuid.NewString())
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	_, err = f.svc.RequeuePayment(context.Background(), "not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+}
+
+func TestRequeueCheckoutPayment(t *testing.T) {
+	f := setupAdmin()
+	open := f.payment(domain.StatusPending, "USD")
+	f.checkout(open, domain.CheckoutOpen)
+	confirmed := f.payment(domain.StatusPending, "USD")
+	f.checkout(confirmed, domain.CheckoutConfirmed)
+
+	_, err := f.svc.RequeuePayment(context.Background(), open.ID.String())
+	var derr domain.Error
+	if assert.ErrorAs(t, err, &derr) {
+		assert.Equal(t, domain.ErrPaymentNotConfirmed, derr.Type)
+		assert.Equal(t, http.StatusConflict, derr.Code)
+	}
+	assert.Empty(t, f.pub.published)
+
+	_, err = f.svc.RequeuePayment(context.Background(), confirmed.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{confirmed.ID.String()}, f.pub.published)
+}
+
+func TestForceFailPayment(t *testing.T) {
+	f := setupAdmin()
+	pending := f.payment(domain.StatusPending, "USD")
+
+	_, err := f.svc.ForceFailPayment(context.Background(), pending.ID.String(), &domain.ForceFailRequest{})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	assert.Equal(t, domain.StatusPending, pending.Status)
+
+	p, err := f.svc.ForceFailPayment(context.Background(), pending.ID.String(), &domain.ForceFailRequest{Reason: "provider confirmed no charge"})
+	assert.NoError(t, err)
+	assert.Equal(t, domain.StatusFailed, p.Status)
+	if assert.Equal(t, []string{"payment.force_failed"}, f.audit.actions()) {
+		assert.Contains(t, string(f.audit.entries[0].Before), `"status":"PENDING"`)
+		assert.Contains(t, string(f.audit.entries[0].After), `"status":"FAILED"`)
+		assert.Contains(t, string(f.audit.entries[0].After), `"reason":"provider confirmed no charge"`)
+	}
+
+	_, err = f.svc.ForceFailPayment(context.Background(), pending.ID.String(), &domain.ForceFailRequest{Reason: "again"})
+	assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+}
+
+func TestAdminListPaymentAttempts(t *testing.T) {
+	f := setupAdmin()
+	p := f.payment(domain.StatusFailed, "USD")
+	_ = f.repo.CreatePaymentAttempt(context.Background(), &domain.PaymentAttempt{PaymentID: p.ID, Provider: "acme", Status: domain.AttemptUnavailable, Error: "timeout"})
+
+	attempts, err := f.svc.ListPaymentAttempts(context.Background(), p.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, p.ID, attempts.PaymentID)
+	assert.Equal(t, domain.StatusFailed, attempts.Status)
+	if assert.Len(t, attempts.Data, 1) {
+		assert.Equal(t, "timeout", attempts.Data[0].Error)
+	}
+
+	attempts, err = f.svc.ListPaymentAttempts(context.Background(), f.payment(domain.StatusPending, "USD").ID.String())
+	assert.NoError(t, err)
+	assert.NotNil(t, attempts.Data)
+	assert.Empty(t, attempts.Data)
+}
+
+func TestRedrivePayments(t *testing.T) {
+	f := setupAdmin()
+	usd := []*domain.Payment{f.payment(domain.StatusPending, "USD"), f.payment(domain.StatusPending, "USD")}
+	f.payment(domain.StatusPending, "ETB")
+	f.payment(domain.StatusSuccess, "USD")
+
+	res, err := f.svc.RedrivePayments(context.Background(), &domain.RedriveRequest{Currency: "USD", DryRun: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Matched)
+	assert.Equal(t, 0, res.Requeued)
+	assert.ElementsMatch(t, []uuid.UUID{usd[0].ID, usd[1].ID}, res.PaymentIDs)
+	assert.Empty(t, f.pub.published)
+	assert.Empty(t, f.audit.entries)
+
+	res, err = f.svc.RedrivePayments(context.Background(), &domain.RedriveRequest{Currency: "USD"})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.Matched)
+	assert.Equal(t, 2, res.Requeued)
+	assert.ElementsMatch(t, []string{usd[0].ID.String(), usd[1].ID.String()}, f.pub.published)
+	assert.Equal(t, []string{"payment.requeued", "payment.requeued"}, f.audit.actions())
+
+	res, err = f.svc.RedrivePayments(context.Background(), &domain.RedriveRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, res.Requeued)
+}
+
+func TestRedrivePaymentsStopsPartway(t *testing.T) {
+	f := setupAdmin()
+	f.payment(domain.StatusPending, "USD")
+	f.payment(domain.StatusPending, "USD")
+
+	f.pub.failAfter = 1
+	res, err := f.svc.RedrivePayments(context.Background(), &domain.RedriveRequest{})
+	assert.Equal(t, http.StatusServiceUnavailable, errorStatus(t, err))
+	if assert.NotNil(t, res) {
+		assert.Equal(t, 2, res.Matched)
+		assert.Equal(t, 1, res.Requeued)
+		assert.Equal(t, []uuid.UUID{uuid.MustParse(f.pub.published[0])}, res.RequeuedIDs)
+		if assert.NotNil(t, res.StoppedAt) {
+			assert.NotEqual(t, res.RequeuedIDs[0], *res.StoppedAt)
+		}
+		assert.NotEmpty(t, res.Error)
+	}
+}
+
+func TestRedrivePaymentsValidation(t *testing.T) {
+	from := time.Now()
+	to := from.Add(-time.Hour)
+	tests := []struct {
+		name string
+		req  domain.RedriveRequest
+	}{
+		{"unknown currency", domain.RedriveRequest{Currency: "EUR"}},
+		{"limit too large", domain.RedriveRequest{Limit: domain.MaxRedriveLimit + 1}},
+		{"reversed range", domain.RedriveRequest{CreatedFrom: &from, CreatedTo: &to}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := setupAdmin().svc.RedrivePayments(context.Background(), &tt.req)
+			assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+)
+
+// auditBatchSize is how many entries are sealed or verified per query.
+const auditBatchSize = 500
+
+type AuditService struct {
+	uow domain.UnitOfWork
+}
+
+func NewAuditService(uow domain.UnitOfWork) domain.AuditService {
+	return &AuditService{uow: uow}
+}
+
+func (s *AuditService) ListEntries(ctx context.Context, filter domain.AuditFilter, page domain.Page) (*domain.AuditList, error) {
+	entries, err := s.uow.Audit().ListEntries(ctx, filter, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list audit log entries",
+			"Error occurred while retrieving the audit log",
+			err,
+			nil,
+		)
+	}
+	return &domain.AuditList{Data: entries, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// Verify walks the sealed entries in chain order. It stops at the first
+// entry out of sequence, not linked to the one before it or whose content no
+// longer matches its hash.
+func (s *AuditService) Verify(ctx context.Context) (*domain.AuditVerification, error) {
+	v := &domain.AuditVerification{OK: true, HeadHash: domain.AuditGenesisHash, CheckedAt: time.Now()}
+	for v.OK {
+		entries, err := s.uow.Audit().ListSealedEntries(ctx, v.HeadSeq, auditBatchSize)
+		if err != nil {
+			return nil, auditVerifyError(err)
+		}
+		for i := range entries {
+			if problem := checkAuditLink(&entries[i], v.HeadSeq, v.HeadHash); problem != "" {
+				v.OK = false
+				v.BrokenAt = entries[i].Seq
+				v.Problem = problem
+				break
+			}
+			v.Checked++
+			v.HeadSeq = *entries[i].Seq
+			v.HeadHash = entries[i].Hash
+		}
+		if len(entries) < auditBatchSize {
+			break
+		}
+	}
+
+	var err error
+	if v.Unsealed, err = s.uow.Audit().CountUnsealedEntries(ctx); err != nil {
+		return nil, auditVerifyError(err)
+	}
+	if !v.OK {
+		logger.FromContext(ctx).Error("audit log verification failed",
+			slog.Int64("broken_at", *v.BrokenAt),
+			slog.String("problem", v.Problem),
+		)
+	}
+	return v, nil
+}
+
+// checkAuditLink describes what is wrong with entry as the successor of the
+// entry at prevSeq with hash prevHash, or returns "".
+func checkAuditLink(entry *domain.AuditEntry, prevSeq int64, prevHash string) string {
+	switch {
+	case *entry.Seq != prevSeq+1:
+		return fmt.Sprintf("expected seq %d, found %d: entries are missing", prevSeq+1, *entry.Seq)
+	case entry.PrevHash != prevHash:
+		return fmt.Sprintf("entry %d does not link to the entry before it", entry.ID)
+	case entry.ComputeHash(*entry.Seq, entry.PrevHash) != entry.Hash:
+		return fmt.Sprintf("entry %d does not match its hash: it was altered", entry.ID)
+	}
+	return ""
+}
+
+func auditVerifyError(err error) error {
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to verify the audit log",
+		"Error occurred while reading the audit log",
+		err,
+		nil,
+	)
+}
+
+// recordAudit appends an entry for a change to the audit log in tx, so it
+// commits or rolls back with the change. The actor and request come from ctx.
+func recordAudit(ctx context.Context, tx domain.UnitOfWork, action, targetType, targetID string, before, after interface{}) error {
+	entry, err := domain.NewAuditEntry(ctx, action, targetType, targetID, before, after)
+	if err != nil {
+		return err
+	}
+	return tx.Audit().AppendEntry(ctx, entry)
+}
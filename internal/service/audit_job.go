@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+)
+
+type AuditConfig struct {
+	// SealInterval is how often new audit log entries are chained.
+	SealInterval time.Duration
+}
+
+// DefaultAuditConfig is used for settings left unset.
+var DefaultAuditConfig = AuditConfig{
+	SealInterval: 10 * time.Second,
+}
+
+// AuditConfigFromEnv reads AUDIT_SEAL_INTERVAL. An unset value falls back to
+// DefaultAuditConfig.
+func AuditConfigFromEnv() (AuditConfig, error) {
+	cfg := DefaultAuditConfig
+	if v := os.Getenv("AUDIT_SEAL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid AUDIT_SEAL_INTERVAL value: %s", v)
+		}
+		cfg.SealInterval = d
+	}
+	return cfg, nil
+}
+
+// AuditSealJob runs in the worker and seals committed audit log entries into
+// the hash chain, oldest first. The chain is locked while it is extended, so
+// when several workers run the job only one seals at a time.
+type AuditSealJob struct {
+	uow domain.UnitOfWork
+	cfg AuditConfig
+}
+
+func NewAuditSealJob(uow domain.UnitOfWork, cfg AuditConfig) *AuditSealJob {
+	if cfg.SealInterval <= 0 {
+		cfg.SealInterval = DefaultAuditConfig.SealInterval
+	}
+	return &AuditSealJob{uow: uow, cfg: cfg}
+}
+
+// Run polls until ctx is canceled.
+func (j *AuditSealJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.SealInterval)
+	defer ticker.Stop()
+	for {
+		if err := j.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Error("audit seal job run failed", slog.Any("error", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce seals every unsealed entry, a batch per transaction.
+func (j *AuditSealJob) RunOnce(ctx context.Context) error {
+	for {
+		n, err := j.sealBatch(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to seal audit log: %w", err)
+		}
+		if n > 0 {
+			logger.FromContext(ctx).Debug("audit log entries sealed", slog.Int("count", n))
+		}
+		if n < auditBatchSize {
+			return nil
+		}
+	}
+}
+
+// sealBatch chains up to auditBatchSize entries after the current head. It
+// seals nothing when another transaction holds the chain.
+func (j *AuditSealJob) sealBatch(ctx context.Context) (int, error) {
+	var n int
+	err := j.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		locked, err := tx.Audit().LockChain(ctx)
+		if err != nil || !locked {
+			return err
+		}
+		seq, prevHash := int64(0), domain.AuditGenesisHash
+		head, err := tx.Audit().GetChainHead(ctx)
+		switch {
+		case err == nil:
+			seq, prevHash = *head.Seq, head.Hash
+		case !errors.Is(err, domain.ErrNotFound):
+			return err
+		}
+
+		entries, err := tx.Audit().ListUnsealedEntries(ctx, auditBatchSize)
+		if err != nil {
+			return err
+		}
+		for i := range entries {
+			seq++
+			hash := entries[i].ComputeHash(seq, prevHash)
+			if _, err := tx.Audit().SealEntry(ctx, entries[i].ID, seq, prevHash, hash); err != nil {
+				return err
+			}
+			prevHash = hash
+		}
+		n = len(entries)
+		return nil
+	})
+	return n, err
+}
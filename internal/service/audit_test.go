@@ -0,0 +1,236 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+	"pgm/internal/service"
+)
+
+type fakeAuditRepo struct {
+	domain.AuditRepo
+	entries []*domain.AuditEntry
+	locked  bool
+}
+
+func (r *fakeAuditRepo) AppendEntry(ctx context.Context, entry *domain.AuditEntry) error {
+	entry.ID = int64(len(r.entries) + 1)
+	entry.CreatedAt = time.Now()
+	stored := *entry
+	r.entries = append(r.entries, &stored)
+	return nil
+}
+
+func (r *fakeAuditRepo) LockChain(ctx context.Context) (bool, error) {
+	return !r.locked, nil
+}
+
+func (r *fakeAuditRepo) GetChainHead(ctx context.Context) (*domain.AuditEntry, error) {
+	var head *domain.AuditEntry
+	for _, e := range r.entries {
+		if e.Seq != nil && (head == nil || *e.Seq > *head.Seq) {
+			head = e
+		}
+	}
+	if head == nil {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	found := *head
+	return &found, nil
+}
+
+func (r *fakeAuditRepo) ListUnsealedEntries(ctx context.Context, limit int) ([]domain.AuditEntry, error) {
+	var entries []domain.AuditEntry
+	for _, e := range r.entries {
+		if e.Seq == nil && len(entries) < limit {
+			entries = append(entries, *e)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeAuditRepo) SealEntry(ctx context.Context, id, seq int64, prevHash, hash string) (*domain.AuditEntry, error) {
+	e := r.entries[id-1]
+	now := time.Now()
+	e.Seq = &seq
+	e.PrevHash = prevHash
+	e.Hash = hash
+	e.SealedAt = &now
+	sealed := *e
+	return &sealed, nil
+}
+
+// ListSealedEntries relies on entries being sealed in insertion order.
+func (r *fakeAuditRepo) ListSealedEntries(ctx context.Context, afterSeq int64, limit int) ([]domain.AuditEntry, error) {
+	var entries []domain.AuditEntry
+	for _, e := range r.entries {
+		if e.Seq != nil && *e.Seq > afterSeq && len(entries) < limit {
+			entries = append(entries, *e)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeAuditRepo) CountUnsealedEntries(ctx context.Context) (int64, error) {
+	var n int64
+	for _, e := range r.entries {
+		if e.Seq == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *fakeAuditRepo) actions() []string {
+	actions := make([]string, 0, len(r.entries))
+	for _, e := range r.entries {
+		actions = append(actions, e.Action)
+	}
+	return actions
+}
+
+func TestPaymentChangesAudited(t *testing.T) {
+	provider := &fakeProvider{name: "acme", status: domain.StatusSuccess}
+	repo := &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)}
+	audit := &fakeAuditRepo{}
+	uow := &fakeUnitOfWork{repo: repo, customers: newFakeCustomerRepo(), ledger: &fakeLedgerRepo{}, fees: &fakeFeeRepo{}, audit: audit}
+	svc := service.NewPaymentService(uow, &fakePublisher{}, &fakeRouter{providers: []domain.Provider{provider}})
+
+	ctx := domain.WithActor(logger.WithRequestID(context.Background(), "req-1"), domain.Actor{Name: "alice", SourceIP: "203.0.113.7"})
+	card := &domain.PaymentMethod{
+		Type: domain.MethodCard,
+		Card: &domain.CardDetails{Token: "tok_secret", Brand: "visa", Last4: "4242", ExpMonth: 12, ExpYear: time.Now().Year() + 1},
+	}
+	p, err := svc.CreatePayment(ctx, &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "ref-audit", PaymentMethod: card})
+	assert.NoError(t, err)
+	workerCtx := domain.WithActor(context.Background(), domain.Actor{Name: domain.ActorWorker})
+	assert.NoError(t, svc.ProcessPayment(workerCtx, p.ID.String()))
+
+	assert.Equal(t, []string{"payment.created", "payment.processed"}, audit.actions())
+	created := audit.entries[0]
+	assert.Equal(t, "alice", created.Actor)
+	assert.Equal(t, "req-1", created.RequestID)
+	assert.Equal(t, "203.0.113.7", created.SourceIP)
+	assert.Equal(t, domain.AuditPayment, created.TargetType)
+	assert.Equal(t, p.ID.String(), created.TargetID)
+	assert.Nil(t, created.Before)
+	assert.Contains(t, string(created.After), `"status":"PENDING"`)
+	assert.Contains(t, string(created.After), `"last4":"4242"`)
+	assert.NotContains(t, string(created.After), "tok_secret")
+
+	processed := audit.entries[1]
+	assert.Equal(t, domain.ActorWorker, processed.Actor)
+	assert.Contains(t, string(processed.Before), `"status":"PENDING"`)
+	assert.Contains(t, string(processed.After), `"status":"SUCCESS"`)
+}
+
+func TestAuditSealAndVerify(t *testing.T) {
+	audit := &fakeAuditRepo{}
+	uow := &fakeUnitOfWork{audit: audit}
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		entry, err := domain.NewAuditEntry(ctx, "customer.created", domain.AuditCustomer, fmt.Sprint(i), nil, map[string]int{"n": i})
+		assert.NoError(t, err)
+		assert.NoError(t, audit.AppendEntry(ctx, entry))
+	}
+	svc := service.NewAuditService(uow)
+
+	v, err := svc.Verify(ctx)
+	assert.NoError(t, err)
+	assert.True(t, v.OK)
+	assert.Equal(t, int64(0), v.Checked)
+	assert.Equal(t, int64(3), v.Unsealed)
+
+	job := service.NewAuditSealJob(uow, service.DefaultAuditConfig)
+	assert.NoError(t, job.RunOnce(ctx))
+	if assert.NotNil(t, audit.entries[0].Seq) {
+		assert.Equal(t, int64(1), *audit.entries[0].Seq)
+	}
+	assert.Equal(t, domain.AuditGenesisHash, audit.entries[0].PrevHash)
+	assert.Equal(t, audit.entries[0].Hash, audit.entries[1].PrevHash)
+
+	// Entries appended later extend the chain rather than starting a new one
+	entry, _ := domain.NewAuditEntry(ctx, "customer.deleted", domain.AuditCustomer, "0", map[string]int{"n": 0}, nil)
+	assert.NoError(t, audit.AppendEntry(ctx, entry))
+	assert.NoError(t, job.RunOnce(ctx))
+	assert.Equal(t, audit.entries[2].Hash, audit.entries[3].PrevHash)
+
+	v, err = svc.Verify(ctx)
+	assert.NoError(t, err)
+	assert.True(t, v.OK)
+	assert.Equal(t, int64(4), v.Checked)
+	assert.Equal(t, int64(0), v.Unsealed)
+	assert.Equal(t, int64(4), v.HeadSeq)
+	assert.Equal(t, audit.entries[3].Hash, v.HeadHash)
+}
+
+func TestAuditSealSkippedWhileLocked(t *testing.T) {
+	audit := &fakeAuditRepo{locked: true}
+	uow := &fakeUnitOfWork{audit: audit}
+	entry, _ := domain.NewAuditEntry(context.Background(), "plan.created", domain.AuditPlan, "p", nil, nil)
+	assert.NoError(t, audit.AppendEntry(context.Background(), entry))
+
+	assert.NoError(t, service.NewAuditSealJob(uow, service.DefaultAuditConfig).RunOnce(context.Background()))
+	assert.Nil(t, audit.entries[0].Seq)
+}
+
+func TestAuditVerifyDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name     string
+		tamper   func(entries []*domain.AuditEntry)
+		brokenAt int64
+	}{
+		{"altered content", func(entries []*domain.AuditEntry) {
+			entries[1].After = []byte(`{"n":99}`)
+		}, 2},
+		{"altered actor", func(entries []*domain.AuditEntry) {
+			entries[1].Actor = "mallory"
+		}, 2},
+		{"rehashed entry", func(entries []*domain.AuditEntry) {
+			entries[1].Actor = "mallory"
+			entries[1].Hash = entries[1].ComputeHash(*entries[1].Seq, entries[1].PrevHash)
+		}, 3},
+		{"deleted entry", func(entries []*domain.AuditEntry) {
+			entries[1].Seq = nil
+		}, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			audit := &fakeAuditRepo{}
+			uow := &fakeUnitOfWork{audit: audit}
+			ctx := context.Background()
+			for i := 0; i < 3; i++ {
+				entry, _ := domain.NewAuditEntry(ctx, "customer.updated", domain.AuditCustomer, "c", nil, map[string]int{"n": i})
+				assert.NoError(t, audit.AppendEntry(ctx, entry))
+			}
+			assert.NoError(t, service.NewAuditSealJob(uow, service.DefaultAuditConfig).RunOnce(ctx))
+
+			tt.tamper(audit.entries)
+			v, err := service.NewAuditService(uow).Verify(ctx)
+			assert.NoError(t, err)
+			assert.False(t, v.OK)
+			assert.NotEmpty(t, v.Problem)
+			if assert.NotNil(t, v.BrokenAt) {
+				assert.Equal(t, tt.brokenAt, *v.BrokenAt)
+			}
+		})
+	}
+}
+
+func TestAuditConfigFromEnv(t *testing.T) {
+	t.Setenv("AUDIT_SEAL_INTERVAL", "1m")
+	cfg, err := service.AuditConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, cfg.SealInterval)
+
+	t.Setenv("AUDIT_SEAL_INTERVAL", "0s")
+	_, err = service.AuditConfigFromEnv()
+	assert.Error(t, err)
+}
@@ -0,0 +1,383 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type CheckoutService struct {
+	uow       domain.UnitOfWork
+	publisher domain.MessagePublisher
+	// baseURL is where the hosted checkout pages are served, e.g. https://pay.example.com.
+	baseURL string
+}
+
+func NewCheckoutService(uow domain.UnitOfWork, publisher domain.MessagePublisher, baseURL string) domain.CheckoutService {
+	return &CheckoutService{
+		uow:       uow,
+		publisher: publisher,
+		baseURL:   strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// CreateSession stores a pending payment together with its session. The
+// payment is screened like any other, but not queued for processing until
+// the payer confirms it.
+func (s *CheckoutService) CreateSession(ctx context.Context, cr *domain.CheckoutSessionRequest) (*domain.CheckoutSession, error) {
+	metadata, err := bindMerchant(ctx, cr.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	cr.Metadata = metadata
+	if err := cr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"checkout session request validation failed",
+			err,
+			map[string]interface{}{"req": cr},
+		)
+	}
+
+	payment := newPayment(&cr.PaymentRequest)
+	var session *domain.CheckoutSession
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) (err error) {
+		session, err = openCheckoutSession(ctx, tx, cr, payment)
+		return err
+	})
+	if err != nil {
+		return nil, paymentCreateError(err, &cr.PaymentRequest)
+	}
+
+	logger.FromContext(ctx).Info("checkout session created",
+		slog.String("checkout_session_id", session.ID.String()),
+		slog.String("payment_id", payment.ID.String()),
+	)
+	return presentCheckout(s.baseURL, session, payment), nil
+}
+
+// openCheckoutSession screens and stores payment, which must not be queued
+// yet, together with an open session for it. The session of a blocked payment
+// is closed right away, since the payment can never be confirmed.
+func openCheckoutSession(ctx context.Context, tx domain.UnitOfWork, cr *domain.CheckoutSessionRequest, payment *domain.Payment) (*domain.CheckoutSession, error) {
+	token, err := randomToken(24)
+	if err != nil {
+		return nil, err
+	}
+	if err := screenAndInsertPayment(ctx, tx, &cr.PaymentRequest, payment); err != nil {
+		return nil, err
+	}
+	session := &domain.CheckoutSession{
+		PaymentID:  payment.ID,
+		Token:      token,
+		SuccessURL: cr.SuccessURL,
+		CancelURL:  cr.CancelURL,
+		ExpiresAt:  time.Now().Add(cr.Expiry()),
+	}
+	if err := tx.CheckoutSessions().CreateCheckoutSession(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, tx, "checkout_session.created", domain.AuditCheckoutSession, session.ID.String(), nil, session); err != nil {
+		return nil, err
+	}
+	if payment.Status != domain.StatusFailed {
+		return session, nil
+	}
+	closed, err := tx.CheckoutSessions().CloseCheckoutSession(ctx, session.ID, domain.CheckoutCanceled)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, tx, "checkout_session.canceled", domain.AuditCheckoutSession, session.ID.String(), session, closed); err != nil {
+		return nil, err
+	}
+	if err := releasePaymentLinkUse(ctx, tx, payment); err != nil {
+		return nil, err
+	}
+	return closed, nil
+}
+
+func (s *CheckoutService) GetSession(ctx context.Context, id string) (*domain.CheckoutSession, error) {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, domain.NewError(
+			domain.ErrInvalidCheckoutID,
+			"Invalid checkout session ID format",
+			"The provided checkout session ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"CheckoutSessionID": id},
+		)
+	}
+
+	session, err := s.uow.CheckoutSessions().GetCheckoutSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, checkoutLookupError(err)
+	}
+	return s.load(ctx, session)
+}
+
+func (s *CheckoutService) GetSessionByToken(ctx context.Context, token string) (*domain.CheckoutSession, error) {
+	session, err := s.uow.CheckoutSessions().GetCheckoutSessionByToken(ctx, token)
+	if err != nil {
+		return nil, checkoutLookupError(err)
+	}
+	return s.load(ctx, session)
+}
+
+// ConfirmSession records the payer's chosen method and queues the payment. A
+// payment held for review is queued once its review is approved instead.
+func (s *CheckoutService) ConfirmSession(ctx context.Context, token string, method *domain.PaymentMethod) (*domain.CheckoutSession, error) {
+	session, err := s.GetSessionByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkoutOpen(session); err != nil {
+		return nil, err
+	}
+	if method == nil {
+		method = &domain.PaymentMethod{}
+	}
+	if err := method.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"payment method validation failed",
+			err,
+			nil,
+		)
+	}
+
+	var payment *domain.Payment
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		before := *session
+		closed, err := tx.CheckoutSessions().CloseCheckoutSession(ctx, session.ID, domain.CheckoutConfirmed)
+		if err != nil {
+			return err
+		}
+		*session = *closed
+		if err := recordAudit(ctx, tx, "checkout_session.confirmed", domain.AuditCheckoutSession, session.ID.String(), before, session); err != nil {
+			return err
+		}
+		payment, err = tx.Payments().SetPaymentMethod(ctx, session.PaymentID, method)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "payment.method_set", domain.AuditPayment, payment.ID.String(), before.Payment, payment)
+	})
+	if err != nil {
+		return nil, checkoutCloseError(err, session)
+	}
+
+	logger.FromContext(ctx).Info("checkout session confirmed",
+		slog.String("checkout_session_id", session.ID.String()),
+		slog.String("payment_id", payment.ID.String()),
+		slog.String("payment_method", string(method.Type)),
+	)
+	if payment.Status == domain.StatusPending {
+		publishPaymentCreated(ctx, s.publisher, payment)
+	}
+	return presentCheckout(s.baseURL, session, payment), nil
+}
+
+// CancelSession closes the session at the payer's request and fails the payment.
+func (s *CheckoutService) CancelSession(ctx context.Context, token string) (*domain.CheckoutSession, error) {
+	session, err := s.GetSessionByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkoutOpen(session); err != nil {
+		return nil, err
+	}
+	if err := s.close(ctx, session, domain.CheckoutCanceled); err != nil {
+		return nil, err
+	}
+	logger.FromContext(ctx).Info("checkout session canceled", slog.String("checkout_session_id", session.ID.String()))
+	return session, nil
+}
+
+// load attaches the payment to session, first expiring the session if it
+// is still open past its expiry. The worker's CheckoutExpiryJob expires
+// abandoned sessions too; doing it on read as well means a session is never
+// shown open past its expiry between the job's runs.
+func (s *CheckoutService) load(ctx context.Context, session *domain.CheckoutSession) (*domain.CheckoutSession, error) {
+	if session.Status == domain.CheckoutOpen && time.Now().After(session.ExpiresAt) {
+		if err := s.close(ctx, session, domain.CheckoutExpired); err != nil {
+			return nil, err
+		}
+		return session, nil
+	}
+
+	payment, err := s.uow.Payments().GetPaymentByID(ctx, session.PaymentID)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to fetch checkout session",
+			"Error occurred while retrieving the session's payment",
+			err,
+			map[string]interface{}{"CheckoutSessionID": session.ID},
+		)
+	}
+	return presentCheckout(s.baseURL, session, payment), nil
+}
+
+// close ends an open session without payment and fails its payment; see
+// closeCheckout. session is updated in place.
+func (s *CheckoutService) close(ctx context.Context, session *domain.CheckoutSession, status domain.CheckoutStatus) error {
+	var payment *domain.Payment
+	err := s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) (err error) {
+		payment, err = closeCheckout(ctx, tx, session, status)
+		return err
+	})
+	if err != nil {
+		return checkoutCloseError(err, session)
+	}
+	presentCheckout(s.baseURL, session, payment)
+	return nil
+}
+
+// closeCheckout ends an open session without payment and fails its payment,
+// which was never queued, giving back the payment's link use. The payment is
+// locked the way ProcessPayment locks it and only failed while nothing else
+// has settled it, so a payment the merchant canceled keeps its status.
+// session is updated in place.
+func closeCheckout(ctx context.Context, tx domain.UnitOfWork, session *domain.CheckoutSession, status domain.CheckoutStatus) (*domain.Payment, error) {
+	before := *session
+	closed, err := tx.CheckoutSessions().CloseCheckoutSession(ctx, session.ID, status)
+	if err != nil {
+		return nil, err
+	}
+	*session = *closed
+	if err := recordAudit(ctx, tx, "checkout_session."+string(status), domain.AuditCheckoutSession, session.ID.String(), before, session); err != nil {
+		return nil, err
+	}
+	payment, err := lockPayment(ctx, tx, session.PaymentID)
+	if err != nil {
+		return nil, err
+	}
+	// A held payment was not charged either; its review is closed by the
+	// SLA job or the reviewer
+	if payment.Status != domain.StatusPending && payment.Status != domain.StatusInReview {
+		return payment, nil
+	}
+	if payment, err = updatePaymentStatus(ctx, tx, payment, domain.StatusFailed); err != nil {
+		return nil, err
+	}
+	return payment, releasePaymentLinkUse(ctx, tx, payment)
+}
+
+// cancelCheckout closes the open session of a payment the merchant canceled,
+// so the payer can no longer confirm it, and gives back its link use. A
+// payment without a session is left alone.
+func cancelCheckout(ctx context.Context, tx domain.UnitOfWork, payment *domain.Payment) error {
+	session, err := tx.CheckoutSessions().GetCheckoutSessionByPaymentID(ctx, payment.ID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if session.Status != domain.CheckoutOpen {
+		return nil
+	}
+	closed, err := tx.CheckoutSessions().CloseCheckoutSession(ctx, session.ID, domain.CheckoutCanceled)
+	if err != nil {
+		return err
+	}
+	if err := recordAudit(ctx, tx, "checkout_session.canceled", domain.AuditCheckoutSession, session.ID.String(), session, closed); err != nil {
+		return err
+	}
+	return releasePaymentLinkUse(ctx, tx, payment)
+}
+
+func presentCheckout(baseURL string, session *domain.CheckoutSession, payment *domain.Payment) *domain.CheckoutSession {
+	session.Payment = payment
+	session.URL = baseURL + "/checkout/" + session.Token
+	return session
+}
+
+// checkoutOpen reports why a session can no longer be confirmed or canceled.
+func checkoutOpen(session *domain.CheckoutSession) error {
+	switch session.Status {
+	case domain.CheckoutOpen:
+		return nil
+	case domain.CheckoutExpired:
+		return domain.NewError(
+			domain.ErrCheckoutExpired,
+			"Checkout session expired",
+			"The checkout session expired before it was completed",
+			nil,
+			map[string]interface{}{"CheckoutSessionID": session.ID},
+		)
+	default:
+		return domain.NewError(
+			domain.ErrCheckoutClosed,
+			"Checkout session closed",
+			"This checkout session has already been "+string(session.Status),
+			nil,
+			map[string]interface{}{"CheckoutSessionID": session.ID, "status": session.Status},
+		)
+	}
+}
+
+func checkoutLookupError(err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrCheckoutNotFound,
+			"Checkout session not found",
+			"The specified checkout session could not be found",
+			err,
+			nil,
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to fetch checkout session",
+		"Error occurred while retrieving the checkout session",
+		err,
+		nil,
+	)
+}
+
+// checkoutCloseError maps a failed transition. ErrNotFound means another
+// request closed the session first.
+func checkoutCloseError(err error, session *domain.CheckoutSession) error {
+	var derr domain.Error
+	if errors.As(err, &derr) {
+		return derr
+	}
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrCheckoutClosed,
+			"Checkout session closed",
+			"The checkout session was closed by another request",
+			err,
+			map[string]interface{}{"CheckoutSessionID": session.ID},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to update checkout session",
+		"Error occurred while updating the checkout session",
+		err,
+		map[string]interface{}{"CheckoutSessionID": session.ID},
+	)
+}
+
+// randomToken returns n random bytes encoded for use in URLs.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+)
+
+type CheckoutExpiryConfig struct {
+	// PollInterval is how often the job looks for expired sessions.
+	PollInterval time.Duration
+}
+
+// DefaultCheckoutExpiryConfig is used for settings left unset.
+var DefaultCheckoutExpiryConfig = CheckoutExpiryConfig{
+	PollInterval: time.Minute,
+}
+
+// CheckoutExpiryConfigFromEnv reads CHECKOUT_EXPIRY_POLL_INTERVAL. An unset
+// value falls back to DefaultCheckoutExpiryConfig.
+func CheckoutExpiryConfigFromEnv() (CheckoutExpiryConfig, error) {
+	cfg := DefaultCheckoutExpiryConfig
+	if v := os.Getenv("CHECKOUT_EXPIRY_POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid CHECKOUT_EXPIRY_POLL_INTERVAL value: %s", v)
+		}
+		cfg.PollInterval = d
+	}
+	return cfg, nil
+}
+
+// CheckoutExpiryJob runs in the worker. It expires open checkout sessions
+// past their expiry and fails their payments, the way reading such a
+// session does, so an abandoned session's payment does not stay pending.
+// Sessions are claimed with SKIP LOCKED, so several workers can run it side
+// by side.
+type CheckoutExpiryJob struct {
+	uow domain.UnitOfWork
+	cfg CheckoutExpiryConfig
+}
+
+func NewCheckoutExpiryJob(uow domain.UnitOfWork, cfg CheckoutExpiryConfig) *CheckoutExpiryJob {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultCheckoutExpiryConfig.PollInterval
+	}
+	return &CheckoutExpiryJob{uow: uow, cfg: cfg}
+}
+
+// Run polls until ctx is canceled.
+func (j *CheckoutExpiryJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := j.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Error("checkout expiry job run failed", slog.Any("error", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce expires every session that was open past its expiry when the run
+// started.
+func (j *CheckoutExpiryJob) RunOnce(ctx context.Context) error {
+	now := time.Now()
+	for {
+		expired, err := j.expireNext(ctx, now)
+		if err != nil {
+			return err
+		}
+		if !expired {
+			return nil
+		}
+	}
+}
+
+// expireNext claims one session that expired by now and closes it.
+func (j *CheckoutExpiryJob) expireNext(ctx context.Context, now time.Time) (bool, error) {
+	var (
+		session *domain.CheckoutSession
+		payment *domain.Payment
+	)
+	err := j.uow.WithinTx(ctx, func(tx domain.UnitOfWork) (err error) {
+		session, err = tx.CheckoutSessions().ClaimExpiredCheckoutSession(ctx, now)
+		if err != nil {
+			return err
+		}
+		payment, err = closeCheckout(ctx, tx, session, domain.CheckoutExpired)
+		return err
+	})
+	if errors.Is(err, domain.ErrNotFound) && session == nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to expire checkout session: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("checkout session expired",
+		slog.String("checkout_session_id", session.ID.String()),
+		slog.String("payment_id", session.PaymentID.String()),
+		slog.String("payment_status", string(payment.Status)),
+	)
+	return true, nil
+}
@@ -0,0 +1,290 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+type fakeCheckoutRepo struct {
+	byID map[uuid.UUID]*domain.CheckoutSession
+}
+
+func (r *fakeCheckoutRepo) CreateCheckoutSession(ctx context.Context, s *domain.CheckoutSession) error {
+	s.ID = uuid.New()
+	s.Status = domain.CheckoutOpen
+	s.CreatedAt = time.Now()
+	s.UpdatedAt = s.CreatedAt
+	stored := *s
+	r.byID[s.ID] = &stored
+	return nil
+}
+
+func (r *fakeCheckoutRepo) GetCheckoutSessionByID(ctx context.Context, id uuid.UUID) (*domain.CheckoutSession, error) {
+	s, ok := r.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	found := *s
+	return &found, nil
+}
+
+func (r *fakeCheckoutRepo) GetCheckoutSessionByToken(ctx context.Context, token string) (*domain.CheckoutSession, error) {
+	for _, s := range r.byID {
+		if s.Token == token {
+			found := *s
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeCheckoutRepo) GetCheckoutSessionByPaymentID(ctx context.Context, paymentID uuid.UUID) (*domain.CheckoutSession, error) {
+	for _, s := range r.byID {
+		if s.PaymentID == paymentID {
+			found := *s
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeCheckoutRepo) CloseCheckoutSession(ctx context.Context, id uuid.UUID, status domain.CheckoutStatus) (*domain.CheckoutSession, error) {
+	s, ok := r.byID[id]
+	if !ok || s.Status != domain.CheckoutOpen {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	s.Status = status
+	closed := *s
+	return &closed, nil
+}
+
+func (r *fakeCheckoutRepo) ClaimExpiredCheckoutSession(ctx context.Context, now time.Time) (*domain.CheckoutSession, error) {
+	var found *domain.CheckoutSession
+	for _, s := range r.byID {
+		if s.Status == domain.CheckoutOpen && !s.ExpiresAt.After(now) && (found == nil || s.ExpiresAt.Before(found.ExpiresAt)) {
+			found = s
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	claimed := *found
+	return &claimed, nil
+}
+
+func (r *fakeRepo) UpdatePaymentStatus(ctx context.Context, id uuid.UUID, status domain.PaymentStatus) (*domain.Payment, error) {
+	p, err := r.GetPaymentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Status = status
+	return p, nil
+}
+
+func (r *fakeRepo) SetPaymentMethod(ctx context.Context, id uuid.UUID, method *domain.PaymentMethod) (*domain.Payment, error) {
+	p, err := r.GetPaymentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if p.Status != domain.StatusPending && p.Status != domain.StatusInReview {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	p.PaymentMethod = method
+	return p, nil
+}
+
+func setupCheckoutService() (domain.CheckoutService, *fakeUnitOfWork, *fakePublisher) {
+	uow := &fakeUnitOfWork{
+		repo:      &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)},
+		customers: newFakeCustomerRepo(),
+		checkouts: &fakeCheckoutRepo{byID: make(map[uuid.UUID]*domain.CheckoutSession)},
+	}
+	pub := &fakePublisher{}
+	return service.NewCheckoutService(uow, pub, "https://pay.example.com/"), uow, pub
+}
+
+func checkoutRequest(reference string) *domain.CheckoutSessionRequest {
+	return &domain.CheckoutSessionRequest{
+		PaymentRequest: domain.PaymentRequest{Amount: 100, Currency: "ETB", Reference: reference},
+		SuccessURL:     "https://shop.example.com/success",
+		CancelURL:      "https://shop.example.com/cancel",
+	}
+}
+
+var mobileMoney = &domain.PaymentMethod{
+	Type:        domain.MethodMobileMoney,
+	MobileMoney: &domain.MobileMoneyDetails{Provider: "telebirr", MSISDN: "+251911234567"},
+}
+
+func TestCreateCheckoutSession(t *testing.T) {
+	t.Run("creates a pending payment without queueing it", func(t *testing.T) {
+		svc, _, pub := setupCheckoutService()
+		s, err := svc.CreateSession(context.Background(), checkoutRequest("ref-1"))
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CheckoutOpen, s.Status)
+		assert.Equal(t, "https://pay.example.com/checkout/"+s.Token, s.URL)
+		assert.Equal(t, domain.StatusPending, s.Payment.Status)
+		assert.WithinDuration(t, time.Now().Add(domain.DefaultCheckoutExpiry), s.ExpiresAt, time.Minute)
+		assert.Empty(t, pub.published)
+	})
+
+	tests := []struct {
+		name   string
+		modify func(cr *domain.CheckoutSessionRequest)
+	}{
+		{"missing success url", func(cr *domain.CheckoutSessionRequest) { cr.SuccessURL = "" }},
+		{"invalid cancel url", func(cr *domain.CheckoutSessionRequest) { cr.CancelURL = "not a url" }},
+		{"expiry too long", func(cr *domain.CheckoutSessionRequest) { cr.ExpiresIn = 2 * 86400 }},
+		{"payment method given up front", func(cr *domain.CheckoutSessionRequest) { cr.PaymentMethod = mobileMoney }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _, _ := setupCheckoutService()
+			cr := checkoutRequest("ref-1")
+			tt.modify(cr)
+			_, err := svc.CreateSession(context.Background(), cr)
+			assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+		})
+	}
+}
+
+func TestConfirmCheckoutSession(t *testing.T) {
+	t.Run("records the method and queues the payment", func(t *testing.T) {
+		svc, uow, pub := setupCheckoutService()
+		created, _ := svc.CreateSession(context.Background(), checkoutRequest("ref-1"))
+
+		s, err := svc.ConfirmSession(context.Background(), created.Token, mobileMoney)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CheckoutConfirmed, s.Status)
+		assert.Equal(t, domain.MethodMobileMoney, uow.repo.byID[s.PaymentID].PaymentMethod.Type)
+		assert.Equal(t, []string{s.PaymentID.String()}, pub.published)
+
+		_, err = svc.ConfirmSession(context.Background(), created.Token, mobileMoney)
+		assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+		assert.Len(t, pub.published, 1)
+	})
+
+	t.Run("invalid method", func(t *testing.T) {
+		svc, _, _ := setupCheckoutService()
+		created, _ := svc.CreateSession(context.Background(), checkoutRequest("ref-1"))
+		_, err := svc.ConfirmSession(context.Background(), created.Token, &domain.PaymentMethod{Type: domain.MethodCard})
+		assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		svc, _, _ := setupCheckoutService()
+		_, err := svc.ConfirmSession(context.Background(), "nope", mobileMoney)
+		assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	})
+
+	t.Run("expired session fails the payment", func(t *testing.T) {
+		svc, uow, pub := setupCheckoutService()
+		created, _ := svc.CreateSession(context.Background(), checkoutRequest("ref-1"))
+		uow.checkouts.byID[created.ID].ExpiresAt = time.Now().Add(-time.Second)
+
+		_, err := svc.ConfirmSession(context.Background(), created.Token, mobileMoney)
+		assert.Equal(t, http.StatusGone, errorStatus(t, err))
+		assert.Equal(t, domain.StatusFailed, uow.repo.byID[created.PaymentID].Status)
+		assert.Empty(t, pub.published)
+
+		s, err := svc.GetSession(context.Background(), created.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CheckoutExpired, s.Status)
+	})
+}
+
+func TestCancelCheckoutSession(t *testing.T) {
+	svc, uow, pub := setupCheckoutService()
+	created, _ := svc.CreateSession(context.Background(), checkoutRequest("ref-1"))
+
+	s, err := svc.CancelSession(context.Background(), created.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CheckoutCanceled, s.Status)
+	assert.Equal(t, domain.StatusFailed, s.Payment.Status)
+	assert.Equal(t, domain.StatusFailed, uow.repo.byID[created.PaymentID].Status)
+	assert.Empty(t, pub.published)
+
+	_, err = svc.ConfirmSession(context.Background(), created.Token, mobileMoney)
+	assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+}
+
+func TestCloseCheckoutKeepsSettledPayment(t *testing.T) {
+	for _, status := range []domain.PaymentStatus{domain.StatusCanceled, domain.StatusSuccess} {
+		t.Run(string(status), func(t *testing.T) {
+			svc, uow, _ := setupCheckoutService()
+			created, _ := svc.CreateSession(context.Background(), checkoutRequest("ref-1"))
+			// Settled after the session was read, e.g. by the worker
+			uow.repo.byID[created.PaymentID].Status = status
+			uow.checkouts.byID[created.ID].ExpiresAt = time.Now().Add(-time.Second)
+
+			s, err := svc.GetSession(context.Background(), created.ID.String())
+			assert.NoError(t, err)
+			assert.Equal(t, domain.CheckoutExpired, s.Status)
+			assert.Equal(t, status, s.Payment.Status)
+			assert.Equal(t, status, uow.repo.byID[created.PaymentID].Status)
+		})
+	}
+}
+
+func TestCheckoutExpiryJob(t *testing.T) {
+	svc, uow, pub := setupCheckoutService()
+	abandoned, _ := svc.CreateSession(context.Background(), checkoutRequest("ref-1"))
+	open, _ := svc.CreateSession(context.Background(), checkoutRequest("ref-2"))
+	uow.checkouts.byID[abandoned.ID].ExpiresAt = time.Now().Add(-time.Second)
+
+	job := service.NewCheckoutExpiryJob(uow, service.CheckoutExpiryConfig{})
+	assert.NoError(t, job.RunOnce(context.Background()))
+	assert.Equal(t, domain.CheckoutExpired, uow.checkouts.byID[abandoned.ID].Status)
+	assert.Equal(t, domain.StatusFailed, uow.repo.byID[abandoned.PaymentID].Status)
+	assert.Equal(t, domain.CheckoutOpen, uow.checkouts.byID[open.ID].Status)
+	assert.Equal(t, domain.StatusPending, uow.repo.byID[open.PaymentID].Status)
+	assert.Empty(t, pub.published)
+
+	// Nothing is left to expire
+	assert.NoError(t, job.RunOnce(context.Background()))
+	_, err := svc.ConfirmSession(context.Background(), abandoned.Token, mobileMoney)
+	assert.Equal(t, http.StatusGone, errorStatus(t, err))
+}
+
+func TestCancelPaymentClosesCheckout(t *testing.T) {
+	svc, uow, _ := setupCheckoutService()
+	acme := domain.WithMerchant(context.Background(), "acme")
+	created, _ := svc.CreateSession(acme, checkoutRequest("ref-1"))
+
+	payments := service.NewPaymentService(uow, &fakePublisher{}, nil)
+	_, err := payments.CancelPayment(acme, created.PaymentID.String())
+	assert.NoError(t, err)
+
+	s, err := svc.GetSessionByToken(context.Background(), created.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.CheckoutCanceled, s.Status)
+	assert.Equal(t, domain.StatusCanceled, s.Payment.Status)
+
+	_, err = svc.ConfirmSession(context.Background(), created.Token, mobileMoney)
+	assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+}
+
+func TestGetCheckoutSession(t *testing.T) {
+	svc, _, _ := setupCheckoutService()
+	created, _ := svc.CreateSession(context.Background(), checkoutRequest("ref-1"))
+
+	s, err := svc.GetSession(context.Background(), created.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, created.PaymentID, s.Payment.ID)
+	assert.True(t, strings.HasSuffix(s.URL, created.Token))
+
+	_, err = svc.GetSession(context.Background(), "not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	_, err = svc.GetSession(context.Background(), uuid.NewString())
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+}
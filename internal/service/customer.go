@@ -0,0 +1,265 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type CustomerService struct {
+	uow domain.UnitOfWork
+}
+
+func NewCustomerService(uow domain.UnitOfWork) domain.CustomerService {
+	return &CustomerService{uow: uow}
+}
+
+func (s *CustomerService) CreateCustomer(ctx context.Context, cr *domain.CustomerRequest) (*domain.Customer, error) {
+	if err := cr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"customer request validation failed",
+			err,
+			nil,
+		)
+	}
+
+	customer := customerFromRequest(callerMerchant(ctx), cr)
+	err := s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		return createCustomer(ctx, tx, customer)
+	})
+	if err != nil {
+		return nil, customerWriteError(err, cr)
+	}
+
+	logger.FromContext(ctx).Info("customer created", slog.String("customer_id", customer.ID.String()))
+	return customer, nil
+}
+
+func (s *CustomerService) GetCustomerByID(ctx context.Context, id string) (*domain.Customer, error) {
+	customerID, err := parseCustomerID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	customer, err := s.uow.Customers().GetCustomerByID(ctx, callerMerchant(ctx), customerID)
+	if err != nil {
+		return nil, customerLookupError(err, id)
+	}
+	return customer, nil
+}
+
+func (s *CustomerService) ListCustomers(ctx context.Context, page domain.Page) (*domain.CustomerList, error) {
+	customers, err := s.uow.Customers().ListCustomers(ctx, callerMerchant(ctx), page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list customers",
+			"Error occurred while retrieving customers",
+			err,
+			nil,
+		)
+	}
+	return &domain.CustomerList{Data: customers, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (s *CustomerService) UpdateCustomer(ctx context.Context, id string, cr *domain.CustomerRequest) (*domain.Customer, error) {
+	customerID, err := parseCustomerID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := cr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"customer request validation failed",
+			err,
+			nil,
+		)
+	}
+
+	customer := customerFromRequest(callerMerchant(ctx), cr)
+	customer.ID = customerID
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		before, err := tx.Customers().GetCustomerByID(ctx, customer.MerchantID, customerID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Customers().UpdateCustomer(ctx, customer); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "customer.updated", domain.AuditCustomer, id, before, customer)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, customerLookupError(err, id)
+		}
+		return nil, customerWriteError(err, cr)
+	}
+	return customer, nil
+}
+
+func (s *CustomerService) DeleteCustomer(ctx context.Context, id string) error {
+	customerID, err := parseCustomerID(id)
+	if err != nil {
+		return err
+	}
+
+	merchantID := callerMerchant(ctx)
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		before, err := tx.Customers().GetCustomerByID(ctx, merchantID, customerID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Customers().DeleteCustomer(ctx, merchantID, customerID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "customer.deleted", domain.AuditCustomer, id, before, nil)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidReference) {
+			return domain.NewError(
+				domain.ErrCustomerHasPayments,
+				"Customer has payments",
+				"A customer with payments cannot be deleted",
+				err,
+				map[string]interface{}{"CustomerID": id},
+			)
+		}
+		return customerLookupError(err, id)
+	}
+	return nil
+}
+
+func (s *CustomerService) ListCustomerPayments(ctx context.Context, id string, page domain.Page) (*domain.PaymentList, error) {
+	customerID, err := parseCustomerID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Distinguish an unknown customer from one without payments
+	if _, err := s.uow.Customers().GetCustomerByID(ctx, callerMerchant(ctx), customerID); err != nil {
+		return nil, customerLookupError(err, id)
+	}
+
+	payments, err := s.uow.Payments().ListPaymentsByCustomer(ctx, customerID, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list payments",
+			"Error occurred while retrieving the customer's payments",
+			err,
+			map[string]interface{}{"CustomerID": id},
+		)
+	}
+	return &domain.PaymentList{Data: payments, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// resolvePayer returns merchantID's customer with the payer's external ID,
+// creating a new customer when there is none.
+func resolvePayer(ctx context.Context, tx domain.UnitOfWork, merchantID string, payer *domain.CustomerRequest) (*domain.Customer, error) {
+	if payer.ExternalID != "" {
+		existing, err := tx.Customers().GetCustomerByExternalID(ctx, merchantID, payer.ExternalID)
+		if err == nil {
+			return existing, nil
+		}
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewError(
+				storageErrorCode(err),
+				"Failed to fetch payer",
+				"Error occurred while looking up the payer",
+				err,
+				nil,
+			)
+		}
+	}
+
+	customer := customerFromRequest(merchantID, payer)
+	if err := createCustomer(ctx, tx, customer); err != nil {
+		return nil, customerWriteError(err, payer)
+	}
+	return customer, nil
+}
+
+// createCustomer stores customer and records its creation in the audit log.
+func createCustomer(ctx context.Context, tx domain.UnitOfWork, customer *domain.Customer) error {
+	if err := tx.Customers().CreateCustomer(ctx, customer); err != nil {
+		return err
+	}
+	return recordAudit(ctx, tx, "customer.created", domain.AuditCustomer, customer.ID.String(), nil, customer)
+}
+
+func customerFromRequest(merchantID string, cr *domain.CustomerRequest) *domain.Customer {
+	return &domain.Customer{
+		Name:       cr.Name,
+		Email:      cr.Email,
+		Phone:      cr.Phone,
+		ExternalID: cr.ExternalID,
+		MerchantID: merchantID,
+	}
+}
+
+// callerMerchant returns the merchant whose API key made the request, or ""
+// for an anonymous caller. Customers are kept apart per merchant.
+func callerMerchant(ctx context.Context) string {
+	merchantID, _ := domain.MerchantFromContext(ctx)
+	return merchantID
+}
+
+func parseCustomerID(id string) (uuid.UUID, error) {
+	customerID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewError(
+			domain.ErrInvalidCustomerID,
+			"Invalid customer ID format",
+			"The provided customer ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"CustomerID": id},
+		)
+	}
+	return customerID, nil
+}
+
+func customerLookupError(err error, id string) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrCustomerNotFound,
+			"Customer not found",
+			"The specified customer could not be found",
+			err,
+			map[string]interface{}{"CustomerID": id},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to fetch customer",
+		"Error occurred while retrieving customer information",
+		err,
+		map[string]interface{}{"CustomerID": id},
+	)
+}
+
+func customerWriteError(err error, cr *domain.CustomerRequest) error {
+	if errors.Is(err, domain.ErrConflict) {
+		return domain.NewError(
+			domain.ErrDuplicateExternalID,
+			"Customer with this external ID already exists",
+			"A customer with the same external_id has already been created",
+			err,
+			map[string]interface{}{"ExternalID": cr.ExternalID},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to save customer",
+		"Error occurred while saving the customer",
+		err,
+		nil,
+	)
+}
@@ -0,0 +1,244 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+type fakeCustomerRepo struct {
+	byID        map[uuid.UUID]*domain.Customer
+	hasPayments map[uuid.UUID]bool
+}
+
+func newFakeCustomerRepo() *fakeCustomerRepo {
+	return &fakeCustomerRepo{
+		byID:        make(map[uuid.UUID]*domain.Customer),
+		hasPayments: make(map[uuid.UUID]bool),
+	}
+}
+
+func (r *fakeCustomerRepo) CreateCustomer(ctx context.Context, c *domain.Customer) error {
+	if c.ExternalID != "" {
+		if _, err := r.GetCustomerByExternalID(ctx, c.MerchantID, c.ExternalID); err == nil {
+			return fmt.Errorf("%w: duplicate key", domain.ErrConflict)
+		}
+	}
+	c.ID = uuid.New()
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = c.CreatedAt
+	r.byID[c.ID] = c
+	return nil
+}
+
+func (r *fakeCustomerRepo) GetCustomerByID(ctx context.Context, merchantID string, id uuid.UUID) (*domain.Customer, error) {
+	c, ok := r.byID[id]
+	if !ok || c.MerchantID != merchantID {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	return c, nil
+}
+
+func (r *fakeCustomerRepo) GetCustomerByExternalID(ctx context.Context, merchantID, externalID string) (*domain.Customer, error) {
+	for _, c := range r.byID {
+		if c.MerchantID == merchantID && c.ExternalID == externalID {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeCustomerRepo) ListCustomers(ctx context.Context, merchantID string, page domain.Page) ([]domain.Customer, error) {
+	customers := make([]domain.Customer, 0, len(r.byID))
+	for _, c := range r.byID {
+		if c.MerchantID == merchantID {
+			customers = append(customers, *c)
+		}
+	}
+	return customers, nil
+}
+
+func (r *fakeCustomerRepo) UpdateCustomer(ctx context.Context, c *domain.Customer) error {
+	if existing, ok := r.byID[c.ID]; !ok || existing.MerchantID != c.MerchantID {
+		return fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	r.byID[c.ID] = c
+	return nil
+}
+
+func (r *fakeCustomerRepo) DeleteCustomer(ctx context.Context, merchantID string, id uuid.UUID) error {
+	if c, ok := r.byID[id]; !ok || c.MerchantID != merchantID {
+		return fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	if r.hasPayments[id] {
+		return fmt.Errorf("%w: foreign key violation", domain.ErrInvalidReference)
+	}
+	delete(r.byID, id)
+	return nil
+}
+
+func (r *fakeRepo) ListPaymentsByCustomer(ctx context.Context, customerID uuid.UUID, page domain.Page) ([]domain.Payment, error) {
+	var payments []domain.Payment
+	for _, p := range r.byID {
+		if p.CustomerID != nil && *p.CustomerID == customerID {
+			payments = append(payments, *p)
+		}
+	}
+	return payments, nil
+}
+
+func setupCustomerService() (domain.CustomerService, *fakeCustomerRepo) {
+	uow := &fakeUnitOfWork{
+		repo:      &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)},
+		customers: newFakeCustomerRepo(),
+	}
+	return service.NewCustomerService(uow), uow.customers
+}
+
+func errorStatus(t *testing.T, err error) int {
+	t.Helper()
+	var derr domain.Error
+	if !errors.As(err, &derr) {
+		t.Fatalf("expected domain.Error, got %T", err)
+	}
+	return derr.Code
+}
+
+func TestCreateCustomer(t *testing.T) {
+	t.Run("creates", func(t *testing.T) {
+		svc, repo := setupCustomerService()
+		c, err := svc.CreateCustomer(context.Background(), &domain.CustomerRequest{Name: "Abebe", Email: "abebe@example.com"})
+		assert.NoError(t, err)
+		assert.NotEqual(t, uuid.Nil, c.ID)
+		assert.Len(t, repo.byID, 1)
+	})
+
+	t.Run("validation failed", func(t *testing.T) {
+		svc, _ := setupCustomerService()
+		_, err := svc.CreateCustomer(context.Background(), &domain.CustomerRequest{Name: "Abebe", Phone: "0911"})
+		assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	})
+
+	t.Run("duplicate external id", func(t *testing.T) {
+		svc, _ := setupCustomerService()
+		req := &domain.CustomerRequest{Name: "Abebe", ExternalID: "cust-1"}
+		_, err := svc.CreateCustomer(context.Background(), req)
+		assert.NoError(t, err)
+
+		_, err = svc.CreateCustomer(context.Background(), req)
+		assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+	})
+}
+
+func TestDeleteCustomer(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         func(repo *fakeCustomerRepo) string
+		wantStatus int
+	}{
+		{
+			name:       "invalid id",
+			id:         func(*fakeCustomerRepo) string { return "not-a-uuid" },
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "not found",
+			id:         func(*fakeCustomerRepo) string { return uuid.NewString() },
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name: "has payments",
+			id: func(repo *fakeCustomerRepo) string {
+				c := &domain.Customer{Name: "Abebe"}
+				_ = repo.CreateCustomer(context.Background(), c)
+				repo.hasPayments[c.ID] = true
+				return c.ID.String()
+			},
+			wantStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo := setupCustomerService()
+			err := svc.DeleteCustomer(context.Background(), tt.id(repo))
+			assert.Equal(t, tt.wantStatus, errorStatus(t, err))
+		})
+	}
+}
+
+func TestCustomerMerchantScope(t *testing.T) {
+	svc, repo := setupCustomerService()
+	acme := domain.WithMerchant(context.Background(), "acme")
+	globex := domain.WithMerchant(context.Background(), "globex")
+	c, err := svc.CreateCustomer(acme, &domain.CustomerRequest{Name: "Abebe", ExternalID: "cust-1"})
+	assert.NoError(t, err)
+	assert.Equal(t, "acme", c.MerchantID)
+
+	_, err = svc.GetCustomerByID(acme, c.ID.String())
+	assert.NoError(t, err)
+	_, err = svc.GetCustomerByID(globex, c.ID.String())
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	_, err = svc.UpdateCustomer(globex, c.ID.String(), &domain.CustomerRequest{Name: "Kebede"})
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, svc.DeleteCustomer(globex, c.ID.String())))
+	_, err = svc.ListCustomerPayments(globex, c.ID.String(), domain.Page{Limit: 20})
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	list, err := svc.ListCustomers(globex, domain.Page{Limit: 20})
+	assert.NoError(t, err)
+	assert.Empty(t, list.Data)
+	assert.Equal(t, "Abebe", repo.byID[c.ID].Name)
+
+	// External IDs are unique per merchant
+	_, err = svc.CreateCustomer(globex, &domain.CustomerRequest{Name: "Almaz", ExternalID: "cust-1"})
+	assert.NoError(t, err)
+}
+
+func TestCreatePaymentWithPayer(t *testing.T) {
+	payer := &domain.CustomerRequest{Name: "Abebe", ExternalID: "cust-1"}
+
+	t.Run("creates the payer once", func(t *testing.T) {
+		_, repo, _ := setupService(nil)
+		customers := newFakeCustomerRepo()
+		svc := service.NewPaymentService(&fakeUnitOfWork{repo: repo, customers: customers}, &fakePublisher{}, &fakeRouter{})
+
+		first, err := svc.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "order-1", Payer: payer})
+		assert.NoError(t, err)
+		second, err := svc.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "order-2", Payer: payer})
+		assert.NoError(t, err)
+
+		assert.Len(t, customers.byID, 1)
+		if assert.NotNil(t, first.CustomerID) && assert.NotNil(t, second.CustomerID) {
+			assert.Equal(t, *first.CustomerID, *second.CustomerID)
+		}
+	})
+
+	t.Run("another merchant's customer id", func(t *testing.T) {
+		_, repo, _ := setupService(nil)
+		customers := newFakeCustomerRepo()
+		svc := service.NewPaymentService(&fakeUnitOfWork{repo: repo, customers: customers}, &fakePublisher{}, &fakeRouter{})
+		acme := domain.WithMerchant(context.Background(), "acme")
+		first, err := svc.CreatePayment(acme, &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "order-1", Payer: payer})
+		assert.NoError(t, err)
+
+		globex := domain.WithMerchant(context.Background(), "globex")
+		_, err = svc.CreatePayment(globex, &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "order-2", CustomerID: first.CustomerID})
+		assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	})
+
+	t.Run("unknown customer id", func(t *testing.T) {
+		svc, _, _ := setupService(fmt.Errorf("%w: foreign key violation", domain.ErrInvalidReference))
+		id := uuid.New()
+		_, err := svc.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "order-1", CustomerID: &id})
+		assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	})
+}
@@ -0,0 +1,509 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type DisputeService struct {
+	uow    domain.UnitOfWork
+	blobs  domain.BlobStore
+	events domain.EventPublisher
+}
+
+func NewDisputeService(uow domain.UnitOfWork, blobs domain.BlobStore, events domain.EventPublisher) domain.DisputeService {
+	return &DisputeService{uow: uow, blobs: blobs, events: events}
+}
+
+// CreateDispute records a chargeback against a successful payment. The
+// dispute starts out needing a response. The payment is locked while its
+// open and lost disputes are summed, so concurrent disputes cannot together
+// claim more than the payment amount.
+func (s *DisputeService) CreateDispute(ctx context.Context, dr *domain.DisputeRequest) (*domain.Dispute, error) {
+	if err := dr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"dispute request validation failed",
+			err,
+			map[string]interface{}{"req": dr},
+		)
+	}
+
+	payment, err := s.uow.Payments().GetPaymentByID(ctx, dr.PaymentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewError(
+				domain.ErrPaymentNotFound,
+				"Payment not found",
+				"The disputed payment could not be found",
+				err,
+				map[string]interface{}{"PaymentID": dr.PaymentID},
+			)
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to fetch payment",
+			"Error occurred while retrieving the disputed payment",
+			err,
+			map[string]interface{}{"PaymentID": dr.PaymentID},
+		)
+	}
+	if payment.Status != domain.StatusSuccess {
+		return nil, domain.NewError(
+			domain.ErrPaymentNotDisputable,
+			"Payment cannot be disputed",
+			"Only successful payments can be disputed; this one is "+string(payment.Status),
+			nil,
+			map[string]interface{}{"PaymentID": payment.ID, "status": payment.Status},
+		)
+	}
+	amount := payment.Amount
+	if dr.Amount != nil {
+		if *dr.Amount > payment.Amount {
+			return nil, domain.NewError(
+				domain.ErrValidationFailed,
+				"validation failed",
+				"amount cannot exceed the payment amount",
+				nil,
+				map[string]interface{}{"amount": *dr.Amount, "payment_amount": payment.Amount},
+			)
+		}
+		amount = *dr.Amount
+	}
+
+	dispute := &domain.Dispute{
+		PaymentID:         payment.ID,
+		ProviderReference: dr.ProviderReference,
+		ReasonCode:        dr.ReasonCode,
+		Amount:            amount,
+		Currency:          payment.Currency,
+		EvidenceDueBy:     dr.EvidenceDueBy,
+	}
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		if _, err := tx.Payments().GetPaymentByIDWithLock(ctx, payment.ID); err != nil {
+			return err
+		}
+		disputed, err := tx.Disputes().DisputedAmount(ctx, payment.ID)
+		if err != nil {
+			return err
+		}
+		if disputed+amount > payment.Amount {
+			return domain.NewError(
+				domain.ErrDisputeAmountExceeded,
+				"Dispute amount exceeded",
+				"The payment's open and lost disputes together cannot exceed the payment amount",
+				nil,
+				map[string]interface{}{"amount": amount, "disputed_amount": disputed, "payment_amount": payment.Amount},
+			)
+		}
+		if err := tx.Disputes().CreateDispute(ctx, dispute); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "dispute.created", domain.AuditDispute, dispute.ID.String(), nil, dispute)
+	})
+	if err != nil {
+		var derr domain.Error
+		if errors.As(err, &derr) {
+			return nil, derr
+		}
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, domain.NewError(
+				domain.ErrDuplicateDispute,
+				"Duplicate dispute",
+				"A dispute with this provider reference already exists",
+				err,
+				map[string]interface{}{"provider_reference": dr.ProviderReference},
+			)
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to create dispute",
+			"Error occurred while saving the dispute",
+			err,
+			map[string]interface{}{"req": dr},
+		)
+	}
+
+	logger.FromContext(ctx).Info("dispute created",
+		slog.String("dispute_id", dispute.ID.String()),
+		slog.String("payment_id", dispute.PaymentID.String()),
+		slog.String("reason_code", dispute.ReasonCode),
+	)
+	s.publish(ctx, domain.EventDisputeCreated, dispute)
+	return dispute, nil
+}
+
+func (s *DisputeService) GetDisputeByID(ctx context.Context, id string) (*domain.Dispute, error) {
+	disputeID, err := parseDisputeID(id)
+	if err != nil {
+		return nil, err
+	}
+	return s.getDispute(ctx, disputeID)
+}
+
+// getDispute fetches a dispute the caller may see. Another merchant's
+// dispute is reported as not found.
+func (s *DisputeService) getDispute(ctx context.Context, disputeID uuid.UUID) (*domain.Dispute, error) {
+	dispute, err := s.uow.Disputes().GetDisputeByID(ctx, disputeID)
+	if err == nil {
+		err = checkDisputeMerchant(ctx, s.uow, dispute)
+	}
+	if err != nil {
+		return nil, disputeLookupError(err, disputeID)
+	}
+	return dispute, nil
+}
+
+// checkDisputeMerchant returns ErrNotFound when the dispute's payment belongs
+// to a merchant other than the caller's.
+func checkDisputeMerchant(ctx context.Context, uow domain.UnitOfWork, dispute *domain.Dispute) error {
+	if _, ok := domain.MerchantFromContext(ctx); !ok {
+		return nil
+	}
+	payment, err := uow.Payments().GetPaymentByID(ctx, dispute.PaymentID)
+	if err != nil {
+		return err
+	}
+	if !visibleToMerchant(ctx, payment.Metadata[domain.MerchantMetadataKey]) {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// ListDisputes lists disputes newest first. A merchant only sees disputes on
+// its own payments.
+func (s *DisputeService) ListDisputes(ctx context.Context, filter domain.DisputeFilter, page domain.Page) (*domain.DisputeList, error) {
+	if merchantID, ok := domain.MerchantFromContext(ctx); ok {
+		filter.MerchantID = &merchantID
+	}
+	disputes, err := s.uow.Disputes().ListDisputes(ctx, filter, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list disputes",
+			"Error occurred while retrieving disputes",
+			err,
+			nil,
+		)
+	}
+	return &domain.DisputeList{Data: disputes, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// SubmitEvidence stores the evidence files and moves the dispute under
+// review. Evidence is accepted once, before the deadline. Files are written
+// to the blob store inside the transaction and removed again if it fails.
+func (s *DisputeService) SubmitEvidence(ctx context.Context, id string, es *domain.EvidenceSubmission) (*domain.Dispute, error) {
+	disputeID, err := parseDisputeID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := es.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"evidence submission validation failed",
+			err,
+			map[string]interface{}{"DisputeID": disputeID},
+		)
+	}
+
+	var (
+		dispute *domain.Dispute
+		stored  []string
+	)
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		dispute, err = tx.Disputes().GetDisputeByIDForUpdate(ctx, disputeID)
+		if err == nil {
+			err = checkDisputeMerchant(ctx, tx, dispute)
+		}
+		if err != nil {
+			return disputeLookupError(err, disputeID)
+		}
+		if dispute.Status != domain.DisputeNeedsResponse {
+			return domain.NewError(
+				domain.ErrDisputeNotAwaiting,
+				"Dispute not awaiting a response",
+				"Evidence has already been submitted or the dispute is closed; it is "+string(dispute.Status),
+				nil,
+				map[string]interface{}{"DisputeID": disputeID, "status": dispute.Status},
+			)
+		}
+		if time.Now().After(dispute.EvidenceDueBy) {
+			return domain.NewError(
+				domain.ErrDisputeDeadlinePassed,
+				"Dispute evidence deadline passed",
+				"Evidence was due by "+dispute.EvidenceDueBy.Format(time.RFC3339),
+				nil,
+				map[string]interface{}{"DisputeID": disputeID},
+			)
+		}
+
+		for _, f := range es.Files {
+			key := fmt.Sprintf("disputes/%s/%s", disputeID, uuid.New())
+			if err := s.blobs.Put(ctx, key, f.Content); err != nil {
+				return fmt.Errorf("failed to store evidence file %q: %w", f.FileName, err)
+			}
+			stored = append(stored, key)
+			evidence := &domain.DisputeEvidence{
+				DisputeID:   disputeID,
+				FileName:    f.FileName,
+				ContentType: f.ContentType,
+				Size:        f.Size,
+				StorageKey:  key,
+			}
+			if err := tx.Disputes().CreateEvidence(ctx, evidence); err != nil {
+				return err
+			}
+		}
+		before := dispute
+		if dispute, err = tx.Disputes().SubmitEvidence(ctx, disputeID, es.Description); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "dispute.evidence_submitted", domain.AuditDispute, disputeID.String(), before, dispute)
+	})
+	if err != nil {
+		s.deleteBlobs(ctx, stored)
+		var derr domain.Error
+		if errors.As(err, &derr) {
+			return nil, derr
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to submit dispute evidence",
+			"Error occurred while storing the dispute evidence",
+			err,
+			map[string]interface{}{"DisputeID": disputeID},
+		)
+	}
+
+	logger.FromContext(ctx).Info("dispute evidence submitted",
+		slog.String("dispute_id", id),
+		slog.Int("files", len(es.Files)),
+	)
+	s.publish(ctx, domain.EventDisputeUnderReview, dispute)
+	return dispute, nil
+}
+
+// deleteBlobs removes files stored for a submission that was rolled back.
+func (s *DisputeService) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			logger.FromContext(ctx).Error("failed to delete orphaned evidence file",
+				slog.String("key", key),
+				slog.Any("error", err),
+			)
+		}
+	}
+}
+
+// CloseDispute records the provider's decision on an open dispute. A
+// dispute can be decided before evidence is submitted, e.g. when the
+// deadline was missed.
+func (s *DisputeService) CloseDispute(ctx context.Context, id string, cr *domain.CloseDisputeRequest) (*domain.Dispute, error) {
+	disputeID, err := parseDisputeID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := cr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"close dispute request validation failed",
+			err,
+			map[string]interface{}{"req": cr},
+		)
+	}
+
+	var dispute *domain.Dispute
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		dispute, err = tx.Disputes().GetDisputeByIDForUpdate(ctx, disputeID)
+		if err == nil {
+			err = checkDisputeMerchant(ctx, tx, dispute)
+		}
+		if err != nil {
+			return disputeLookupError(err, disputeID)
+		}
+		if dispute.Status.Closed() {
+			return domain.NewError(
+				domain.ErrDisputeClosed,
+				"Dispute closed",
+				"The dispute has already been "+string(dispute.Status),
+				nil,
+				map[string]interface{}{"DisputeID": disputeID, "status": dispute.Status},
+			)
+		}
+		before := dispute
+		if dispute, err = tx.Disputes().CloseDispute(ctx, disputeID, cr.Outcome); err != nil {
+			return err
+		}
+		if dispute.Status == domain.DisputeLost {
+			payment, err := tx.Payments().GetPaymentByID(ctx, dispute.PaymentID)
+			if err != nil {
+				return err
+			}
+			if err := postJournalEntry(ctx, tx, domain.NewChargebackEntry(payment, dispute)); err != nil {
+				return domain.NewError(
+					storageErrorCode(err),
+					"Failed to record chargeback in the ledger",
+					"Error occurred while posting the refund journal entry",
+					err,
+					map[string]interface{}{"DisputeID": disputeID, "PaymentID": dispute.PaymentID},
+				)
+			}
+		}
+		return recordAudit(ctx, tx, "dispute.closed", domain.AuditDispute, disputeID.String(), before, dispute)
+	})
+	if err != nil {
+		var derr domain.Error
+		if errors.As(err, &derr) {
+			return nil, derr
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to close dispute",
+			"Error occurred while recording the dispute outcome",
+			err,
+			map[string]interface{}{"DisputeID": disputeID},
+		)
+	}
+
+	logger.FromContext(ctx).Info("dispute closed",
+		slog.String("dispute_id", id),
+		slog.String("outcome", string(cr.Outcome)),
+	)
+	eventType := domain.EventDisputeWon
+	if cr.Outcome == domain.DisputeLost {
+		eventType = domain.EventDisputeLost
+	}
+	s.publish(ctx, eventType, dispute)
+	return dispute, nil
+}
+
+func (s *DisputeService) ListEvidence(ctx context.Context, id string) (*domain.DisputeEvidenceList, error) {
+	disputeID, err := parseDisputeID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getDispute(ctx, disputeID); err != nil {
+		return nil, err
+	}
+	evidence, err := s.uow.Disputes().ListEvidence(ctx, disputeID)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list dispute evidence",
+			"Error occurred while retrieving the dispute evidence",
+			err,
+			map[string]interface{}{"DisputeID": disputeID},
+		)
+	}
+	return &domain.DisputeEvidenceList{Data: evidence}, nil
+}
+
+func (s *DisputeService) OpenEvidence(ctx context.Context, id, evidenceID string) (*domain.DisputeEvidence, io.ReadCloser, error) {
+	disputeID, err := parseDisputeID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	fileID, err := uuid.Parse(evidenceID)
+	if err != nil {
+		return nil, nil, domain.NewError(
+			domain.ErrInvalidEvidenceID,
+			"Invalid dispute evidence ID format",
+			"The provided dispute evidence ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"EvidenceID": evidenceID},
+		)
+	}
+
+	if _, err := s.getDispute(ctx, disputeID); err != nil {
+		return nil, nil, err
+	}
+	evidence, err := s.uow.Disputes().GetEvidenceByID(ctx, disputeID, fileID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, nil, domain.NewError(
+				domain.ErrEvidenceNotFound,
+				"Dispute evidence not found",
+				"The specified evidence file could not be found on this dispute",
+				err,
+				map[string]interface{}{"DisputeID": disputeID, "EvidenceID": fileID},
+			)
+		}
+		return nil, nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to fetch dispute evidence",
+			"Error occurred while retrieving the dispute evidence",
+			err,
+			map[string]interface{}{"DisputeID": disputeID, "EvidenceID": fileID},
+		)
+	}
+	content, err := s.blobs.Open(ctx, evidence.StorageKey)
+	if err != nil {
+		// The row exists, so a missing file is lost data rather than a bad request
+		return nil, nil, domain.NewError(
+			domain.ErrInternal,
+			"Failed to open dispute evidence",
+			"Error occurred while reading the evidence file",
+			err,
+			map[string]interface{}{"DisputeID": disputeID, "EvidenceID": fileID},
+		)
+	}
+	return evidence, content, nil
+}
+
+// publish tells subscribers about a dispute's new state. A failure is
+// logged rather than returned because the change has been committed.
+func (s *DisputeService) publish(ctx context.Context, eventType string, dispute *domain.Dispute) {
+	if err := s.events.PublishEvent(ctx, domain.NewEvent(eventType, dispute)); err != nil {
+		logger.FromContext(ctx).Error("failed to publish dispute event",
+			slog.String("dispute_id", dispute.ID.String()),
+			slog.String("event_type", eventType),
+			slog.Any("error", err),
+		)
+	}
+}
+
+func parseDisputeID(id string) (uuid.UUID, error) {
+	disputeID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewError(
+			domain.ErrInvalidDisputeID,
+			"Invalid dispute ID format",
+			"The provided dispute ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"DisputeID": id},
+		)
+	}
+	return disputeID, nil
+}
+
+func disputeLookupError(err error, id uuid.UUID) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrDisputeNotFound,
+			"Dispute not found",
+			"The specified dispute could not be found",
+			err,
+			map[string]interface{}{"DisputeID": id},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to fetch dispute",
+		"Error occurred while retrieving the dispute",
+		err,
+		map[string]interface{}{"DisputeID": id},
+	)
+}
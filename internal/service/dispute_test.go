@@ -0,0 +1,398 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+type fakeDisputeRepo struct {
+	domain.DisputeRepo
+	disputes map[uuid.UUID]*domain.Dispute
+	evidence []domain.DisputeEvidence
+	// failEvidence makes CreateEvidence fail, to exercise the rollback.
+	failEvidence error
+	filter       domain.DisputeFilter
+}
+
+func (r *fakeDisputeRepo) DisputedAmount(ctx context.Context, paymentID uuid.UUID) (float64, error) {
+	var amount float64
+	for _, d := range r.disputes {
+		if d.PaymentID == paymentID && d.Status != domain.DisputeWon {
+			amount += d.Amount
+		}
+	}
+	return amount, nil
+}
+
+func (r *fakeDisputeRepo) ListDisputes(ctx context.Context, filter domain.DisputeFilter, page domain.Page) ([]domain.Dispute, error) {
+	r.filter = filter
+	return nil, nil
+}
+
+func (r *fakeDisputeRepo) CreateDispute(ctx context.Context, dispute *domain.Dispute) error {
+	for _, d := range r.disputes {
+		if dispute.ProviderReference != "" && d.ProviderReference == dispute.ProviderReference {
+			return fmt.Errorf("%w: duplicate key", domain.ErrConflict)
+		}
+	}
+	dispute.ID = uuid.New()
+	dispute.Status = domain.DisputeNeedsResponse
+	dispute.CreatedAt = time.Now()
+	stored := *dispute
+	r.disputes[dispute.ID] = &stored
+	return nil
+}
+
+func (r *fakeDisputeRepo) GetDisputeByID(ctx context.Context, id uuid.UUID) (*domain.Dispute, error) {
+	d, ok := r.disputes[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	dispute := *d
+	return &dispute, nil
+}
+
+func (r *fakeDisputeRepo) GetDisputeByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Dispute, error) {
+	return r.GetDisputeByID(ctx, id)
+}
+
+func (r *fakeDisputeRepo) SubmitEvidence(ctx context.Context, id uuid.UUID, description string) (*domain.Dispute, error) {
+	d := r.disputes[id]
+	now := time.Now()
+	d.Status = domain.DisputeUnderReview
+	d.EvidenceDescription = description
+	d.EvidenceSubmittedAt = &now
+	return r.GetDisputeByID(ctx, id)
+}
+
+func (r *fakeDisputeRepo) CloseDispute(ctx context.Context, id uuid.UUID, outcome domain.DisputeStatus) (*domain.Dispute, error) {
+	d := r.disputes[id]
+	now := time.Now()
+	d.Status = outcome
+	d.ClosedAt = &now
+	return r.GetDisputeByID(ctx, id)
+}
+
+func (r *fakeDisputeRepo) CreateEvidence(ctx context.Context, evidence *domain.DisputeEvidence) error {
+	if r.failEvidence != nil {
+		return r.failEvidence
+	}
+	evidence.ID = uuid.New()
+	r.evidence = append(r.evidence, *evidence)
+	return nil
+}
+
+func (r *fakeDisputeRepo) ListEvidence(ctx context.Context, disputeID uuid.UUID) ([]domain.DisputeEvidence, error) {
+	var evidence []domain.DisputeEvidence
+	for _, e := range r.evidence {
+		if e.DisputeID == disputeID {
+			evidence = append(evidence, e)
+		}
+	}
+	return evidence, nil
+}
+
+func (r *fakeDisputeRepo) GetEvidenceByID(ctx context.Context, disputeID, id uuid.UUID) (*domain.DisputeEvidence, error) {
+	for _, e := range r.evidence {
+		if e.DisputeID == disputeID && e.ID == id {
+			return &e, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+type fakeBlobStore struct {
+	blobs map[string][]byte
+}
+
+func (s *fakeBlobStore) Put(ctx context.Context, key string, r io.Reader) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	s.blobs[key] = b
+	return nil
+}
+
+func (s *fakeBlobStore) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	b, ok := s.blobs[key]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+
+func (s *fakeBlobStore) Delete(ctx context.Context, key string) error {
+	delete(s.blobs, key)
+	return nil
+}
+
+type fakeEventPublisher struct {
+	types []string
+}
+
+func (p *fakeEventPublisher) PublishEvent(ctx context.Context, event *domain.Event) error {
+	p.types = append(p.types, event.Type)
+	return nil
+}
+
+type disputeFixture struct {
+	svc      domain.DisputeService
+	disputes *fakeDisputeRepo
+	blobs    *fakeBlobStore
+	events   *fakeEventPublisher
+	ledger   *fakeLedgerRepo
+	payment  *domain.Payment
+}
+
+func setupDisputes(status domain.PaymentStatus) disputeFixture {
+	payment := &domain.Payment{ID: uuid.New(), Amount: 100, Currency: "USD", Status: status}
+	repo := &fakeRepo{byID: map[uuid.UUID]*domain.Payment{payment.ID: payment}}
+	f := disputeFixture{
+		disputes: &fakeDisputeRepo{disputes: make(map[uuid.UUID]*domain.Dispute)},
+		blobs:    &fakeBlobStore{blobs: make(map[string][]byte)},
+		events:   &fakeEventPublisher{},
+		ledger:   &fakeLedgerRepo{},
+		payment:  payment,
+	}
+	f.svc = service.NewDisputeService(&fakeUnitOfWork{repo: repo, disputes: f.disputes, ledger: f.ledger}, f.blobs, f.events)
+	return f
+}
+
+func (f disputeFixture) open(t *testing.T) *domain.Dispute {
+	t.Helper()
+	d, err := f.svc.CreateDispute(context.Background(), &domain.DisputeRequest{
+		PaymentID:     f.payment.ID,
+		ReasonCode:    "4853",
+		EvidenceDueBy: time.Now().Add(7 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("create dispute: %v", err)
+	}
+	return d
+}
+
+func evidenceFile(name, contentType, content string) domain.EvidenceFile {
+	return domain.EvidenceFile{FileName: name, ContentType: contentType, Size: int64(len(content)), Content: strings.NewReader(content)}
+}
+
+func TestCreateDispute(t *testing.T) {
+	t.Run("defaults to the payment amount", func(t *testing.T) {
+		f := setupDisputes(domain.StatusSuccess)
+		d := f.open(t)
+		assert.Equal(t, 100.0, d.Amount)
+		assert.Equal(t, "USD", d.Currency)
+		assert.Equal(t, domain.DisputeNeedsResponse, d.Status)
+		assert.Equal(t, []string{domain.EventDisputeCreated}, f.events.types)
+	})
+
+	t.Run("only successful payments can be disputed", func(t *testing.T) {
+		f := setupDisputes(domain.StatusFailed)
+		_, err := f.svc.CreateDispute(context.Background(), &domain.DisputeRequest{PaymentID: f.payment.ID, ReasonCode: "4853", EvidenceDueBy: time.Now()})
+		var derr domain.Error
+		if assert.ErrorAs(t, err, &derr) {
+			assert.Equal(t, domain.ErrPaymentNotDisputable, derr.Type)
+		}
+	})
+
+	t.Run("amount cannot exceed the payment", func(t *testing.T) {
+		f := setupDisputes(domain.StatusSuccess)
+		amount := 150.0
+		_, err := f.svc.CreateDispute(context.Background(), &domain.DisputeRequest{PaymentID: f.payment.ID, ReasonCode: "4853", Amount: &amount, EvidenceDueBy: time.Now()})
+		assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	})
+
+	t.Run("open and lost disputes cannot exceed the payment", func(t *testing.T) {
+		f := setupDisputes(domain.StatusSuccess)
+		create := func(amount float64) (*domain.Dispute, error) {
+			return f.svc.CreateDispute(context.Background(), &domain.DisputeRequest{PaymentID: f.payment.ID, ReasonCode: "4853", Amount: &amount, EvidenceDueBy: time.Now().Add(time.Hour)})
+		}
+		first, err := create(60)
+		assert.NoError(t, err)
+		_, err = create(50)
+		var derr domain.Error
+		if assert.ErrorAs(t, err, &derr) {
+			assert.Equal(t, domain.ErrDisputeAmountExceeded, derr.Type)
+		}
+		assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+		_, err = create(40)
+		assert.NoError(t, err)
+
+		// A won dispute no longer counts against the payment
+		_, err = f.svc.CloseDispute(context.Background(), first.ID.String(), &domain.CloseDisputeRequest{Outcome: domain.DisputeWon})
+		assert.NoError(t, err)
+		_, err = create(60)
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown payment", func(t *testing.T) {
+		f := setupDisputes(domain.StatusSuccess)
+		_, err := f.svc.CreateDispute(context.Background(), &domain.DisputeRequest{PaymentID: uuid.New(), ReasonCode: "4853", EvidenceDueBy: time.Now()})
+		assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	})
+
+	t.Run("duplicate provider reference", func(t *testing.T) {
+		f := setupDisputes(domain.StatusSuccess)
+		amount := 40.0
+		req := &domain.DisputeRequest{PaymentID: f.payment.ID, ProviderReference: "cb_1", ReasonCode: "4853", Amount: &amount, EvidenceDueBy: time.Now()}
+		_, err := f.svc.CreateDispute(context.Background(), req)
+		assert.NoError(t, err)
+		_, err = f.svc.CreateDispute(context.Background(), req)
+		var derr domain.Error
+		if assert.ErrorAs(t, err, &derr) {
+			assert.Equal(t, domain.ErrDuplicateDispute, derr.Type)
+		}
+	})
+}
+
+func TestSubmitEvidence(t *testing.T) {
+	t.Run("stores files and moves under review", func(t *testing.T) {
+		f := setupDisputes(domain.StatusSuccess)
+		d := f.open(t)
+
+		got, err := f.svc.SubmitEvidence(context.Background(), d.ID.String(), &domain.EvidenceSubmission{
+			Description: "Goods were delivered",
+			Files:       []domain.EvidenceFile{evidenceFile("receipt.pdf", "application/pdf", "%PDF-1.4")},
+		})
+		assert.NoError(t, err)
+		if got == nil {
+			t.Fatal("expected a dispute")
+		}
+		assert.Equal(t, domain.DisputeUnderReview, got.Status)
+		assert.Equal(t, []string{domain.EventDisputeCreated, domain.EventDisputeUnderReview}, f.events.types)
+
+		list, err := f.svc.ListEvidence(context.Background(), d.ID.String())
+		assert.NoError(t, err)
+		if list == nil || len(list.Data) != 1 {
+			t.Fatalf("expected 1 evidence file, got %v", list)
+		}
+		meta, content, err := f.svc.OpenEvidence(context.Background(), d.ID.String(), list.Data[0].ID.String())
+		assert.NoError(t, err)
+		if content == nil {
+			t.Fatal("expected evidence content")
+		}
+		defer content.Close()
+		b, _ := io.ReadAll(content)
+		assert.Equal(t, "%PDF-1.4", string(b))
+		assert.Equal(t, "receipt.pdf", meta.FileName)
+
+		_, err = f.svc.SubmitEvidence(context.Background(), d.ID.String(), &domain.EvidenceSubmission{Description: "more"})
+		var derr domain.Error
+		if assert.ErrorAs(t, err, &derr) {
+			assert.Equal(t, domain.ErrDisputeNotAwaiting, derr.Type)
+		}
+	})
+
+	t.Run("rejects evidence after the deadline", func(t *testing.T) {
+		f := setupDisputes(domain.StatusSuccess)
+		d := f.open(t)
+		f.disputes.disputes[d.ID].EvidenceDueBy = time.Now().Add(-time.Hour)
+
+		_, err := f.svc.SubmitEvidence(context.Background(), d.ID.String(), &domain.EvidenceSubmission{Description: "late"})
+		var derr domain.Error
+		if assert.ErrorAs(t, err, &derr) {
+			assert.Equal(t, domain.ErrDisputeDeadlinePassed, derr.Type)
+		}
+	})
+
+	t.Run("rejects unsupported files", func(t *testing.T) {
+		f := setupDisputes(domain.StatusSuccess)
+		d := f.open(t)
+
+		_, err := f.svc.SubmitEvidence(context.Background(), d.ID.String(), &domain.EvidenceSubmission{
+			Files: []domain.EvidenceFile{evidenceFile("run.exe", "application/octet-stream", "MZ")},
+		})
+		assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+		assert.Empty(t, f.blobs.blobs)
+	})
+
+	t.Run("removes stored files when the submission fails", func(t *testing.T) {
+		f := setupDisputes(domain.StatusSuccess)
+		d := f.open(t)
+		f.disputes.failEvidence = errors.New("connection reset")
+
+		_, err := f.svc.SubmitEvidence(context.Background(), d.ID.String(), &domain.EvidenceSubmission{
+			Files: []domain.EvidenceFile{evidenceFile("note.txt", "text/plain", "hello")},
+		})
+		assert.Error(t, err)
+		assert.Empty(t, f.blobs.blobs)
+		assert.Equal(t, domain.DisputeNeedsResponse, f.disputes.disputes[d.ID].Status)
+	})
+}
+
+func TestCloseDispute(t *testing.T) {
+	f := setupDisputes(domain.StatusSuccess)
+	d := f.open(t)
+
+	_, err := f.svc.CloseDispute(context.Background(), d.ID.String(), &domain.CloseDisputeRequest{Outcome: domain.DisputeUnderReview})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+
+	got, err := f.svc.CloseDispute(context.Background(), d.ID.String(), &domain.CloseDisputeRequest{Outcome: domain.DisputeLost})
+	assert.NoError(t, err)
+	if got == nil {
+		t.Fatal("expected a dispute")
+	}
+	assert.Equal(t, domain.DisputeLost, got.Status)
+	assert.NotNil(t, got.ClosedAt)
+	assert.Equal(t, []string{domain.EventDisputeCreated, domain.EventDisputeLost}, f.events.types)
+	if assert.Len(t, f.ledger.entries, 1) {
+		entry := f.ledger.entries[0]
+		assert.Equal(t, domain.EntryRefund, entry.Kind)
+		assert.Equal(t, f.payment.ID, *entry.PaymentID)
+		assert.Contains(t, entry.Postings, domain.Posting{Account: domain.AccountMerchantPayable, Currency: "USD", Amount: 100})
+	}
+
+	_, err = f.svc.CloseDispute(context.Background(), d.ID.String(), &domain.CloseDisputeRequest{Outcome: domain.DisputeWon})
+	assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+	assert.Len(t, f.ledger.entries, 1)
+
+	won := setupDisputes(domain.StatusSuccess)
+	d = won.open(t)
+	_, err = won.svc.CloseDispute(context.Background(), d.ID.String(), &domain.CloseDisputeRequest{Outcome: domain.DisputeWon})
+	assert.NoError(t, err)
+	assert.Empty(t, won.ledger.entries)
+
+	_, err = f.svc.CloseDispute(context.Background(), uuid.NewString(), &domain.CloseDisputeRequest{Outcome: domain.DisputeWon})
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+}
+
+func TestDisputeMerchantScope(t *testing.T) {
+	f := setupDisputes(domain.StatusSuccess)
+	f.payment.Metadata = map[string]string{domain.MerchantMetadataKey: "acme"}
+	d := f.open(t)
+	acme := domain.WithMerchant(context.Background(), "acme")
+	globex := domain.WithMerchant(context.Background(), "globex")
+
+	_, err := f.svc.GetDisputeByID(acme, d.ID.String())
+	assert.NoError(t, err)
+	_, err = f.svc.GetDisputeByID(globex, d.ID.String())
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	_, err = f.svc.ListEvidence(globex, d.ID.String())
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	_, err = f.svc.SubmitEvidence(globex, d.ID.String(), &domain.EvidenceSubmission{Description: "Goods were delivered"})
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	assert.Equal(t, domain.DisputeNeedsResponse, f.disputes.disputes[d.ID].Status)
+
+	_, err = f.svc.ListDisputes(globex, domain.DisputeFilter{}, domain.Page{Limit: 20})
+	assert.NoError(t, err)
+	if assert.NotNil(t, f.disputes.filter.MerchantID) {
+		assert.Equal(t, "globex", *f.disputes.filter.MerchantID)
+	}
+
+	// Operator routes carry no merchant and see every dispute
+	_, err = f.svc.GetDisputeByID(context.Background(), d.ID.String())
+	assert.NoError(t, err)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type FeeService struct {
+	uow domain.UnitOfWork
+}
+
+func NewFeeService(uow domain.UnitOfWork) domain.FeeService {
+	return &FeeService{uow: uow}
+}
+
+// CreateFeeSchedule adds a schedule. Schedules are never edited: a new one
+// with a later effective_from takes over from the one it replaces.
+func (s *FeeService) CreateFeeSchedule(ctx context.Context, fr *domain.FeeScheduleRequest) (*domain.FeeSchedule, error) {
+	if err := fr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"fee schedule request validation failed",
+			err,
+			map[string]interface{}{"req": fr},
+		)
+	}
+
+	schedule := &domain.FeeSchedule{
+		Currency:      fr.Currency,
+		PaymentMethod: fr.PaymentMethod,
+		MerchantID:    fr.MerchantID,
+		Percentage:    fr.Percentage,
+		FixedAmount:   fr.FixedAmount,
+		MinFee:        fr.MinFee,
+		MaxFee:        fr.MaxFee,
+		EffectiveFrom: time.Now(),
+		EffectiveTo:   fr.EffectiveTo,
+	}
+	if fr.EffectiveFrom != nil {
+		schedule.EffectiveFrom = *fr.EffectiveFrom
+	}
+	err := s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		if err := tx.Fees().CreateFeeSchedule(ctx, schedule); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "fee_schedule.created", domain.AuditFeeSchedule, schedule.ID.String(), nil, schedule)
+	})
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to create fee schedule",
+			"Error occurred while saving the fee schedule",
+			err,
+			map[string]interface{}{"req": fr},
+		)
+	}
+
+	logger.FromContext(ctx).Info("fee schedule created", slog.String("fee_schedule_id", schedule.ID.String()))
+	return schedule, nil
+}
+
+func (s *FeeService) GetFeeScheduleByID(ctx context.Context, id string) (*domain.FeeSchedule, error) {
+	scheduleID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, domain.NewError(
+			domain.ErrInvalidFeeScheduleID,
+			"Invalid fee schedule ID format",
+			"The provided fee schedule ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"FeeScheduleID": id},
+		)
+	}
+
+	schedule, err := s.uow.Fees().GetFeeScheduleByID(ctx, scheduleID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewError(
+				domain.ErrFeeScheduleNotFound,
+				"Fee schedule not found",
+				"The specified fee schedule could not be found",
+				err,
+				map[string]interface{}{"FeeScheduleID": id},
+			)
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to fetch fee schedule",
+			"Error occurred while retrieving the fee schedule",
+			err,
+			map[string]interface{}{"FeeScheduleID": id},
+		)
+	}
+	return schedule, nil
+}
+
+func (s *FeeService) ListFeeSchedules(ctx context.Context, page domain.Page) (*domain.FeeScheduleList, error) {
+	schedules, err := s.uow.Fees().ListFeeSchedules(ctx, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list fee schedules",
+			"Error occurred while retrieving fee schedules",
+			err,
+			nil,
+		)
+	}
+	return &domain.FeeScheduleList{Data: schedules, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// applyFee charges a successful payment the fee of the schedule in force at
+// its success and stores the fee with the net amount. A payment no schedule
+// covers is charged nothing.
+func applyFee(ctx context.Context, tx domain.UnitOfWork, p *domain.Payment) (*domain.Payment, error) {
+	var fee float64
+	schedule, err := tx.Fees().FindFeeSchedule(ctx, p, p.UpdatedAt)
+	switch {
+	case err == nil:
+		fee = schedule.Compute(p.Amount)
+	case !errors.Is(err, domain.ErrNotFound):
+		return nil, err
+	}
+	return tx.Payments().SetPaymentFee(ctx, p.ID, fee)
+}
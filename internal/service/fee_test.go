@@ -0,0 +1,161 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+type fakeFeeRepo struct {
+	domain.FeeRepo
+	schedules []domain.FeeSchedule
+}
+
+func (r *fakeFeeRepo) CreateFeeSchedule(ctx context.Context, s *domain.FeeSchedule) error {
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now()
+	r.schedules = append(r.schedules, *s)
+	return nil
+}
+
+// FindFeeSchedule ranks the schedules in force the way the query does: the
+// merchant's first, then the method's, then the most recent.
+func (r *fakeFeeRepo) FindFeeSchedule(ctx context.Context, p *domain.Payment, at time.Time) (*domain.FeeSchedule, error) {
+	rank := func(s domain.FeeSchedule) [3]int64 {
+		var k [3]int64
+		if s.MerchantID != "" {
+			k[0] = 1
+		}
+		if s.PaymentMethod != "" {
+			k[1] = 1
+		}
+		k[2] = s.EffectiveFrom.UnixNano()
+		return k
+	}
+	var best *domain.FeeSchedule
+	for i, s := range r.schedules {
+		if s.Currency != p.Currency || s.EffectiveFrom.After(at) || (s.EffectiveTo != nil && !s.EffectiveTo.After(at)) {
+			continue
+		}
+		if s.PaymentMethod != "" && (p.PaymentMethod == nil || p.PaymentMethod.Type != s.PaymentMethod) {
+			continue
+		}
+		if s.MerchantID != "" && p.Metadata[domain.MerchantMetadataKey] != s.MerchantID {
+			continue
+		}
+		if best == nil {
+			best = &r.schedules[i]
+			continue
+		}
+		a, b := rank(s), rank(*best)
+		if a[0] > b[0] || (a[0] == b[0] && (a[1] > b[1] || (a[1] == b[1] && a[2] > b[2]))) {
+			best = &r.schedules[i]
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	return best, nil
+}
+
+func TestFeeScheduleCompute(t *testing.T) {
+	floatp := func(f float64) *float64 { return &f }
+	tests := []struct {
+		name     string
+		schedule domain.FeeSchedule
+		amount   float64
+		expected float64
+	}{
+		{"percentage plus fixed", domain.FeeSchedule{Percentage: 2.9, FixedAmount: 0.3}, 100, 3.2},
+		{"rounded to cents", domain.FeeSchedule{Percentage: 2.9}, 10.55, 0.31},
+		{"raised to the minimum", domain.FeeSchedule{Percentage: 1, MinFee: floatp(0.5)}, 10, 0.5},
+		{"lowered to the maximum", domain.FeeSchedule{Percentage: 3, MaxFee: floatp(25)}, 10000, 25},
+		{"never more than the amount", domain.FeeSchedule{FixedAmount: 2}, 1.5, 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.schedule.Compute(tt.amount))
+		})
+	}
+}
+
+func TestProcessPaymentAppliesFee(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	later := time.Now().Add(time.Hour)
+	schedules := []domain.FeeSchedule{
+		{Currency: "USD", Percentage: 2.9, FixedAmount: 0.3, EffectiveFrom: past.Add(-time.Hour)},
+		{Currency: "USD", Percentage: 2.5, FixedAmount: 0.3, EffectiveFrom: past},
+		{Currency: "USD", Percentage: 5, EffectiveFrom: later},
+		{Currency: "USD", PaymentMethod: domain.MethodMobileMoney, Percentage: 1.5, EffectiveFrom: past},
+		{Currency: "USD", MerchantID: "acme", Percentage: 1, EffectiveFrom: past},
+	}
+	tests := []struct {
+		name     string
+		currency string
+		method   *domain.PaymentMethod
+		merchant string
+		fee      float64
+	}{
+		{"latest schedule in force", "USD", nil, "", 2.8},
+		{"method schedule", "USD", &domain.PaymentMethod{Type: domain.MethodMobileMoney, MobileMoney: &domain.MobileMoneyDetails{Provider: "telebirr", MSISDN: "+251911000000"}}, "", 1.5},
+		{"merchant schedule wins", "USD", &domain.PaymentMethod{Type: domain.MethodMobileMoney, MobileMoney: &domain.MobileMoneyDetails{Provider: "telebirr", MSISDN: "+251911000000"}}, "acme", 1},
+		{"no schedule is free", "ETB", nil, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)}
+			ledger := &fakeLedgerRepo{}
+			uow := &fakeUnitOfWork{repo: repo, customers: newFakeCustomerRepo(), ledger: ledger, fees: &fakeFeeRepo{schedules: schedules}}
+			router := &fakeRouter{providers: []domain.Provider{&fakeProvider{name: "a", status: domain.StatusSuccess}}}
+			svc := service.NewPaymentService(uow, &fakePublisher{}, router)
+			ctx := context.Background()
+			if tt.merchant != "" {
+				ctx = domain.WithMerchant(ctx, tt.merchant)
+			}
+			created, err := svc.CreatePayment(ctx, &domain.PaymentRequest{
+				Amount: 100, Currency: tt.currency, Reference: "order-1", PaymentMethod: tt.method,
+			})
+			assert.NoError(t, err)
+
+			assert.NoError(t, svc.ProcessPayment(context.Background(), created.ID.String()))
+			p, err := svc.GetPaymentByID(context.Background(), created.ID.String())
+			assert.NoError(t, err)
+			if p.FeeAmount == nil || p.NetAmount == nil {
+				t.Fatal("fee and net amount not set")
+			}
+			assert.Equal(t, tt.fee, *p.FeeAmount)
+			assert.InDelta(t, 100-tt.fee, *p.NetAmount, 0.001)
+
+			if tt.fee == 0 {
+				assert.Len(t, ledger.entries, 1)
+				return
+			}
+			assert.Len(t, ledger.entries, 2)
+			assert.Equal(t, domain.EntryFee, ledger.entries[1].Kind)
+			assert.Equal(t, -tt.fee, ledger.entries[1].Postings[1].Amount)
+		})
+	}
+}
+
+func TestCreateFeeSchedule(t *testing.T) {
+	floatp := func(f float64) *float64 { return &f }
+	svc := service.NewFeeService(&fakeUnitOfWork{fees: &fakeFeeRepo{}})
+
+	schedule, err := svc.CreateFeeSchedule(context.Background(), &domain.FeeScheduleRequest{Currency: "ETB", Percentage: 2, MinFee: floatp(1)})
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), schedule.EffectiveFrom, time.Second)
+
+	_, err = svc.CreateFeeSchedule(context.Background(), &domain.FeeScheduleRequest{Currency: "ETB", MinFee: floatp(5), MaxFee: floatp(1)})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+
+	_, err = svc.CreateFeeSchedule(context.Background(), &domain.FeeScheduleRequest{Currency: "ETB", Percentage: 120})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+}
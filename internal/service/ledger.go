@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+// integrityCheckLimit caps how many problems of each kind a report lists.
+const integrityCheckLimit = 100
+
+type LedgerService struct {
+	uow domain.UnitOfWork
+}
+
+func NewLedgerService(uow domain.UnitOfWork) domain.LedgerService {
+	return &LedgerService{uow: uow}
+}
+
+func (s *LedgerService) GetBalances(ctx context.Context) (*domain.LedgerBalances, error) {
+	balances, err := s.uow.Ledger().ListBalances(ctx)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to fetch ledger balances",
+			"Error occurred while totalling the ledger accounts",
+			err,
+			nil,
+		)
+	}
+	return &domain.LedgerBalances{Data: balances}, nil
+}
+
+func (s *LedgerService) ListPaymentEntries(ctx context.Context, id string) (*domain.LedgerEntryList, error) {
+	paymentID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, domain.NewError(
+			domain.ErrInvalidPaymentID,
+			"Invalid payment ID format",
+			"The provided payment ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"PaymentID": id},
+		)
+	}
+
+	entries, err := s.uow.Ledger().ListJournalEntriesByPayment(ctx, paymentID)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list ledger entries",
+			"Error occurred while retrieving the payment's journal entries",
+			err,
+			map[string]interface{}{"PaymentID": id},
+		)
+	}
+	return &domain.LedgerEntryList{Data: entries}, nil
+}
+
+// CheckIntegrity looks for journal entries that do not sum to zero and for
+// successful payments the ledger has no record of.
+func (s *LedgerService) CheckIntegrity(ctx context.Context) (*domain.IntegrityReport, error) {
+	report := &domain.IntegrityReport{CheckedAt: time.Now()}
+	var err error
+	report.Unbalanced, err = s.uow.Ledger().ListUnbalancedEntries(ctx, integrityCheckLimit)
+	if err == nil {
+		report.UnrecordedPayments, err = s.uow.Ledger().ListUnrecordedPayments(ctx, integrityCheckLimit)
+	}
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to check ledger integrity",
+			"Error occurred while checking the ledger",
+			err,
+			nil,
+		)
+	}
+
+	report.OK = len(report.Unbalanced) == 0 && len(report.UnrecordedPayments) == 0
+	if !report.OK {
+		logger.FromContext(ctx).Error("ledger integrity check failed",
+			slog.Int("unbalanced_entries", len(report.Unbalanced)),
+			slog.Int("unrecorded_payments", len(report.UnrecordedPayments)),
+		)
+	}
+	return report, nil
+}
+
+// postJournalEntry checks that entry balances and stores it in tx, so it
+// commits or rolls back with the change it records.
+func postJournalEntry(ctx context.Context, tx domain.UnitOfWork, entry *domain.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return fmt.Errorf("unbalanced %s journal entry: %w", entry.Kind, err)
+	}
+	return tx.Ledger().PostJournalEntry(ctx, entry)
+}
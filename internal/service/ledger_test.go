@@ -0,0 +1,137 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+type fakeLedgerRepo struct {
+	domain.LedgerRepo
+	entries []domain.JournalEntry
+}
+
+func (r *fakeLedgerRepo) PostJournalEntry(ctx context.Context, e *domain.JournalEntry) error {
+	e.ID = uuid.New()
+	r.entries = append(r.entries, *e)
+	return nil
+}
+
+func (r *fakeLedgerRepo) ListJournalEntriesByPayment(ctx context.Context, paymentID uuid.UUID) ([]domain.JournalEntry, error) {
+	var entries []domain.JournalEntry
+	for _, e := range r.entries {
+		if e.PaymentID != nil && *e.PaymentID == paymentID {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeLedgerRepo) ListUnbalancedEntries(ctx context.Context, limit int) ([]domain.UnbalancedEntry, error) {
+	var unbalanced []domain.UnbalancedEntry
+	for _, e := range r.entries {
+		if e.Validate() != nil {
+			unbalanced = append(unbalanced, domain.UnbalancedEntry{JournalEntryID: e.ID})
+		}
+	}
+	return unbalanced, nil
+}
+
+func (r *fakeLedgerRepo) ListUnrecordedPayments(ctx context.Context, limit int) ([]uuid.UUID, error) {
+	return nil, nil
+}
+
+func TestProcessPaymentPostsToLedger(t *testing.T) {
+	tests := []struct {
+		name            string
+		status          domain.PaymentStatus
+		expectedEntries int
+	}{
+		{"success is captured", domain.StatusSuccess, 1},
+		{"failure moves no money", domain.StatusFailed, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)}
+			uow := &fakeUnitOfWork{repo: repo, customers: newFakeCustomerRepo(), ledger: &fakeLedgerRepo{}, fees: &fakeFeeRepo{}}
+			router := &fakeRouter{providers: []domain.Provider{&fakeProvider{name: "a", status: tt.status}}}
+			svc := service.NewPaymentService(uow, &fakePublisher{}, router)
+			created, err := svc.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 10.5, Currency: "ETB", Reference: "order-1"})
+			assert.NoError(t, err)
+
+			assert.NoError(t, svc.ProcessPayment(context.Background(), created.ID.String()))
+			entries, err := service.NewLedgerService(uow).ListPaymentEntries(context.Background(), created.ID.String())
+			assert.NoError(t, err)
+			assert.Len(t, entries.Data, tt.expectedEntries)
+			if tt.expectedEntries == 0 {
+				return
+			}
+			entry := entries.Data[0]
+			assert.Equal(t, domain.EntryPaymentCaptured, entry.Kind)
+			assert.Equal(t, []domain.Posting{
+				{Account: domain.AccountProviderReceivable, Currency: "ETB", Amount: 10.5},
+				{Account: domain.AccountMerchantPayable, Currency: "ETB", Amount: -10.5},
+			}, entry.Postings)
+		})
+	}
+}
+
+func TestJournalEntryValidate(t *testing.T) {
+	payment := &domain.Payment{ID: uuid.New(), Reference: "order-1", Amount: 100, Currency: "USD"}
+	tests := []struct {
+		name    string
+		entry   *domain.JournalEntry
+		wantErr bool
+	}{
+		{"capture", domain.NewCaptureEntry(payment), false},
+		{"refund", domain.NewRefundEntry(payment, 40), false},
+		{"fee", domain.NewFeeEntry(payment, 2.9), false},
+		{"payout", domain.NewPayoutEntry("USD", 57.1, "Weekly payout"), false},
+		{"one-sided", &domain.JournalEntry{Postings: []domain.Posting{
+			{Account: domain.AccountCash, Currency: "USD", Amount: 5},
+			{Account: domain.AccountFeeRevenue, Currency: "USD", Amount: -4.99},
+		}}, true},
+		{"balanced in total but not per currency", &domain.JournalEntry{Postings: []domain.Posting{
+			{Account: domain.AccountCash, Currency: "USD", Amount: 5},
+			{Account: domain.AccountFeeRevenue, Currency: "ETB", Amount: -5},
+		}}, true},
+		{"unknown account", &domain.JournalEntry{Postings: []domain.Posting{
+			{Account: "suspense", Currency: "USD", Amount: 5},
+			{Account: domain.AccountCash, Currency: "USD", Amount: -5},
+		}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.entry.Validate()
+			assert.Equal(t, tt.wantErr, err != nil, "error: %v", err)
+		})
+	}
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	ledger := &fakeLedgerRepo{}
+	svc := service.NewLedgerService(&fakeUnitOfWork{ledger: ledger})
+	payment := &domain.Payment{ID: uuid.New(), Reference: "order-1", Amount: 100, Currency: "USD"}
+	ledger.entries = append(ledger.entries, *domain.NewCaptureEntry(payment))
+
+	report, err := svc.CheckIntegrity(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, report.OK)
+
+	broken := domain.NewFeeEntry(payment, 3)
+	broken.Postings = broken.Postings[:1]
+	ledger.entries = append(ledger.entries, *broken)
+	report, err = svc.CheckIntegrity(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, report.OK)
+	assert.Len(t, report.Unbalanced, 1)
+
+	_, err = svc.ListPaymentEntries(context.Background(), "nope")
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+}
@@ -2,101 +2,255 @@ package service
 
 import (
 	"context"
-	"database/sql"
 	"errors"
 	"fmt"
-	"math/rand"
-	"net/http"
-	"time"
+	"log/slog"
+	"maps"
 
 	"pgm/internal/domain"
-	"pgm/internal/repo/db"
+	"pgm/internal/logger"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/shopspring/decimal"
 )
 
 type PaymentService struct {
-	queries   db.Querier
-	pool      *pgxpool.Pool
+	uow       domain.UnitOfWork
 	publisher domain.MessagePublisher
+	router    domain.Router
 }
 
-func NewPaymentService(q db.Querier, pool *pgxpool.Pool, publisher domain.MessagePublisher) domain.PaymentService {
+func NewPaymentService(uow domain.UnitOfWork, publisher domain.MessagePublisher, router domain.Router) domain.PaymentService {
 	return &PaymentService{
-		queries:   q,
-		pool:      pool,
+		uow:       uow,
 		publisher: publisher,
+		router:    router,
 	}
 }
 
 func (u *PaymentService) CreatePayment(ctx context.Context, p *domain.PaymentRequest) (*domain.Payment, error) {
+	metadata, err := bindMerchant(ctx, p.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	p.Metadata = metadata
 	// validate request
 	if err := p.Validate(); err != nil {
 		return nil, domain.NewError(
-			http.StatusBadRequest,
+			domain.ErrValidationFailed,
 			"validation failed",
 			"payment request validation failed",
 			err,
 			map[string]interface{}{"req": p},
 		)
 	}
-	// Check if reference already exists
 
-	exists, err := u.queries.CheckExistence(ctx, p.Reference)
+	payment := newPayment(p)
+	// The payer, the payment and its review are created together or not at all
+	err = u.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		return screenAndInsertPayment(ctx, tx, p, payment)
+	})
 	if err != nil {
-		if !errors.Is(err, sql.ErrNoRows) {
-			return nil, domain.NewError(
-				500,
-				"Failed to check for existing payment",
-				"An unexpected error occurred while checking for existing payment", err,
-				map[string]interface{}{"PaymentRequest": p})
-		}
+		return nil, paymentCreateError(err, p)
 	}
-	if exists {
+
+	logger.FromContext(ctx).Info("payment created",
+		slog.String("payment_id", payment.ID.String()),
+		slog.String("reference", payment.Reference),
+		slog.String("risk_outcome", string(payment.Risk.Outcome)),
+		slog.Int("risk_score", payment.Risk.Score),
+	)
+	switch payment.Risk.Outcome {
+	case domain.RiskBlock:
+		// A blocked payment is stored as FAILED and never processed
+		return payment, nil
+	case domain.RiskReview:
+		// A held payment is queued once its review is approved
+		return payment, nil
+	}
+
+	// Publish to RabbitMQ
+	publishPaymentCreated(ctx, u.publisher, payment)
+
+	return payment, nil
+}
+
+// bindMerchant ties metadata to the merchant whose API key authenticated the
+// request. Fee schedules and settlement batches are chosen by merchant_id, so
+// callers may only name themselves, and anonymous callers no merchant at all.
+func bindMerchant(ctx context.Context, metadata domain.Metadata) (domain.Metadata, error) {
+	merchantID, authenticated := domain.MerchantFromContext(ctx)
+	if given, ok := metadata[domain.MerchantMetadataKey]; ok && (!authenticated || given != merchantID) {
 		return nil, domain.NewError(
-			409,
-			"Payment with this reference already exists",
-			"A payment with the same reference has already been created",
-			fmt.Errorf("payment with reference %s already exists", p.Reference),
-			map[string]interface{}{"PaymentRequest": p},
+			domain.ErrForbidden,
+			"merchant_id not allowed",
+			"metadata merchant_id must be the merchant whose API key made the request",
+			nil,
+			map[string]interface{}{"merchant_id": given},
 		)
 	}
+	if !authenticated {
+		return metadata, nil
+	}
+	bound := make(domain.Metadata, len(metadata)+1)
+	maps.Copy(bound, metadata)
+	bound[domain.MerchantMetadataKey] = merchantID
+	return bound, nil
+}
 
-	payment, err := u.queries.CreatePayment(ctx, db.CreatePaymentParams{
-		Amount:    decimal.NewFromFloat(p.Amount),
-		Currency:  p.Currency,
-		Reference: p.Reference,
-	})
+// visibleToMerchant reports whether a record of merchantID may be shown to
+// the caller. A merchant API key sees only its own records; operator routes,
+// which carry no merchant, see every record.
+func visibleToMerchant(ctx context.Context, merchantID string) bool {
+	caller, ok := domain.MerchantFromContext(ctx)
+	return !ok || caller == merchantID
+}
+
+func newPayment(p *domain.PaymentRequest) *domain.Payment {
+	return &domain.Payment{
+		Amount:        p.Amount,
+		Currency:      p.Currency,
+		Reference:     p.Reference,
+		CustomerID:    p.CustomerID,
+		Metadata:      p.Metadata,
+		PaymentMethod: p.PaymentMethod,
+		ClientIP:      p.ClientIP,
+	}
+}
+
+// screenAndInsertPayment stores payment, first resolving an inline payer to
+// a customer and screening the payment. The unique constraint on reference
+// is the source of truth, so concurrent duplicates are caught here rather
+// than by a racy existence check.
+func screenAndInsertPayment(ctx context.Context, tx domain.UnitOfWork, p *domain.PaymentRequest, payment *domain.Payment) error {
+	payer, err := attachPayer(ctx, tx, p, payment)
 	if err != nil {
-		return nil, domain.NewError(
-			500,
-			"Failed to create payment",
-			err.Error(),
-			err,
+		return err
+	}
+	return screenAndCreatePayment(ctx, tx, payment, payer)
+}
+
+// screenAndCreatePayment screens payment against the fraud rules and stores
+// it. Every payment is created through here. A blocked payment is stored as
+// FAILED and one flagged for review is held IN_REVIEW with a review in the
+// queue; only a PENDING payment may be queued for processing.
+func screenAndCreatePayment(ctx context.Context, tx domain.UnitOfWork, payment *domain.Payment, payer *domain.Customer) error {
+	var err error
+	if payer == nil && payment.CustomerID != nil {
+		// The customer must belong to the payment's merchant
+		payer, err = tx.Customers().GetCustomerByID(ctx, payment.Metadata[domain.MerchantMetadataKey], *payment.CustomerID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return fmt.Errorf("%w: %w", domain.ErrInvalidReference, err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	email := ""
+	if payer != nil {
+		email = payer.Email
+	}
+
+	payment.Risk, err = screenPayment(ctx, tx, payment, email)
+	if err != nil {
+		return err
+	}
+	switch payment.Risk.Outcome {
+	case domain.RiskBlock:
+		payment.Status = domain.StatusFailed
+	case domain.RiskReview:
+		payment.Status = domain.StatusInReview
+	}
+	if err := createPayment(ctx, tx, payment); err != nil {
+		return err
+	}
+	if payment.Status == domain.StatusInReview {
+		review := &domain.PaymentReview{PaymentID: payment.ID}
+		if err := tx.Reviews().CreateReview(ctx, review); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "payment_review.created", domain.AuditReview, review.ID.String(), nil, review)
+	}
+	return nil
+}
+
+// createPayment stores payment and records its creation in the audit log.
+func createPayment(ctx context.Context, tx domain.UnitOfWork, payment *domain.Payment) error {
+	if err := tx.Payments().CreatePayment(ctx, payment); err != nil {
+		return err
+	}
+	return recordAudit(ctx, tx, "payment.created", domain.AuditPayment, payment.ID.String(), nil, payment)
+}
+
+// updatePaymentStatus moves payment to status and records the change in the
+// audit log.
+func updatePaymentStatus(ctx context.Context, tx domain.UnitOfWork, payment *domain.Payment, status domain.PaymentStatus) (*domain.Payment, error) {
+	updated, err := tx.Payments().UpdatePaymentStatus(ctx, payment.ID, status)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, tx, "payment.status_changed", domain.AuditPayment, payment.ID.String(), payment, updated); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// attachPayer resolves an inline payer to a customer and links it to
+// payment. It returns nil when the request has no payer.
+func attachPayer(ctx context.Context, tx domain.UnitOfWork, p *domain.PaymentRequest, payment *domain.Payment) (*domain.Customer, error) {
+	if p.Payer == nil {
+		return nil, nil
+	}
+	payer, err := resolvePayer(ctx, tx, payment.Metadata[domain.MerchantMetadataKey], p.Payer)
+	if err != nil {
+		return nil, err
+	}
+	payment.CustomerID = &payer.ID
+	return payer, nil
+}
+
+// paymentCreateError maps an insertPayment failure to a domain error.
+func paymentCreateError(err error, p *domain.PaymentRequest) error {
+	var derr domain.Error
+	switch {
+	case errors.As(err, &derr):
+		return derr
+	case errors.Is(err, domain.ErrConflict):
+		return domain.NewError(
+			domain.ErrDuplicateReference,
+			"Payment with this reference already exists",
+			"A payment with the same reference has already been created",
+			fmt.Errorf("payment with reference %s already exists: %w", p.Reference, err),
 			map[string]interface{}{"PaymentRequest": p},
 		)
+	case errors.Is(err, domain.ErrInvalidReference):
+		return domain.NewError(
+			domain.ErrCustomerNotFound,
+			"Customer not found",
+			"The specified customer could not be found",
+			err,
+			map[string]interface{}{"CustomerID": p.CustomerID},
+		)
 	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to create payment",
+		"Error occurred while saving the payment",
+		err,
+		map[string]interface{}{"PaymentRequest": p},
+	)
+}
 
-	// Publish to RabbitMQ
-	err = u.publisher.PublishPaymentCreated(ctx, payment.ID.String())
-	if err != nil {
+// publishPaymentCreated queues payment for processing. A failure is logged
+// rather than returned because the payment itself has been stored.
+func publishPaymentCreated(ctx context.Context, publisher domain.MessagePublisher, payment *domain.Payment) {
+	if err := publisher.PublishPaymentCreated(ctx, payment.ID.String()); err != nil {
 		// In a real-world scenario, we might want to use an outbox pattern here
 		// to ensure the message is eventually published.
-		fmt.Printf("failed to publish message: %v\n", err)
+		logger.FromContext(ctx).Error("failed to publish payment created message",
+			slog.String("payment_id", payment.ID.String()),
+			slog.Any("error", err),
+		)
 	}
-
-	return &domain.Payment{
-		ID:        payment.ID,
-		Amount:    payment.Amount.InexactFloat64(),
-		Currency:  payment.Currency,
-		Reference: payment.Reference,
-		Status:    domain.PaymentStatus(payment.Status),
-		CreatedAt: payment.CreatedAt.Time,
-		UpdatedAt: payment.UpdatedAt.Time,
-	}, nil
 }
 
 func (u *PaymentService) GetPaymentByID(ctx context.Context, id string) (*domain.Payment, error) {
@@ -104,7 +258,7 @@ func (u *PaymentService) GetPaymentByID(ctx context.Context, id string) (*domain
 	paymentID, err := uuid.Parse(id)
 	if err != nil {
 		return nil, domain.NewError(
-			400,
+			domain.ErrInvalidPaymentID,
 			"Invalid payment ID format",
 			"The provided payment ID is not a valid UUID format",
 			err,
@@ -112,10 +266,22 @@ func (u *PaymentService) GetPaymentByID(ctx context.Context, id string) (*domain
 		)
 	}
 
-	payment, err := u.queries.GetPaymentByID(ctx, paymentID)
+	payment, err := u.uow.Payments().GetPaymentByID(ctx, paymentID)
+	if err == nil && !visibleToMerchant(ctx, payment.Metadata[domain.MerchantMetadataKey]) {
+		err = domain.ErrNotFound
+	}
 	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewError(
+				domain.ErrPaymentNotFound,
+				"Payment not found",
+				"The specified payment could not be found",
+				err,
+				map[string]interface{}{"PaymentID": id},
+			)
+		}
 		return nil, domain.NewError(
-			500,
+			storageErrorCode(err),
 			"Failed to fetch payment",
 			"Error occurred while retrieving payment information",
 			err,
@@ -123,100 +289,359 @@ func (u *PaymentService) GetPaymentByID(ctx context.Context, id string) (*domain
 		)
 	}
 
-	return &domain.Payment{
-		ID:        payment.ID,
-		Amount:    payment.Amount.InexactFloat64(),
-		Currency:  payment.Currency,
-		Reference: payment.Reference,
-		Status:    domain.PaymentStatus(payment.Status),
-		CreatedAt: payment.CreatedAt.Time,
-		UpdatedAt: payment.UpdatedAt.Time,
-	}, nil
+	payment.Attempts, err = u.uow.Payments().ListPaymentAttempts(ctx, paymentID)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to fetch payment attempts",
+			"Error occurred while retrieving the payment's provider attempts",
+			err,
+			map[string]interface{}{"PaymentID": id},
+		)
+	}
+
+	return payment, nil
+}
+
+// CancelPayment stops a pending payment before the worker charges it. The
+// payment is locked the way ProcessPayment locks it, so a cancel either waits
+// for a charge in progress and then finds the payment processed, or wins and
+// the worker skips the payment. Only the merchant that owns the payment may
+// cancel it.
+func (u *PaymentService) CancelPayment(ctx context.Context, id string) (*domain.Payment, error) {
+	merchantID, ok := domain.MerchantFromContext(ctx)
+	if !ok {
+		return nil, domain.NewError(
+			domain.ErrUnauthorized,
+			"merchant API key required",
+			"payments are only canceled by the merchant whose API key made the request",
+			nil,
+			nil,
+		)
+	}
+	paymentID, err := parsePaymentID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var payment *domain.Payment
+	err = u.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		p, err := lockPayment(ctx, tx, paymentID)
+		if err != nil {
+			return err
+		}
+		if p.Metadata[domain.MerchantMetadataKey] != merchantID {
+			// Another merchant's payment is reported as missing
+			return domain.NewError(
+				domain.ErrPaymentNotFound,
+				"Payment not found",
+				"The specified payment could not be found",
+				domain.ErrNotFound,
+				map[string]interface{}{"PaymentID": paymentID},
+			)
+		}
+		if p.Status != domain.StatusPending {
+			return paymentNotPendingError(p)
+		}
+		if payment, err = updatePaymentStatus(ctx, tx, p, domain.StatusCanceled); err != nil {
+			return err
+		}
+		return cancelCheckout(ctx, tx, payment)
+	})
+	if err != nil {
+		var derr domain.Error
+		if errors.As(err, &derr) {
+			return nil, derr
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to cancel payment",
+			"Error occurred while canceling the payment",
+			err,
+			map[string]interface{}{"PaymentID": id},
+		)
+	}
+
+	logger.FromContext(ctx).Info("payment canceled", slog.String("payment_id", id))
+	return payment, nil
+}
+
+// ListPayments lists the calling merchant's payments, newest first. Payments
+// are only listed for a merchant API key.
+func (u *PaymentService) ListPayments(ctx context.Context, filter domain.PaymentFilter, page domain.Page) (*domain.PaymentList, error) {
+	merchantID, ok := domain.MerchantFromContext(ctx)
+	if !ok {
+		return nil, domain.NewError(
+			domain.ErrUnauthorized,
+			"merchant API key required",
+			"payments are only listed for the merchant whose API key made the request",
+			nil,
+			nil,
+		)
+	}
+	filter.MerchantID = merchantID
+	payments, err := u.uow.Payments().ListPayments(ctx, filter, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list payments",
+			"Error occurred while retrieving payments",
+			err,
+			nil,
+		)
+	}
+	return &domain.PaymentList{Data: payments, Limit: page.Limit, Offset: page.Offset}, nil
 }
 
 func (u *PaymentService) ProcessPayment(ctx context.Context, id string) error {
+	// The consumer's logger already carries the payment ID
+	log := logger.FromContext(ctx)
+
 	// Parse payment id
 	paymentID, err := uuid.Parse(id)
 	if err != nil {
 		return domain.NewError(
-			400,
+			domain.ErrInvalidPaymentID,
 			"Invalid payment ID format",
 			"The provided payment ID is not a valid UUID format",
 			err,
 			map[string]interface{}{"PaymentID": id},
 		)
 	}
-	// create transaction object to track the processing
-	tx, err := u.pool.BeginTx(ctx, pgx.TxOptions{
-		IsoLevel: pgx.Serializable,
+
+	// The whole check-and-update runs in one transaction to track the processing
+	err = u.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		p, err := lockPayment(ctx, tx, paymentID)
+		if err != nil {
+			return err
+		}
+
+		// A held payment is queued again once its review is approved
+		if p.Status == domain.StatusInReview {
+			log.Info("payment held for review, skipping")
+			return nil
+		}
+
+		// A canceled payment is never charged, so its message is simply acked
+		if p.Status == domain.StatusCanceled {
+			log.Info("payment canceled, skipping")
+			return nil
+		}
+
+		// Idempotency check: only process if PENDING
+		if p.Status != domain.StatusPending {
+			log.Info("payment already processed", slog.String("status", string(p.Status)))
+			return domain.NewError(
+				domain.ErrPaymentAlreadyProcessed,
+				"Payment already processed",
+				"This payment has already been processed with status "+string(p.Status),
+				nil,
+				map[string]interface{}{"status": p.Status},
+			)
+		}
+
+		before := *p
+		newStatus, provider, err := u.charge(ctx, p)
+		if err != nil {
+			return err
+		}
+
+		updated, err := tx.Payments().UpdatePaymentResult(ctx, paymentID, newStatus, provider)
+		if err != nil {
+			return domain.NewError(
+				storageErrorCode(err),
+				"Failed to update payment status",
+				"Error occurred while updating payment status in the database",
+				err,
+				map[string]interface{}{"PaymentID": id, "NewStatus": newStatus, "OldStatus": p.Status, "Payment": p},
+			)
+		}
+
+		// A failed payment moved no money, so only a success is charged a fee
+		// and recorded
+		if newStatus == domain.StatusSuccess {
+			updated, err = applyFee(ctx, tx, updated)
+			if err != nil {
+				return domain.NewError(
+					storageErrorCode(err),
+					"Failed to apply payment fee",
+					"Error occurred while computing the payment fee",
+					err,
+					map[string]interface{}{"PaymentID": id},
+				)
+			}
+			entries := []*domain.JournalEntry{domain.NewCaptureEntry(updated)}
+			if *updated.FeeAmount > 0 {
+				entries = append(entries, domain.NewFeeEntry(updated, *updated.FeeAmount))
+			}
+			for _, entry := range entries {
+				if err := postJournalEntry(ctx, tx, entry); err != nil {
+					return domain.NewError(
+						storageErrorCode(err),
+						"Failed to record payment in the ledger",
+						"Error occurred while posting the "+string(entry.Kind)+" journal entry",
+						err,
+						map[string]interface{}{"PaymentID": id},
+					)
+				}
+			}
+		}
+
+		if err := recordAudit(ctx, tx, "payment.processed", domain.AuditPayment, id, before, updated); err != nil {
+			return domain.NewError(
+				storageErrorCode(err),
+				"Failed to audit payment processing",
+				"Error occurred while recording the status change in the audit log",
+				err,
+				map[string]interface{}{"PaymentID": id},
+			)
+		}
+
+		log.Info("payment processed",
+			slog.String("status", string(newStatus)),
+			slog.String("provider", provider),
+			slog.String("payment_method", string(paymentMethodType(p))),
+		)
+		return nil
 	})
 	if err != nil {
+		var derr domain.Error
+		if errors.As(err, &derr) {
+			return derr
+		}
 		return domain.NewError(
-			500,
-			"Failed to begin transaction",
-			"Error occurred while starting database transaction",
+			storageErrorCode(err),
+			"Failed to process payment",
+			"Error occurred while committing the payment transaction",
 			err,
 			map[string]interface{}{"PaymentID": id},
 		)
 	}
-	defer tx.Rollback(ctx)
-	qtx := db.New(tx)
+	return nil
+}
 
-	// Use row-level locking to prevent race conditions
-	p, err := qtx.GetPaymentByIDWithLock(ctx, paymentID)
+// lockPayment reads a payment with a row-level lock held until tx ends, so
+// its status cannot change underneath the caller. The worker holds it while
+// it charges the payment.
+func lockPayment(ctx context.Context, tx domain.UnitOfWork, paymentID uuid.UUID) (*domain.Payment, error) {
+	p, err := tx.Payments().GetPaymentByIDWithLock(ctx, paymentID)
 	if err != nil {
-		return domain.NewError(
-			500,
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewError(
+				domain.ErrPaymentNotFound,
+				"Payment not found",
+				"The specified payment could not be found",
+				err,
+				map[string]interface{}{"PaymentID": paymentID},
+			)
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
 			"Failed to fetch payment",
 			"Error occurred while retrieving payment information",
 			err,
-			map[string]interface{}{"PaymentID": id},
+			map[string]interface{}{"PaymentID": paymentID},
 		)
 	}
-	if p.ID == uuid.Nil {
-		return domain.NewError(
-			404,
-			"Payment not found",
-			"The specified payment could not be found",
-			nil,
-			map[string]interface{}{"PaymentID": id},
-		)
+	return p, nil
+}
+
+// charge asks the routed providers for a result, failing over to the next
+// candidate when one is unavailable. A payment no rule routes is failed.
+func (u *PaymentService) charge(ctx context.Context, p *domain.Payment) (domain.PaymentStatus, string, error) {
+	log := logger.FromContext(ctx)
+
+	candidates, err := u.router.Route(p)
+	if errors.Is(err, domain.ErrNoRoute) {
+		log.Warn("no provider route for payment", slog.String("currency", p.Currency), slog.Float64("amount", p.Amount))
+		return domain.StatusFailed, "", nil
+	}
+	if err != nil {
+		return "", "", domain.NewError(domain.ErrInternal, "Failed to route payment", "Error occurred while selecting a provider", err, nil)
 	}
 
-	// Idempotency check: only process if PENDING
-	if string(p.Status) != string(domain.StatusPending) {
-		fmt.Printf("payment %s already processed with status %s\n", id, p.Status)
-		return domain.NewError(
-			409,
-			"Payment already processed",
-			"This payment has already been processed with status "+string(p.Status),
-			nil,
-			map[string]interface{}{"status": p.Status},
-		)
+	var lastErr error
+	allOpen := true
+	for _, provider := range candidates {
+		res, err := provider.Charge(ctx, p)
+		if !errors.Is(err, domain.ErrCircuitOpen) {
+			// A short-circuited call never reached the provider
+			u.recordAttempt(ctx, p.ID, provider.Name(), res, err)
+		}
+
+		switch {
+		case err == nil:
+			return res.Status, provider.Name(), nil
+		case errors.Is(err, domain.ErrProviderUnavailable):
+			log.Warn("provider unavailable, failing over", slog.String("provider", provider.Name()), slog.Any("error", err))
+			lastErr = err
+			allOpen = allOpen && errors.Is(err, domain.ErrCircuitOpen)
+		default:
+			// The provider rejected the payment itself; another one would too
+			return domain.StatusFailed, provider.Name(), nil
+		}
 	}
 
-	// Simulate processing
-	time.Sleep(2 * time.Second)
+	// Every candidate was unavailable: leave the payment pending for a retry
+	if allOpen {
+		// Nothing was even tried, so retrying right away would only spin
+		return "", "", domain.NewError(
+			domain.ErrProviderCircuitOpen,
+			"Payment providers temporarily disabled",
+			"The circuit breaker of every provider routed for this payment is open",
+			lastErr,
+			map[string]interface{}{"PaymentID": p.ID},
+		)
+	}
+	return "", "", domain.NewError(
+		domain.ErrNoProviderAvailable,
+		"No payment provider available",
+		"Every provider routed for this payment was unavailable",
+		lastErr,
+		map[string]interface{}{"PaymentID": p.ID},
+	)
+}
 
-	newStatus := domain.StatusSuccess
-	if rand.Float32() < 0.3 { // 30% failure rate for simulation
-		newStatus = domain.StatusFailed
+// recordAttempt stores a provider call. It deliberately bypasses the
+// processing transaction so attempts survive a rollback, and a failure to
+// record one does not change the payment's outcome.
+func (u *PaymentService) recordAttempt(ctx context.Context, paymentID uuid.UUID, provider string, res *domain.ChargeResult, err error) {
+	attempt := &domain.PaymentAttempt{PaymentID: paymentID, Provider: provider}
+	switch {
+	case err == nil && res.Status == domain.StatusSuccess:
+		attempt.Status = domain.AttemptSucceeded
+		attempt.ProviderReference = res.Reference
+	case err == nil:
+		attempt.Status = domain.AttemptDeclined
+		attempt.ProviderReference = res.Reference
+	case errors.Is(err, domain.ErrProviderUnavailable):
+		attempt.Status = domain.AttemptUnavailable
+		attempt.Error = err.Error()
+	default:
+		attempt.Status = domain.AttemptFailed
+		attempt.Error = err.Error()
 	}
 
-	_, err = qtx.UpdatePaymentStatus(ctx, db.UpdatePaymentStatusParams{
-		ID:     uuid.MustParse(id),
-		Status: db.Paymentstatus(newStatus),
-	})
-	if err != nil {
-		return domain.NewError(
-			500,
-			"Failed to update payment status",
-			"Error occurred while updating payment status in the database",
-			err,
-			map[string]interface{}{"PaymentID": id, "NewStatus": newStatus, "OldStatus": p.Status, "Payment": p},
+	if err := u.uow.Payments().CreatePaymentAttempt(ctx, attempt); err != nil {
+		logger.FromContext(ctx).Error("failed to record payment attempt",
+			slog.String("provider", provider),
+			slog.Any("error", err),
 		)
 	}
+}
+
+func paymentMethodType(p *domain.Payment) domain.PaymentMethodType {
+	if p.PaymentMethod == nil {
+		return ""
+	}
+	return p.PaymentMethod.Type
+}
 
-	fmt.Printf("payment %s processed with status %s\n", id, newStatus)
-	return nil
+// storageErrorCode classifies an unexpected repository error. Both codes are
+// server errors, so the worker treats them as retryable.
+func storageErrorCode(err error) domain.ErrorCode {
+	if errors.Is(err, domain.ErrUnavailable) {
+		return domain.ErrServiceUnavailable
+	}
+	return domain.ErrInternal
 }
@@ -0,0 +1,331 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type PaymentLinkService struct {
+	uow domain.UnitOfWork
+	// baseURL is where the link and checkout pages are served.
+	baseURL string
+}
+
+func NewPaymentLinkService(uow domain.UnitOfWork, baseURL string) domain.PaymentLinkService {
+	return &PaymentLinkService{
+		uow:     uow,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+func (s *PaymentLinkService) CreatePaymentLink(ctx context.Context, lr *domain.PaymentLinkRequest) (*domain.PaymentLink, error) {
+	metadata, err := bindMerchant(ctx, lr.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	lr.Metadata = metadata
+	if err := lr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"payment link request validation failed",
+			err,
+			map[string]interface{}{"req": lr},
+		)
+	}
+
+	slug, err := randomToken(9)
+	if err != nil {
+		return nil, domain.NewError(domain.ErrInternal, "Failed to create payment link", "Error occurred while generating the link slug", err, nil)
+	}
+	link := &domain.PaymentLink{
+		Slug:        slug,
+		Amount:      lr.Amount,
+		Currency:    lr.Currency,
+		Description: lr.Description,
+		MaxUses:     lr.MaxUses,
+		ExpiresAt:   lr.ExpiresAt,
+		SuccessURL:  lr.SuccessURL,
+		CancelURL:   lr.CancelURL,
+		Metadata:    lr.Metadata,
+	}
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		if err := tx.PaymentLinks().CreatePaymentLink(ctx, link); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "payment_link.created", domain.AuditPaymentLink, link.ID.String(), nil, link)
+	})
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to create payment link",
+			"Error occurred while saving the payment link",
+			err,
+			map[string]interface{}{"req": lr},
+		)
+	}
+
+	logger.FromContext(ctx).Info("payment link created", slog.String("payment_link_id", link.ID.String()))
+	return s.present(link), nil
+}
+
+// GetPaymentLinkByID returns the link along with the totals collected through it.
+func (s *PaymentLinkService) GetPaymentLinkByID(ctx context.Context, id string) (*domain.PaymentLink, error) {
+	linkID, err := parsePaymentLinkID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	link, err := s.uow.PaymentLinks().GetPaymentLinkByID(ctx, linkID)
+	if err != nil {
+		return nil, paymentLinkLookupError(err)
+	}
+	link.Collected, err = s.uow.PaymentLinks().GetPaymentLinkTotals(ctx, linkID)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to fetch payment link totals",
+			"Error occurred while totalling the link's payments",
+			err,
+			map[string]interface{}{"PaymentLinkID": id},
+		)
+	}
+	return s.present(link), nil
+}
+
+func (s *PaymentLinkService) GetPaymentLinkBySlug(ctx context.Context, slug string) (*domain.PaymentLink, error) {
+	link, err := s.uow.PaymentLinks().GetPaymentLinkBySlug(ctx, slug)
+	if err != nil {
+		return nil, paymentLinkLookupError(err)
+	}
+	return s.present(link), nil
+}
+
+func (s *PaymentLinkService) ListPaymentLinks(ctx context.Context, page domain.Page) (*domain.PaymentLinkList, error) {
+	links, err := s.uow.PaymentLinks().ListPaymentLinks(ctx, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list payment links",
+			"Error occurred while retrieving payment links",
+			err,
+			nil,
+		)
+	}
+	for i := range links {
+		s.present(&links[i])
+	}
+	return &domain.PaymentLinkList{Data: links, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (s *PaymentLinkService) SetPaymentLinkActive(ctx context.Context, id string, active bool) (*domain.PaymentLink, error) {
+	linkID, err := parsePaymentLinkID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var link *domain.PaymentLink
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		before, err := tx.PaymentLinks().GetPaymentLinkByID(ctx, linkID)
+		if err != nil {
+			return err
+		}
+		if link, err = tx.PaymentLinks().SetPaymentLinkActive(ctx, linkID, active); err != nil {
+			return err
+		}
+		action := "payment_link.deactivated"
+		if active {
+			action = "payment_link.activated"
+		}
+		return recordAudit(ctx, tx, action, domain.AuditPaymentLink, id, before, link)
+	})
+	if err != nil {
+		return nil, paymentLinkLookupError(err)
+	}
+	logger.FromContext(ctx).Info("payment link updated",
+		slog.String("payment_link_id", id),
+		slog.Bool("active", active),
+	)
+	return s.present(link), nil
+}
+
+func (s *PaymentLinkService) ListPaymentLinkPayments(ctx context.Context, id string, page domain.Page) (*domain.PaymentList, error) {
+	linkID, err := parsePaymentLinkID(id)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.uow.PaymentLinks().GetPaymentLinkByID(ctx, linkID); err != nil {
+		return nil, paymentLinkLookupError(err)
+	}
+
+	payments, err := s.uow.Payments().ListPaymentsByLink(ctx, linkID, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list payment link payments",
+			"Error occurred while retrieving the link's payments",
+			err,
+			map[string]interface{}{"PaymentLinkID": id},
+		)
+	}
+	return &domain.PaymentList{Data: payments, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// PayPaymentLink counts a use of the link and creates its payment and
+// checkout session together, so a link is never used without a payment. The
+// use is given back if the session closes without the payment being made.
+func (s *PaymentLinkService) PayPaymentLink(ctx context.Context, slug string, amount float64, clientIP string) (*domain.CheckoutSession, error) {
+	link, err := s.GetPaymentLinkBySlug(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+	if link.State != domain.LinkActive {
+		return nil, paymentLinkUnavailableError(link)
+	}
+
+	reference, err := randomToken(6)
+	if err != nil {
+		return nil, domain.NewError(domain.ErrInternal, "Failed to create payment", "Error occurred while generating the payment reference", err, nil)
+	}
+	if link.Amount != nil {
+		amount = *link.Amount
+	}
+	cr := &domain.CheckoutSessionRequest{
+		PaymentRequest: domain.PaymentRequest{
+			Amount:    amount,
+			Currency:  link.Currency,
+			Reference: "link-" + link.Slug + "-" + reference,
+			Metadata:  link.Metadata,
+			ClientIP:  clientIP,
+		},
+		SuccessURL: link.SuccessURL,
+		CancelURL:  link.CancelURL,
+	}
+	if cr.SuccessURL == "" {
+		cr.SuccessURL = link.URL + "?status=paid"
+	}
+	if cr.CancelURL == "" {
+		cr.CancelURL = link.URL
+	}
+	if err := cr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"payment amount validation failed",
+			err,
+			map[string]interface{}{"PaymentLinkID": link.ID},
+		)
+	}
+
+	payment := newPayment(&cr.PaymentRequest)
+	payment.PaymentLinkID = &link.ID
+	var session *domain.CheckoutSession
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		used, err := tx.PaymentLinks().UsePaymentLink(ctx, link.ID)
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) {
+				// Used up or deactivated since it was read
+				return paymentLinkUnavailableError(link)
+			}
+			return err
+		}
+		if err := recordAudit(ctx, tx, "payment_link.used", domain.AuditPaymentLink, link.ID.String(), link, used); err != nil {
+			return err
+		}
+		session, err = openCheckoutSession(ctx, tx, cr, payment)
+		return err
+	})
+	if err != nil {
+		return nil, paymentCreateError(err, &cr.PaymentRequest)
+	}
+
+	logger.FromContext(ctx).Info("payment link used",
+		slog.String("payment_link_id", link.ID.String()),
+		slog.String("payment_id", payment.ID.String()),
+		slog.String("checkout_session_id", session.ID.String()),
+	)
+	return presentCheckout(s.baseURL, session, payment), nil
+}
+
+// releasePaymentLinkUse gives back the link use counted for payment when its
+// checkout session closes without the payment being made, so abandoned or
+// canceled sessions do not exhaust a link.
+func releasePaymentLinkUse(ctx context.Context, tx domain.UnitOfWork, payment *domain.Payment) error {
+	if payment.PaymentLinkID == nil {
+		return nil
+	}
+	released, err := tx.PaymentLinks().ReleasePaymentLink(ctx, *payment.PaymentLinkID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return recordAudit(ctx, tx, "payment_link.released", domain.AuditPaymentLink, released.ID.String(), nil, released)
+}
+
+func (s *PaymentLinkService) present(link *domain.PaymentLink) *domain.PaymentLink {
+	link.URL = s.baseURL + "/pay/" + link.Slug
+	link.State = link.StateAt(time.Now())
+	return link
+}
+
+func parsePaymentLinkID(id string) (uuid.UUID, error) {
+	linkID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewError(
+			domain.ErrInvalidPaymentLinkID,
+			"Invalid payment link ID format",
+			"The provided payment link ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"PaymentLinkID": id},
+		)
+	}
+	return linkID, nil
+}
+
+func paymentLinkLookupError(err error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrPaymentLinkNotFound,
+			"Payment link not found",
+			"The specified payment link could not be found",
+			err,
+			nil,
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to fetch payment link",
+		"Error occurred while retrieving the payment link",
+		err,
+		nil,
+	)
+}
+
+func paymentLinkUnavailableError(link *domain.PaymentLink) error {
+	descriptions := map[domain.PaymentLinkState]string{
+		domain.LinkInactive:  "This payment link has been deactivated",
+		domain.LinkExpired:   "This payment link has expired",
+		domain.LinkExhausted: "This payment link has reached its maximum number of uses",
+	}
+	description, ok := descriptions[link.StateAt(time.Now())]
+	if !ok {
+		description = "This payment link can no longer be used"
+	}
+	return domain.NewError(
+		domain.ErrPaymentLinkUnavailable,
+		"Payment link unavailable",
+		description,
+		nil,
+		map[string]interface{}{"PaymentLinkID": link.ID},
+	)
+}
@@ -0,0 +1,241 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+type fakeLinkRepo struct {
+	domain.PaymentLinkRepo
+	byID     map[uuid.UUID]*domain.PaymentLink
+	payments *fakeRepo
+}
+
+func (r *fakeLinkRepo) CreatePaymentLink(ctx context.Context, l *domain.PaymentLink) error {
+	l.ID = uuid.New()
+	l.Active = true
+	l.CreatedAt = time.Now()
+	stored := *l
+	r.byID[l.ID] = &stored
+	return nil
+}
+
+func (r *fakeLinkRepo) GetPaymentLinkByID(ctx context.Context, id uuid.UUID) (*domain.PaymentLink, error) {
+	l, ok := r.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	found := *l
+	return &found, nil
+}
+
+func (r *fakeLinkRepo) GetPaymentLinkBySlug(ctx context.Context, slug string) (*domain.PaymentLink, error) {
+	for _, l := range r.byID {
+		if l.Slug == slug {
+			found := *l
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeLinkRepo) SetPaymentLinkActive(ctx context.Context, id uuid.UUID, active bool) (*domain.PaymentLink, error) {
+	l, ok := r.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	l.Active = active
+	updated := *l
+	return &updated, nil
+}
+
+func (r *fakeLinkRepo) UsePaymentLink(ctx context.Context, id uuid.UUID) (*domain.PaymentLink, error) {
+	l, ok := r.byID[id]
+	if !ok || l.StateAt(time.Now()) != domain.LinkActive {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	l.Uses++
+	used := *l
+	return &used, nil
+}
+
+func (r *fakeLinkRepo) ReleasePaymentLink(ctx context.Context, id uuid.UUID) (*domain.PaymentLink, error) {
+	l, ok := r.byID[id]
+	if !ok || l.Uses == 0 {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	l.Uses--
+	released := *l
+	return &released, nil
+}
+
+func (r *fakeLinkRepo) GetPaymentLinkTotals(ctx context.Context, id uuid.UUID) (*domain.PaymentLinkTotals, error) {
+	totals := &domain.PaymentLinkTotals{}
+	for _, p := range r.payments.byID {
+		if p.PaymentLinkID != nil && *p.PaymentLinkID == id && p.Status == domain.StatusSuccess {
+			totals.Count++
+			totals.Amount += p.Amount
+		}
+	}
+	return totals, nil
+}
+
+func (r *fakeRepo) ListPaymentsByLink(ctx context.Context, linkID uuid.UUID, page domain.Page) ([]domain.Payment, error) {
+	var payments []domain.Payment
+	for _, p := range r.byID {
+		if p.PaymentLinkID != nil && *p.PaymentLinkID == linkID {
+			payments = append(payments, *p)
+		}
+	}
+	return payments, nil
+}
+
+func setupPaymentLinkService() (domain.PaymentLinkService, *fakeUnitOfWork) {
+	repo := &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)}
+	uow := &fakeUnitOfWork{
+		repo:      repo,
+		customers: newFakeCustomerRepo(),
+		checkouts: &fakeCheckoutRepo{byID: make(map[uuid.UUID]*domain.CheckoutSession)},
+		links:     &fakeLinkRepo{byID: make(map[uuid.UUID]*domain.PaymentLink), payments: repo},
+	}
+	return service.NewPaymentLinkService(uow, "https://pay.example.com"), uow
+}
+
+func TestCreatePaymentLink(t *testing.T) {
+	amount, zero, past := 150.0, 0, time.Now().Add(-time.Hour)
+	tests := []struct {
+		name           string
+		req            domain.PaymentLinkRequest
+		expectedStatus int
+	}{
+		{"fixed amount", domain.PaymentLinkRequest{Amount: &amount, Currency: "ETB"}, 0},
+		{"payer-entered amount", domain.PaymentLinkRequest{Currency: "USD", Description: "Donations"}, 0},
+		{"unsupported currency", domain.PaymentLinkRequest{Currency: "EUR"}, http.StatusBadRequest},
+		{"zero max uses", domain.PaymentLinkRequest{Currency: "ETB", MaxUses: &zero}, http.StatusBadRequest},
+		{"expiry in the past", domain.PaymentLinkRequest{Currency: "ETB", ExpiresAt: &past}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := setupPaymentLinkService()
+			link, err := svc.CreatePaymentLink(context.Background(), &tt.req)
+			if tt.expectedStatus != 0 {
+				assert.Equal(t, tt.expectedStatus, errorStatus(t, err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "https://pay.example.com/pay/"+link.Slug, link.URL)
+			assert.Equal(t, domain.LinkActive, link.State)
+		})
+	}
+}
+
+func TestPayPaymentLink(t *testing.T) {
+	t.Run("fixed amount", func(t *testing.T) {
+		svc, uow := setupPaymentLinkService()
+		amount, maxUses := 150.0, 1
+		link, _ := svc.CreatePaymentLink(domain.WithMerchant(context.Background(), "acme"), &domain.PaymentLinkRequest{
+			Amount: &amount, Currency: "ETB", MaxUses: &maxUses, Metadata: domain.Metadata{"merchant_id": "acme"},
+		})
+
+		s, err := svc.PayPaymentLink(context.Background(), link.Slug, 999, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 150.0, s.Payment.Amount)
+		assert.Equal(t, &link.ID, s.Payment.PaymentLinkID)
+		assert.True(t, strings.HasPrefix(s.Payment.Reference, "link-"+link.Slug+"-"))
+		assert.Equal(t, "acme", s.Payment.Metadata["merchant_id"])
+		assert.Equal(t, link.URL+"?status=paid", s.SuccessURL)
+		assert.Equal(t, 1, uow.links.byID[link.ID].Uses)
+
+		_, err = svc.PayPaymentLink(context.Background(), link.Slug, 0, "")
+		assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+	})
+
+	t.Run("session closed without payment", func(t *testing.T) {
+		for _, expire := range []bool{false, true} {
+			svc, uow := setupPaymentLinkService()
+			checkouts := service.NewCheckoutService(uow, &fakePublisher{}, "https://pay.example.com")
+			amount, maxUses := 150.0, 1
+			link, _ := svc.CreatePaymentLink(context.Background(), &domain.PaymentLinkRequest{
+				Amount: &amount, Currency: "ETB", MaxUses: &maxUses,
+			})
+
+			s, err := svc.PayPaymentLink(context.Background(), link.Slug, 0, "")
+			assert.NoError(t, err)
+			if expire {
+				uow.checkouts.byID[s.ID].ExpiresAt = time.Now().Add(-time.Second)
+				_, err = checkouts.GetSessionByToken(context.Background(), s.Token)
+			} else {
+				_, err = checkouts.CancelSession(context.Background(), s.Token)
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, domain.StatusFailed, uow.repo.byID[s.Payment.ID].Status)
+			assert.Equal(t, 0, uow.links.byID[link.ID].Uses)
+
+			// The released use can be taken by another payer
+			_, err = svc.PayPaymentLink(context.Background(), link.Slug, 0, "")
+			assert.NoError(t, err)
+			assert.Equal(t, 1, uow.links.byID[link.ID].Uses)
+		}
+	})
+
+	t.Run("payer-entered amount", func(t *testing.T) {
+		svc, _ := setupPaymentLinkService()
+		link, _ := svc.CreatePaymentLink(context.Background(), &domain.PaymentLinkRequest{Currency: "USD"})
+
+		_, err := svc.PayPaymentLink(context.Background(), link.Slug, 0, "")
+		assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+
+		first, err := svc.PayPaymentLink(context.Background(), link.Slug, 20, "")
+		assert.NoError(t, err)
+		second, err := svc.PayPaymentLink(context.Background(), link.Slug, 35, "")
+		assert.NoError(t, err)
+		assert.NotEqual(t, first.Payment.Reference, second.Payment.Reference)
+
+		payments, err := svc.ListPaymentLinkPayments(context.Background(), link.ID.String(), domain.Page{Limit: 20})
+		assert.NoError(t, err)
+		assert.Len(t, payments.Data, 2)
+	})
+
+	t.Run("inactive link", func(t *testing.T) {
+		svc, _ := setupPaymentLinkService()
+		link, _ := svc.CreatePaymentLink(context.Background(), &domain.PaymentLinkRequest{Currency: "USD"})
+		link, err := svc.SetPaymentLinkActive(context.Background(), link.ID.String(), false)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.LinkInactive, link.State)
+
+		_, err = svc.PayPaymentLink(context.Background(), link.Slug, 20, "")
+		assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+	})
+
+	t.Run("unknown slug", func(t *testing.T) {
+		svc, _ := setupPaymentLinkService()
+		_, err := svc.PayPaymentLink(context.Background(), "nope", 20, "")
+		assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	})
+}
+
+func TestGetPaymentLinkByID(t *testing.T) {
+	svc, uow := setupPaymentLinkService()
+	link, _ := svc.CreatePaymentLink(context.Background(), &domain.PaymentLinkRequest{Currency: "ETB"})
+	paid, _ := svc.PayPaymentLink(context.Background(), link.Slug, 40, "")
+	_, _ = svc.PayPaymentLink(context.Background(), link.Slug, 60, "")
+	uow.repo.byID[paid.PaymentID].Status = domain.StatusSuccess
+
+	got, err := svc.GetPaymentLinkByID(context.Background(), link.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, got.Uses)
+	assert.Equal(t, &domain.PaymentLinkTotals{Count: 1, Amount: 40}, got.Collected)
+
+	_, err = svc.GetPaymentLinkByID(context.Background(), "not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+}
@@ -0,0 +1,557 @@
+package service_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+	"pgm/internal/service"
+)
+
+type fakeRepo struct {
+	domain.PaymentRepo
+	byID     map[uuid.UUID]*domain.Payment
+	attempts []domain.PaymentAttempt
+	failure  error
+}
+
+func (r *fakeRepo) CreatePayment(ctx context.Context, p *domain.Payment) error {
+	if r.failure != nil {
+		return r.failure
+	}
+	for _, existing := range r.byID {
+		if existing.Reference == p.Reference {
+			return fmt.Errorf("%w: duplicate key", domain.ErrConflict)
+		}
+	}
+	p.ID = uuid.New()
+	if p.Status == "" {
+		p.Status = domain.StatusPending
+	}
+	p.CreatedAt = time.Now()
+	p.UpdatedAt = p.CreatedAt
+	r.byID[p.ID] = p
+	return nil
+}
+
+func (r *fakeRepo) GetPaymentByID(ctx context.Context, id uuid.UUID) (*domain.Payment, error) {
+	if r.failure != nil {
+		return nil, r.failure
+	}
+	p, ok := r.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	return p, nil
+}
+
+func (r *fakeRepo) ListPayments(ctx context.Context, filter domain.PaymentFilter, page domain.Page) ([]domain.Payment, error) {
+	var payments []domain.Payment
+	for _, p := range r.byID {
+		if p.Metadata[domain.MerchantMetadataKey] == filter.MerchantID {
+			payments = append(payments, *p)
+		}
+	}
+	return payments, nil
+}
+
+func (r *fakeRepo) GetPaymentByIDWithLock(ctx context.Context, id uuid.UUID) (*domain.Payment, error) {
+	return r.GetPaymentByID(ctx, id)
+}
+
+func (r *fakeRepo) UpdatePaymentResult(ctx context.Context, id uuid.UUID, status domain.PaymentStatus, provider string) (*domain.Payment, error) {
+	p, err := r.GetPaymentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p.Status = status
+	p.Provider = provider
+	return p, nil
+}
+
+func (r *fakeRepo) SetPaymentFee(ctx context.Context, id uuid.UUID, fee float64) (*domain.Payment, error) {
+	p, err := r.GetPaymentByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	net := p.Amount - fee
+	p.FeeAmount = &fee
+	p.NetAmount = &net
+	return p, nil
+}
+
+func (r *fakeRepo) CreatePaymentAttempt(ctx context.Context, a *domain.PaymentAttempt) error {
+	a.ID = uuid.New()
+	r.attempts = append(r.attempts, *a)
+	return nil
+}
+
+func (r *fakeRepo) ListPaymentAttempts(ctx context.Context, paymentID uuid.UUID) ([]domain.PaymentAttempt, error) {
+	var attempts []domain.PaymentAttempt
+	for _, a := range r.attempts {
+		if a.PaymentID == paymentID {
+			attempts = append(attempts, a)
+		}
+	}
+	return attempts, nil
+}
+
+type fakeUnitOfWork struct {
+	repo            *fakeRepo
+	customers       *fakeCustomerRepo
+	checkouts       *fakeCheckoutRepo
+	links           *fakeLinkRepo
+	plans           *fakePlanRepo
+	subs            *fakeSubscriptionRepo
+	ledger          *fakeLedgerRepo
+	fees            *fakeFeeRepo
+	settlements     *fakeSettlementRepo
+	reconciliations *fakeReconciliationRepo
+	disputes        *fakeDisputeRepo
+	risk            *fakeRiskRepo
+	reviews         *fakeReviewRepo
+	audit           *fakeAuditRepo
+}
+
+func (u *fakeUnitOfWork) Payments() domain.PaymentRepo { return u.repo }
+
+func (u *fakeUnitOfWork) Customers() domain.CustomerRepo { return u.customers }
+
+func (u *fakeUnitOfWork) CheckoutSessions() domain.CheckoutSessionRepo {
+	if u.checkouts == nil {
+		u.checkouts = &fakeCheckoutRepo{byID: make(map[uuid.UUID]*domain.CheckoutSession)}
+	}
+	return u.checkouts
+}
+
+func (u *fakeUnitOfWork) PaymentLinks() domain.PaymentLinkRepo { return u.links }
+
+func (u *fakeUnitOfWork) Plans() domain.PlanRepo { return u.plans }
+
+func (u *fakeUnitOfWork) Subscriptions() domain.SubscriptionRepo { return u.subs }
+
+func (u *fakeUnitOfWork) Ledger() domain.LedgerRepo { return u.ledger }
+
+func (u *fakeUnitOfWork) Fees() domain.FeeRepo { return u.fees }
+
+func (u *fakeUnitOfWork) Settlements() domain.SettlementRepo { return u.settlements }
+
+func (u *fakeUnitOfWork) Reconciliations() domain.ReconciliationRepo { return u.reconciliations }
+
+func (u *fakeUnitOfWork) Disputes() domain.DisputeRepo { return u.disputes }
+
+// Risk defaults to a repo without rules because every created payment is
+// screened.
+func (u *fakeUnitOfWork) Risk() domain.RiskRepo {
+	if u.risk == nil {
+		u.risk = &fakeRiskRepo{}
+	}
+	return u.risk
+}
+
+// Reviews defaults to an empty queue for the same reason.
+func (u *fakeUnitOfWork) Reviews() domain.ReviewRepo {
+	if u.reviews == nil {
+		u.reviews = &fakeReviewRepo{}
+	}
+	return u.reviews
+}
+
+// Audit defaults to an empty log because every change is recorded.
+func (u *fakeUnitOfWork) Audit() domain.AuditRepo {
+	if u.audit == nil {
+		u.audit = &fakeAuditRepo{}
+	}
+	return u.audit
+}
+
+func (u *fakeUnitOfWork) WithinTx(ctx context.Context, fn func(tx domain.UnitOfWork) error) error {
+	return fn(u)
+}
+
+type fakePublisher struct {
+	published []string
+	// failAfter, when set, fails every publish once that many were sent.
+	failAfter int
+}
+
+func (p *fakePublisher) PublishPaymentCreated(ctx context.Context, paymentID string) error {
+	if p.failAfter > 0 && len(p.published) >= p.failAfter {
+		return errors.New("broker down")
+	}
+	p.published = append(p.published, paymentID)
+	return nil
+}
+
+// fakeProvider answers every charge with status, or fails with err.
+type fakeProvider struct {
+	name   string
+	status domain.PaymentStatus
+	err    error
+	calls  int
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Charge(ctx context.Context, payment *domain.Payment) (*domain.ChargeResult, error) {
+	p.calls++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &domain.ChargeResult{Status: p.status, Reference: p.name + "-ref"}, nil
+}
+
+type fakeRouter struct {
+	providers []domain.Provider
+}
+
+func (r *fakeRouter) Route(p *domain.Payment) ([]domain.Provider, error) {
+	if len(r.providers) == 0 {
+		return nil, domain.ErrNoRoute
+	}
+	return r.providers, nil
+}
+
+func setupService(failure error, providers ...domain.Provider) (domain.PaymentService, *fakeRepo, *fakePublisher) {
+	repo := &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment), failure: failure}
+	pub := &fakePublisher{}
+	uow := &fakeUnitOfWork{repo: repo, customers: newFakeCustomerRepo(), ledger: &fakeLedgerRepo{}, fees: &fakeFeeRepo{}}
+	return service.NewPaymentService(uow, pub, &fakeRouter{providers: providers}), repo, pub
+}
+
+func TestCreatePayment(t *testing.T) {
+	req := &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "order-1"}
+
+	t.Run("creates and publishes", func(t *testing.T) {
+		svc, _, pub := setupService(nil)
+		p, err := svc.CreatePayment(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusPending, p.Status)
+		assert.Equal(t, []string{p.ID.String()}, pub.published)
+	})
+
+	t.Run("duplicate reference is a conflict", func(t *testing.T) {
+		svc, _, _ := setupService(nil)
+		_, err := svc.CreatePayment(context.Background(), req)
+		assert.NoError(t, err)
+
+		_, err = svc.CreatePayment(context.Background(), req)
+		var derr domain.Error
+		if assert.ErrorAs(t, err, &derr) {
+			assert.Equal(t, http.StatusConflict, derr.Code)
+			assert.Equal(t, domain.ErrDuplicateReference, derr.Type)
+		}
+	})
+
+	t.Run("stores metadata", func(t *testing.T) {
+		svc, repo, _ := setupService(nil)
+		withMetadata := *req
+		withMetadata.Metadata = domain.Metadata{"order_id": "1234"}
+		p, err := svc.CreatePayment(context.Background(), &withMetadata)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Metadata{"order_id": "1234"}, repo.byID[p.ID].Metadata)
+	})
+
+	t.Run("merchant_id is bound to the authenticated merchant", func(t *testing.T) {
+		svc, repo, _ := setupService(nil)
+		acme := domain.WithMerchant(context.Background(), "acme")
+		withMetadata := *req
+		withMetadata.Metadata = domain.Metadata{"order_id": "1234"}
+		p, err := svc.CreatePayment(acme, &withMetadata)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.Metadata{"order_id": "1234", "merchant_id": "acme"}, repo.byID[p.ID].Metadata)
+
+		tests := []struct {
+			name string
+			ctx  context.Context
+		}{
+			{"another merchant", acme},
+			{"anonymous caller", context.Background()},
+		}
+		for _, tt := range tests {
+			withMetadata := *req
+			withMetadata.Reference = "order-" + tt.name
+			withMetadata.Metadata = domain.Metadata{"merchant_id": "globex"}
+			_, err := svc.CreatePayment(tt.ctx, &withMetadata)
+			var derr domain.Error
+			if assert.ErrorAs(t, err, &derr, tt.name) {
+				assert.Equal(t, http.StatusForbidden, derr.Code)
+				assert.Equal(t, domain.ErrForbidden, derr.Type)
+			}
+		}
+	})
+
+	t.Run("too many metadata keys", func(t *testing.T) {
+		svc, _, _ := setupService(nil)
+		withMetadata := *req
+		withMetadata.Metadata = domain.Metadata{}
+		for i := 0; i <= domain.MaxMetadataKeys; i++ {
+			withMetadata.Metadata[fmt.Sprintf("key_%d", i)] = "v"
+		}
+		_, err := svc.CreatePayment(context.Background(), &withMetadata)
+		var derr domain.Error
+		if assert.ErrorAs(t, err, &derr) {
+			assert.Equal(t, domain.ErrValidationFailed, derr.Type)
+		}
+	})
+
+	t.Run("unavailable database", func(t *testing.T) {
+		svc, _, _ := setupService(fmt.Errorf("%w: dial tcp", domain.ErrUnavailable))
+		_, err := svc.CreatePayment(context.Background(), req)
+		var derr domain.Error
+		if assert.ErrorAs(t, err, &derr) {
+			assert.Equal(t, http.StatusServiceUnavailable, derr.Code)
+		}
+	})
+}
+
+func TestProcessPaymentLogsPaymentIDOnce(t *testing.T) {
+	unavailable := &fakeProvider{name: "a", err: fmt.Errorf("%w: timeout", domain.ErrProviderUnavailable)}
+	svc, _, _ := setupService(nil, unavailable, &fakeProvider{name: "b", status: domain.StatusSuccess})
+	p, err := svc.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "order-1"})
+	assert.NoError(t, err)
+
+	// The consumer tags its logger with the payment ID before processing
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil)).With(slog.String("payment_id", p.ID.String()))
+	assert.NoError(t, svc.ProcessPayment(logger.NewContext(context.Background(), log), p.ID.String()))
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.Equal(t, 1, strings.Count(line, "payment_id="), line)
+	}
+}
+
+func TestGetPaymentByID(t *testing.T) {
+	t.Run("not found", func(t *testing.T) {
+		svc, _, _ := setupService(nil)
+		_, err := svc.GetPaymentByID(context.Background(), uuid.NewString())
+		var derr domain.Error
+		if assert.ErrorAs(t, err, &derr) {
+			assert.Equal(t, http.StatusNotFound, derr.Code)
+			assert.Equal(t, domain.ErrPaymentNotFound, derr.Type)
+		}
+	})
+
+	t.Run("found", func(t *testing.T) {
+		svc, _, _ := setupService(nil)
+		created, err := svc.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 5, Currency: "ETB", Reference: "order-2"})
+		assert.NoError(t, err)
+
+		got, err := svc.GetPaymentByID(context.Background(), created.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, created.ID, got.ID)
+	})
+
+	t.Run("another merchant's payment", func(t *testing.T) {
+		svc, _, _ := setupService(nil)
+		acme := domain.WithMerchant(context.Background(), "acme")
+		created, err := svc.CreatePayment(acme, &domain.PaymentRequest{Amount: 5, Currency: "ETB", Reference: "order-3"})
+		assert.NoError(t, err)
+
+		_, err = svc.GetPaymentByID(acme, created.ID.String())
+		assert.NoError(t, err)
+		_, err = svc.GetPaymentByID(domain.WithMerchant(context.Background(), "globex"), created.ID.String())
+		assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	})
+}
+
+func TestListPayments(t *testing.T) {
+	svc, _, _ := setupService(nil)
+	acme := domain.WithMerchant(context.Background(), "acme")
+	created, err := svc.CreatePayment(acme, &domain.PaymentRequest{Amount: 5, Currency: "ETB", Reference: "order-4"})
+	assert.NoError(t, err)
+	_, err = svc.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 5, Currency: "ETB", Reference: "order-5"})
+	assert.NoError(t, err)
+
+	list, err := svc.ListPayments(acme, domain.PaymentFilter{MerchantID: "globex"}, domain.Page{Limit: 20})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Data, 1) {
+		assert.Equal(t, created.ID, list.Data[0].ID)
+	}
+
+	_, err = svc.ListPayments(context.Background(), domain.PaymentFilter{}, domain.Page{Limit: 20})
+	assert.Equal(t, http.StatusUnauthorized, errorStatus(t, err))
+}
+
+func TestProcessPayment(t *testing.T) {
+	unavailable := fmt.Errorf("%w: timeout", domain.ErrProviderUnavailable)
+	circuitOpen := fmt.Errorf("%w: %w", domain.ErrProviderUnavailable, domain.ErrCircuitOpen)
+
+	tests := []struct {
+		name             string
+		providers        []*fakeProvider
+		expectedStatus   domain.PaymentStatus
+		expectedProvider string
+		expectedAttempts []domain.AttemptStatus
+		expectedErr      domain.ErrorCode
+	}{
+		{
+			name:             "first provider succeeds",
+			providers:        []*fakeProvider{{name: "a", status: domain.StatusSuccess}, {name: "b", status: domain.StatusSuccess}},
+			expectedStatus:   domain.StatusSuccess,
+			expectedProvider: "a",
+			expectedAttempts: []domain.AttemptStatus{domain.AttemptSucceeded},
+		},
+		{
+			name:             "fails over when unavailable",
+			providers:        []*fakeProvider{{name: "a", err: unavailable}, {name: "b", status: domain.StatusSuccess}},
+			expectedStatus:   domain.StatusSuccess,
+			expectedProvider: "b",
+			expectedAttempts: []domain.AttemptStatus{domain.AttemptUnavailable, domain.AttemptSucceeded},
+		},
+		{
+			name:             "decline does not fail over",
+			providers:        []*fakeProvider{{name: "a", status: domain.StatusFailed}, {name: "b", status: domain.StatusSuccess}},
+			expectedStatus:   domain.StatusFailed,
+			expectedProvider: "a",
+			expectedAttempts: []domain.AttemptStatus{domain.AttemptDeclined},
+		},
+		{
+			name:             "non-retryable error fails the payment",
+			providers:        []*fakeProvider{{name: "a", err: errors.New("invalid account")}, {name: "b", status: domain.StatusSuccess}},
+			expectedStatus:   domain.StatusFailed,
+			expectedProvider: "a",
+			expectedAttempts: []domain.AttemptStatus{domain.AttemptFailed},
+		},
+		{
+			name:             "all unavailable stays pending",
+			providers:        []*fakeProvider{{name: "a", err: unavailable}, {name: "b", err: unavailable}},
+			expectedStatus:   domain.StatusPending,
+			expectedAttempts: []domain.AttemptStatus{domain.AttemptUnavailable, domain.AttemptUnavailable},
+			expectedErr:      domain.ErrNoProviderAvailable,
+		},
+		{
+			name:           "all circuits open asks to retry later",
+			providers:      []*fakeProvider{{name: "a", err: circuitOpen}, {name: "b", err: circuitOpen}},
+			expectedStatus: domain.StatusPending,
+			expectedErr:    domain.ErrProviderCircuitOpen,
+		},
+		{
+			name:             "open circuit is skipped without an attempt",
+			providers:        []*fakeProvider{{name: "a", err: circuitOpen}, {name: "b", status: domain.StatusSuccess}},
+			expectedStatus:   domain.StatusSuccess,
+			expectedProvider: "b",
+			expectedAttempts: []domain.AttemptStatus{domain.AttemptSucceeded},
+		},
+		{
+			name:           "no route fails the payment",
+			expectedStatus: domain.StatusFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var providers []domain.Provider
+			for _, p := range tt.providers {
+				providers = append(providers, p)
+			}
+			svc, repo, _ := setupService(nil, providers...)
+			created, err := svc.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "order-1"})
+			assert.NoError(t, err)
+
+			err = svc.ProcessPayment(context.Background(), created.ID.String())
+			if tt.expectedErr != "" {
+				var derr domain.Error
+				if assert.ErrorAs(t, err, &derr) {
+					assert.Equal(t, tt.expectedErr, derr.Type)
+					assert.Equal(t, http.StatusServiceUnavailable, derr.Code)
+				}
+			} else {
+				assert.NoError(t, err)
+			}
+
+			got, err := svc.GetPaymentByID(context.Background(), created.ID.String())
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedStatus, got.Status)
+			assert.Equal(t, tt.expectedProvider, got.Provider)
+
+			var statuses []domain.AttemptStatus
+			for _, a := range repo.attempts {
+				statuses = append(statuses, a.Status)
+			}
+			assert.Equal(t, tt.expectedAttempts, statuses)
+			assert.Len(t, got.Attempts, len(tt.expectedAttempts))
+		})
+	}
+}
+
+func TestCancelPayment(t *testing.T) {
+	// CreatePayment binds the merchant into the request's metadata
+	req := func() *domain.PaymentRequest {
+		return &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "order-1"}
+	}
+	acme := domain.WithMerchant(context.Background(), "acme")
+
+	t.Run("cancels pending and worker skips it", func(t *testing.T) {
+		provider := &fakeProvider{name: "a", status: domain.StatusSuccess}
+		svc, _, _ := setupService(nil, provider)
+		created, err := svc.CreatePayment(acme, req())
+		assert.NoError(t, err)
+
+		p, err := svc.CancelPayment(acme, created.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusCanceled, p.Status)
+
+		// The consumer acks a nil result, so the message is not retried
+		assert.NoError(t, svc.ProcessPayment(context.Background(), created.ID.String()))
+		assert.Equal(t, 0, provider.calls)
+		got, err := svc.GetPaymentByID(context.Background(), created.ID.String())
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusCanceled, got.Status)
+	})
+
+	t.Run("processed payment cannot be canceled", func(t *testing.T) {
+		svc, _, _ := setupService(nil, &fakeProvider{name: "a", status: domain.StatusSuccess})
+		created, err := svc.CreatePayment(acme, req())
+		assert.NoError(t, err)
+		assert.NoError(t, svc.ProcessPayment(context.Background(), created.ID.String()))
+
+		_, err = svc.CancelPayment(acme, created.ID.String())
+		assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+	})
+
+	t.Run("another merchant's payment", func(t *testing.T) {
+		svc, repo, _ := setupService(nil)
+		created, err := svc.CreatePayment(acme, req())
+		assert.NoError(t, err)
+
+		_, err = svc.CancelPayment(domain.WithMerchant(context.Background(), "globex"), created.ID.String())
+		assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+		assert.Equal(t, domain.StatusPending, repo.byID[created.ID].Status)
+	})
+
+	t.Run("without a merchant key", func(t *testing.T) {
+		svc, repo, _ := setupService(nil)
+		created, err := svc.CreatePayment(context.Background(), req())
+		assert.NoError(t, err)
+
+		_, err = svc.CancelPayment(context.Background(), created.ID.String())
+		assert.Equal(t, http.StatusUnauthorized, errorStatus(t, err))
+		assert.Equal(t, domain.StatusPending, repo.byID[created.ID].Status)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		svc, _, _ := setupService(nil)
+		_, err := svc.CancelPayment(acme, "not-a-uuid")
+		assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	})
+
+	t.Run("unknown payment", func(t *testing.T) {
+		svc, _, _ := setupService(nil)
+		_, err := svc.CancelPayment(acme, uuid.NewString())
+		assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	})
+}
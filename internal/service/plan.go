@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type PlanService struct {
+	uow domain.UnitOfWork
+}
+
+func NewPlanService(uow domain.UnitOfWork) domain.PlanService {
+	return &PlanService{uow: uow}
+}
+
+func (s *PlanService) CreatePlan(ctx context.Context, pr *domain.PlanRequest) (*domain.Plan, error) {
+	if err := pr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"plan request validation failed",
+			err,
+			map[string]interface{}{"req": pr},
+		)
+	}
+
+	plan := &domain.Plan{
+		Name:          pr.Name,
+		Amount:        pr.Amount,
+		Currency:      pr.Currency,
+		Interval:      pr.Interval,
+		IntervalCount: pr.IntervalCount,
+		TrialDays:     pr.TrialDays,
+	}
+	if plan.IntervalCount == 0 {
+		plan.IntervalCount = 1
+	}
+	err := s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		if err := tx.Plans().CreatePlan(ctx, plan); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "plan.created", domain.AuditPlan, plan.ID.String(), nil, plan)
+	})
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to create plan",
+			"Error occurred while saving the plan",
+			err,
+			map[string]interface{}{"req": pr},
+		)
+	}
+
+	logger.FromContext(ctx).Info("plan created", slog.String("plan_id", plan.ID.String()))
+	return plan, nil
+}
+
+func (s *PlanService) GetPlanByID(ctx context.Context, id string) (*domain.Plan, error) {
+	planID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, domain.NewError(
+			domain.ErrInvalidPlanID,
+			"Invalid plan ID format",
+			"The provided plan ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"PlanID": id},
+		)
+	}
+
+	plan, err := s.uow.Plans().GetPlanByID(ctx, planID)
+	if err != nil {
+		return nil, planLookupError(err, planID)
+	}
+	return plan, nil
+}
+
+func (s *PlanService) ListPlans(ctx context.Context, page domain.Page) (*domain.PlanList, error) {
+	plans, err := s.uow.Plans().ListPlans(ctx, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list plans",
+			"Error occurred while retrieving plans",
+			err,
+			nil,
+		)
+	}
+	return &domain.PlanList{Data: plans, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func planLookupError(err error, id uuid.UUID) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrPlanNotFound,
+			"Plan not found",
+			"The specified plan could not be found",
+			err,
+			map[string]interface{}{"PlanID": id},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to fetch plan",
+		"Error occurred while retrieving the plan",
+		err,
+		map[string]interface{}{"PlanID": id},
+	)
+}
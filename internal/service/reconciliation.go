@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type ReconciliationService struct {
+	uow     domain.UnitOfWork
+	parsers map[domain.StatementFormat]domain.StatementParser
+}
+
+func NewReconciliationService(uow domain.UnitOfWork, parsers map[domain.StatementFormat]domain.StatementParser) domain.ReconciliationService {
+	return &ReconciliationService{uow: uow, parsers: parsers}
+}
+
+// ImportStatement reconciles every line of the statement against the
+// payments and stores the outcome as a run. A line matches the payment whose
+// reference, or provider reference, it carries; it is missing internally if
+// there is none, the payment did not succeed or an earlier line already
+// settled it. Successful payments within the statement period that no line
+// names are missing at the provider; a statement without a period skips
+// that check.
+func (s *ReconciliationService) ImportStatement(ctx context.Context, format domain.StatementFormat, provider string, body io.Reader) (*domain.ReconciliationRun, error) {
+	parser, ok := s.parsers[format]
+	if !ok {
+		return nil, domain.NewError(
+			domain.ErrInvalidRequest,
+			"Unsupported statement format",
+			"The statement format is not one of the supported formats",
+			nil,
+			map[string]interface{}{"format": format},
+		)
+	}
+	stmt, err := parser.Parse(body)
+	if err != nil {
+		return nil, domain.NewError(
+			domain.ErrInvalidStatement,
+			"Invalid statement",
+			"The statement could not be read as "+string(format)+": "+err.Error(),
+			err,
+			map[string]interface{}{"format": format},
+		)
+	}
+	if len(stmt.Lines) == 0 {
+		return nil, domain.NewError(
+			domain.ErrInvalidStatement,
+			"Invalid statement",
+			"The statement has no settled lines",
+			nil,
+			map[string]interface{}{"format": format},
+		)
+	}
+
+	run := &domain.ReconciliationRun{
+		Format:      format,
+		Provider:    provider,
+		PeriodStart: stmt.PeriodStart,
+		PeriodEnd:   stmt.PeriodEnd,
+		LineCount:   len(stmt.Lines),
+	}
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		items, err := reconcile(ctx, tx, provider, stmt)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			run.Count(item.Result)
+		}
+		if err := tx.Reconciliations().CreateRun(ctx, run); err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].RunID = run.ID
+			if err := tx.Reconciliations().CreateItem(ctx, &items[i]); err != nil {
+				return err
+			}
+		}
+		return recordAudit(ctx, tx, "reconciliation.imported", domain.AuditReconciliation, run.ID.String(), nil, run)
+	})
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to reconcile statement",
+			"Error occurred while reconciling the statement against the payments",
+			err,
+			map[string]interface{}{"format": format, "provider": provider},
+		)
+	}
+
+	logger.FromContext(ctx).Info("statement reconciled",
+		slog.String("reconciliation_id", run.ID.String()),
+		slog.String("provider", provider),
+		slog.Int("matched", run.MatchedCount),
+		slog.Int("missing_internally", run.MissingInternallyCount),
+		slog.Int("missing_at_provider", run.MissingAtProviderCount),
+		slog.Int("amount_mismatch", run.AmountMismatchCount),
+	)
+	return run, nil
+}
+
+func reconcile(ctx context.Context, tx domain.UnitOfWork, provider string, stmt *domain.Statement) ([]domain.ReconciliationItem, error) {
+	items := make([]domain.ReconciliationItem, 0, len(stmt.Lines))
+	seen := make(map[uuid.UUID]bool)
+	for _, l := range stmt.Lines {
+		amount := l.Amount
+		item := domain.ReconciliationItem{
+			Result:            domain.ResultMissingInternally,
+			Reference:         l.Reference,
+			StatementAmount:   &amount,
+			StatementCurrency: l.Currency,
+			BookedAt:          l.BookedAt,
+		}
+		p, err := tx.Reconciliations().FindPayment(ctx, l.Reference)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+		if p != nil {
+			item.PaymentID = &p.ID
+			item.PaymentAmount = &p.Amount
+			item.PaymentCurrency = p.Currency
+			switch {
+			case p.Status != domain.StatusSuccess || seen[p.ID]:
+			case p.Currency != l.Currency || math.Abs(p.Amount-l.Amount) >= 0.005:
+				item.Result = domain.ResultAmountMismatch
+			default:
+				item.Result = domain.ResultMatched
+			}
+			seen[p.ID] = true
+		}
+		items = append(items, item)
+	}
+
+	if stmt.PeriodStart == nil || stmt.PeriodEnd == nil {
+		return items, nil
+	}
+	payments, err := tx.Reconciliations().ListSuccessfulPayments(ctx, provider, *stmt.PeriodStart, *stmt.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range payments {
+		if seen[p.ID] {
+			continue
+		}
+		items = append(items, domain.ReconciliationItem{
+			Result:          domain.ResultMissingAtProvider,
+			Reference:       p.Reference,
+			PaymentID:       &p.ID,
+			PaymentAmount:   &p.Amount,
+			PaymentCurrency: p.Currency,
+		})
+	}
+	return items, nil
+}
+
+func (s *ReconciliationService) ListRuns(ctx context.Context, page domain.Page) (*domain.ReconciliationRunList, error) {
+	runs, err := s.uow.Reconciliations().ListRuns(ctx, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list reconciliations",
+			"Error occurred while retrieving reconciliations",
+			err,
+			nil,
+		)
+	}
+	return &domain.ReconciliationRunList{Data: runs, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (s *ReconciliationService) GetRunByID(ctx context.Context, id string) (*domain.ReconciliationRun, error) {
+	runID, err := parseReconciliationID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	run, err := s.uow.Reconciliations().GetRunByID(ctx, runID)
+	if err != nil {
+		return nil, reconciliationLookupError(err, runID)
+	}
+	return run, nil
+}
+
+// ListRunItems lists the lines of a run, only those with result unless it
+// is nil.
+func (s *ReconciliationService) ListRunItems(ctx context.Context, id string, result *domain.ReconciliationResult, page domain.Page) (*domain.ReconciliationItemList, error) {
+	runID, err := parseReconciliationID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.uow.Reconciliations().GetRunByID(ctx, runID); err != nil {
+		return nil, reconciliationLookupError(err, runID)
+	}
+	items, err := s.uow.Reconciliations().ListItems(ctx, runID, result, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list reconciliation lines",
+			"Error occurred while retrieving the lines of the reconciliation",
+			err,
+			map[string]interface{}{"ReconciliationID": runID},
+		)
+	}
+	return &domain.ReconciliationItemList{Data: items, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// ListExceptions lists the unresolved exceptions of every run, oldest first.
+func (s *ReconciliationService) ListExceptions(ctx context.Context, page domain.Page) (*domain.ReconciliationItemList, error) {
+	items, err := s.uow.Reconciliations().ListUnresolvedItems(ctx, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list reconciliation exceptions",
+			"Error occurred while retrieving unresolved reconciliation exceptions",
+			err,
+			nil,
+		)
+	}
+	return &domain.ReconciliationItemList{Data: items, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// ResolveException closes an exception with a note on how it was settled.
+func (s *ReconciliationService) ResolveException(ctx context.Context, id string, rr *domain.ResolveExceptionRequest) (*domain.ReconciliationItem, error) {
+	itemID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, domain.NewError(
+			domain.ErrInvalidExceptionID,
+			"Invalid reconciliation exception ID format",
+			"The provided reconciliation exception ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"ExceptionID": id},
+		)
+	}
+	if err := rr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"resolve exception request validation failed",
+			err,
+			map[string]interface{}{"req": rr},
+		)
+	}
+
+	var item *domain.ReconciliationItem
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		var err error
+		if item, err = tx.Reconciliations().ResolveItem(ctx, itemID, rr.Note); err != nil {
+			return err
+		}
+		before := *item
+		before.ResolvedAt, before.ResolutionNote = nil, ""
+		return recordAudit(ctx, tx, "reconciliation_exception.resolved", domain.AuditReconciliationException, itemID.String(), before, item)
+	})
+	if errors.Is(err, domain.ErrNotFound) {
+		// Tell an already resolved exception apart from a missing one
+		item, err = s.uow.Reconciliations().GetItemByID(ctx, itemID)
+		switch {
+		case err == nil && item.ResolvedAt != nil:
+			return nil, domain.NewError(
+				domain.ErrExceptionResolved,
+				"Reconciliation exception already resolved",
+				"The reconciliation exception was resolved at "+item.ResolvedAt.Format(time.RFC3339),
+				nil,
+				map[string]interface{}{"ExceptionID": itemID},
+			)
+		case err == nil, errors.Is(err, domain.ErrNotFound):
+			// Matched lines are not exceptions
+			return nil, domain.NewError(
+				domain.ErrExceptionNotFound,
+				"Reconciliation exception not found",
+				"The specified reconciliation exception could not be found",
+				err,
+				map[string]interface{}{"ExceptionID": itemID},
+			)
+		}
+	}
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to resolve reconciliation exception",
+			"Error occurred while resolving the reconciliation exception",
+			err,
+			map[string]interface{}{"ExceptionID": itemID},
+		)
+	}
+
+	logger.FromContext(ctx).Info("reconciliation exception resolved",
+		slog.String("exception_id", id),
+		slog.String("result", string(item.Result)),
+	)
+	return item, nil
+}
+
+func parseReconciliationID(id string) (uuid.UUID, error) {
+	runID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewError(
+			domain.ErrInvalidReconciliationID,
+			"Invalid reconciliation ID format",
+			"The provided reconciliation ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"ReconciliationID": id},
+		)
+	}
+	return runID, nil
+}
+
+func reconciliationLookupError(err error, id uuid.UUID) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrReconciliationNotFound,
+			"Reconciliation not found",
+			"The specified reconciliation could not be found",
+			err,
+			map[string]interface{}{"ReconciliationID": id},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to fetch reconciliation",
+		"Error occurred while retrieving the reconciliation",
+		err,
+		map[string]interface{}{"ReconciliationID": id},
+	)
+}
@@ -0,0 +1,186 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+	"pgm/internal/statement"
+)
+
+type fakeReconciliationRepo struct {
+	domain.ReconciliationRepo
+	payments []*domain.Payment
+	runs     []*domain.ReconciliationRun
+	items    []*domain.ReconciliationItem
+}
+
+func (r *fakeReconciliationRepo) FindPayment(ctx context.Context, reference string) (*domain.ReconciledPayment, error) {
+	for _, p := range r.payments {
+		if p.Reference == reference {
+			return &domain.ReconciledPayment{ID: p.ID, Reference: p.Reference, Amount: p.Amount, Currency: p.Currency, Status: p.Status}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeReconciliationRepo) ListSuccessfulPayments(ctx context.Context, provider string, from, to time.Time) ([]domain.ReconciledPayment, error) {
+	var payments []domain.ReconciledPayment
+	for _, p := range r.payments {
+		if p.Status == domain.StatusSuccess && !p.UpdatedAt.Before(from) && p.UpdatedAt.Before(to) {
+			payments = append(payments, domain.ReconciledPayment{ID: p.ID, Reference: p.Reference, Amount: p.Amount, Currency: p.Currency, Status: p.Status})
+		}
+	}
+	return payments, nil
+}
+
+func (r *fakeReconciliationRepo) CreateRun(ctx context.Context, run *domain.ReconciliationRun) error {
+	run.ID = uuid.New()
+	r.runs = append(r.runs, run)
+	return nil
+}
+
+func (r *fakeReconciliationRepo) CreateItem(ctx context.Context, item *domain.ReconciliationItem) error {
+	item.ID = uuid.New()
+	stored := *item
+	r.items = append(r.items, &stored)
+	return nil
+}
+
+func (r *fakeReconciliationRepo) GetItemByID(ctx context.Context, id uuid.UUID) (*domain.ReconciliationItem, error) {
+	for _, i := range r.items {
+		if i.ID == id {
+			return i, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeReconciliationRepo) ResolveItem(ctx context.Context, id uuid.UUID, note string) (*domain.ReconciliationItem, error) {
+	i, err := r.GetItemByID(ctx, id)
+	if err != nil || i.Result == domain.ResultMatched || i.ResolvedAt != nil {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	now := time.Now()
+	i.ResolvedAt = &now
+	i.ResolutionNote = note
+	return i, nil
+}
+
+func (r *fakeReconciliationRepo) byResult(result domain.ReconciliationResult) []*domain.ReconciliationItem {
+	var items []*domain.ReconciliationItem
+	for _, i := range r.items {
+		if i.Result == result {
+			items = append(items, i)
+		}
+	}
+	return items
+}
+
+func setupReconciliation(payments ...*domain.Payment) (domain.ReconciliationService, *fakeReconciliationRepo) {
+	for _, p := range payments {
+		p.ID = uuid.New()
+	}
+	reconciliations := &fakeReconciliationRepo{payments: payments}
+	return service.NewReconciliationService(&fakeUnitOfWork{reconciliations: reconciliations}, statement.Parsers()), reconciliations
+}
+
+func TestImportStatement(t *testing.T) {
+	day := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	svc, reconciliations := setupReconciliation(
+		&domain.Payment{Reference: "ref-1", Amount: 100, Currency: "USD", Status: domain.StatusSuccess, UpdatedAt: day},
+		&domain.Payment{Reference: "ref-2", Amount: 50, Currency: "USD", Status: domain.StatusSuccess, UpdatedAt: day},
+		&domain.Payment{Reference: "ref-3", Amount: 20, Currency: "USD", Status: domain.StatusFailed, UpdatedAt: day},
+		&domain.Payment{Reference: "ref-4", Amount: 75, Currency: "USD", Status: domain.StatusSuccess, UpdatedAt: day},
+		&domain.Payment{Reference: "ref-5", Amount: 10, Currency: "USD", Status: domain.StatusSuccess, UpdatedAt: day.AddDate(0, 0, 1)},
+	)
+	csv := "reference,amount,currency,date\n" +
+		"ref-1,100.00,USD,2026-03-01\n" +
+		"ref-2,45.00,USD,2026-03-01\n" +
+		"ref-3,20.00,USD,2026-03-01\n" +
+		"ref-1,100.00,USD,2026-03-01\n" +
+		"unknown,5.00,USD,2026-03-01\n"
+
+	run, err := svc.ImportStatement(context.Background(), domain.FormatCSV, "", strings.NewReader(csv))
+	assert.NoError(t, err)
+	if run == nil {
+		t.Fatal("expected a run")
+	}
+	assert.Equal(t, 5, run.LineCount)
+	assert.Equal(t, 1, run.MatchedCount)
+	assert.Equal(t, 1, run.AmountMismatchCount)
+	// The failed payment, the duplicate line and the unknown reference
+	assert.Equal(t, 3, run.MissingInternallyCount)
+	// ref-4 succeeded on the statement's day but is not on it; ref-5 is outside the period
+	assert.Equal(t, 1, run.MissingAtProviderCount)
+
+	missing := reconciliations.byResult(domain.ResultMissingAtProvider)
+	if len(missing) != 1 {
+		t.Fatalf("expected 1 payment missing at the provider, got %d", len(missing))
+	}
+	assert.Equal(t, "ref-4", missing[0].Reference)
+	assert.Equal(t, run.ID, missing[0].RunID)
+
+	mismatched := reconciliations.byResult(domain.ResultAmountMismatch)
+	if len(mismatched) != 1 {
+		t.Fatalf("expected 1 amount mismatch, got %d", len(mismatched))
+	}
+	assert.Equal(t, 45.0, *mismatched[0].StatementAmount)
+	assert.Equal(t, 50.0, *mismatched[0].PaymentAmount)
+}
+
+func TestImportStatementErrors(t *testing.T) {
+	svc, reconciliations := setupReconciliation()
+
+	_, err := svc.ImportStatement(context.Background(), "mt940", "", strings.NewReader(""))
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+
+	for _, body := range []string{"reference,amount\n", "reference,amount,currency\n", "<Document/>"} {
+		_, err = svc.ImportStatement(context.Background(), domain.FormatCSV, "", strings.NewReader(body))
+		var derr domain.Error
+		if !errors.As(err, &derr) {
+			t.Fatalf("expected domain.Error, got %T", err)
+		}
+		assert.Equal(t, domain.ErrInvalidStatement, derr.Type)
+	}
+	assert.Empty(t, reconciliations.runs)
+}
+
+func TestResolveException(t *testing.T) {
+	svc, reconciliations := setupReconciliation(&domain.Payment{Reference: "ref-1", Amount: 10, Currency: "USD", Status: domain.StatusSuccess})
+	_, err := svc.ImportStatement(context.Background(), domain.FormatCSV, "", strings.NewReader("reference,amount,currency\nref-1,10,USD\nunknown,5,USD\n"))
+	assert.NoError(t, err)
+	matched := reconciliations.byResult(domain.ResultMatched)
+	missing := reconciliations.byResult(domain.ResultMissingInternally)
+	if len(matched) != 1 || len(missing) != 1 {
+		t.Fatalf("expected 1 matched and 1 missing line, got %d and %d", len(matched), len(missing))
+	}
+
+	_, err = svc.ResolveException(context.Background(), missing[0].ID.String(), &domain.ResolveExceptionRequest{})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+
+	item, err := svc.ResolveException(context.Background(), missing[0].ID.String(), &domain.ResolveExceptionRequest{Note: "Refunded by the bank"})
+	assert.NoError(t, err)
+	if item == nil || item.ResolvedAt == nil {
+		t.Fatal("expected the exception to be resolved")
+	}
+	assert.Equal(t, "Refunded by the bank", item.ResolutionNote)
+
+	_, err = svc.ResolveException(context.Background(), missing[0].ID.String(), &domain.ResolveExceptionRequest{Note: "again"})
+	assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+
+	_, err = svc.ResolveException(context.Background(), matched[0].ID.String(), &domain.ResolveExceptionRequest{Note: "n/a"})
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+
+	_, err = svc.ResolveException(context.Background(), "nope", &domain.ResolveExceptionRequest{Note: "n/a"})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+}
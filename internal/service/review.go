@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type ReviewService struct {
+	uow       domain.UnitOfWork
+	publisher domain.MessagePublisher
+}
+
+func NewReviewService(uow domain.UnitOfWork, publisher domain.MessagePublisher) domain.ReviewService {
+	return &ReviewService{uow: uow, publisher: publisher}
+}
+
+func (s *ReviewService) GetReviewByID(ctx context.Context, id string) (*domain.PaymentReview, error) {
+	reviewID, err := parseReviewID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := s.uow.Reviews().GetReviewByID(ctx, reviewID)
+	if err != nil {
+		return nil, reviewLookupError(err, reviewID)
+	}
+	if review.Risk, err = paymentRisk(ctx, s.uow, review.PaymentID); err != nil {
+		return nil, err
+	}
+	return review, nil
+}
+
+func (s *ReviewService) ListReviews(ctx context.Context, filter domain.ReviewFilter, page domain.Page) (*domain.ReviewList, error) {
+	reviews, err := s.uow.Reviews().ListReviews(ctx, filter, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list reviews",
+			"Error occurred while retrieving the review queue",
+			err,
+			nil,
+		)
+	}
+	return &domain.ReviewList{Data: reviews, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// ClaimReview assigns an open review to the reviewer. Claiming a review the
+// reviewer already holds is a no-op.
+func (s *ReviewService) ClaimReview(ctx context.Context, id string, cr *domain.ClaimReviewRequest) (*domain.PaymentReview, error) {
+	reviewID, err := parseReviewID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := cr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"claim review request validation failed",
+			err,
+			map[string]interface{}{"req": cr},
+		)
+	}
+
+	var review *domain.PaymentReview
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		review, err = tx.Reviews().GetReviewByIDForUpdate(ctx, reviewID)
+		if err != nil {
+			return reviewLookupError(err, reviewID)
+		}
+		switch {
+		case review.Status.Decided():
+			return reviewDecidedError(review)
+		case review.Status == domain.ReviewClaimed && review.ClaimedBy == cr.Reviewer:
+			return nil
+		case review.Status == domain.ReviewClaimed:
+			return domain.NewError(
+				domain.ErrReviewAlreadyClaimed,
+				"Review claimed by another reviewer",
+				"The review is already being worked by "+review.ClaimedBy,
+				nil,
+				map[string]interface{}{"ReviewID": reviewID, "claimed_by": review.ClaimedBy},
+			)
+		}
+		before := review
+		if review, err = tx.Reviews().ClaimReview(ctx, reviewID, cr.Reviewer); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "payment_review.claimed", domain.AuditReview, id, before, review)
+	})
+	if err != nil {
+		return nil, reviewWriteError(err, reviewID, "Failed to claim review", "Error occurred while assigning the review")
+	}
+
+	logger.FromContext(ctx).Info("payment review claimed",
+		slog.String("review_id", id),
+		slog.String("payment_id", review.PaymentID.String()),
+		slog.String("reviewer", cr.Reviewer),
+	)
+	return review, nil
+}
+
+// ApproveReview releases the held payment back to PENDING and queues it for
+// processing. A checkout payment the payer has not confirmed yet is left to
+// be queued by the confirmation.
+func (s *ReviewService) ApproveReview(ctx context.Context, id string, dr *domain.ReviewDecisionRequest) (*domain.PaymentReview, error) {
+	review, payment, err := s.decide(ctx, id, domain.ReviewApproved, dr)
+	if err != nil {
+		return nil, err
+	}
+	// Checked after the commit, so a confirmation racing the approval sees
+	// either the approved payment or the confirmed session
+	if err := checkoutConfirmed(ctx, s.uow, payment); err != nil {
+		logger.FromContext(ctx).Info("approved payment awaits checkout confirmation",
+			slog.String("payment_id", payment.ID.String()),
+			slog.Any("reason", err),
+		)
+		return review, nil
+	}
+	publishPaymentCreated(ctx, s.publisher, payment)
+	return review, nil
+}
+
+// DeclineReview fails the held payment. It is never processed.
+func (s *ReviewService) DeclineReview(ctx context.Context, id string, dr *domain.ReviewDecisionRequest) (*domain.PaymentReview, error) {
+	review, _, err := s.decide(ctx, id, domain.ReviewDeclined, dr)
+	return review, err
+}
+
+// decide records the claiming reviewer's decision on a review.
+func (s *ReviewService) decide(ctx context.Context, id string, decision domain.ReviewStatus, dr *domain.ReviewDecisionRequest) (*domain.PaymentReview, *domain.Payment, error) {
+	reviewID, err := parseReviewID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := dr.Validate(decision); err != nil {
+		return nil, nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"review decision request validation failed",
+			err,
+			map[string]interface{}{"req": dr},
+		)
+	}
+
+	var (
+		review  *domain.PaymentReview
+		payment *domain.Payment
+	)
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		review, err = tx.Reviews().GetReviewByIDForUpdate(ctx, reviewID)
+		if err != nil {
+			return reviewLookupError(err, reviewID)
+		}
+		if review.Status.Decided() {
+			return reviewDecidedError(review)
+		}
+		if review.Status != domain.ReviewClaimed || review.ClaimedBy != dr.Reviewer {
+			return domain.NewError(
+				domain.ErrReviewNotClaimed,
+				"Review not claimed by this reviewer",
+				"A review must be claimed by the reviewer deciding it",
+				nil,
+				map[string]interface{}{"ReviewID": reviewID, "claimed_by": review.ClaimedBy, "reviewer": dr.Reviewer},
+			)
+		}
+		review, payment, err = decideReview(ctx, tx, review, decision, dr.Reviewer, dr.Notes)
+		return err
+	})
+	if err != nil {
+		return nil, nil, reviewWriteError(err, reviewID, "Failed to decide review", "Error occurred while recording the review decision")
+	}
+
+	logger.FromContext(ctx).Info("payment review decided",
+		slog.String("review_id", id),
+		slog.String("payment_id", review.PaymentID.String()),
+		slog.String("decision", string(decision)),
+		slog.String("reviewer", dr.Reviewer),
+	)
+	return review, payment, nil
+}
+
+// decideReview settles a locked review along with its held payment: an
+// approved payment goes back to PENDING and a declined one is failed. The
+// payment is locked the same way ProcessPayment locks it.
+func decideReview(ctx context.Context, tx domain.UnitOfWork, review *domain.PaymentReview, decision domain.ReviewStatus, reviewer, notes string) (*domain.PaymentReview, *domain.Payment, error) {
+	payment, err := tx.Payments().GetPaymentByIDWithLock(ctx, review.PaymentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if payment.Status != domain.StatusInReview {
+		return nil, nil, domain.NewError(
+			domain.ErrPaymentNotInReview,
+			"Payment not held for review",
+			"The reviewed payment is "+string(payment.Status)+" rather than held for review",
+			nil,
+			map[string]interface{}{"ReviewID": review.ID, "PaymentID": payment.ID, "status": payment.Status},
+		)
+	}
+
+	status := domain.StatusPending
+	if decision == domain.ReviewDeclined {
+		status = domain.StatusFailed
+	}
+	if payment, err = updatePaymentStatus(ctx, tx, payment, status); err != nil {
+		return nil, nil, err
+	}
+	decided, err := closeReview(ctx, tx, review, decision, reviewer, notes)
+	if err != nil {
+		return nil, nil, err
+	}
+	return decided, payment, nil
+}
+
+// closeReview records the decision on review and audits it.
+func closeReview(ctx context.Context, tx domain.UnitOfWork, review *domain.PaymentReview, decision domain.ReviewStatus, reviewer, notes string) (*domain.PaymentReview, error) {
+	decided, err := tx.Reviews().DecideReview(ctx, review.ID, decision, reviewer, notes)
+	if err != nil {
+		return nil, err
+	}
+	if err := recordAudit(ctx, tx, "payment_review."+string(decision), domain.AuditReview, review.ID.String(), review, decided); err != nil {
+		return nil, err
+	}
+	return decided, nil
+}
+
+func parseReviewID(id string) (uuid.UUID, error) {
+	reviewID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewError(
+			domain.ErrInvalidReviewID,
+			"Invalid review ID format",
+			"The provided review ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"ReviewID": id},
+		)
+	}
+	return reviewID, nil
+}
+
+func reviewLookupError(err error, id uuid.UUID) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrReviewNotFound,
+			"Review not found",
+			"The specified review could not be found",
+			err,
+			map[string]interface{}{"ReviewID": id},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to fetch review",
+		"Error occurred while retrieving the review",
+		err,
+		map[string]interface{}{"ReviewID": id},
+	)
+}
+
+func reviewDecidedError(review *domain.PaymentReview) error {
+	return domain.NewError(
+		domain.ErrReviewDecided,
+		"Review already decided",
+		"The review has already been "+string(review.Status),
+		nil,
+		map[string]interface{}{"ReviewID": review.ID, "status": review.Status},
+	)
+}
+
+// reviewWriteError passes domain errors from a review transaction through
+// and wraps anything else as a storage failure.
+func reviewWriteError(err error, id uuid.UUID, msg, desc string) error {
+	var derr domain.Error
+	if errors.As(err, &derr) {
+		return derr
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		msg,
+		desc,
+		err,
+		map[string]interface{}{"ReviewID": id},
+	)
+}
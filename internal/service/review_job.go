@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+)
+
+type ReviewConfig struct {
+	// PollInterval is how often the job looks for overdue reviews.
+	PollInterval time.Duration
+	// EscalateAfter is how long a review may stay unclaimed before it is
+	// escalated. Zero disables escalation.
+	EscalateAfter time.Duration
+	// DeclineAfter is how long a review may stay unclaimed before its
+	// payment is declined. Zero disables auto-declining.
+	DeclineAfter time.Duration
+}
+
+// DefaultReviewConfig is used for settings left unset.
+var DefaultReviewConfig = ReviewConfig{
+	PollInterval:  time.Minute,
+	EscalateAfter: 4 * time.Hour,
+	DeclineAfter:  24 * time.Hour,
+}
+
+// ReviewConfigFromEnv reads REVIEW_POLL_INTERVAL, REVIEW_ESCALATE_AFTER and
+// REVIEW_DECLINE_AFTER. The last two accept 0 to turn the timer off. Unset
+// values fall back to DefaultReviewConfig.
+func ReviewConfigFromEnv() (ReviewConfig, error) {
+	cfg := DefaultReviewConfig
+	if v := os.Getenv("REVIEW_POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid REVIEW_POLL_INTERVAL value: %s", v)
+		}
+		cfg.PollInterval = d
+	}
+	if v := os.Getenv("REVIEW_ESCALATE_AFTER"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return cfg, fmt.Errorf("invalid REVIEW_ESCALATE_AFTER value: %s", v)
+		}
+		cfg.EscalateAfter = d
+	}
+	if v := os.Getenv("REVIEW_DECLINE_AFTER"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return cfg, fmt.Errorf("invalid REVIEW_DECLINE_AFTER value: %s", v)
+		}
+		cfg.DeclineAfter = d
+	}
+	return cfg, nil
+}
+
+// ReviewSLAJob runs in the worker and enforces the review queue's SLA on
+// reviews nobody has claimed: they are escalated after EscalateAfter and
+// declined after DeclineAfter. Overdue reviews are claimed with SKIP
+// LOCKED, so several workers can run it side by side.
+type ReviewSLAJob struct {
+	uow    domain.UnitOfWork
+	events domain.EventPublisher
+	cfg    ReviewConfig
+}
+
+func NewReviewSLAJob(uow domain.UnitOfWork, events domain.EventPublisher, cfg ReviewConfig) *ReviewSLAJob {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultReviewConfig.PollInterval
+	}
+	return &ReviewSLAJob{uow: uow, events: events, cfg: cfg}
+}
+
+// Run polls until ctx is canceled.
+func (j *ReviewSLAJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := j.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Error("review SLA job run failed", slog.Any("error", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce declines the reviews past DeclineAfter and then escalates those
+// past EscalateAfter, so a review overdue for both is only declined.
+func (j *ReviewSLAJob) RunOnce(ctx context.Context) error {
+	now := time.Now()
+	if j.cfg.DeclineAfter > 0 {
+		for {
+			declined, err := j.declineNext(ctx, now.Add(-j.cfg.DeclineAfter))
+			if err != nil {
+				return err
+			}
+			if !declined {
+				break
+			}
+		}
+	}
+
+	if j.cfg.EscalateAfter <= 0 {
+		return nil
+	}
+	var escalated []domain.PaymentReview
+	err := j.uow.WithinTx(ctx, func(tx domain.UnitOfWork) (err error) {
+		escalated, err = tx.Reviews().EscalateOverdueReviews(ctx, now.Add(-j.cfg.EscalateAfter))
+		if err != nil {
+			return err
+		}
+		for i := range escalated {
+			before := escalated[i]
+			before.EscalatedAt = nil
+			if err := recordAudit(ctx, tx, domain.EventReviewEscalated, domain.AuditReview, before.ID.String(), before, escalated[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to escalate reviews: %w", err)
+	}
+	for i := range escalated {
+		review := &escalated[i]
+		logger.FromContext(ctx).Warn("payment review escalated",
+			slog.String("review_id", review.ID.String()),
+			slog.String("payment_id", review.PaymentID.String()),
+			slog.Duration("waiting", now.Sub(review.CreatedAt)),
+		)
+		// A failure is logged rather than returned because the escalation
+		// has been committed
+		if err := j.events.PublishEvent(ctx, domain.NewEvent(domain.EventReviewEscalated, review)); err != nil {
+			logger.FromContext(ctx).Error("failed to publish review event",
+				slog.String("review_id", review.ID.String()),
+				slog.String("event_type", domain.EventReviewEscalated),
+				slog.Any("error", err),
+			)
+		}
+	}
+	return nil
+}
+
+// declineNext claims one unclaimed review created before before and
+// declines its payment.
+func (j *ReviewSLAJob) declineNext(ctx context.Context, before time.Time) (bool, error) {
+	var review *domain.PaymentReview
+	err := j.uow.WithinTx(ctx, func(tx domain.UnitOfWork) (err error) {
+		review, err = tx.Reviews().ClaimOverdueReview(ctx, before)
+		if err != nil {
+			return err
+		}
+		notes := fmt.Sprintf("Not reviewed within %s", j.cfg.DeclineAfter)
+		decided, _, err := decideReview(ctx, tx, review, domain.ReviewDeclined, domain.ReviewerSLA, notes)
+		var derr domain.Error
+		if errors.As(err, &derr) && derr.Type == domain.ErrPaymentNotInReview {
+			// The payment was settled some other way; close the review so it
+			// leaves the queue
+			decided, err = closeReview(ctx, tx, review, domain.ReviewDeclined, domain.ReviewerSLA, derr.Description)
+		}
+		if err != nil {
+			return err
+		}
+		review = decided
+		return nil
+	})
+	if errors.Is(err, domain.ErrNotFound) && review == nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to decline overdue review: %w", err)
+	}
+
+	logger.FromContext(ctx).Warn("payment review declined by SLA",
+		slog.String("review_id", review.ID.String()),
+		slog.String("payment_id", review.PaymentID.String()),
+	)
+	return true, nil
+}
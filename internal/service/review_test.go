@@ -0,0 +1,328 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+type fakeReviewRepo struct {
+	domain.ReviewRepo
+	reviews []*domain.PaymentReview
+}
+
+func (r *fakeReviewRepo) CreateReview(ctx context.Context, review *domain.PaymentReview) error {
+	review.ID = uuid.New()
+	review.Status = domain.ReviewOpen
+	review.CreatedAt = time.Now()
+	review.UpdatedAt = review.CreatedAt
+	stored := *review
+	r.reviews = append(r.reviews, &stored)
+	return nil
+}
+
+func (r *fakeReviewRepo) GetReviewByID(ctx context.Context, id uuid.UUID) (*domain.PaymentReview, error) {
+	for _, rv := range r.reviews {
+		if rv.ID == id {
+			found := *rv
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeReviewRepo) GetReviewByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.PaymentReview, error) {
+	return r.GetReviewByID(ctx, id)
+}
+
+func (r *fakeReviewRepo) ClaimReview(ctx context.Context, id uuid.UUID, reviewer string) (*domain.PaymentReview, error) {
+	return r.update(id, func(rv *domain.PaymentReview) {
+		now := time.Now()
+		rv.Status = domain.ReviewClaimed
+		rv.ClaimedBy = reviewer
+		rv.ClaimedAt = &now
+	})
+}
+
+func (r *fakeReviewRepo) DecideReview(ctx context.Context, id uuid.UUID, decision domain.ReviewStatus, reviewer, notes string) (*domain.PaymentReview, error) {
+	return r.update(id, func(rv *domain.PaymentReview) {
+		now := time.Now()
+		rv.Status = decision
+		rv.DecidedBy = reviewer
+		rv.DecidedAt = &now
+		rv.Notes = notes
+	})
+}
+
+func (r *fakeReviewRepo) EscalateOverdueReviews(ctx context.Context, before time.Time) ([]domain.PaymentReview, error) {
+	var escalated []domain.PaymentReview
+	for _, rv := range r.reviews {
+		if rv.Status == domain.ReviewOpen && rv.EscalatedAt == nil && !rv.CreatedAt.After(before) {
+			now := time.Now()
+			rv.EscalatedAt = &now
+			escalated = append(escalated, *rv)
+		}
+	}
+	return escalated, nil
+}
+
+func (r *fakeReviewRepo) ClaimOverdueReview(ctx context.Context, before time.Time) (*domain.PaymentReview, error) {
+	for _, rv := range r.reviews {
+		if rv.Status == domain.ReviewOpen && !rv.CreatedAt.After(before) {
+			found := *rv
+			return &found, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeReviewRepo) update(id uuid.UUID, fn func(rv *domain.PaymentReview)) (*domain.PaymentReview, error) {
+	for _, rv := range r.reviews {
+		if rv.ID == id {
+			fn(rv)
+			updated := *rv
+			return &updated, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+type reviewFixture struct {
+	payments domain.PaymentService
+	svc      domain.ReviewService
+	uow      *fakeUnitOfWork
+	reviews  *fakeReviewRepo
+	pub      *fakePublisher
+}
+
+// setupReviews holds every payment over 1000 for review.
+func setupReviews() reviewFixture {
+	risk := &fakeRiskRepo{}
+	reviews := &fakeReviewRepo{}
+	uow := &fakeUnitOfWork{repo: &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)}, customers: newFakeCustomerRepo(), risk: risk, reviews: reviews}
+	pub := &fakePublisher{}
+	if _, err := service.NewRiskService(uow).CreateRule(context.Background(), &domain.RiskRuleRequest{Name: "large", Expression: `amount > 1000`, Action: domain.RiskReview, Score: 50}); err != nil {
+		panic(err)
+	}
+	// A nil router makes any attempt to charge a held payment panic
+	return reviewFixture{
+		payments: service.NewPaymentService(uow, pub, nil),
+		svc:      service.NewReviewService(uow, pub),
+		uow:      uow,
+		reviews:  reviews,
+		pub:      pub,
+	}
+}
+
+func (f reviewFixture) hold(t *testing.T, reference string) (*domain.Payment, *domain.PaymentReview) {
+	t.Helper()
+	p, err := f.payments.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 5000, Currency: "USD", Reference: reference})
+	if err != nil {
+		t.Fatal(err)
+	}
+	review := f.reviews.reviews[len(f.reviews.reviews)-1]
+	return p, review
+}
+
+func TestHeldPaymentReview(t *testing.T) {
+	f := setupReviews()
+	ctx := context.Background()
+
+	p, review := f.hold(t, "order-1")
+	assert.Equal(t, domain.StatusInReview, p.Status)
+	assert.Equal(t, p.ID, review.PaymentID)
+	assert.Equal(t, domain.ReviewOpen, review.Status)
+	assert.Empty(t, f.pub.published)
+
+	// Operators see how the payment was screened on the review
+	got, err := f.svc.GetReviewByID(ctx, review.ID.String())
+	assert.NoError(t, err)
+	if assert.NotNil(t, got.Risk) && assert.NotNil(t, got.Risk.Risk) {
+		assert.Equal(t, p.ID, got.Risk.PaymentID)
+		assert.Equal(t, domain.RiskReview, got.Risk.Risk.Outcome)
+		assert.Equal(t, []string{"large"}, got.Risk.Risk.Rules)
+	}
+
+	// The worker acknowledges a held payment without charging it
+	assert.NoError(t, f.payments.ProcessPayment(ctx, p.ID.String()))
+	assert.Equal(t, domain.StatusInReview, f.uow.repo.byID[p.ID].Status)
+
+	id := review.ID.String()
+	_, err = f.svc.ApproveReview(ctx, id, &domain.ReviewDecisionRequest{Reviewer: "alice"})
+	assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+
+	claimed, err := f.svc.ClaimReview(ctx, id, &domain.ClaimReviewRequest{Reviewer: "alice"})
+	assert.NoError(t, err)
+	if claimed == nil {
+		t.Fatal("expected a review")
+	}
+	assert.Equal(t, domain.ReviewClaimed, claimed.Status)
+	assert.Equal(t, "alice", claimed.ClaimedBy)
+
+	_, err = f.svc.ClaimReview(ctx, id, &domain.ClaimReviewRequest{Reviewer: "alice"})
+	assert.NoError(t, err)
+	_, err = f.svc.ClaimReview(ctx, id, &domain.ClaimReviewRequest{Reviewer: "bob"})
+	var derr domain.Error
+	if assert.ErrorAs(t, err, &derr) {
+		assert.Equal(t, domain.ErrReviewAlreadyClaimed, derr.Type)
+	}
+	_, err = f.svc.ApproveReview(ctx, id, &domain.ReviewDecisionRequest{Reviewer: "bob"})
+	if assert.ErrorAs(t, err, &derr) {
+		assert.Equal(t, domain.ErrReviewNotClaimed, derr.Type)
+	}
+
+	approved, err := f.svc.ApproveReview(ctx, id, &domain.ReviewDecisionRequest{Reviewer: "alice", Notes: "known customer"})
+	assert.NoError(t, err)
+	if approved == nil {
+		t.Fatal("expected a review")
+	}
+	assert.Equal(t, domain.ReviewApproved, approved.Status)
+	assert.Equal(t, "known customer", approved.Notes)
+	assert.Equal(t, domain.StatusPending, f.uow.repo.byID[p.ID].Status)
+	assert.Equal(t, []string{p.ID.String()}, f.pub.published)
+
+	_, err = f.svc.DeclineReview(ctx, id, &domain.ReviewDecisionRequest{Reviewer: "alice", Notes: "changed my mind"})
+	if assert.ErrorAs(t, err, &derr) {
+		assert.Equal(t, domain.ErrReviewDecided, derr.Type)
+	}
+}
+
+func TestDeclineReview(t *testing.T) {
+	f := setupReviews()
+	ctx := context.Background()
+	p, review := f.hold(t, "order-2")
+	id := review.ID.String()
+
+	_, err := f.svc.ClaimReview(ctx, id, &domain.ClaimReviewRequest{Reviewer: "alice"})
+	assert.NoError(t, err)
+
+	_, err = f.svc.DeclineReview(ctx, id, &domain.ReviewDecisionRequest{Reviewer: "alice"})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+
+	declined, err := f.svc.DeclineReview(ctx, id, &domain.ReviewDecisionRequest{Reviewer: "alice", Notes: "card testing pattern"})
+	assert.NoError(t, err)
+	if declined == nil {
+		t.Fatal("expected a review")
+	}
+	assert.Equal(t, domain.ReviewDeclined, declined.Status)
+	assert.Equal(t, domain.StatusFailed, f.uow.repo.byID[p.ID].Status)
+	assert.Empty(t, f.pub.published)
+
+	_, err = f.svc.GetReviewByID(ctx, "not-a-uuid")
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	_, err = f.svc.GetReviewByID(ctx, uuid.NewString())
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+}
+
+func TestReviewSLAJob(t *testing.T) {
+	f := setupReviews()
+	ctx := context.Background()
+
+	stale, staleReview := f.hold(t, "order-stale")
+	staleReview.CreatedAt = time.Now().Add(-25 * time.Hour)
+	_, slowReview := f.hold(t, "order-slow")
+	slowReview.CreatedAt = time.Now().Add(-5 * time.Hour)
+	_, claimedReview := f.hold(t, "order-claimed")
+	claimedReview.CreatedAt = time.Now().Add(-30 * time.Hour)
+	_, err := f.svc.ClaimReview(ctx, claimedReview.ID.String(), &domain.ClaimReviewRequest{Reviewer: "alice"})
+	assert.NoError(t, err)
+	_, freshReview := f.hold(t, "order-fresh")
+
+	events := &fakeEventPublisher{}
+	job := service.NewReviewSLAJob(f.uow, events, service.ReviewConfig{EscalateAfter: 4 * time.Hour, DeclineAfter: 24 * time.Hour})
+	assert.NoError(t, job.RunOnce(ctx))
+
+	assert.Equal(t, domain.ReviewDeclined, staleReview.Status)
+	assert.Equal(t, domain.ReviewerSLA, staleReview.DecidedBy)
+	assert.Equal(t, domain.StatusFailed, f.uow.repo.byID[stale.ID].Status)
+	assert.Nil(t, staleReview.EscalatedAt)
+
+	assert.Equal(t, domain.ReviewOpen, slowReview.Status)
+	assert.NotNil(t, slowReview.EscalatedAt)
+	assert.Equal(t, []string{domain.EventReviewEscalated}, events.types)
+
+	// A claimed review is being worked, so the timers leave it alone
+	assert.Equal(t, domain.ReviewClaimed, claimedReview.Status)
+	assert.Nil(t, claimedReview.EscalatedAt)
+	assert.Equal(t, domain.ReviewOpen, freshReview.Status)
+	assert.Nil(t, freshReview.EscalatedAt)
+
+	// Escalation happens once
+	assert.NoError(t, job.RunOnce(ctx))
+	assert.Len(t, events.types, 1)
+	assert.Empty(t, f.pub.published)
+}
+
+func TestReviewConfigFromEnv(t *testing.T) {
+	t.Setenv("REVIEW_ESCALATE_AFTER", "0")
+	t.Setenv("REVIEW_DECLINE_AFTER", "48h")
+	cfg, err := service.ReviewConfigFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), cfg.EscalateAfter)
+	assert.Equal(t, 48*time.Hour, cfg.DeclineAfter)
+	assert.Equal(t, service.DefaultReviewConfig.PollInterval, cfg.PollInterval)
+
+	t.Setenv("REVIEW_DECLINE_AFTER", "-1h")
+	_, err = service.ReviewConfigFromEnv()
+	assert.Error(t, err)
+}
+
+func TestHeldCheckoutPayment(t *testing.T) {
+	ctx := context.Background()
+	decide := func(f reviewFixture, paymentID uuid.UUID) {
+		t.Helper()
+		for _, review := range f.reviews.reviews {
+			if review.PaymentID == paymentID {
+				_, err := f.svc.ClaimReview(ctx, review.ID.String(), &domain.ClaimReviewRequest{Reviewer: "alice"})
+				assert.NoError(t, err)
+				_, err = f.svc.ApproveReview(ctx, review.ID.String(), &domain.ReviewDecisionRequest{Reviewer: "alice"})
+				assert.NoError(t, err)
+			}
+		}
+	}
+	cr := &domain.CheckoutSessionRequest{
+		PaymentRequest: domain.PaymentRequest{Amount: 5000, Currency: "USD", Reference: "checkout-1"},
+		SuccessURL:     "https://shop.example.com/success",
+		CancelURL:      "https://shop.example.com/cancel",
+	}
+
+	t.Run("approved before the payer confirms", func(t *testing.T) {
+		f := setupReviews()
+		checkout := service.NewCheckoutService(f.uow, f.pub, "https://pay.example.com")
+		s, err := checkout.CreateSession(ctx, cr)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CheckoutOpen, s.Status)
+		assert.Equal(t, domain.StatusInReview, s.Payment.Status)
+
+		decide(f, s.Payment.ID)
+		assert.Equal(t, domain.StatusPending, f.uow.repo.byID[s.Payment.ID].Status)
+		assert.Empty(t, f.pub.published)
+
+		_, err = checkout.ConfirmSession(ctx, s.Token, mobileMoney)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{s.Payment.ID.String()}, f.pub.published)
+	})
+
+	t.Run("confirmed before the review is approved", func(t *testing.T) {
+		f := setupReviews()
+		checkout := service.NewCheckoutService(f.uow, f.pub, "https://pay.example.com")
+		s, err := checkout.CreateSession(ctx, cr)
+		assert.NoError(t, err)
+
+		_, err = checkout.ConfirmSession(ctx, s.Token, mobileMoney)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.StatusInReview, f.uow.repo.byID[s.Payment.ID].Status)
+		assert.Empty(t, f.pub.published)
+
+		decide(f, s.Payment.ID)
+		assert.Equal(t, []string{s.Payment.ID.String()}, f.pub.published)
+	})
+}
@@ -0,0 +1,523 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+	"pgm/internal/risk"
+
+	"github.com/google/uuid"
+)
+
+// ruleDecls are the variables and functions a fraud rule can use.
+//
+//	amount, currency, reference      the payment
+//	customer_id, email               the customer, "" when there is none
+//	ip                               the address the payment came from
+//	payment_method                   the method type, "" when not chosen yet
+//	velocity_ip(window)              payments from ip within window, e.g. "1h"
+//	velocity_customer(window)        payments by the customer within window
+//	velocity_reference(prefix, window) payments whose reference starts with prefix
+//	blocked(kind, value)             whether value is on the ip, email or customer blocklist
+var ruleDecls = risk.Declarations{
+	Vars: []string{"amount", "currency", "reference", "customer_id", "email", "ip", "payment_method"},
+	Funcs: map[string]int{
+		"velocity_ip":        1,
+		"velocity_customer":  1,
+		"velocity_reference": 2,
+		"blocked":            2,
+	},
+}
+
+type RiskService struct {
+	uow domain.UnitOfWork
+}
+
+func NewRiskService(uow domain.UnitOfWork) domain.RiskService {
+	return &RiskService{uow: uow}
+}
+
+func (s *RiskService) CreateRule(ctx context.Context, rr *domain.RiskRuleRequest) (*domain.RiskRule, error) {
+	if err := validateRiskRule(rr); err != nil {
+		return nil, err
+	}
+
+	rule := &domain.RiskRule{Enabled: true}
+	applyRiskRuleRequest(rule, rr)
+	err := s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		if err := tx.Risk().CreateRule(ctx, rule); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "risk_rule.created", domain.AuditRiskRule, rule.ID.String(), nil, rule)
+	})
+	if err != nil {
+		return nil, riskRuleWriteError(err, rr)
+	}
+
+	logger.FromContext(ctx).Info("risk rule created",
+		slog.String("risk_rule_id", rule.ID.String()),
+		slog.String("name", rule.Name),
+	)
+	return rule, nil
+}
+
+func (s *RiskService) GetRuleByID(ctx context.Context, id string) (*domain.RiskRule, error) {
+	ruleID, err := parseRiskRuleID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.uow.Risk().GetRuleByID(ctx, ruleID)
+	if err != nil {
+		return nil, riskRuleLookupError(err, ruleID)
+	}
+	return rule, nil
+}
+
+func (s *RiskService) ListRules(ctx context.Context, page domain.Page) (*domain.RiskRuleList, error) {
+	rules, err := s.uow.Risk().ListRules(ctx, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list risk rules",
+			"Error occurred while retrieving risk rules",
+			err,
+			nil,
+		)
+	}
+	return &domain.RiskRuleList{Data: rules, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// UpdateRule replaces a rule. Enabled is left as it was when not given.
+func (s *RiskService) UpdateRule(ctx context.Context, id string, rr *domain.RiskRuleRequest) (*domain.RiskRule, error) {
+	ruleID, err := parseRiskRuleID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateRiskRule(rr); err != nil {
+		return nil, err
+	}
+
+	var rule *domain.RiskRule
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		rule, err = tx.Risk().GetRuleByID(ctx, ruleID)
+		if err != nil {
+			return riskRuleLookupError(err, ruleID)
+		}
+		before := *rule
+		applyRiskRuleRequest(rule, rr)
+		if err := tx.Risk().UpdateRule(ctx, rule); err != nil {
+			return riskRuleWriteError(err, rr)
+		}
+		return recordAudit(ctx, tx, "risk_rule.updated", domain.AuditRiskRule, id, before, rule)
+	})
+	if err != nil {
+		var derr domain.Error
+		if errors.As(err, &derr) {
+			return nil, derr
+		}
+		return nil, riskRuleWriteError(err, rr)
+	}
+
+	logger.FromContext(ctx).Info("risk rule updated", slog.String("risk_rule_id", id))
+	return rule, nil
+}
+
+func (s *RiskService) DeleteRule(ctx context.Context, id string) error {
+	ruleID, err := parseRiskRuleID(id)
+	if err != nil {
+		return err
+	}
+
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		rule, err := tx.Risk().GetRuleByID(ctx, ruleID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Risk().DeleteRule(ctx, ruleID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "risk_rule.deleted", domain.AuditRiskRule, id, rule, nil)
+	})
+	if err != nil {
+		return riskRuleLookupError(err, ruleID)
+	}
+	logger.FromContext(ctx).Info("risk rule deleted", slog.String("risk_rule_id", id))
+	return nil
+}
+
+func (s *RiskService) CreateBlocklistEntry(ctx context.Context, br *domain.BlocklistEntryRequest) (*domain.BlocklistEntry, error) {
+	if err := br.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"blocklist entry request validation failed",
+			err,
+			map[string]interface{}{"req": br},
+		)
+	}
+
+	entry := &domain.BlocklistEntry{Kind: br.Kind, Value: blocklistValue(br.Kind, br.Value), Reason: br.Reason}
+	err := s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		if err := tx.Risk().CreateBlocklistEntry(ctx, entry); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "risk_blocklist.created", domain.AuditRiskBlocklist, entry.ID.String(), nil, entry)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, domain.NewError(
+				domain.ErrDuplicateBlocklistEntry,
+				"Duplicate blocklist entry",
+				"This value is already on the blocklist",
+				err,
+				map[string]interface{}{"kind": br.Kind, "value": entry.Value},
+			)
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to create blocklist entry",
+			"Error occurred while saving the blocklist entry",
+			err,
+			map[string]interface{}{"req": br},
+		)
+	}
+
+	logger.FromContext(ctx).Info("blocklist entry created",
+		slog.String("blocklist_entry_id", entry.ID.String()),
+		slog.String("kind", string(entry.Kind)),
+	)
+	return entry, nil
+}
+
+func (s *RiskService) ListBlocklistEntries(ctx context.Context, kind *domain.BlocklistKind, page domain.Page) (*domain.BlocklistEntryList, error) {
+	entries, err := s.uow.Risk().ListBlocklistEntries(ctx, kind, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list blocklist entries",
+			"Error occurred while retrieving blocklist entries",
+			err,
+			nil,
+		)
+	}
+	return &domain.BlocklistEntryList{Data: entries, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (s *RiskService) DeleteBlocklistEntry(ctx context.Context, id string) error {
+	entryID, err := uuid.Parse(id)
+	if err != nil {
+		return domain.NewError(
+			domain.ErrInvalidBlocklistID,
+			"Invalid blocklist entry ID format",
+			"The provided blocklist entry ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"BlocklistEntryID": id},
+		)
+	}
+
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		if err := tx.Risk().DeleteBlocklistEntry(ctx, entryID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "risk_blocklist.deleted", domain.AuditRiskBlocklist, id, nil, nil)
+	})
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return domain.NewError(
+				domain.ErrBlocklistEntryNotFound,
+				"Blocklist entry not found",
+				"The specified blocklist entry could not be found",
+				err,
+				map[string]interface{}{"BlocklistEntryID": id},
+			)
+		}
+		return domain.NewError(
+			storageErrorCode(err),
+			"Failed to delete blocklist entry",
+			"Error occurred while deleting the blocklist entry",
+			err,
+			map[string]interface{}{"BlocklistEntryID": id},
+		)
+	}
+	logger.FromContext(ctx).Info("blocklist entry deleted", slog.String("blocklist_entry_id", id))
+	return nil
+}
+
+// GetPaymentRisk returns how a payment was screened and the address it was
+// created from.
+func (s *RiskService) GetPaymentRisk(ctx context.Context, id string) (*domain.PaymentRisk, error) {
+	paymentID, err := parsePaymentID(id)
+	if err != nil {
+		return nil, err
+	}
+	return paymentRisk(ctx, s.uow, paymentID)
+}
+
+func paymentRisk(ctx context.Context, uow domain.UnitOfWork, paymentID uuid.UUID) (*domain.PaymentRisk, error) {
+	payment, err := uow.Payments().GetPaymentByID(ctx, paymentID)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, domain.NewError(
+				domain.ErrPaymentNotFound,
+				"Payment not found",
+				"The specified payment could not be found",
+				err,
+				map[string]interface{}{"PaymentID": paymentID},
+			)
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to fetch payment risk",
+			"Error occurred while retrieving the payment's risk assessment",
+			err,
+			map[string]interface{}{"PaymentID": paymentID},
+		)
+	}
+	return domain.NewPaymentRisk(payment), nil
+}
+
+// validateRiskRule checks the request and that its expression compiles, so a
+// broken rule is rejected when saved rather than skipped at every payment.
+func validateRiskRule(rr *domain.RiskRuleRequest) error {
+	err := rr.Validate()
+	if err == nil {
+		if _, cerr := risk.Compile(rr.Expression, ruleDecls); cerr != nil {
+			err = fmt.Errorf("expression: %w", cerr)
+		}
+	}
+	if err != nil {
+		return domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"risk rule request validation failed",
+			err,
+			map[string]interface{}{"req": rr},
+		)
+	}
+	return nil
+}
+
+func applyRiskRuleRequest(rule *domain.RiskRule, rr *domain.RiskRuleRequest) {
+	rule.Name = rr.Name
+	rule.Expression = rr.Expression
+	rule.Action = rr.Action
+	rule.Score = rr.Score
+	if rr.Enabled != nil {
+		rule.Enabled = *rr.Enabled
+	}
+}
+
+// blocklistValue normalises value so lookups match regardless of case.
+func blocklistValue(kind domain.BlocklistKind, value string) string {
+	value = strings.TrimSpace(value)
+	if kind == domain.BlockEmail || kind == domain.BlockCustomer {
+		return strings.ToLower(value)
+	}
+	return value
+}
+
+func parseRiskRuleID(id string) (uuid.UUID, error) {
+	ruleID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewError(
+			domain.ErrInvalidRiskRuleID,
+			"Invalid risk rule ID format",
+			"The provided risk rule ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"RiskRuleID": id},
+		)
+	}
+	return ruleID, nil
+}
+
+func riskRuleLookupError(err error, id uuid.UUID) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrRiskRuleNotFound,
+			"Risk rule not found",
+			"The specified risk rule could not be found",
+			err,
+			map[string]interface{}{"RiskRuleID": id},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to fetch risk rule",
+		"Error occurred while retrieving the risk rule",
+		err,
+		map[string]interface{}{"RiskRuleID": id},
+	)
+}
+
+func riskRuleWriteError(err error, rr *domain.RiskRuleRequest) error {
+	if errors.Is(err, domain.ErrConflict) {
+		return domain.NewError(
+			domain.ErrDuplicateRiskRule,
+			"Duplicate risk rule",
+			"A risk rule with this name already exists",
+			err,
+			map[string]interface{}{"name": rr.Name},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to save risk rule",
+		"Error occurred while saving the risk rule",
+		err,
+		map[string]interface{}{"req": rr},
+	)
+}
+
+// screenPayment evaluates the enabled rules against a payment about to be
+// created. The outcome is the strongest action among the matched rules and
+// the score the sum of their scores. Velocity counts cover the payments
+// created before this one. A rule that fails to compile or evaluate, say on
+// a malformed window, is logged and skipped so a bad rule cannot halt
+// payments.
+func screenPayment(ctx context.Context, tx domain.UnitOfWork, payment *domain.Payment, email string) (*domain.RiskAssessment, error) {
+	rules, err := tx.Risk().ListEnabledRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	assessment := &domain.RiskAssessment{Outcome: domain.RiskAllow, Rules: []string{}}
+	if len(rules) == 0 {
+		return assessment, nil
+	}
+	env := riskEnv(ctx, tx.Risk(), payment, strings.ToLower(email))
+	for _, rule := range rules {
+		program, err := risk.Compile(rule.Expression, ruleDecls)
+		var matched bool
+		if err == nil {
+			matched, err = program.Eval(env)
+		}
+		if err != nil {
+			var derr domain.Error
+			if errors.As(err, &derr) {
+				// A storage failure inside a function, not a bad rule
+				return nil, derr
+			}
+			logger.FromContext(ctx).Warn("risk rule skipped",
+				slog.String("risk_rule_id", rule.ID.String()),
+				slog.String("name", rule.Name),
+				slog.Any("error", err),
+			)
+			continue
+		}
+		if !matched {
+			continue
+		}
+		assessment.Rules = append(assessment.Rules, rule.Name)
+		assessment.Score = min(assessment.Score+rule.Score, 100)
+		if rule.Action == domain.RiskBlock || assessment.Outcome == domain.RiskAllow {
+			assessment.Outcome = rule.Action
+		}
+	}
+	return assessment, nil
+}
+
+// riskEnv exposes payment and the lookups behind ruleDecls to the rules.
+func riskEnv(ctx context.Context, repo domain.RiskRepo, payment *domain.Payment, email string) risk.Env {
+	customerID := ""
+	if payment.CustomerID != nil {
+		customerID = payment.CustomerID.String()
+	}
+	method := ""
+	if payment.PaymentMethod != nil {
+		method = string(payment.PaymentMethod.Type)
+	}
+
+	// count runs a velocity lookup for the window in args[i]
+	count := func(args []risk.Value, i int, lookup func(since time.Time) (int, error)) (risk.Value, error) {
+		w, ok := args[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("window must be a duration string such as \"1h\"")
+		}
+		window, err := time.ParseDuration(w)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid window %q", w)
+		}
+		n, err := lookup(time.Now().Add(-window))
+		if err != nil {
+			return nil, domain.NewError(
+				storageErrorCode(err),
+				"Failed to screen payment",
+				"Error occurred while counting recent payments",
+				err,
+				nil,
+			)
+		}
+		return float64(n), nil
+	}
+
+	return risk.Env{
+		Vars: map[string]risk.Value{
+			"amount":         payment.Amount,
+			"currency":       payment.Currency,
+			"reference":      payment.Reference,
+			"customer_id":    customerID,
+			"email":          email,
+			"ip":             payment.ClientIP,
+			"payment_method": method,
+		},
+		Funcs: map[string]risk.Func{
+			"velocity_ip": func(args []risk.Value) (risk.Value, error) {
+				return count(args, 0, func(since time.Time) (int, error) {
+					if payment.ClientIP == "" {
+						return 0, nil
+					}
+					return repo.CountPaymentsByClientIP(ctx, payment.ClientIP, since)
+				})
+			},
+			"velocity_customer": func(args []risk.Value) (risk.Value, error) {
+				return count(args, 0, func(since time.Time) (int, error) {
+					if payment.CustomerID == nil {
+						return 0, nil
+					}
+					return repo.CountPaymentsByCustomer(ctx, *payment.CustomerID, since)
+				})
+			},
+			"velocity_reference": func(args []risk.Value) (risk.Value, error) {
+				prefix, ok := args[0].(string)
+				if !ok || prefix == "" {
+					return nil, fmt.Errorf("prefix must be a non-empty string")
+				}
+				return count(args, 1, func(since time.Time) (int, error) {
+					return repo.CountPaymentsByReferencePrefix(ctx, prefix, since)
+				})
+			},
+			"blocked": func(args []risk.Value) (risk.Value, error) {
+				kind, ok1 := args[0].(string)
+				value, ok2 := args[1].(string)
+				if !ok1 || !ok2 {
+					return nil, fmt.Errorf("expected a kind and a value string")
+				}
+				k := domain.BlocklistKind(kind)
+				if k != domain.BlockIP && k != domain.BlockEmail && k != domain.BlockCustomer {
+					return nil, fmt.Errorf("unknown blocklist kind %q", kind)
+				}
+				if value == "" {
+					return false, nil
+				}
+				blocked, err := repo.IsBlocklisted(ctx, k, blocklistValue(k, value))
+				if err != nil {
+					return nil, domain.NewError(
+						storageErrorCode(err),
+						"Failed to screen payment",
+						"Error occurred while checking the blocklist",
+						err,
+						nil,
+					)
+				}
+				return blocked, nil
+			},
+		},
+	}
+}
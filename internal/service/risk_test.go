@@ -0,0 +1,297 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+type fakeRiskRepo struct {
+	domain.RiskRepo
+	rules     []domain.RiskRule
+	blocklist []domain.BlocklistEntry
+	// recent is returned by every velocity count; counted records the
+	// lookups made.
+	recent  int
+	counted []string
+}
+
+func (r *fakeRiskRepo) CreateRule(ctx context.Context, rule *domain.RiskRule) error {
+	for _, existing := range r.rules {
+		if existing.Name == rule.Name {
+			return fmt.Errorf("%w: duplicate key", domain.ErrConflict)
+		}
+	}
+	rule.ID = uuid.New()
+	r.rules = append(r.rules, *rule)
+	return nil
+}
+
+func (r *fakeRiskRepo) GetRuleByID(ctx context.Context, id uuid.UUID) (*domain.RiskRule, error) {
+	for _, rule := range r.rules {
+		if rule.ID == id {
+			return &rule, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeRiskRepo) UpdateRule(ctx context.Context, rule *domain.RiskRule) error {
+	for i := range r.rules {
+		if r.rules[i].ID == rule.ID {
+			r.rules[i] = *rule
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeRiskRepo) ListEnabledRules(ctx context.Context) ([]domain.RiskRule, error) {
+	var rules []domain.RiskRule
+	for _, rule := range r.rules {
+		if rule.Enabled {
+			rules = append(rules, rule)
+		}
+	}
+	return rules, nil
+}
+
+func (r *fakeRiskRepo) CreateBlocklistEntry(ctx context.Context, entry *domain.BlocklistEntry) error {
+	for _, e := range r.blocklist {
+		if e.Kind == entry.Kind && e.Value == entry.Value {
+			return fmt.Errorf("%w: duplicate key", domain.ErrConflict)
+		}
+	}
+	entry.ID = uuid.New()
+	r.blocklist = append(r.blocklist, *entry)
+	return nil
+}
+
+func (r *fakeRiskRepo) IsBlocklisted(ctx context.Context, kind domain.BlocklistKind, value string) (bool, error) {
+	for _, e := range r.blocklist {
+		if e.Kind == kind && e.Value == value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRiskRepo) CountPaymentsByClientIP(ctx context.Context, ip string, since time.Time) (int, error) {
+	r.counted = append(r.counted, "ip:"+ip)
+	return r.recent, nil
+}
+
+func (r *fakeRiskRepo) CountPaymentsByCustomer(ctx context.Context, customerID uuid.UUID, since time.Time) (int, error) {
+	r.counted = append(r.counted, "customer:"+customerID.String())
+	return r.recent, nil
+}
+
+func (r *fakeRiskRepo) CountPaymentsByReferencePrefix(ctx context.Context, prefix string, since time.Time) (int, error) {
+	r.counted = append(r.counted, "reference:"+prefix)
+	return r.recent, nil
+}
+
+func setupRisk(rules ...domain.RiskRuleRequest) (domain.PaymentService, domain.RiskService, *fakeRiskRepo, *fakePublisher) {
+	risk := &fakeRiskRepo{}
+	uow := &fakeUnitOfWork{repo: &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)}, customers: newFakeCustomerRepo(), risk: risk}
+	pub := &fakePublisher{}
+	rs := service.NewRiskService(uow)
+	for _, rr := range rules {
+		if _, err := rs.CreateRule(context.Background(), &rr); err != nil {
+			panic(err)
+		}
+	}
+	return service.NewPaymentService(uow, pub, nil), rs, risk, pub
+}
+
+func TestScreenPayment(t *testing.T) {
+	rules := []domain.RiskRuleRequest{
+		{Name: "large-usd", Expression: `currency == "USD" && amount > 5000`, Action: domain.RiskReview, Score: 40},
+		{Name: "busy-ip", Expression: `velocity_ip("1h") >= 10`, Action: domain.RiskReview, Score: 30},
+		{Name: "blocked-email", Expression: `blocked("email", email)`, Action: domain.RiskBlock, Score: 90},
+		{Name: "bad-window", Expression: `velocity_customer("soon") > 1`, Action: domain.RiskBlock, Score: 100},
+	}
+
+	t.Run("allows a payment no rule matches", func(t *testing.T) {
+		svc, _, risk, pub := setupRisk(rules...)
+		p, err := svc.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 10, Currency: "USD", Reference: "order-1", ClientIP: "10.0.0.1"})
+		assert.NoError(t, err)
+		if p == nil || p.Risk == nil {
+			t.Fatal("expected a screened payment")
+		}
+		assert.Equal(t, domain.RiskAllow, p.Risk.Outcome)
+		assert.Equal(t, 0, p.Risk.Score)
+		assert.Empty(t, p.Risk.Rules)
+		assert.Equal(t, "10.0.0.1", p.ClientIP)
+		assert.Equal(t, domain.StatusPending, p.Status)
+		assert.Equal(t, []string{"ip:10.0.0.1"}, risk.counted)
+		assert.Len(t, pub.published, 1)
+	})
+
+	t.Run("review rules add up and hold the payment", func(t *testing.T) {
+		svc, _, risk, pub := setupRisk(rules...)
+		risk.recent = 12
+		p, err := svc.CreatePayment(context.Background(), &domain.PaymentRequest{Amount: 6000, Currency: "USD", Reference: "order-2", ClientIP: "10.0.0.1"})
+		assert.NoError(t, err)
+		if p == nil || p.Risk == nil {
+			t.Fatal("expected a screened payment")
+		}
+		assert.Equal(t, domain.RiskReview, p.Risk.Outcome)
+		assert.Equal(t, 70, p.Risk.Score)
+		assert.Equal(t, []string{"large-usd", "busy-ip"}, p.Risk.Rules)
+		assert.Equal(t, domain.StatusInReview, p.Status)
+		assert.Empty(t, pub.published)
+	})
+
+	t.Run("block stores a failed payment without queueing it", func(t *testing.T) {
+		svc, rs, _, pub := setupRisk(rules...)
+		_, err := rs.CreateBlocklistEntry(context.Background(), &domain.BlocklistEntryRequest{Kind: domain.BlockEmail, Value: "Fraud@Example.com"})
+		assert.NoError(t, err)
+
+		p, err := svc.CreatePayment(context.Background(), &domain.PaymentRequest{
+			Amount: 6000, Currency: "USD", Reference: "order-3",
+			Payer: &domain.CustomerRequest{Name: "F", Email: "fraud@example.COM"},
+		})
+		assert.NoError(t, err)
+		if p == nil || p.Risk == nil {
+			t.Fatal("expected a screened payment")
+		}
+		assert.Equal(t, domain.RiskBlock, p.Risk.Outcome)
+		assert.Equal(t, 100, p.Risk.Score)
+		assert.Equal(t, []string{"large-usd", "blocked-email"}, p.Risk.Rules)
+		assert.Equal(t, domain.StatusFailed, p.Status)
+		assert.Empty(t, pub.published)
+	})
+}
+
+func TestRiskRules(t *testing.T) {
+	_, rs, risk, _ := setupRisk()
+	ctx := context.Background()
+
+	_, err := rs.CreateRule(ctx, &domain.RiskRuleRequest{Name: "typo", Expression: `amount >`, Action: domain.RiskReview})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	_, err = rs.CreateRule(ctx, &domain.RiskRuleRequest{Name: "geo", Expression: `country == "XX"`, Action: domain.RiskBlock})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	_, err = rs.CreateRule(ctx, &domain.RiskRuleRequest{Name: "allow", Expression: `true`, Action: domain.RiskAllow})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+
+	rule, err := rs.CreateRule(ctx, &domain.RiskRuleRequest{Name: "large", Expression: `amount > 1000`, Action: domain.RiskReview, Score: 20})
+	assert.NoError(t, err)
+	if rule == nil {
+		t.Fatal("expected a rule")
+	}
+	assert.True(t, rule.Enabled)
+
+	_, err = rs.CreateRule(ctx, &domain.RiskRuleRequest{Name: "large", Expression: `amount > 2000`, Action: domain.RiskReview})
+	var derr domain.Error
+	if assert.ErrorAs(t, err, &derr) {
+		assert.Equal(t, domain.ErrDuplicateRiskRule, derr.Type)
+	}
+
+	disabled := false
+	updated, err := rs.UpdateRule(ctx, rule.ID.String(), &domain.RiskRuleRequest{Name: "large", Expression: `amount > 2000`, Action: domain.RiskBlock, Score: 50, Enabled: &disabled})
+	assert.NoError(t, err)
+	if updated == nil {
+		t.Fatal("expected a rule")
+	}
+	assert.False(t, updated.Enabled)
+	assert.Equal(t, domain.RiskBlock, risk.rules[0].Action)
+
+	// Leaving enabled out keeps the rule disabled
+	updated, err = rs.UpdateRule(ctx, rule.ID.String(), &domain.RiskRuleRequest{Name: "large", Expression: `amount > 3000`, Action: domain.RiskBlock})
+	assert.NoError(t, err)
+	assert.False(t, updated.Enabled)
+
+	_, err = rs.UpdateRule(ctx, uuid.NewString(), &domain.RiskRuleRequest{Name: "x", Expression: `true`, Action: domain.RiskReview})
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+}
+
+func TestCreateBlocklistEntry(t *testing.T) {
+	_, rs, risk, _ := setupRisk()
+	ctx := context.Background()
+
+	_, err := rs.CreateBlocklistEntry(ctx, &domain.BlocklistEntryRequest{Kind: domain.BlockIP, Value: "not-an-ip"})
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+
+	entry, err := rs.CreateBlocklistEntry(ctx, &domain.BlocklistEntryRequest{Kind: domain.BlockEmail, Value: "Fraud@Example.com", Reason: "chargebacks"})
+	assert.NoError(t, err)
+	if entry == nil {
+		t.Fatal("expected an entry")
+	}
+	assert.Equal(t, "fraud@example.com", entry.Value)
+	assert.Len(t, risk.blocklist, 1)
+
+	_, err = rs.CreateBlocklistEntry(ctx, &domain.BlocklistEntryRequest{Kind: domain.BlockEmail, Value: "fraud@example.com"})
+	assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+}
+
+func TestScreenEveryPaymentCreation(t *testing.T) {
+	block := domain.RiskRuleRequest{Name: "blocked-ip", Expression: `blocked("ip", ip)`, Action: domain.RiskBlock, Score: 100}
+
+	t.Run("checkout session of a blocked payment is closed", func(t *testing.T) {
+		uow := &fakeUnitOfWork{repo: &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)}, customers: newFakeCustomerRepo()}
+		rs := service.NewRiskService(uow)
+		_, err := rs.CreateRule(context.Background(), &block)
+		assert.NoError(t, err)
+		_, err = rs.CreateBlocklistEntry(context.Background(), &domain.BlocklistEntryRequest{Kind: domain.BlockIP, Value: "10.0.0.9"})
+		assert.NoError(t, err)
+		pub := &fakePublisher{}
+		svc := service.NewCheckoutService(uow, pub, "https://pay.example.com")
+
+		cr := checkoutRequest("ref-blocked")
+		cr.ClientIP = "10.0.0.9"
+		s, err := svc.CreateSession(context.Background(), cr)
+		assert.NoError(t, err)
+		assert.Equal(t, domain.CheckoutCanceled, s.Status)
+		assert.Equal(t, domain.StatusFailed, s.Payment.Status)
+		assert.Equal(t, "10.0.0.9", s.Payment.ClientIP)
+		if assert.NotNil(t, s.Payment.Risk) {
+			assert.Equal(t, domain.RiskBlock, s.Payment.Risk.Outcome)
+		}
+
+		_, err = svc.ConfirmSession(context.Background(), s.Token, mobileMoney)
+		assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+		assert.Empty(t, pub.published)
+	})
+
+	t.Run("payment link payments carry the payer's address", func(t *testing.T) {
+		svc, uow := setupPaymentLinkService()
+		_, err := service.NewRiskService(uow).CreateRule(context.Background(), &domain.RiskRuleRequest{Name: "busy-ip", Expression: `velocity_ip("1h") >= 10`, Action: domain.RiskReview, Score: 30})
+		assert.NoError(t, err)
+		link, err := svc.CreatePaymentLink(context.Background(), &domain.PaymentLinkRequest{Currency: "USD"})
+		assert.NoError(t, err)
+		s, err := svc.PayPaymentLink(context.Background(), link.Slug, 20, "10.0.0.7")
+		assert.NoError(t, err)
+		assert.Equal(t, "10.0.0.7", s.Payment.ClientIP)
+		assert.NotNil(t, s.Payment.Risk)
+		assert.Equal(t, []string{"ip:10.0.0.7"}, uow.risk.counted)
+	})
+
+	t.Run("blocked renewal is not queued", func(t *testing.T) {
+		svc, uow := setupSubscriptions()
+		rs := service.NewRiskService(uow)
+		_, err := rs.CreateRule(context.Background(), &domain.RiskRuleRequest{Name: "no-etb", Expression: `currency == "ETB"`, Action: domain.RiskBlock, Score: 100})
+		assert.NoError(t, err)
+		publisher := &fakePublisher{}
+		scheduler := service.NewSubscriptionScheduler(uow, publisher, service.DefaultSchedulerConfig)
+		plan := createPlan(t, uow, domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth})
+		sub, err := svc.CreateSubscription(context.Background(), subscriptionRequest(t, uow, plan))
+		assert.NoError(t, err)
+
+		assert.NoError(t, scheduler.RunOnce(context.Background()))
+		assert.Empty(t, publisher.published)
+		// The blocked payment settles like a declined one
+		assert.NoError(t, scheduler.RunOnce(context.Background()))
+		got := uow.subs.byID[sub.ID]
+		assert.Nil(t, got.PendingPaymentID)
+		assert.Equal(t, domain.SubscriptionPastDue, got.Status)
+	})
+}
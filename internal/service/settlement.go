@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type SettlementService struct {
+	uow domain.UnitOfWork
+}
+
+func NewSettlementService(uow domain.UnitOfWork) domain.SettlementService {
+	return &SettlementService{uow: uow}
+}
+
+// ListBatches lists batches matching filter. A merchant API key only lists
+// its own.
+func (s *SettlementService) ListBatches(ctx context.Context, filter domain.SettlementFilter, page domain.Page) (*domain.SettlementBatchList, error) {
+	if merchantID, ok := domain.MerchantFromContext(ctx); ok {
+		filter.MerchantID = &merchantID
+	}
+	batches, err := s.uow.Settlements().ListBatches(ctx, filter, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list settlement batches",
+			"Error occurred while retrieving settlement batches",
+			err,
+			nil,
+		)
+	}
+	return &domain.SettlementBatchList{Data: batches, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+func (s *SettlementService) GetBatchByID(ctx context.Context, id string) (*domain.SettlementBatch, error) {
+	batchID, err := parseSettlementID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getBatch(ctx, batchID)
+}
+
+// getBatch returns the batch, reporting another merchant's batch as not
+// found.
+func (s *SettlementService) getBatch(ctx context.Context, batchID uuid.UUID) (*domain.SettlementBatch, error) {
+	batch, err := s.uow.Settlements().GetBatchByID(ctx, batchID)
+	if err == nil && !visibleToMerchant(ctx, batch.MerchantID) {
+		err = domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, settlementLookupError(err, batchID)
+	}
+	return batch, nil
+}
+
+// ListBatchPayments lists the payments settled in a batch, in the order they
+// were added.
+func (s *SettlementService) ListBatchPayments(ctx context.Context, id string, page domain.Page) (*domain.SettlementItemList, error) {
+	batchID, err := parseSettlementID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getBatch(ctx, batchID); err != nil {
+		return nil, err
+	}
+	items, err := s.uow.Settlements().ListItems(ctx, batchID, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list settlement payments",
+			"Error occurred while retrieving the payments of the settlement batch",
+			err,
+			map[string]interface{}{"SettlementID": batchID},
+		)
+	}
+	return &domain.SettlementItemList{Data: items, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// ListBatchAdjustments lists the refunds and chargebacks on earlier batches'
+// payments that the batch takes off its payout, in the order they were added.
+func (s *SettlementService) ListBatchAdjustments(ctx context.Context, id string, page domain.Page) (*domain.SettlementAdjustmentList, error) {
+	batchID, err := parseSettlementID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getBatch(ctx, batchID); err != nil {
+		return nil, err
+	}
+	adjustments, err := s.uow.Settlements().ListAdjustments(ctx, batchID, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list settlement adjustments",
+			"Error occurred while retrieving the adjustments of the settlement batch",
+			err,
+			map[string]interface{}{"SettlementID": batchID},
+		)
+	}
+	return &domain.SettlementAdjustmentList{Data: adjustments, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// PayBatch marks a closed batch paid and records the payout in the ledger in
+// the same transaction. The transfer itself happens outside the system.
+func (s *SettlementService) PayBatch(ctx context.Context, id string) (*domain.SettlementBatch, error) {
+	batchID, err := parseSettlementID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var batch *domain.SettlementBatch
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		batch, err = tx.Settlements().GetBatchByIDForUpdate(ctx, batchID)
+		if err != nil {
+			return settlementLookupError(err, batchID)
+		}
+		if batch.Status != domain.SettlementClosed {
+			return domain.NewError(
+				domain.ErrSettlementNotPayable,
+				"Settlement batch not payable",
+				"Only closed batches can be paid; this one is "+string(batch.Status),
+				nil,
+				map[string]interface{}{"SettlementID": batchID, "status": batch.Status},
+			)
+		}
+		before := batch
+		batch, err = tx.Settlements().MarkBatchPaid(ctx, batchID)
+		if err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, tx, "settlement_batch.paid", domain.AuditSettlementBatch, id, before, batch); err != nil {
+			return err
+		}
+		// Refunds can leave nothing to pay out
+		if batch.NetAmount <= 0 {
+			return nil
+		}
+		return postJournalEntry(ctx, tx, domain.NewPayoutEntry(batch.Currency, batch.NetAmount, "Settlement batch "+batchID.String()+" paid"))
+	})
+	if err != nil {
+		var derr domain.Error
+		if errors.As(err, &derr) {
+			return nil, derr
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to pay settlement batch",
+			"Error occurred while recording the settlement payout",
+			err,
+			map[string]interface{}{"SettlementID": batchID},
+		)
+	}
+
+	logger.FromContext(ctx).Info("settlement batch paid",
+		slog.String("settlement_id", id),
+		slog.String("merchant_id", batch.MerchantID),
+		slog.String("net_amount", fmt.Sprintf("%.2f %s", batch.NetAmount, batch.Currency)),
+	)
+	return batch, nil
+}
+
+func parseSettlementID(id string) (uuid.UUID, error) {
+	batchID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewError(
+			domain.ErrInvalidSettlementID,
+			"Invalid settlement batch ID format",
+			"The provided settlement batch ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"SettlementID": id},
+		)
+	}
+	return batchID, nil
+}
+
+func settlementLookupError(err error, id uuid.UUID) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrSettlementNotFound,
+			"Settlement batch not found",
+			"The specified settlement batch could not be found",
+			err,
+			map[string]interface{}{"SettlementID": id},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to fetch settlement batch",
+		"Error occurred while retrieving the settlement batch",
+		err,
+		map[string]interface{}{"SettlementID": id},
+	)
+}
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+)
+
+type SettlementConfig struct {
+	// PollInterval is how often the job batches new successful payments and
+	// closes batches past their cutoff.
+	PollInterval time.Duration
+	// Cutoff is the time of day, in UTC, at which the day's batches close.
+	Cutoff time.Duration
+}
+
+// DefaultSettlementConfig is used for settings left unset.
+var DefaultSettlementConfig = SettlementConfig{
+	PollInterval: 5 * time.Minute,
+}
+
+// SettlementConfigFromEnv reads SETTLEMENT_POLL_INTERVAL and SETTLEMENT_CUTOFF,
+// an HH:MM time of day in UTC. Unset values fall back to
+// DefaultSettlementConfig.
+func SettlementConfigFromEnv() (SettlementConfig, error) {
+	cfg := DefaultSettlementConfig
+	if v := os.Getenv("SETTLEMENT_POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid SETTLEMENT_POLL_INTERVAL value: %s", v)
+		}
+		cfg.PollInterval = d
+	}
+	if v := os.Getenv("SETTLEMENT_CUTOFF"); v != "" {
+		t, err := time.Parse("15:04", v)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid SETTLEMENT_CUTOFF value: %s", v)
+		}
+		cfg.Cutoff = time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	}
+	return cfg, nil
+}
+
+// NextCutoff returns the first cutoff after t.
+func (c SettlementConfig) NextCutoff(t time.Time) time.Time {
+	t = t.UTC()
+	cutoff := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Add(c.Cutoff)
+	if !cutoff.After(t) {
+		cutoff = cutoff.AddDate(0, 0, 1)
+	}
+	return cutoff
+}
+
+// SettlementJob runs in the worker. It adds each successful payment to the
+// open batch of its merchant and currency, takes money returned on payments
+// already batched out of the next open batch, and closes batches once their
+// cutoff passes. Payments are claimed with SKIP LOCKED, so several workers
+// can run it side by side.
+type SettlementJob struct {
+	uow domain.UnitOfWork
+	cfg SettlementConfig
+}
+
+func NewSettlementJob(uow domain.UnitOfWork, cfg SettlementConfig) *SettlementJob {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultSettlementConfig.PollInterval
+	}
+	return &SettlementJob{uow: uow, cfg: cfg}
+}
+
+// Run polls until ctx is canceled.
+func (j *SettlementJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := j.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Error("settlement job run failed", slog.Any("error", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce batches every unsettled payment and refund and then closes the
+// batches whose cutoff has passed. Batching first lets a payment that
+// succeeded just before the cutoff still make its batch.
+func (j *SettlementJob) RunOnce(ctx context.Context) error {
+	for _, next := range []func(context.Context) (bool, error){j.batchNext, j.adjustNext} {
+		for {
+			batched, err := next(ctx)
+			if err != nil {
+				return err
+			}
+			if !batched {
+				break
+			}
+		}
+	}
+
+	var closed []domain.SettlementBatch
+	err := j.uow.WithinTx(ctx, func(tx domain.UnitOfWork) (err error) {
+		closed, err = tx.Settlements().CloseDueBatches(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		for i := range closed {
+			before := closed[i]
+			before.Status = domain.SettlementOpen
+			before.ClosedAt = nil
+			if err := auditBatchClosed(ctx, tx, &before, &closed[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to close settlement batches: %w", err)
+	}
+	for _, b := range closed {
+		logger.FromContext(ctx).Info("settlement batch closed",
+			slog.String("settlement_id", b.ID.String()),
+			slog.String("merchant_id", b.MerchantID),
+			slog.Int("payment_count", b.PaymentCount),
+			slog.String("net_amount", fmt.Sprintf("%.2f %s", b.NetAmount, b.Currency)),
+		)
+	}
+	return nil
+}
+
+// batchNext claims one unsettled payment and adds it to its batch.
+func (j *SettlementJob) batchNext(ctx context.Context) (bool, error) {
+	var (
+		payment *domain.Payment
+		item    *domain.SettlementItem
+	)
+	err := j.uow.WithinTx(ctx, func(tx domain.UnitOfWork) (err error) {
+		var refunded float64
+		payment, refunded, err = tx.Settlements().ClaimUnsettledPayment(ctx)
+		if err != nil {
+			return err
+		}
+		batch, err := j.openBatch(ctx, tx, payment, payment.UpdatedAt)
+		if err != nil {
+			return err
+		}
+
+		item = domain.NewSettlementItem(payment, refunded)
+		item.BatchID = batch.ID
+		_, err = tx.Settlements().AddItem(ctx, item)
+		return err
+	})
+	if errors.Is(err, domain.ErrNotFound) && payment == nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to batch payment for settlement: %w", err)
+	}
+
+	logger.FromContext(ctx).Debug("payment added to settlement batch",
+		slog.String("payment_id", payment.ID.String()),
+		slog.String("settlement_id", item.BatchID.String()),
+	)
+	return true, nil
+}
+
+// adjustNext claims one batched payment with money refunded or charged back
+// on it since, and takes that amount off the merchant's open batch. A batch
+// already closed or paid is never changed.
+func (j *SettlementJob) adjustNext(ctx context.Context) (bool, error) {
+	var adjustment *domain.SettlementAdjustment
+	err := j.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		payment, unsettled, err := tx.Settlements().ClaimUnsettledRefund(ctx)
+		if err != nil {
+			return err
+		}
+		batch, err := j.openBatch(ctx, tx, payment, time.Now())
+		if err != nil {
+			return err
+		}
+
+		adjustment = &domain.SettlementAdjustment{
+			PaymentID: payment.ID,
+			BatchID:   batch.ID,
+			Amount:    -unsettled,
+		}
+		_, err = tx.Settlements().AddAdjustment(ctx, adjustment)
+		return err
+	})
+	if errors.Is(err, domain.ErrNotFound) && adjustment == nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to adjust settlement batch: %w", err)
+	}
+
+	logger.FromContext(ctx).Debug("refund carried into settlement batch",
+		slog.String("payment_id", adjustment.PaymentID.String()),
+		slog.String("settlement_id", adjustment.BatchID.String()),
+		slog.String("amount", fmt.Sprintf("%.2f", adjustment.Amount)),
+	)
+	return true, nil
+}
+
+// openBatch returns the open batch of payment's merchant and currency for
+// money moved at. That is the merchant's open batch unless it closes before
+// at, in which case it is closed and the next one started.
+func (j *SettlementJob) openBatch(ctx context.Context, tx domain.UnitOfWork, payment *domain.Payment, at time.Time) (*domain.SettlementBatch, error) {
+	merchantID := payment.Metadata[domain.MerchantMetadataKey]
+	cutoff := j.cfg.NextCutoff(at)
+	batch, err := tx.Settlements().OpenBatch(ctx, merchantID, payment.Currency, cutoff)
+	if err != nil {
+		return nil, err
+	}
+	if at.Before(batch.CutoffAt) {
+		return batch, nil
+	}
+	closed, err := tx.Settlements().CloseBatch(ctx, batch.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := auditBatchClosed(ctx, tx, batch, closed); err != nil {
+		return nil, err
+	}
+	return tx.Settlements().OpenBatch(ctx, merchantID, payment.Currency, cutoff)
+}
+
+// auditBatchClosed records a batch closing once its cutoff has passed.
+func auditBatchClosed(ctx context.Context, tx domain.UnitOfWork, before, after *domain.SettlementBatch) error {
+	return recordAudit(ctx, tx, "settlement_batch.closed", domain.AuditSettlementBatch, after.ID.String(), before, after)
+}
@@ -0,0 +1,348 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+type fakeSettlementRepo struct {
+	domain.SettlementRepo
+	payments *fakeRepo
+	refunds  map[uuid.UUID]float64
+	batches  []*domain.SettlementBatch
+	items    []domain.SettlementItem
+	// adjustments are stored in the order they were added
+	adjustments []domain.SettlementAdjustment
+}
+
+func (r *fakeSettlementRepo) ClaimUnsettledPayment(ctx context.Context) (*domain.Payment, float64, error) {
+	var unsettled []*domain.Payment
+	for _, p := range r.payments.byID {
+		if p.Status == domain.StatusSuccess && !r.settled(p.ID) {
+			unsettled = append(unsettled, p)
+		}
+	}
+	if len(unsettled) == 0 {
+		return nil, 0, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	sort.Slice(unsettled, func(i, j int) bool { return unsettled[i].UpdatedAt.Before(unsettled[j].UpdatedAt) })
+	p := *unsettled[0]
+	return &p, r.refunds[p.ID], nil
+}
+
+func (r *fakeSettlementRepo) settled(paymentID uuid.UUID) bool {
+	for _, i := range r.items {
+		if i.PaymentID == paymentID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *fakeSettlementRepo) ClaimUnsettledRefund(ctx context.Context) (*domain.Payment, float64, error) {
+	for _, i := range r.items {
+		unsettled := r.refunds[i.PaymentID] - i.RefundedAmount
+		for _, a := range r.adjustments {
+			if a.PaymentID == i.PaymentID {
+				unsettled += a.Amount
+			}
+		}
+		if unsettled > 0.001 {
+			p := *r.payments.byID[i.PaymentID]
+			return &p, unsettled, nil
+		}
+	}
+	return nil, 0, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeSettlementRepo) OpenBatch(ctx context.Context, merchantID, currency string, cutoff time.Time) (*domain.SettlementBatch, error) {
+	for _, b := range r.batches {
+		if b.Status == domain.SettlementOpen && b.MerchantID == merchantID && b.Currency == currency {
+			return b, nil
+		}
+	}
+	b := &domain.SettlementBatch{ID: uuid.New(), MerchantID: merchantID, Currency: currency, Status: domain.SettlementOpen, CutoffAt: cutoff}
+	r.batches = append(r.batches, b)
+	return b, nil
+}
+
+func (r *fakeSettlementRepo) AddItem(ctx context.Context, item *domain.SettlementItem) (*domain.SettlementBatch, error) {
+	if r.settled(item.PaymentID) {
+		return nil, fmt.Errorf("%w: duplicate key", domain.ErrConflict)
+	}
+	r.items = append(r.items, *item)
+	b, err := r.GetBatchByID(ctx, item.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	b.PaymentCount++
+	b.GrossAmount += item.Amount
+	b.FeeAmount += item.FeeAmount
+	b.RefundedAmount += item.RefundedAmount
+	b.NetAmount += item.NetAmount
+	return b, nil
+}
+
+func (r *fakeSettlementRepo) AddAdjustment(ctx context.Context, adjustment *domain.SettlementAdjustment) (*domain.SettlementBatch, error) {
+	adjustment.ID = uuid.New()
+	r.adjustments = append(r.adjustments, *adjustment)
+	b, err := r.GetBatchByID(ctx, adjustment.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	b.AdjustmentAmount += adjustment.Amount
+	b.NetAmount += adjustment.Amount
+	return b, nil
+}
+
+func (r *fakeSettlementRepo) CloseBatch(ctx context.Context, id uuid.UUID) (*domain.SettlementBatch, error) {
+	b, err := r.GetBatchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	b.Status = domain.SettlementClosed
+	return b, nil
+}
+
+func (r *fakeSettlementRepo) CloseDueBatches(ctx context.Context, now time.Time) ([]domain.SettlementBatch, error) {
+	var closed []domain.SettlementBatch
+	for _, b := range r.batches {
+		if b.Status == domain.SettlementOpen && !b.CutoffAt.After(now) {
+			b.Status = domain.SettlementClosed
+			closed = append(closed, *b)
+		}
+	}
+	return closed, nil
+}
+
+func (r *fakeSettlementRepo) MarkBatchPaid(ctx context.Context, id uuid.UUID) (*domain.SettlementBatch, error) {
+	b, err := r.GetBatchByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	b.Status = domain.SettlementPaid
+	return b, nil
+}
+
+func (r *fakeSettlementRepo) GetBatchByID(ctx context.Context, id uuid.UUID) (*domain.SettlementBatch, error) {
+	for _, b := range r.batches {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeSettlementRepo) GetBatchByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.SettlementBatch, error) {
+	return r.GetBatchByID(ctx, id)
+}
+
+func (r *fakeSettlementRepo) ListBatches(ctx context.Context, filter domain.SettlementFilter, page domain.Page) ([]domain.SettlementBatch, error) {
+	var batches []domain.SettlementBatch
+	for _, b := range r.batches {
+		if filter.MerchantID == nil || b.MerchantID == *filter.MerchantID {
+			batches = append(batches, *b)
+		}
+	}
+	return batches, nil
+}
+
+func (r *fakeSettlementRepo) ListItems(ctx context.Context, batchID uuid.UUID, page domain.Page) ([]domain.SettlementItem, error) {
+	var items []domain.SettlementItem
+	for _, i := range r.items {
+		if i.BatchID == batchID {
+			items = append(items, i)
+		}
+	}
+	return items, nil
+}
+
+func (r *fakeSettlementRepo) ListAdjustments(ctx context.Context, batchID uuid.UUID, page domain.Page) ([]domain.SettlementAdjustment, error) {
+	var adjustments []domain.SettlementAdjustment
+	for _, a := range r.adjustments {
+		if a.BatchID == batchID {
+			adjustments = append(adjustments, a)
+		}
+	}
+	return adjustments, nil
+}
+
+// setupSettlements stores payments that succeeded at the given times.
+func setupSettlements(payments ...*domain.Payment) (*fakeUnitOfWork, *fakeSettlementRepo) {
+	repo := &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)}
+	for _, p := range payments {
+		p.ID = uuid.New()
+		repo.byID[p.ID] = p
+	}
+	settlements := &fakeSettlementRepo{payments: repo, refunds: map[uuid.UUID]float64{}}
+	return &fakeUnitOfWork{repo: repo, ledger: &fakeLedgerRepo{}, settlements: settlements}, settlements
+}
+
+func TestSettlementConfigNextCutoff(t *testing.T) {
+	cfg := service.SettlementConfig{Cutoff: 18 * time.Hour}
+	day := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected time.Time
+	}{
+		{"before the cutoff", day.Add(10 * time.Hour), day.Add(18 * time.Hour)},
+		{"at the cutoff", day.Add(18 * time.Hour), day.AddDate(0, 0, 1).Add(18 * time.Hour)},
+		{"after the cutoff", day.Add(20 * time.Hour), day.AddDate(0, 0, 1).Add(18 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, cfg.NextCutoff(tt.at))
+		})
+	}
+}
+
+func TestSettlementJob(t *testing.T) {
+	fee := func(f float64) *float64 { return &f }
+	acme := domain.Metadata{domain.MerchantMetadataKey: "acme"}
+	old := &domain.Payment{Amount: 100, Currency: "USD", Status: domain.StatusSuccess, Metadata: acme, FeeAmount: fee(3.2), UpdatedAt: time.Now().AddDate(0, 0, -2)}
+	refunded := &domain.Payment{Amount: 50, Currency: "USD", Status: domain.StatusSuccess, Metadata: acme, FeeAmount: fee(1.75), UpdatedAt: time.Now()}
+	noMerchant := &domain.Payment{Amount: 200, Currency: "ETB", Status: domain.StatusSuccess, UpdatedAt: time.Now()}
+	failed := &domain.Payment{Amount: 80, Currency: "USD", Status: domain.StatusFailed, Metadata: acme, UpdatedAt: time.Now()}
+	uow, settlements := setupSettlements(old, refunded, noMerchant, failed)
+	settlements.refunds[refunded.ID] = 10
+
+	job := service.NewSettlementJob(uow, service.SettlementConfig{})
+	assert.NoError(t, job.RunOnce(context.Background()))
+	assert.Len(t, settlements.items, 3)
+	if len(settlements.batches) != 3 {
+		t.Fatalf("expected 3 batches, got %d", len(settlements.batches))
+	}
+
+	// The old payment's cutoff passed, so its batch closed and a new one opened
+	past, current, etb := settlements.batches[0], settlements.batches[1], settlements.batches[2]
+	assert.Equal(t, domain.SettlementClosed, past.Status)
+	assert.Equal(t, 1, past.PaymentCount)
+	assert.InDelta(t, 96.8, past.NetAmount, 0.001)
+
+	assert.Equal(t, domain.SettlementOpen, current.Status)
+	assert.Equal(t, "acme", current.MerchantID)
+	assert.InDelta(t, 10, current.RefundedAmount, 0.001)
+	assert.InDelta(t, 38.25, current.NetAmount, 0.001)
+
+	assert.Equal(t, "", etb.MerchantID)
+	assert.Equal(t, "ETB", etb.Currency)
+	assert.InDelta(t, 200, etb.NetAmount, 0.001)
+
+	// Settled payments are not picked up again
+	assert.NoError(t, job.RunOnce(context.Background()))
+	assert.Len(t, settlements.items, 3)
+}
+
+func TestSettlementJobCarriesLaterRefunds(t *testing.T) {
+	acme := domain.Metadata{domain.MerchantMetadataKey: "acme"}
+	payment := &domain.Payment{Amount: 100, Currency: "USD", Status: domain.StatusSuccess, Metadata: acme, UpdatedAt: time.Now().AddDate(0, 0, -2)}
+	uow, settlements := setupSettlements(payment)
+	settlements.refunds[payment.ID] = 10
+	job := service.NewSettlementJob(uow, service.SettlementConfig{})
+
+	assert.NoError(t, job.RunOnce(context.Background()))
+	if len(settlements.batches) != 1 {
+		t.Fatalf("expected 1 batch, got %d", len(settlements.batches))
+	}
+	past := settlements.batches[0]
+	assert.Equal(t, domain.SettlementClosed, past.Status)
+	assert.InDelta(t, 90, past.NetAmount, 0.001)
+	assert.Empty(t, settlements.adjustments)
+
+	// A refund and then a chargeback posted after the payment was batched
+	settlements.refunds[payment.ID] = 35
+	assert.NoError(t, job.RunOnce(context.Background()))
+	settlements.refunds[payment.ID] = 50
+	assert.NoError(t, job.RunOnce(context.Background()))
+	assert.NoError(t, job.RunOnce(context.Background()))
+
+	if len(settlements.batches) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(settlements.batches))
+	}
+	next := settlements.batches[1]
+	assert.Equal(t, domain.SettlementOpen, next.Status)
+	assert.Equal(t, "acme", next.MerchantID)
+	assert.InDelta(t, -40, next.AdjustmentAmount, 0.001)
+	assert.InDelta(t, -40, next.NetAmount, 0.001)
+	// The closed batch keeps what it already reported
+	assert.InDelta(t, 90, past.NetAmount, 0.001)
+
+	list, err := service.NewSettlementService(uow).ListBatchAdjustments(context.Background(), next.ID.String(), domain.Page{Limit: 20})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Data, 2) {
+		assert.Equal(t, payment.ID, list.Data[0].PaymentID)
+		assert.InDelta(t, -25, list.Data[0].Amount, 0.001)
+		assert.InDelta(t, -15, list.Data[1].Amount, 0.001)
+	}
+}
+
+func TestPayBatch(t *testing.T) {
+	uow, settlements := setupSettlements(&domain.Payment{Amount: 100, Currency: "USD", Status: domain.StatusSuccess, UpdatedAt: time.Now().AddDate(0, 0, -2)})
+	assert.NoError(t, service.NewSettlementJob(uow, service.SettlementConfig{}).RunOnce(context.Background()))
+	if len(settlements.batches) != 1 {
+		t.Fatalf("expected 1 batch, got %d", len(settlements.batches))
+	}
+	batch := settlements.batches[0]
+	svc := service.NewSettlementService(uow)
+
+	paid, err := svc.PayBatch(context.Background(), batch.ID.String())
+	assert.NoError(t, err)
+	assert.Equal(t, domain.SettlementPaid, paid.Status)
+	if len(uow.ledger.entries) != 1 {
+		t.Fatalf("expected a payout entry, got %d entries", len(uow.ledger.entries))
+	}
+	assert.Equal(t, domain.EntryPayout, uow.ledger.entries[0].Kind)
+	assert.Equal(t, 100.0, uow.ledger.entries[0].Postings[0].Amount)
+
+	_, err = svc.PayBatch(context.Background(), batch.ID.String())
+	assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+
+	open, _ := settlements.OpenBatch(context.Background(), "acme", "USD", time.Now().Add(time.Hour))
+	_, err = svc.PayBatch(context.Background(), open.ID.String())
+	assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+
+	_, err = svc.PayBatch(context.Background(), "nope")
+	assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	_, err = svc.PayBatch(context.Background(), uuid.NewString())
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+}
+
+func TestSettlementMerchantScope(t *testing.T) {
+	uow, settlements := setupSettlements()
+	acmeBatch, _ := settlements.OpenBatch(context.Background(), "acme", "USD", time.Now())
+	globexBatch, _ := settlements.OpenBatch(context.Background(), "globex", "USD", time.Now())
+	svc := service.NewSettlementService(uow)
+	acme := domain.WithMerchant(context.Background(), "acme")
+
+	globex := "globex"
+	list, err := svc.ListBatches(acme, domain.SettlementFilter{MerchantID: &globex}, domain.Page{Limit: 20})
+	assert.NoError(t, err)
+	if assert.Len(t, list.Data, 1) {
+		assert.Equal(t, acmeBatch.ID, list.Data[0].ID)
+	}
+
+	_, err = svc.GetBatchByID(acme, acmeBatch.ID.String())
+	assert.NoError(t, err)
+	_, err = svc.GetBatchByID(acme, globexBatch.ID.String())
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	_, err = svc.ListBatchPayments(acme, globexBatch.ID.String(), domain.Page{Limit: 20})
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	_, err = svc.ListBatchAdjustments(acme, globexBatch.ID.String(), domain.Page{Limit: 20})
+	assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+
+	// Operator routes carry no merchant and see every batch
+	list, err = svc.ListBatches(context.Background(), domain.SettlementFilter{}, domain.Page{Limit: 20})
+	assert.NoError(t, err)
+	assert.Len(t, list.Data, 2)
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type SubscriptionService struct {
+	uow domain.UnitOfWork
+}
+
+func NewSubscriptionService(uow domain.UnitOfWork) domain.SubscriptionService {
+	return &SubscriptionService{uow: uow}
+}
+
+// CreateSubscription starts a subscription. Nothing is charged here; the
+// scheduler charges the first cycle at the billing anchor, which is the end
+// of the plan's trial unless the request sets a later one.
+func (s *SubscriptionService) CreateSubscription(ctx context.Context, sr *domain.SubscriptionRequest) (*domain.Subscription, error) {
+	metadata, err := bindMerchant(ctx, sr.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	sr.Metadata = metadata
+	if err := sr.Validate(); err != nil {
+		return nil, domain.NewError(
+			domain.ErrValidationFailed,
+			"validation failed",
+			"subscription request validation failed",
+			err,
+			map[string]interface{}{"req": sr},
+		)
+	}
+
+	plan, err := s.uow.Plans().GetPlanByID(ctx, sr.PlanID)
+	if err != nil {
+		return nil, planLookupError(err, sr.PlanID)
+	}
+	if _, err := s.uow.Customers().GetCustomerByID(ctx, callerMerchant(ctx), sr.CustomerID); err != nil {
+		return nil, customerLookupError(err, sr.CustomerID.String())
+	}
+
+	now := time.Now()
+	sub := &domain.Subscription{
+		PlanID:             plan.ID,
+		CustomerID:         sr.CustomerID,
+		Status:             domain.SubscriptionActive,
+		PaymentMethod:      sr.PaymentMethod,
+		BillingAnchor:      now,
+		CurrentPeriodStart: now,
+		Metadata:           sr.Metadata,
+	}
+	if plan.TrialDays > 0 {
+		trialEnd := now.AddDate(0, 0, plan.TrialDays)
+		sub.Status = domain.SubscriptionTrialing
+		sub.TrialEnd = &trialEnd
+		sub.BillingAnchor = trialEnd
+	}
+	if sr.BillingAnchor != nil {
+		if sr.BillingAnchor.Before(sub.BillingAnchor) {
+			return nil, domain.NewError(
+				domain.ErrValidationFailed,
+				"validation failed",
+				"billing_anchor must not be in the past or before the end of the trial",
+				nil,
+				map[string]interface{}{"req": sr},
+			)
+		}
+		sub.BillingAnchor = *sr.BillingAnchor
+	}
+	// Until the first charge the current period runs up to the anchor
+	sub.CurrentPeriodEnd = sub.BillingAnchor
+	sub.NextBillingAt = &sub.BillingAnchor
+
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		if err := tx.Subscriptions().CreateSubscription(ctx, sub); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "subscription.created", domain.AuditSubscription, sub.ID.String(), nil, sub)
+	})
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to create subscription",
+			"Error occurred while saving the subscription",
+			err,
+			map[string]interface{}{"req": sr},
+		)
+	}
+
+	logger.FromContext(ctx).Info("subscription created",
+		slog.String("subscription_id", sub.ID.String()),
+		slog.String("plan_id", plan.ID.String()),
+		slog.String("status", string(sub.Status)),
+	)
+	return sub, nil
+}
+
+func (s *SubscriptionService) GetSubscriptionByID(ctx context.Context, id string) (*domain.Subscription, error) {
+	subID, err := parseSubscriptionID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	sub, err := s.uow.Subscriptions().GetSubscriptionByID(ctx, subID)
+	if err != nil {
+		return nil, subscriptionLookupError(err, subID)
+	}
+	return sub, nil
+}
+
+func (s *SubscriptionService) ListSubscriptions(ctx context.Context, page domain.Page) (*domain.SubscriptionList, error) {
+	subs, err := s.uow.Subscriptions().ListSubscriptions(ctx, page)
+	if err != nil {
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to list subscriptions",
+			"Error occurred while retrieving subscriptions",
+			err,
+			nil,
+		)
+	}
+	return &domain.SubscriptionList{Data: subs, Limit: page.Limit, Offset: page.Offset}, nil
+}
+
+// CancelSubscription cancels right away or, with AtPeriodEnd, once the
+// current period ends. A cycle payment already queued is not recalled.
+func (s *SubscriptionService) CancelSubscription(ctx context.Context, id string, cr *domain.CancelSubscriptionRequest) (*domain.Subscription, error) {
+	subID, err := parseSubscriptionID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var sub *domain.Subscription
+	err = s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		sub, err = tx.Subscriptions().GetSubscriptionByIDForUpdate(ctx, subID)
+		if err != nil {
+			return subscriptionLookupError(err, subID)
+		}
+		if sub.Status == domain.SubscriptionCanceled {
+			return domain.NewError(
+				domain.ErrSubscriptionCanceled,
+				"Subscription canceled",
+				"The subscription has already been canceled",
+				nil,
+				map[string]interface{}{"SubscriptionID": subID},
+			)
+		}
+		before := *sub
+		if cr.AtPeriodEnd {
+			sub.CancelAtPeriodEnd = true
+		} else {
+			cancelSubscription(sub, time.Now())
+		}
+		if err := tx.Subscriptions().UpdateSubscription(ctx, sub); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "subscription.canceled", domain.AuditSubscription, id, before, sub)
+	})
+	if err != nil {
+		var derr domain.Error
+		if errors.As(err, &derr) {
+			return nil, derr
+		}
+		return nil, domain.NewError(
+			storageErrorCode(err),
+			"Failed to cancel subscription",
+			"Error occurred while updating the subscription",
+			err,
+			map[string]interface{}{"SubscriptionID": subID},
+		)
+	}
+
+	logger.FromContext(ctx).Info("subscription canceled",
+		slog.String("subscription_id", id),
+		slog.Bool("at_period_end", cr.AtPeriodEnd),
+	)
+	return sub, nil
+}
+
+// cancelSubscription ends sub at now and stops billing it.
+func cancelSubscription(sub *domain.Subscription, now time.Time) {
+	sub.Status = domain.SubscriptionCanceled
+	sub.CanceledAt = &now
+	sub.NextBillingAt = nil
+	sub.CancelAtPeriodEnd = false
+}
+
+func parseSubscriptionID(id string) (uuid.UUID, error) {
+	subID, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, domain.NewError(
+			domain.ErrInvalidSubscriptionID,
+			"Invalid subscription ID format",
+			"The provided subscription ID is not a valid UUID format",
+			err,
+			map[string]interface{}{"SubscriptionID": id},
+		)
+	}
+	return subID, nil
+}
+
+func subscriptionLookupError(err error, id uuid.UUID) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewError(
+			domain.ErrSubscriptionNotFound,
+			"Subscription not found",
+			"The specified subscription could not be found",
+			err,
+			map[string]interface{}{"SubscriptionID": id},
+		)
+	}
+	return domain.NewError(
+		storageErrorCode(err),
+		"Failed to fetch subscription",
+		"Error occurred while retrieving the subscription",
+		err,
+		map[string]interface{}{"SubscriptionID": id},
+	)
+}
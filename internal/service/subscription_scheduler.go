@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"pgm/internal/domain"
+	"pgm/internal/logger"
+
+	"github.com/google/uuid"
+)
+
+type SchedulerConfig struct {
+	// PollInterval is how often the scheduler looks for due subscriptions
+	// and settled cycle payments.
+	PollInterval time.Duration
+	// DunningSchedule lists how long to wait before each retry of a failed
+	// cycle payment. The subscription is canceled when the last retry fails.
+	DunningSchedule []time.Duration
+	// RetryAfter is how long a subscription that could not be billed, for
+	// example because its plan is missing, waits before it is tried again.
+	RetryAfter time.Duration
+}
+
+// DefaultSchedulerConfig is used for settings left unset.
+var DefaultSchedulerConfig = SchedulerConfig{
+	PollInterval:    time.Minute,
+	DunningSchedule: []time.Duration{24 * time.Hour, 72 * time.Hour, 120 * time.Hour},
+	RetryAfter:      time.Hour,
+}
+
+// SchedulerConfigFromEnv reads SUBSCRIPTION_POLL_INTERVAL,
+// SUBSCRIPTION_RETRY_AFTER and DUNNING_SCHEDULE, a comma-separated list of
+// durations. Unset values fall back to DefaultSchedulerConfig.
+func SchedulerConfigFromEnv() (SchedulerConfig, error) {
+	cfg := DefaultSchedulerConfig
+	if v := os.Getenv("SUBSCRIPTION_POLL_INTERVAL"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid SUBSCRIPTION_POLL_INTERVAL value: %s", v)
+		}
+		cfg.PollInterval = d
+	}
+	if v := os.Getenv("SUBSCRIPTION_RETRY_AFTER"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return cfg, fmt.Errorf("invalid SUBSCRIPTION_RETRY_AFTER value: %s", v)
+		}
+		cfg.RetryAfter = d
+	}
+	if v := os.Getenv("DUNNING_SCHEDULE"); v != "" {
+		var schedule []time.Duration
+		for _, s := range strings.Split(v, ",") {
+			d, err := time.ParseDuration(strings.TrimSpace(s))
+			if err != nil || d <= 0 {
+				return cfg, fmt.Errorf("invalid DUNNING_SCHEDULE value: %s", v)
+			}
+			schedule = append(schedule, d)
+		}
+		cfg.DunningSchedule = schedule
+	}
+	return cfg, nil
+}
+
+// SubscriptionScheduler runs in the worker. It charges subscriptions as
+// their cycles come due and, once the worker has processed a cycle payment,
+// moves the subscription on: to the next period when the payment succeeded,
+// or along the dunning schedule when it failed. Subscriptions are claimed
+// with SKIP LOCKED, so several workers can run it side by side.
+type SubscriptionScheduler struct {
+	uow       domain.UnitOfWork
+	publisher domain.MessagePublisher
+	cfg       SchedulerConfig
+}
+
+func NewSubscriptionScheduler(uow domain.UnitOfWork, publisher domain.MessagePublisher, cfg SchedulerConfig) *SubscriptionScheduler {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultSchedulerConfig.PollInterval
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = DefaultSchedulerConfig.RetryAfter
+	}
+	return &SubscriptionScheduler{
+		uow:       uow,
+		publisher: publisher,
+		cfg:       cfg,
+	}
+}
+
+// Run polls until ctx is canceled.
+func (s *SubscriptionScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.FromContext(ctx).Error("subscription scheduler run failed", slog.Any("error", err))
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce settles every processed cycle payment and then bills every due
+// subscription. Settling first lets a subscription whose retry just failed
+// be rescheduled before it is considered for billing.
+func (s *SubscriptionScheduler) RunOnce(ctx context.Context) error {
+	for {
+		settled, err := s.settleNext(ctx)
+		if err != nil {
+			return err
+		}
+		if !settled {
+			break
+		}
+	}
+	for {
+		billed, err := s.billNext(ctx)
+		if err != nil {
+			return err
+		}
+		if !billed {
+			return nil
+		}
+	}
+}
+
+// billNext claims one due subscription and creates its cycle payment, or
+// cancels it if it was set to cancel at the end of the period. The payment
+// is queued once the transaction has committed. A subscription that cannot
+// be billed is put off by RetryAfter rather than failing the run, so it
+// does not hold up the subscriptions due after it.
+func (s *SubscriptionScheduler) billNext(ctx context.Context) (bool, error) {
+	var (
+		sub     *domain.Subscription
+		payment *domain.Payment
+	)
+	now := time.Now()
+	err := s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) (err error) {
+		sub, err = tx.Subscriptions().ClaimDueSubscription(ctx, now)
+		if err != nil {
+			return err
+		}
+		before := *sub
+		action := "subscription.billed"
+		if sub.CancelAtPeriodEnd {
+			cancelSubscription(sub, now)
+			action = "subscription.canceled"
+		} else {
+			plan, err := tx.Plans().GetPlanByID(ctx, sub.PlanID)
+			if err != nil {
+				return err
+			}
+			payment = cyclePayment(sub, plan)
+			if err := screenAndCreatePayment(ctx, tx, payment, nil); err != nil {
+				return err
+			}
+			sub.PendingPaymentID = &payment.ID
+		}
+		if err := tx.Subscriptions().UpdateSubscription(ctx, sub); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, action, domain.AuditSubscription, sub.ID.String(), before, sub)
+	})
+	if errors.Is(err, domain.ErrNotFound) && sub == nil {
+		return false, nil
+	}
+	if err != nil && sub != nil && ctx.Err() == nil {
+		logger.FromContext(ctx).Error("subscription billing failed",
+			slog.String("subscription_id", sub.ID.String()),
+			slog.Duration("retry_after", s.cfg.RetryAfter),
+			slog.Any("error", err),
+		)
+		if derr := s.deferBilling(ctx, sub.ID, now); derr != nil {
+			return false, fmt.Errorf("failed to bill subscription: %w", errors.Join(err, derr))
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to bill subscription: %w", err)
+	}
+
+	log := logger.FromContext(ctx).With(slog.String("subscription_id", sub.ID.String()))
+	if payment == nil {
+		log.Info("subscription canceled at period end")
+		return true, nil
+	}
+	log.Info("subscription cycle billed",
+		slog.String("payment_id", payment.ID.String()),
+		slog.Int("attempt", sub.FailedAttempts+1),
+		slog.String("risk_outcome", string(payment.Risk.Outcome)),
+	)
+	// A blocked payment settles as failed and a held one is queued once its
+	// review is approved
+	if payment.Status == domain.StatusPending {
+		publishPaymentCreated(ctx, s.publisher, payment)
+	}
+	return true, nil
+}
+
+// deferBilling moves the next billing of a subscription that was due at now
+// but could not be billed RetryAfter into the future. A subscription another
+// worker has billed or rescheduled since is left alone.
+func (s *SubscriptionScheduler) deferBilling(ctx context.Context, id uuid.UUID, now time.Time) error {
+	return s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) error {
+		sub, err := tx.Subscriptions().GetSubscriptionByIDForUpdate(ctx, id)
+		if err != nil {
+			return err
+		}
+		if sub.PendingPaymentID != nil || sub.NextBillingAt == nil || sub.NextBillingAt.After(now) {
+			return nil
+		}
+		before := *sub
+		retry := time.Now().Add(s.cfg.RetryAfter)
+		sub.NextBillingAt = &retry
+		if err := tx.Subscriptions().UpdateSubscription(ctx, sub); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "subscription.billing_deferred", domain.AuditSubscription, sub.ID.String(), before, sub)
+	})
+}
+
+// settleNext claims one subscription whose cycle payment has been processed
+// and applies the result.
+func (s *SubscriptionScheduler) settleNext(ctx context.Context) (bool, error) {
+	var (
+		sub       *domain.Subscription
+		status    domain.PaymentStatus
+		paymentID uuid.UUID
+	)
+	now := time.Now()
+	err := s.uow.WithinTx(ctx, func(tx domain.UnitOfWork) (err error) {
+		sub, status, err = tx.Subscriptions().ClaimSettledSubscription(ctx)
+		if err != nil {
+			return err
+		}
+		before := *sub
+		paymentID = *sub.PendingPaymentID
+		sub.PendingPaymentID = nil
+		switch {
+		case sub.Status == domain.SubscriptionCanceled:
+			// Canceled while the payment was in flight; nothing to move on
+		case status == domain.StatusSuccess:
+			plan, err := tx.Plans().GetPlanByID(ctx, sub.PlanID)
+			if err != nil {
+				return err
+			}
+			s.advance(sub, plan)
+		default:
+			// A cycle payment canceled before it was charged counts as failed
+			s.dun(sub, now)
+		}
+		if err := tx.Subscriptions().UpdateSubscription(ctx, sub); err != nil {
+			return err
+		}
+		return recordAudit(ctx, tx, "subscription.settled", domain.AuditSubscription, sub.ID.String(), before, sub)
+	})
+	if errors.Is(err, domain.ErrNotFound) && sub == nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to settle subscription: %w", err)
+	}
+
+	logger.FromContext(ctx).Info("subscription cycle settled",
+		slog.String("subscription_id", sub.ID.String()),
+		slog.String("payment_id", paymentID.String()),
+		slog.String("payment_status", string(status)),
+		slog.String("status", string(sub.Status)),
+	)
+	return true, nil
+}
+
+// advance starts the period that was just paid for.
+func (s *SubscriptionScheduler) advance(sub *domain.Subscription, plan *domain.Plan) {
+	sub.CurrentPeriodStart = sub.CurrentPeriodEnd
+	sub.CurrentPeriodEnd = plan.NextBillingDate(sub.BillingAnchor, sub.CurrentPeriodStart)
+	sub.Status = domain.SubscriptionActive
+	sub.FailedAttempts = 0
+	next := sub.CurrentPeriodEnd
+	sub.NextBillingAt = &next
+}
+
+// dun schedules the next retry of a failed cycle, or cancels the
+// subscription once the dunning schedule is used up.
+func (s *SubscriptionScheduler) dun(sub *domain.Subscription, now time.Time) {
+	sub.FailedAttempts++
+	if sub.FailedAttempts > len(s.cfg.DunningSchedule) {
+		cancelSubscription(sub, now)
+		return
+	}
+	sub.Status = domain.SubscriptionPastDue
+	retry := now.Add(s.cfg.DunningSchedule[sub.FailedAttempts-1])
+	sub.NextBillingAt = &retry
+}
+
+// cyclePayment builds the payment for the period starting at the end of the
+// current one. The reference names the cycle and attempt, so a retry never
+// collides with the payment it replaces.
+func cyclePayment(sub *domain.Subscription, plan *domain.Plan) *domain.Payment {
+	metadata := domain.Metadata{}
+	for k, v := range sub.Metadata {
+		metadata[k] = v
+	}
+	metadata["subscription_id"] = sub.ID.String()
+	return &domain.Payment{
+		Amount:   plan.Amount,
+		Currency: plan.Currency,
+		Reference: fmt.Sprintf("sub-%s-%s-%d",
+			sub.ID, sub.CurrentPeriodEnd.UTC().Format("20060102"), sub.FailedAttempts+1),
+		CustomerID:    &sub.CustomerID,
+		Metadata:      metadata,
+		PaymentMethod: sub.PaymentMethod,
+	}
+}
@@ -0,0 +1,380 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"pgm/internal/domain"
+	"pgm/internal/service"
+)
+
+type fakePlanRepo struct {
+	domain.PlanRepo
+	byID map[uuid.UUID]*domain.Plan
+}
+
+func (r *fakePlanRepo) CreatePlan(ctx context.Context, p *domain.Plan) error {
+	p.ID = uuid.New()
+	p.CreatedAt = time.Now()
+	stored := *p
+	r.byID[p.ID] = &stored
+	return nil
+}
+
+func (r *fakePlanRepo) GetPlanByID(ctx context.Context, id uuid.UUID) (*domain.Plan, error) {
+	p, ok := r.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	found := *p
+	return &found, nil
+}
+
+type fakeSubscriptionRepo struct {
+	domain.SubscriptionRepo
+	byID     map[uuid.UUID]*domain.Subscription
+	payments *fakeRepo
+}
+
+func (r *fakeSubscriptionRepo) CreateSubscription(ctx context.Context, s *domain.Subscription) error {
+	s.ID = uuid.New()
+	s.CreatedAt = time.Now()
+	stored := *s
+	r.byID[s.ID] = &stored
+	return nil
+}
+
+func (r *fakeSubscriptionRepo) GetSubscriptionByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	s, ok := r.byID[id]
+	if !ok {
+		return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+	}
+	found := *s
+	return &found, nil
+}
+
+func (r *fakeSubscriptionRepo) GetSubscriptionByIDForUpdate(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	return r.GetSubscriptionByID(ctx, id)
+}
+
+func (r *fakeSubscriptionRepo) UpdateSubscription(ctx context.Context, s *domain.Subscription) error {
+	stored := *s
+	r.byID[s.ID] = &stored
+	return nil
+}
+
+func (r *fakeSubscriptionRepo) ClaimDueSubscription(ctx context.Context, now time.Time) (*domain.Subscription, error) {
+	for _, s := range r.byID {
+		if s.Status != domain.SubscriptionCanceled && s.PendingPaymentID == nil &&
+			s.NextBillingAt != nil && !s.NextBillingAt.After(now) {
+			return r.GetSubscriptionByID(ctx, s.ID)
+		}
+	}
+	return nil, fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func (r *fakeSubscriptionRepo) ClaimSettledSubscription(ctx context.Context) (*domain.Subscription, domain.PaymentStatus, error) {
+	for _, s := range r.byID {
+		if s.PendingPaymentID == nil {
+			continue
+		}
+		if p := r.payments.byID[*s.PendingPaymentID]; p.Status != domain.StatusPending {
+			found, _ := r.GetSubscriptionByID(ctx, s.ID)
+			return found, p.Status, nil
+		}
+	}
+	return nil, "", fmt.Errorf("%w: no rows", domain.ErrNotFound)
+}
+
+func setupSubscriptions() (domain.SubscriptionService, *fakeUnitOfWork) {
+	repo := &fakeRepo{byID: make(map[uuid.UUID]*domain.Payment)}
+	uow := &fakeUnitOfWork{
+		repo:      repo,
+		customers: newFakeCustomerRepo(),
+		plans:     &fakePlanRepo{byID: make(map[uuid.UUID]*domain.Plan)},
+		subs:      &fakeSubscriptionRepo{byID: make(map[uuid.UUID]*domain.Subscription), payments: repo},
+	}
+	return service.NewSubscriptionService(uow), uow
+}
+
+func createPlan(t *testing.T, uow *fakeUnitOfWork, pr domain.PlanRequest) *domain.Plan {
+	t.Helper()
+	plan, err := service.NewPlanService(uow).CreatePlan(context.Background(), &pr)
+	assert.NoError(t, err)
+	return plan
+}
+
+func subscriptionRequest(t *testing.T, uow *fakeUnitOfWork, plan *domain.Plan) *domain.SubscriptionRequest {
+	t.Helper()
+	customer := &domain.Customer{Name: "Abebe"}
+	assert.NoError(t, uow.customers.CreateCustomer(context.Background(), customer))
+	return &domain.SubscriptionRequest{
+		PlanID:     plan.ID,
+		CustomerID: customer.ID,
+		PaymentMethod: &domain.PaymentMethod{
+			Type:        domain.MethodMobileMoney,
+			MobileMoney: &domain.MobileMoneyDetails{Provider: "telebirr", MSISDN: "+251911234567"},
+		},
+		Metadata: domain.Metadata{"plan": "gold"},
+	}
+}
+
+// makeDue moves the subscription's next billing date into the past.
+func makeDue(uow *fakeUnitOfWork, id uuid.UUID) {
+	past := time.Now().Add(-time.Minute)
+	uow.subs.byID[id].NextBillingAt = &past
+}
+
+// settlePending gives the subscription's pending payment status, as the
+// payment worker would.
+func settlePending(t *testing.T, uow *fakeUnitOfWork, id uuid.UUID, status domain.PaymentStatus) *domain.Payment {
+	t.Helper()
+	pending := uow.subs.byID[id].PendingPaymentID
+	if pending == nil {
+		t.Fatal("expected a pending cycle payment")
+	}
+	payment := uow.repo.byID[*pending]
+	payment.Status = status
+	return payment
+}
+
+func TestCreatePlan(t *testing.T) {
+	tests := []struct {
+		name           string
+		req            domain.PlanRequest
+		expectedStatus int
+	}{
+		{"monthly", domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth}, 0},
+		{"zero amount", domain.PlanRequest{Name: "Free", Currency: "ETB", Interval: domain.IntervalMonth}, http.StatusBadRequest},
+		{"unknown interval", domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: "fortnight"}, http.StatusBadRequest},
+		{"negative trial", domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalWeek, TrialDays: -1}, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, uow := setupSubscriptions()
+			plan, err := service.NewPlanService(uow).CreatePlan(context.Background(), &tt.req)
+			if tt.expectedStatus != 0 {
+				assert.Equal(t, tt.expectedStatus, errorStatus(t, err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, 1, plan.IntervalCount)
+		})
+	}
+}
+
+func TestPlanNextBillingDate(t *testing.T) {
+	monthly := domain.Plan{Interval: domain.IntervalMonth, IntervalCount: 1}
+	anchor := time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC)
+
+	feb := monthly.NextBillingDate(anchor, anchor)
+	assert.Equal(t, time.Date(2026, time.February, 28, 9, 0, 0, 0, time.UTC), feb)
+	// Counting from the anchor brings the 31st back after a short month
+	assert.Equal(t, time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC), monthly.NextBillingDate(anchor, feb))
+
+	biweekly := domain.Plan{Interval: domain.IntervalWeek, IntervalCount: 2}
+	assert.Equal(t, anchor.AddDate(0, 0, 14), biweekly.NextBillingDate(anchor, anchor))
+}
+
+func TestCreateSubscription(t *testing.T) {
+	t.Run("trial delays the first charge", func(t *testing.T) {
+		svc, uow := setupSubscriptions()
+		plan := createPlan(t, uow, domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth, TrialDays: 14})
+
+		sub, err := svc.CreateSubscription(context.Background(), subscriptionRequest(t, uow, plan))
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SubscriptionTrialing, sub.Status)
+		assert.Equal(t, sub.TrialEnd, &sub.BillingAnchor)
+		assert.Equal(t, sub.BillingAnchor, *sub.NextBillingAt)
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, 14), sub.BillingAnchor, time.Minute)
+	})
+
+	t.Run("anchor before the trial ends", func(t *testing.T) {
+		svc, uow := setupSubscriptions()
+		plan := createPlan(t, uow, domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth, TrialDays: 14})
+		req := subscriptionRequest(t, uow, plan)
+		anchor := time.Now().AddDate(0, 0, 7)
+		req.BillingAnchor = &anchor
+
+		_, err := svc.CreateSubscription(context.Background(), req)
+		assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	})
+
+	t.Run("unknown plan", func(t *testing.T) {
+		svc, uow := setupSubscriptions()
+		req := subscriptionRequest(t, uow, &domain.Plan{ID: uuid.New()})
+
+		_, err := svc.CreateSubscription(context.Background(), req)
+		assert.Equal(t, http.StatusNotFound, errorStatus(t, err))
+	})
+
+	t.Run("missing payment method", func(t *testing.T) {
+		svc, uow := setupSubscriptions()
+		plan := createPlan(t, uow, domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth})
+		req := subscriptionRequest(t, uow, plan)
+		req.PaymentMethod = nil
+
+		_, err := svc.CreateSubscription(context.Background(), req)
+		assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	})
+}
+
+func TestSchedulerBillsAndAdvances(t *testing.T) {
+	svc, uow := setupSubscriptions()
+	publisher := &fakePublisher{}
+	scheduler := service.NewSubscriptionScheduler(uow, publisher, service.DefaultSchedulerConfig)
+	plan := createPlan(t, uow, domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth})
+	sub, err := svc.CreateSubscription(context.Background(), subscriptionRequest(t, uow, plan))
+	assert.NoError(t, err)
+	anchor := sub.BillingAnchor
+
+	assert.NoError(t, scheduler.RunOnce(context.Background()))
+	payment := settlePending(t, uow, sub.ID, domain.StatusSuccess)
+	assert.Equal(t, []string{payment.ID.String()}, publisher.published)
+	assert.Equal(t, 300.0, payment.Amount)
+	assert.Equal(t, sub.CustomerID, *payment.CustomerID)
+	assert.Equal(t, domain.MethodMobileMoney, payment.PaymentMethod.Type)
+	assert.Equal(t, domain.Metadata{"plan": "gold", "subscription_id": sub.ID.String()}, payment.Metadata)
+
+	// A pending payment is not billed again
+	assert.NoError(t, scheduler.RunOnce(context.Background()))
+	got := uow.subs.byID[sub.ID]
+	assert.Nil(t, got.PendingPaymentID)
+	assert.Equal(t, domain.SubscriptionActive, got.Status)
+	assert.Equal(t, anchor, got.CurrentPeriodStart)
+	assert.Equal(t, plan.CycleEnd(anchor, 1), got.CurrentPeriodEnd)
+	assert.Equal(t, got.CurrentPeriodEnd, *got.NextBillingAt)
+	assert.Len(t, publisher.published, 1)
+}
+
+func TestSchedulerDunning(t *testing.T) {
+	svc, uow := setupSubscriptions()
+	publisher := &fakePublisher{}
+	cfg := service.SchedulerConfig{DunningSchedule: []time.Duration{time.Hour, 2 * time.Hour}}
+	scheduler := service.NewSubscriptionScheduler(uow, publisher, cfg)
+	plan := createPlan(t, uow, domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth})
+	sub, err := svc.CreateSubscription(context.Background(), subscriptionRequest(t, uow, plan))
+	assert.NoError(t, err)
+
+	var references []string
+	for i, retryIn := range cfg.DunningSchedule {
+		assert.NoError(t, scheduler.RunOnce(context.Background()))
+		references = append(references, settlePending(t, uow, sub.ID, domain.StatusFailed).Reference)
+		assert.NoError(t, scheduler.RunOnce(context.Background()))
+
+		got := uow.subs.byID[sub.ID]
+		assert.Equal(t, domain.SubscriptionPastDue, got.Status)
+		assert.Equal(t, i+1, got.FailedAttempts)
+		assert.WithinDuration(t, time.Now().Add(retryIn), *got.NextBillingAt, time.Minute)
+		makeDue(uow, sub.ID)
+	}
+
+	assert.NoError(t, scheduler.RunOnce(context.Background()))
+	references = append(references, settlePending(t, uow, sub.ID, domain.StatusFailed).Reference)
+	assert.NoError(t, scheduler.RunOnce(context.Background()))
+
+	got := uow.subs.byID[sub.ID]
+	assert.Equal(t, domain.SubscriptionCanceled, got.Status)
+	assert.NotNil(t, got.CanceledAt)
+	assert.Nil(t, got.NextBillingAt)
+	assert.Len(t, publisher.published, 3)
+	// The period did not move, so every retry bills the same cycle
+	assert.Equal(t, references[0][:len(references[0])-1]+"3", references[2])
+}
+
+func TestSchedulerRecoversFromPastDue(t *testing.T) {
+	svc, uow := setupSubscriptions()
+	scheduler := service.NewSubscriptionScheduler(uow, &fakePublisher{}, service.DefaultSchedulerConfig)
+	plan := createPlan(t, uow, domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth})
+	sub, err := svc.CreateSubscription(context.Background(), subscriptionRequest(t, uow, plan))
+	assert.NoError(t, err)
+
+	assert.NoError(t, scheduler.RunOnce(context.Background()))
+	settlePending(t, uow, sub.ID, domain.StatusFailed)
+	assert.NoError(t, scheduler.RunOnce(context.Background()))
+	makeDue(uow, sub.ID)
+	assert.NoError(t, scheduler.RunOnce(context.Background()))
+	settlePending(t, uow, sub.ID, domain.StatusSuccess)
+	assert.NoError(t, scheduler.RunOnce(context.Background()))
+
+	got := uow.subs.byID[sub.ID]
+	assert.Equal(t, domain.SubscriptionActive, got.Status)
+	assert.Equal(t, 0, got.FailedAttempts)
+	assert.Equal(t, sub.BillingAnchor, got.CurrentPeriodStart)
+}
+
+func TestSchedulerSkipsFailingSubscription(t *testing.T) {
+	svc, uow := setupSubscriptions()
+	publisher := &fakePublisher{}
+	cfg := service.SchedulerConfig{RetryAfter: 2 * time.Hour}
+	scheduler := service.NewSubscriptionScheduler(uow, publisher, cfg)
+	plan := createPlan(t, uow, domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth})
+	broken := createPlan(t, uow, domain.PlanRequest{Name: "Silver", Amount: 100, Currency: "ETB", Interval: domain.IntervalMonth})
+	sub, err := svc.CreateSubscription(context.Background(), subscriptionRequest(t, uow, plan))
+	assert.NoError(t, err)
+	failing, err := svc.CreateSubscription(context.Background(), subscriptionRequest(t, uow, broken))
+	assert.NoError(t, err)
+	delete(uow.plans.byID, broken.ID)
+
+	// The subscription that cannot be billed does not stop the run
+	assert.NoError(t, scheduler.RunOnce(context.Background()))
+	settlePending(t, uow, sub.ID, domain.StatusSuccess)
+	assert.Len(t, publisher.published, 1)
+
+	got := uow.subs.byID[failing.ID]
+	assert.Nil(t, got.PendingPaymentID)
+	assert.Equal(t, domain.SubscriptionActive, got.Status)
+	assert.WithinDuration(t, time.Now().Add(cfg.RetryAfter), *got.NextBillingAt, time.Minute)
+
+	// Nor is it claimed again before the retry is due
+	assert.NoError(t, scheduler.RunOnce(context.Background()))
+	assert.Len(t, publisher.published, 1)
+}
+
+func TestCancelSubscription(t *testing.T) {
+	t.Run("at period end", func(t *testing.T) {
+		svc, uow := setupSubscriptions()
+		publisher := &fakePublisher{}
+		scheduler := service.NewSubscriptionScheduler(uow, publisher, service.DefaultSchedulerConfig)
+		plan := createPlan(t, uow, domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth})
+		sub, err := svc.CreateSubscription(context.Background(), subscriptionRequest(t, uow, plan))
+		assert.NoError(t, err)
+
+		sub, err = svc.CancelSubscription(context.Background(), sub.ID.String(), &domain.CancelSubscriptionRequest{AtPeriodEnd: true})
+		assert.NoError(t, err)
+		assert.True(t, sub.CancelAtPeriodEnd)
+		assert.Equal(t, domain.SubscriptionActive, sub.Status)
+
+		assert.NoError(t, scheduler.RunOnce(context.Background()))
+		got := uow.subs.byID[sub.ID]
+		assert.Equal(t, domain.SubscriptionCanceled, got.Status)
+		assert.Empty(t, publisher.published)
+	})
+
+	t.Run("immediately", func(t *testing.T) {
+		svc, uow := setupSubscriptions()
+		plan := createPlan(t, uow, domain.PlanRequest{Name: "Gold", Amount: 300, Currency: "ETB", Interval: domain.IntervalMonth, TrialDays: 7})
+		sub, err := svc.CreateSubscription(context.Background(), subscriptionRequest(t, uow, plan))
+		assert.NoError(t, err)
+
+		sub, err = svc.CancelSubscription(context.Background(), sub.ID.String(), &domain.CancelSubscriptionRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, domain.SubscriptionCanceled, sub.Status)
+		assert.Nil(t, sub.NextBillingAt)
+
+		_, err = svc.CancelSubscription(context.Background(), sub.ID.String(), &domain.CancelSubscriptionRequest{})
+		assert.Equal(t, http.StatusConflict, errorStatus(t, err))
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		svc, _ := setupSubscriptions()
+		_, err := svc.CancelSubscription(context.Background(), "nope", &domain.CancelSubscriptionRequest{})
+		assert.Equal(t, http.StatusBadRequest, errorStatus(t, err))
+	})
+}
@@ -0,0 +1,201 @@
+package statement
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"pgm/internal/domain"
+)
+
+// Camt053 reads an ISO 20022 camt.053 bank-to-customer statement. Only
+// booked credit entries that are not reversals become lines; an entry
+// carrying several transactions yields one line per transaction. The
+// reference is the transaction's end-to-end ID, falling back to its
+// unstructured remittance information and then the entry's own references.
+type Camt053 struct{}
+
+var _ domain.StatementParser = Camt053{}
+
+// The camt structs match on local names so every camt.053 version decodes.
+type camtDocument struct {
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	From    string      `xml:"FrToDt>FrDtTm"`
+	To      string      `xml:"FrToDt>ToDtTm"`
+	Entries []camtEntry `xml:"Ntry"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtEntry struct {
+	Reference          string            `xml:"NtryRef"`
+	Amount             camtAmount        `xml:"Amt"`
+	CreditDebit        string            `xml:"CdtDbtInd"`
+	Reversal           bool              `xml:"RvslInd"`
+	Status             camtStatus        `xml:"Sts"`
+	BookingDate        camtDate          `xml:"BookgDt"`
+	AccountServicerRef string            `xml:"AcctSvcrRef"`
+	Transactions       []camtTransaction `xml:"NtryDtls>TxDtls"`
+}
+
+// camtStatus is a bare code before camt.053.001.08 and a Cd element since.
+type camtStatus struct {
+	Text string `xml:",chardata"`
+	Code string `xml:"Cd"`
+}
+
+func (s camtStatus) String() string {
+	if s.Code != "" {
+		return s.Code
+	}
+	return strings.TrimSpace(s.Text)
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtTransaction struct {
+	EndToEndID   string      `xml:"Refs>EndToEndId"`
+	Amount       *camtAmount `xml:"Amt"`
+	TxAmount     *camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+	Unstructured []string    `xml:"RmtInf>Ustrd"`
+}
+
+func (Camt053) Parse(r io.Reader) (*domain.Statement, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("statement is empty")
+		}
+		return nil, err
+	}
+	if len(doc.Statements) == 0 {
+		return nil, errors.New("not a camt.053 statement")
+	}
+
+	s := &domain.Statement{}
+	for i, stmt := range doc.Statements {
+		if err := widenPeriod(s, stmt); err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+		for j, e := range stmt.Entries {
+			lines, err := e.lines()
+			if err != nil {
+				return nil, fmt.Errorf("statement %d entry %d: %w", i+1, j+1, err)
+			}
+			s.Lines = append(s.Lines, lines...)
+		}
+	}
+	fillPeriod(s)
+	return s, nil
+}
+
+// widenPeriod extends s to cover stmt's period, if it states one.
+func widenPeriod(s *domain.Statement, stmt camtStatement) error {
+	if stmt.From != "" {
+		from, err := parseDate(strings.TrimSpace(stmt.From))
+		if err != nil {
+			return err
+		}
+		if s.PeriodStart == nil || from.Before(*s.PeriodStart) {
+			s.PeriodStart = &from
+		}
+	}
+	if stmt.To != "" {
+		to, err := parseDate(strings.TrimSpace(stmt.To))
+		if err != nil {
+			return err
+		}
+		if s.PeriodEnd == nil || to.After(*s.PeriodEnd) {
+			s.PeriodEnd = &to
+		}
+	}
+	return nil
+}
+
+func (e camtEntry) lines() ([]domain.StatementLine, error) {
+	if e.CreditDebit != "CRDT" || e.Reversal || e.Status.String() != "BOOK" {
+		return nil, nil
+	}
+	var bookedAt *time.Time
+	if d := strings.TrimSpace(e.BookingDate.DateTime + e.BookingDate.Date); d != "" {
+		t, err := parseDate(d)
+		if err != nil {
+			return nil, err
+		}
+		bookedAt = &t
+	}
+	entryRef := strings.TrimSpace(e.AccountServicerRef)
+	if ref := strings.TrimSpace(e.Reference); ref != "" {
+		entryRef = ref
+	}
+
+	if len(e.Transactions) <= 1 {
+		ref := entryRef
+		if len(e.Transactions) == 1 {
+			ref = e.Transactions[0].reference(entryRef)
+		}
+		l, err := newCamtLine(ref, e.Amount, bookedAt)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.StatementLine{l}, nil
+	}
+
+	// A batched entry: each transaction states its own amount
+	lines := make([]domain.StatementLine, 0, len(e.Transactions))
+	for _, tx := range e.Transactions {
+		amount := tx.Amount
+		if amount == nil {
+			amount = tx.TxAmount
+		}
+		if amount == nil {
+			return nil, errors.New("batched transaction has no amount")
+		}
+		l, err := newCamtLine(tx.reference(""), *amount, bookedAt)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, nil
+}
+
+func (tx camtTransaction) reference(fallback string) string {
+	if id := strings.TrimSpace(tx.EndToEndID); id != "" && id != "NOTPROVIDED" {
+		return id
+	}
+	for _, u := range tx.Unstructured {
+		if u = strings.TrimSpace(u); u != "" {
+			return u
+		}
+	}
+	return fallback
+}
+
+func newCamtLine(reference string, amount camtAmount, bookedAt *time.Time) (domain.StatementLine, error) {
+	if reference == "" {
+		return domain.StatementLine{}, errors.New("no reference")
+	}
+	value, err := parseAmount(amount.Value)
+	if err != nil {
+		return domain.StatementLine{}, err
+	}
+	return domain.StatementLine{
+		Reference: reference,
+		Amount:    value,
+		Currency:  strings.ToUpper(strings.TrimSpace(amount.Currency)),
+		BookedAt:  bookedAt,
+	}, nil
+}
@@ -0,0 +1,114 @@
+package statement_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"pgm/internal/statement"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const camt053 = `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+  <BkToCstmrStmt>
+    <GrpHdr><MsgId>STMT-1</MsgId><CreDtTm>2026-03-02T06:00:00Z</CreDtTm></GrpHdr>
+    <Stmt>
+      <Id>STMT-1</Id>
+      <FrToDt><FrDtTm>2026-03-01T00:00:00Z</FrDtTm><ToDtTm>2026-03-02T00:00:00Z</ToDtTm></FrToDt>
+      <Ntry>
+        <Amt Ccy="USD">100.50</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2026-03-01</Dt></BookgDt>
+        <NtryDtls><TxDtls><Refs><EndToEndId>ref-1</EndToEndId></Refs></TxDtls></NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="USD">30.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <BookgDt><Dt>2026-03-01</Dt></BookgDt>
+        <NtryDtls>
+          <TxDtls>
+            <Refs><EndToEndId>NOTPROVIDED</EndToEndId></Refs>
+            <AmtDtls><TxAmt><Amt Ccy="USD">10.00</Amt></TxAmt></AmtDtls>
+            <RmtInf><Ustrd>ref-2</Ustrd></RmtInf>
+          </TxDtls>
+          <TxDtls>
+            <Refs><EndToEndId>ref-3</EndToEndId></Refs>
+            <Amt Ccy="USD">20.00</Amt>
+          </TxDtls>
+        </NtryDtls>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="USD">5.00</Amt>
+        <CdtDbtInd>DBIT</CdtDbtInd>
+        <Sts>BOOK</Sts>
+        <AcctSvcrRef>fee-1</AcctSvcrRef>
+      </Ntry>
+      <Ntry>
+        <Amt Ccy="USD">7.00</Amt>
+        <CdtDbtInd>CRDT</CdtDbtInd>
+        <Sts>PDNG</Sts>
+        <AcctSvcrRef>pending-1</AcctSvcrRef>
+      </Ntry>
+    </Stmt>
+  </BkToCstmrStmt>
+</Document>`
+
+func TestCamt053Parse(t *testing.T) {
+	s, err := statement.Camt053{}.Parse(strings.NewReader(camt053))
+	assert.NoError(t, err)
+	if s == nil || len(s.Lines) != 3 {
+		t.Fatalf("expected 3 lines, got %+v", s)
+	}
+	assert.Equal(t, "ref-1", s.Lines[0].Reference)
+	assert.Equal(t, 100.5, s.Lines[0].Amount)
+	assert.Equal(t, "USD", s.Lines[0].Currency)
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *s.Lines[0].BookedAt)
+
+	// A batched entry yields one line per transaction
+	assert.Equal(t, "ref-2", s.Lines[1].Reference)
+	assert.Equal(t, 10.0, s.Lines[1].Amount)
+	assert.Equal(t, "ref-3", s.Lines[2].Reference)
+	assert.Equal(t, 20.0, s.Lines[2].Amount)
+
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *s.PeriodStart)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), *s.PeriodEnd)
+}
+
+func TestCamt053ParseStatusCode(t *testing.T) {
+	doc := `<Document><BkToCstmrStmt><Stmt><Ntry>
+		<NtryRef>ref-1</NtryRef><Amt Ccy="ETB">50</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts><Cd>BOOK</Cd></Sts>
+	</Ntry></Stmt></BkToCstmrStmt></Document>`
+	s, err := statement.Camt053{}.Parse(strings.NewReader(doc))
+	assert.NoError(t, err)
+	if s == nil || len(s.Lines) != 1 {
+		t.Fatalf("expected 1 line, got %+v", s)
+	}
+	assert.Equal(t, "ref-1", s.Lines[0].Reference)
+	assert.Nil(t, s.PeriodStart)
+}
+
+func TestCamt053ParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", "statement is empty"},
+		{"not camt", "<Document><Other/></Document>", "not a camt.053 statement"},
+		{"malformed", "<Document><BkToCstmrStmt>", "unexpected EOF"},
+		{"bad amount", `<Document><BkToCstmrStmt><Stmt><Ntry><NtryRef>r</NtryRef><Amt Ccy="USD">x</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts></Ntry></Stmt></BkToCstmrStmt></Document>`, "statement 1 entry 1: invalid amount"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := statement.Camt053{}.Parse(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
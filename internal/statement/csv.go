@@ -0,0 +1,75 @@
+package statement
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"pgm/internal/domain"
+)
+
+// CSV reads a generic CSV statement. The header row names the columns in
+// any order and case: reference, amount and currency are required; date,
+// when present, is the booking date as YYYY-MM-DD or RFC 3339. Every row is
+// one settled payment.
+type CSV struct{}
+
+var _ domain.StatementParser = CSV{}
+
+func (CSV) Parse(r io.Reader) (*domain.Statement, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, errors.New("statement is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"reference", "amount", "currency"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("missing %s column", name)
+		}
+	}
+	dateColumn, hasDate := columns["date"]
+
+	s := &domain.Statement{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+
+		l := domain.StatementLine{
+			Reference: strings.TrimSpace(record[columns["reference"]]),
+			Currency:  strings.ToUpper(strings.TrimSpace(record[columns["currency"]])),
+		}
+		if l.Reference == "" {
+			return nil, fmt.Errorf("line %d: reference is empty", line)
+		}
+		if l.Amount, err = parseAmount(record[columns["amount"]]); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if hasDate && strings.TrimSpace(record[dateColumn]) != "" {
+			bookedAt, err := parseDate(strings.TrimSpace(record[dateColumn]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", line, err)
+			}
+			l.BookedAt = &bookedAt
+		}
+		s.Lines = append(s.Lines, l)
+	}
+	fillPeriod(s)
+	return s, nil
+}
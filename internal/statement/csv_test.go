@@ -0,0 +1,62 @@
+package statement_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"pgm/internal/statement"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVParse(t *testing.T) {
+	s, err := statement.CSV{}.Parse(strings.NewReader("Currency,Reference,Amount,Date\nusd,ref-1,100.50,2026-03-01\nETB, ref-2 ,20,2026-03-02T10:00:00Z\n"))
+	assert.NoError(t, err)
+	if s == nil || len(s.Lines) != 2 {
+		t.Fatalf("expected 2 lines, got %+v", s)
+	}
+	assert.Equal(t, "ref-1", s.Lines[0].Reference)
+	assert.Equal(t, 100.5, s.Lines[0].Amount)
+	assert.Equal(t, "USD", s.Lines[0].Currency)
+	assert.Equal(t, "ref-2", s.Lines[1].Reference)
+
+	// The period covers the days the lines were booked on
+	assert.Equal(t, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), *s.PeriodStart)
+	assert.Equal(t, time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), *s.PeriodEnd)
+}
+
+func TestCSVParseWithoutDates(t *testing.T) {
+	s, err := statement.CSV{}.Parse(strings.NewReader("reference,amount,currency\nref-1,10,USD\n"))
+	assert.NoError(t, err)
+	if s == nil {
+		t.Fatal("expected a statement")
+	}
+	assert.Len(t, s.Lines, 1)
+	assert.Nil(t, s.PeriodStart)
+	assert.Nil(t, s.PeriodEnd)
+}
+
+func TestCSVParseErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"empty", "", "statement is empty"},
+		{"missing column", "reference,amount\nref-1,10\n", "missing currency column"},
+		{"bad amount", "reference,amount,currency\nref-1,ten,USD\n", "line 2: invalid amount"},
+		{"negative amount", "reference,amount,currency\nref-1,-5,USD\n", "line 2: invalid amount"},
+		{"empty reference", "reference,amount,currency\n,5,USD\n", "line 2: reference is empty"},
+		{"bad date", "reference,amount,currency,date\nref-1,5,USD,01/03/2026\n", "line 2: invalid date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := statement.CSV{}.Parse(strings.NewReader(tt.input))
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			assert.Contains(t, err.Error(), tt.expected)
+		})
+	}
+}
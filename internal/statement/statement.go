@@ -0,0 +1,72 @@
+// Package statement parses provider and bank settlement statements for
+// reconciliation.
+package statement
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"pgm/internal/domain"
+)
+
+// Parsers returns a parser for every supported statement format.
+func Parsers() map[domain.StatementFormat]domain.StatementParser {
+	return map[domain.StatementFormat]domain.StatementParser{
+		domain.FormatCSV:     CSV{},
+		domain.FormatCamt053: Camt053{},
+	}
+}
+
+// dateLayouts are tried in order when reading a date or timestamp. Values
+// without a zone are taken as UTC.
+var dateLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+func parseAmount(s string) (float64, error) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || amount <= 0 {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	return amount, nil
+}
+
+// fillPeriod bounds a statement that does not state its period by the days
+// its lines were booked on.
+func fillPeriod(s *domain.Statement) {
+	if s.PeriodStart != nil && s.PeriodEnd != nil {
+		return
+	}
+	var first, last time.Time
+	for _, l := range s.Lines {
+		if l.BookedAt == nil {
+			continue
+		}
+		if first.IsZero() || l.BookedAt.Before(first) {
+			first = *l.BookedAt
+		}
+		if l.BookedAt.After(last) {
+			last = *l.BookedAt
+		}
+	}
+	if first.IsZero() {
+		return
+	}
+	start := first.UTC().Truncate(24 * time.Hour)
+	end := last.UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	if s.PeriodStart == nil {
+		s.PeriodStart = &start
+	}
+	if s.PeriodEnd == nil {
+		s.PeriodEnd = &end
+	}
+}